@@ -11,6 +11,7 @@ type StudentAssessmentCreateDTO struct {
 	TaskType       string `json:"task_type" validate:"required,min=1,max=70"`
 	Grade          int    `json:"grade" validate:"required"`
 	AssessmentDate string `json:"assessment_date" validate:"required"` // Строка в формате DD.MM.YYYY
+	OccurrenceID   *int   `json:"occurrence_id,omitempty"`             // Занятие журнала (lesson_occurrence)
 }
 
 type StudentAssessmentUpdateDTO struct {
@@ -19,15 +20,19 @@ type StudentAssessmentUpdateDTO struct {
 	TaskType       *string `json:"task_type,omitempty" validate:"omitempty,min=1,max=70"`
 	Grade          *int    `json:"grade,omitempty" validate:"omitempty"`
 	AssessmentDate *string `json:"assessment_date,omitempty" validate:"omitempty"` // Строка в формате DD.MM.YYYY
+	OccurrenceID   *int    `json:"occurrence_id,omitempty"`                        // Занятие журнала (lesson_occurrence)
 }
 
 type StudentAssessmentResponseDTO struct {
-	ID             int    `json:"id"`
-	LessonID       int    `json:"lesson_id"`
-	StudentID      int    `json:"student_id"`
-	TaskType       string `json:"task_type"`
-	Grade          int    `json:"grade"`
-	AssessmentDate string `json:"assessment_date"` // Строка в формате DD.MM.YYYY
+	ID             int     `json:"id"`
+	LessonID       int     `json:"lesson_id"`
+	StudentID      int     `json:"student_id"`
+	TaskType       string  `json:"task_type"`
+	Grade          int     `json:"grade"`
+	AssessmentDate string  `json:"assessment_date"` // Строка в формате DD.MM.YYYY
+	OccurrenceID   *int    `json:"occurrence_id,omitempty"`
+	Version        int     `json:"version"`
+	DeletedAt      *string `json:"deleted_at,omitempty"`
 }
 
 // AssessmentMapper реализует маппинг для оценок
@@ -57,6 +62,7 @@ func (m *AssessmentMapper) ToDomain(dto *StudentAssessmentCreateDTO) *domain.Stu
 		TaskType:       dto.TaskType,
 		Grade:          dto.Grade,
 		AssessmentDate: ParseDMY(dto.AssessmentDate), // Преобразуем строку в time.Time
+		OccurrenceID:   dto.OccurrenceID,
 	}
 }
 
@@ -77,6 +83,9 @@ func (m *AssessmentMapper) UpdateDomain(assessment *domain.StudentAssessment, dt
 	if dto.AssessmentDate != nil {
 		assessment.AssessmentDate = ParseDMY(*dto.AssessmentDate) // Преобразуем строку в time.Time
 	}
+	if dto.OccurrenceID != nil {
+		assessment.OccurrenceID = dto.OccurrenceID
+	}
 }
 
 // ToResponse преобразует доменную модель в ResponseDTO
@@ -88,6 +97,9 @@ func (m *AssessmentMapper) ToResponse(assessment *domain.StudentAssessment) *Stu
 		TaskType:       assessment.TaskType,
 		Grade:          assessment.Grade,
 		AssessmentDate: ToDMY(assessment.AssessmentDate), // Преобразуем time.Time в строку DD.MM.YYYY
+		OccurrenceID:   assessment.OccurrenceID,
+		Version:        assessment.Version,
+		DeletedAt:      domain.ToDateTimePtr(assessment.DeletedAt),
 	}
 }
 
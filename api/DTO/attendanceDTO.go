@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"GO_Music/domain"
+	"time"
+)
+
+// StudentAttendanceCreateDTO для создания записи посещаемости
+type StudentAttendanceCreateDTO struct {
+	StudentID      int    `json:"student_id" validate:"required"`
+	LessonID       int    `json:"lesson_id" validate:"required"`
+	PresenceMark   bool   `json:"presence_mark"`
+	AttendanceDate string `json:"attendance_date" validate:"required"` // Строка в формате DD.MM.YYYY
+	OccurrenceID   *int   `json:"occurrence_id,omitempty"`             // Занятие журнала (lesson_occurrence)
+}
+
+// StudentAttendanceUpdateDTO для обновления записи посещаемости
+type StudentAttendanceUpdateDTO struct {
+	StudentID      *int    `json:"student_id,omitempty" validate:"omitempty"`
+	LessonID       *int    `json:"lesson_id,omitempty" validate:"omitempty"`
+	PresenceMark   *bool   `json:"presence_mark,omitempty"`
+	AttendanceDate *string `json:"attendance_date,omitempty" validate:"omitempty"` // Строка в формате DD.MM.YYYY
+	OccurrenceID   *int    `json:"occurrence_id,omitempty"`                        // Занятие журнала (lesson_occurrence)
+}
+
+// StudentAttendanceResponseDTO для ответа API
+type StudentAttendanceResponseDTO struct {
+	AttendanceNoteID int     `json:"attendance_note_id"`
+	StudentID        int     `json:"student_id"`
+	LessonID         int     `json:"lesson_id"`
+	PresenceMark     bool    `json:"presence_mark"`
+	AttendanceDate   string  `json:"attendance_date"`
+	OccurrenceID     *int    `json:"occurrence_id,omitempty"`
+	Version          int     `json:"version"`
+	DeletedAt        *string `json:"deleted_at,omitempty"`
+}
+
+// StudentAttendanceMapper реализует маппинг для посещаемости
+type StudentAttendanceMapper struct{}
+
+func NewStudentAttendanceMapper() *StudentAttendanceMapper {
+	return &StudentAttendanceMapper{}
+}
+
+// toDBDate преобразует "DD.MM.YYYY" в "YYYY-MM-DD" (формат хранения в домене)
+func toDBDate(dateStr string) string {
+	if t := domain.ParseDMY(dateStr); !t.IsZero() {
+		return t.Format("2006-01-02")
+	}
+	return dateStr
+}
+
+// fromDBDate преобразует "YYYY-MM-DD" (или RFC3339 из БД) в "DD.MM.YYYY"
+func fromDBDate(dateStr string) string {
+	if len(dateStr) >= 10 {
+		if t, err := time.Parse("2006-01-02", dateStr[:10]); err == nil {
+			return ToDMY(t)
+		}
+	}
+	return dateStr
+}
+
+func (m *StudentAttendanceMapper) ToDomain(dto *StudentAttendanceCreateDTO) *domain.StudentAttendance {
+	return &domain.StudentAttendance{
+		StudentID:      dto.StudentID,
+		LessonID:       dto.LessonID,
+		PresenceMark:   dto.PresenceMark,
+		AttendanceDate: toDBDate(dto.AttendanceDate),
+		OccurrenceID:   dto.OccurrenceID,
+	}
+}
+
+func (m *StudentAttendanceMapper) UpdateDomain(attendance *domain.StudentAttendance, dto *StudentAttendanceUpdateDTO) {
+	if dto.StudentID != nil {
+		attendance.StudentID = *dto.StudentID
+	}
+	if dto.LessonID != nil {
+		attendance.LessonID = *dto.LessonID
+	}
+	if dto.PresenceMark != nil {
+		attendance.PresenceMark = *dto.PresenceMark
+	}
+	if dto.AttendanceDate != nil {
+		attendance.AttendanceDate = toDBDate(*dto.AttendanceDate)
+	}
+	if dto.OccurrenceID != nil {
+		attendance.OccurrenceID = dto.OccurrenceID
+	}
+}
+
+func (m *StudentAttendanceMapper) ToResponse(attendance *domain.StudentAttendance) *StudentAttendanceResponseDTO {
+	return &StudentAttendanceResponseDTO{
+		AttendanceNoteID: attendance.AttendanceNoteID,
+		StudentID:        attendance.StudentID,
+		LessonID:         attendance.LessonID,
+		PresenceMark:     attendance.PresenceMark,
+		AttendanceDate:   attendance.AttendanceDate,
+		OccurrenceID:     attendance.OccurrenceID,
+		Version:          attendance.Version,
+		DeletedAt:        domain.ToDateTimePtr(attendance.DeletedAt),
+	}
+}
+
+func (m *StudentAttendanceMapper) ToResponseList(records []*domain.StudentAttendance) []*StudentAttendanceResponseDTO {
+	result := make([]*StudentAttendanceResponseDTO, len(records))
+	for i, record := range records {
+		result[i] = m.ToResponse(record)
+	}
+	return result
+}
+
+// ToResponseListWithFormattedDate преобразует список с датами в формате DD.MM.YYYY
+func (m *StudentAttendanceMapper) ToResponseListWithFormattedDate(records []*domain.StudentAttendance) []*StudentAttendanceResponseDTO {
+	result := m.ToResponseList(records)
+	for _, item := range result {
+		item.AttendanceDate = fromDBDate(item.AttendanceDate)
+	}
+	return result
+}
@@ -22,11 +22,13 @@ type AudienceUpdateDTO struct {
 
 // AudienceResponseDTO для ответа API
 type AudienceResponseDTO struct {
-	AudienceID  int    `json:"audience_id"`
-	Name        string `json:"name"`
-	AudinType   string `json:"audin_type"`
-	AudinNumber string `json:"audin_number"`
-	Capacity    int    `json:"capacity"`
+	AudienceID  int     `json:"audience_id"`
+	Name        string  `json:"name"`
+	AudinType   string  `json:"audin_type"`
+	AudinNumber string  `json:"audin_number"`
+	Capacity    int     `json:"capacity"`
+	Version     int     `json:"version"`
+	DeletedAt   *string `json:"deleted_at,omitempty"`
 }
 
 // AudienceMapper реализует маппинг для аудиторий
@@ -67,6 +69,8 @@ func (m *AudienceMapper) ToResponse(audience *domain.Audience) *AudienceResponse
 		AudinType:   audience.AudinType,
 		AudinNumber: audience.AudinNumber,
 		Capacity:    audience.Capacity,
+		Version:     audience.Version,
+		DeletedAt:   domain.ToDateTimePtr(audience.DeletedAt),
 	}
 }
 
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"GO_Music/domain"
+)
+
+// AuditEntryResponseDTO для ответа API; журнал доступен только для чтения, поэтому Create/Update DTO нет
+type AuditEntryResponseDTO struct {
+	AuditID   int             `json:"audit_id"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	UserID    *int            `json:"user_id,omitempty"`
+	Changes   json.RawMessage `json:"changes"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// AuditMapper реализует маппинг для журнала изменений
+type AuditMapper struct{}
+
+func NewAuditMapper() *AuditMapper {
+	return &AuditMapper{}
+}
+
+func (m *AuditMapper) ToResponse(entry *domain.AuditEntry) *AuditEntryResponseDTO {
+	changes := json.RawMessage(entry.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+
+	return &AuditEntryResponseDTO{
+		AuditID:   entry.AuditID,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Action:    entry.Action,
+		UserID:    entry.UserID,
+		Changes:   changes,
+		CreatedAt: domain.ToDateTime(entry.CreatedAt),
+	}
+}
+
+func (m *AuditMapper) ToResponseList(entries []*domain.AuditEntry) []*AuditEntryResponseDTO {
+	result := make([]*AuditEntryResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = m.ToResponse(entry)
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package dto
+
+import (
+	"GO_Music/domain"
+	"time"
+)
+
+// AvailabilityCreateDTO для создания окна (weekly: day_week, time_begin, time_end) или отсутствия (absence: date_start, date_end)
+type AvailabilityCreateDTO struct {
+	Kind      string  `json:"kind" validate:"required,oneof=weekly absence"`
+	DayWeek   *string `json:"day_week,omitempty" validate:"omitempty,max=20"`
+	TimeBegin *string `json:"time_begin,omitempty" validate:"omitempty,datetime=15:04"`      // Формат "15:04"; у отсутствия без времени - весь день
+	TimeEnd   *string `json:"time_end,omitempty" validate:"omitempty,datetime=15:04"`        // Формат "15:04"
+	DateStart *string `json:"date_start,omitempty" validate:"omitempty,datetime=02.01.2006"` // Формат "DD.MM.YYYY"; у окна - начало действия
+	DateEnd   *string `json:"date_end,omitempty" validate:"omitempty,datetime=02.01.2006"`   // Формат "DD.MM.YYYY"; у окна - конец действия
+	Reason    *string `json:"reason,omitempty" validate:"omitempty,max=255"`                 // Например "Больничный", "Отпуск"
+}
+
+// AvailabilityUpdateDTO для обновления записи доступности; "" - убрать необязательное поле
+type AvailabilityUpdateDTO struct {
+	Kind      *string `json:"kind,omitempty" validate:"omitempty,oneof=weekly absence"`
+	DayWeek   *string `json:"day_week,omitempty" validate:"omitempty,max=20"`
+	TimeBegin *string `json:"time_begin,omitempty"` // Формат "15:04"
+	TimeEnd   *string `json:"time_end,omitempty"`   // Формат "15:04"
+	DateStart *string `json:"date_start,omitempty"` // Формат "DD.MM.YYYY"
+	DateEnd   *string `json:"date_end,omitempty"`   // Формат "DD.MM.YYYY"
+	Reason    *string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// AvailabilityResponseDTO для ответа API
+type AvailabilityResponseDTO struct {
+	AvailabilityID int     `json:"availability_id"`
+	EmployeeID     int     `json:"employee_id"`
+	Kind           string  `json:"kind"`
+	DayWeek        *string `json:"day_week,omitempty"`
+	TimeBegin      *string `json:"time_begin,omitempty"`
+	TimeEnd        *string `json:"time_end,omitempty"`
+	DateStart      *string `json:"date_start,omitempty"`
+	DateEnd        *string `json:"date_end,omitempty"`
+	Reason         *string `json:"reason,omitempty"`
+	Version        int     `json:"version"`
+}
+
+// AvailabilityMapper реализует маппинг для доступности преподавателей
+type AvailabilityMapper struct{}
+
+func NewAvailabilityMapper() *AvailabilityMapper {
+	return &AvailabilityMapper{}
+}
+
+// ToDomain создает запись доступности преподавателя employeeID
+func (m *AvailabilityMapper) ToDomain(employeeID int, dto *AvailabilityCreateDTO) *domain.EmployeeAvailability {
+	return &domain.EmployeeAvailability{
+		EmployeeID: employeeID,
+		Kind:       dto.Kind,
+		DayWeek:    optional(dto.DayWeek),
+		TimeBegin:  parseOptional(dto.TimeBegin, domain.ParseTimeHM),
+		TimeEnd:    parseOptional(dto.TimeEnd, domain.ParseTimeHM),
+		DateStart:  parseOptional(dto.DateStart, domain.ParseDMY),
+		DateEnd:    parseOptional(dto.DateEnd, domain.ParseDMY),
+		Reason:     optional(dto.Reason),
+	}
+}
+
+func (m *AvailabilityMapper) UpdateDomain(entry *domain.EmployeeAvailability, dto *AvailabilityUpdateDTO) {
+	if dto.Kind != nil {
+		entry.Kind = *dto.Kind
+	}
+	if dto.DayWeek != nil {
+		entry.DayWeek = optional(dto.DayWeek)
+	}
+	if dto.TimeBegin != nil {
+		entry.TimeBegin = parseOptional(dto.TimeBegin, domain.ParseTimeHM)
+	}
+	if dto.TimeEnd != nil {
+		entry.TimeEnd = parseOptional(dto.TimeEnd, domain.ParseTimeHM)
+	}
+	if dto.DateStart != nil {
+		entry.DateStart = parseOptional(dto.DateStart, domain.ParseDMY)
+	}
+	if dto.DateEnd != nil {
+		entry.DateEnd = parseOptional(dto.DateEnd, domain.ParseDMY)
+	}
+	if dto.Reason != nil {
+		entry.Reason = optional(dto.Reason)
+	}
+}
+
+func (m *AvailabilityMapper) ToResponse(entry *domain.EmployeeAvailability) *AvailabilityResponseDTO {
+	return &AvailabilityResponseDTO{
+		AvailabilityID: entry.AvailabilityID,
+		EmployeeID:     entry.EmployeeID,
+		Kind:           entry.Kind,
+		DayWeek:        entry.DayWeek,
+		TimeBegin:      formatOptional(entry.TimeBegin, domain.ToTimeHM),
+		TimeEnd:        formatOptional(entry.TimeEnd, domain.ToTimeHM),
+		DateStart:      formatOptional(entry.DateStart, domain.ToDMY),
+		DateEnd:        formatOptional(entry.DateEnd, domain.ToDMY),
+		Reason:         entry.Reason,
+		Version:        entry.Version,
+	}
+}
+
+func (m *AvailabilityMapper) ToResponseList(entries []*domain.EmployeeAvailability) []*AvailabilityResponseDTO {
+	result := make([]*AvailabilityResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = m.ToResponse(entry)
+	}
+	return result
+}
+
+// optional возвращает nil вместо пустой строки
+func optional(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	v := *value
+	return &v
+}
+
+// parseOptional разбирает необязательное значение; nil или "" - nil
+func parseOptional(value *string, parse func(string) time.Time) *time.Time {
+	if value == nil || *value == "" {
+		return nil
+	}
+	t := parse(*value)
+	return &t
+}
+
+func formatOptional(value *time.Time, format func(time.Time) string) *string {
+	if value == nil {
+		return nil
+	}
+	s := format(*value)
+	return &s
+}
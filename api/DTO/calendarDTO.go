@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"GO_Music/domain"
+)
+
+// CalendarCreateDTO для создания записи учебного календаря; без date_end запись занимает один день
+type CalendarCreateDTO struct {
+	Kind      string  `json:"kind" validate:"required,oneof=term holiday swap"`
+	Name      string  `json:"name" validate:"required,min=1,max=255"`
+	DateStart string  `json:"date_start" validate:"required,datetime=02.01.2006"`           // Формат "DD.MM.YYYY"
+	DateEnd   *string `json:"date_end,omitempty" validate:"omitempty,datetime=02.01.2006"`  // Формат "DD.MM.YYYY"
+	WorkedAs  *string `json:"worked_as,omitempty" validate:"omitempty,datetime=02.01.2006"` // Только для swap: дата, чьи занятия идут в date_start
+}
+
+// CalendarUpdateDTO для обновления записи учебного календаря
+type CalendarUpdateDTO struct {
+	Kind      *string `json:"kind,omitempty" validate:"omitempty,oneof=term holiday swap"`
+	Name      *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	DateStart *string `json:"date_start,omitempty" validate:"omitempty,datetime=02.01.2006"` // Формат "DD.MM.YYYY"
+	DateEnd   *string `json:"date_end,omitempty" validate:"omitempty,datetime=02.01.2006"`   // Формат "DD.MM.YYYY"
+	WorkedAs  *string `json:"worked_as,omitempty"`                                           // "" - убрать дату переноса
+}
+
+// CalendarResponseDTO для ответа API
+type CalendarResponseDTO struct {
+	CalendarID int     `json:"calendar_id"`
+	Kind       string  `json:"kind"`
+	Name       string  `json:"name"`
+	DateStart  string  `json:"date_start"`
+	DateEnd    string  `json:"date_end"`
+	WorkedAs   *string `json:"worked_as,omitempty"`
+	SourceUID  *string `json:"source_uid,omitempty"`
+	Version    int     `json:"version"`
+}
+
+// CalendarChangeResponseDTO запись календаря вместе с занятиями, которые она отменяет или переносит
+type CalendarChangeResponseDTO struct {
+	Entry    *CalendarResponseDTO     `json:"entry"`
+	Affected []*OccurrenceResponseDTO `json:"affected"`
+}
+
+// CalendarImportResponseDTO итог импорта календаря
+type CalendarImportResponseDTO struct {
+	Created  []*CalendarResponseDTO   `json:"created"`
+	Updated  []*CalendarResponseDTO   `json:"updated"`
+	Affected []*OccurrenceResponseDTO `json:"affected"`
+}
+
+// CalendarMapper реализует маппинг для учебного календаря
+type CalendarMapper struct {
+	occurrences *ScheduleMapper
+}
+
+func NewCalendarMapper() *CalendarMapper {
+	return &CalendarMapper{occurrences: NewScheduleMapper()}
+}
+
+func (m *CalendarMapper) ToDomain(dto *CalendarCreateDTO) *domain.CalendarEntry {
+	entry := &domain.CalendarEntry{
+		Kind:      dto.Kind,
+		Name:      dto.Name,
+		DateStart: domain.ParseDMY(dto.DateStart),
+	}
+	entry.DateEnd = entry.DateStart
+	if dto.DateEnd != nil {
+		entry.DateEnd = domain.ParseDMY(*dto.DateEnd)
+	}
+	if dto.WorkedAs != nil {
+		workedAs := domain.ParseDMY(*dto.WorkedAs)
+		entry.WorkedAs = &workedAs
+	}
+	return entry
+}
+
+func (m *CalendarMapper) UpdateDomain(entry *domain.CalendarEntry, dto *CalendarUpdateDTO) {
+	if dto.Kind != nil {
+		entry.Kind = *dto.Kind
+	}
+	if dto.Name != nil {
+		entry.Name = *dto.Name
+	}
+	if dto.DateStart != nil {
+		entry.DateStart = domain.ParseDMY(*dto.DateStart)
+	}
+	if dto.DateEnd != nil {
+		entry.DateEnd = domain.ParseDMY(*dto.DateEnd)
+	}
+	if dto.WorkedAs != nil {
+		if *dto.WorkedAs == "" {
+			entry.WorkedAs = nil
+		} else {
+			workedAs := domain.ParseDMY(*dto.WorkedAs)
+			entry.WorkedAs = &workedAs
+		}
+	}
+}
+
+func (m *CalendarMapper) ToResponse(entry *domain.CalendarEntry) *CalendarResponseDTO {
+	response := &CalendarResponseDTO{
+		CalendarID: entry.CalendarID,
+		Kind:       entry.Kind,
+		Name:       entry.Name,
+		DateStart:  domain.ToDMY(entry.DateStart),
+		DateEnd:    domain.ToDMY(entry.DateEnd),
+		SourceUID:  entry.SourceUID,
+		Version:    entry.Version,
+	}
+	if entry.WorkedAs != nil {
+		workedAs := domain.ToDMY(*entry.WorkedAs)
+		response.WorkedAs = &workedAs
+	}
+	return response
+}
+
+func (m *CalendarMapper) ToResponseList(entries []*domain.CalendarEntry) []*CalendarResponseDTO {
+	result := make([]*CalendarResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = m.ToResponse(entry)
+	}
+	return result
+}
+
+// ToChangeResponse преобразует запись и затронутые ею занятия в ответ API
+func (m *CalendarMapper) ToChangeResponse(entry *domain.CalendarEntry, affected []domain.Occurrence) *CalendarChangeResponseDTO {
+	return &CalendarChangeResponseDTO{
+		Entry:    m.ToResponse(entry),
+		Affected: m.occurrences.ToOccurrenceResponseList(affected),
+	}
+}
+
+// ToImportResponse преобразует итог импорта в ответ API
+func (m *CalendarMapper) ToImportResponse(created, updated []*domain.CalendarEntry, affected []domain.Occurrence) *CalendarImportResponseDTO {
+	return &CalendarImportResponseDTO{
+		Created:  m.ToResponseList(created),
+		Updated:  m.ToResponseList(updated),
+		Affected: m.occurrences.ToOccurrenceResponseList(affected),
+	}
+}
@@ -18,9 +18,11 @@ type ProgrammDistributionUpdateDTO struct {
 
 // ProgrammDistributionResponseDTO для ответа API
 type ProgrammDistributionResponseDTO struct {
-	ProgrammDistrID int `json:"programm_distr_id"`
-	MusprogrammID   int `json:"musprogramm_id"`
-	SubjectID       int `json:"subject_id"`
+	ProgrammDistrID int     `json:"programm_distr_id"`
+	MusprogrammID   int     `json:"musprogramm_id"`
+	SubjectID       int     `json:"subject_id"`
+	Version         int     `json:"version"`
+	DeletedAt       *string `json:"deleted_at,omitempty"`
 }
 
 // ProgrammDistributionMapper реализует маппинг для распределений программ
@@ -51,6 +53,8 @@ func (m *ProgrammDistributionMapper) ToResponse(distribution *domain.ProgrammDis
 		ProgrammDistrID: distribution.ProgrammDistrID,
 		MusprogrammID:   distribution.MusprogrammID,
 		SubjectID:       distribution.SubjectID,
+		Version:         distribution.Version,
+		DeletedAt:       domain.ToDateTimePtr(distribution.DeletedAt),
 	}
 }
 
@@ -18,9 +18,11 @@ type SubjectDistributionUpdateDTO struct {
 
 // SubjectDistributionResponseDTO для ответа API
 type SubjectDistributionResponseDTO struct {
-	SubjectDistrID int `json:"subject_distr_id"`
-	EmployeeID     int `json:"employee_id"`
-	SubjectID      int `json:"subject_id"`
+	SubjectDistrID int     `json:"subject_distr_id"`
+	EmployeeID     int     `json:"employee_id"`
+	SubjectID      int     `json:"subject_id"`
+	Version        int     `json:"version"`
+	DeletedAt      *string `json:"deleted_at,omitempty"`
 }
 
 // SubjectDistributionMapper реализует маппинг для распределений предметов
@@ -51,6 +53,8 @@ func (m *SubjectDistributionMapper) ToResponse(distribution *domain.SubjectDistr
 		SubjectDistrID: distribution.SubjectDistrID,
 		EmployeeID:     distribution.EmployeeID,
 		SubjectID:      distribution.SubjectID,
+		Version:        distribution.Version,
+		DeletedAt:      domain.ToDateTimePtr(distribution.DeletedAt),
 	}
 }
 
@@ -40,6 +40,8 @@ type EmployeeResponseDTO struct {
 	PhoneNumber    string  `json:"phone_number"`
 	Job            string  `json:"job"`
 	WorkExperience int     `json:"work_experience"`
+	Version        int     `json:"version"`
+	DeletedAt      *string `json:"deleted_at,omitempty"`
 }
 
 // EmployeeMapper реализует маппинг для сотрудников
@@ -100,6 +102,8 @@ func (m *EmployeeMapper) ToResponse(employee *domain.Employee) *EmployeeResponse
 		PhoneNumber:    employee.PhoneNumber,
 		Job:            employee.Job,
 		WorkExperience: employee.WorkExperience,
+		Version:        employee.Version,
+		DeletedAt:      domain.ToDateTimePtr(employee.DeletedAt),
 	}
 }
 
@@ -6,27 +6,30 @@ import (
 
 // StudyGroupCreateDTO для создания учебной группы
 type StudyGroupCreateDTO struct {
-	MusProgrammID    int    `json:"musprogramm_id" validate:"required"`
-	GroupName        string `json:"group_name" validate:"required,min=1,max=100"`
-	StudyYear        int    `json:"study_year" validate:"required"`
-	NumberOfStudents int    `json:"number_of_students" validate:"required"`
+	MusProgrammID int    `json:"musprogramm_id" validate:"required"`
+	GroupName     string `json:"group_name" validate:"required,min=1,max=100"`
+	StudyYear     int    `json:"study_year" validate:"required"`
+	MaxStudents   *int   `json:"max_students,omitempty" validate:"omitempty,min=1"` // без поля - группа без ограничения
 }
 
 // StudyGroupUpdateDTO для обновления учебной группы
 type StudyGroupUpdateDTO struct {
-	MusProgrammID    *int    `json:"musprogramm_id,omitempty" validate:"omitempty"`
-	GroupName        *string `json:"group_name,omitempty" validate:"omitempty,min=1,max=100"`
-	StudyYear        *int    `json:"study_year,omitempty" validate:"omitempty"`
-	NumberOfStudents *int    `json:"number_of_students,omitempty" validate:"omitempty"`
+	MusProgrammID *int    `json:"musprogramm_id,omitempty" validate:"omitempty"`
+	GroupName     *string `json:"group_name,omitempty" validate:"omitempty,min=1,max=100"`
+	StudyYear     *int    `json:"study_year,omitempty" validate:"omitempty"`
+	MaxStudents   *int    `json:"max_students,omitempty" validate:"omitempty,min=0"` // 0 снимает ограничение
 }
 
-// StudyGroupResponseDTO для ответа API
+// StudyGroupResponseDTO для ответа API; number_of_students только для чтения - его ведет сервер
 type StudyGroupResponseDTO struct {
-	GroupID          int    `json:"group_id"`
-	MusProgrammID    int    `json:"musprogramm_id"`
-	GroupName        string `json:"group_name"`
-	StudyYear        int    `json:"study_year"`
-	NumberOfStudents int    `json:"number_of_students"`
+	GroupID          int     `json:"group_id"`
+	MusProgrammID    int     `json:"musprogramm_id"`
+	GroupName        string  `json:"group_name"`
+	StudyYear        int     `json:"study_year"`
+	NumberOfStudents int     `json:"number_of_students"`
+	MaxStudents      *int    `json:"max_students,omitempty"`
+	Version          int     `json:"version"`
+	DeletedAt        *string `json:"deleted_at,omitempty"`
 }
 
 // StudyGroupMapper реализует маппинг для учебных групп
@@ -38,10 +41,10 @@ func NewStudyGroupMapper() *StudyGroupMapper {
 
 func (m *StudyGroupMapper) ToDomain(dto *StudyGroupCreateDTO) *domain.StudyGroup {
 	return &domain.StudyGroup{
-		MusProgrammID:    dto.MusProgrammID,
-		GroupName:        dto.GroupName,
-		StudyYear:        dto.StudyYear,
-		NumberOfStudents: dto.NumberOfStudents,
+		MusProgrammID: dto.MusProgrammID,
+		GroupName:     dto.GroupName,
+		StudyYear:     dto.StudyYear,
+		MaxStudents:   dto.MaxStudents,
 	}
 }
 
@@ -55,8 +58,11 @@ func (m *StudyGroupMapper) UpdateDomain(group *domain.StudyGroup, dto *StudyGrou
 	if dto.StudyYear != nil {
 		group.StudyYear = *dto.StudyYear
 	}
-	if dto.NumberOfStudents != nil {
-		group.NumberOfStudents = *dto.NumberOfStudents
+	if dto.MaxStudents != nil {
+		group.MaxStudents = dto.MaxStudents
+		if *dto.MaxStudents == 0 {
+			group.MaxStudents = nil
+		}
 	}
 }
 
@@ -67,6 +73,9 @@ func (m *StudyGroupMapper) ToResponse(group *domain.StudyGroup) *StudyGroupRespo
 		GroupName:        group.GroupName,
 		StudyYear:        group.StudyYear,
 		NumberOfStudents: group.NumberOfStudents,
+		MaxStudents:      group.MaxStudents,
+		Version:          group.Version,
+		DeletedAt:        domain.ToDateTimePtr(group.DeletedAt),
 	}
 }
 
@@ -22,11 +22,13 @@ type InstrumentUpdateDTO struct {
 
 // InstrumentResponseDTO для ответа API
 type InstrumentResponseDTO struct {
-	InstrumentID int    `json:"instrument_id"`
-	AudienceID   int    `json:"audience_id"`
-	Name         string `json:"name"`
-	InstrType    string `json:"instr_type"`
-	Condition    string `json:"condition"`
+	InstrumentID int     `json:"instrument_id"`
+	AudienceID   int     `json:"audience_id"`
+	Name         string  `json:"name"`
+	InstrType    string  `json:"instr_type"`
+	Condition    string  `json:"condition"`
+	Version      int     `json:"version"`
+	DeletedAt    *string `json:"deleted_at,omitempty"`
 }
 
 // InstrumentMapper реализует маппинг для инструментов
@@ -67,6 +69,8 @@ func (m *InstrumentMapper) ToResponse(instrument *domain.Instrument) *Instrument
 		Name:         instrument.Name,
 		InstrType:    instrument.InstrType,
 		Condition:    instrument.Condition,
+		Version:      instrument.Version,
+		DeletedAt:    domain.ToDateTimePtr(instrument.DeletedAt),
 	}
 }
 
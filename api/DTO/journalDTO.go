@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"GO_Music/domain"
+)
+
+// JournalGradeDTO оценка ученика в журнале; без assessment_note_id - новая оценка
+type JournalGradeDTO struct {
+	AssessmentNoteID int    `json:"assessment_note_id,omitempty" validate:"omitempty,min=1"`
+	TaskType         string `json:"task_type" validate:"required,min=1,max=70"`
+	Grade            int    `json:"grade" validate:"required"`
+}
+
+// JournalMarkDTO строка журнала: присутствие и полный список оценок ученика за занятие
+type JournalMarkDTO struct {
+	StudentID    int               `json:"student_id" validate:"required"`
+	PresenceMark bool              `json:"presence_mark"`
+	Grades       []JournalGradeDTO `json:"grades" validate:"dive"` // Оценки ученика, которых нет в списке, удаляются
+}
+
+// JournalUpdateDTO для сохранения журнала занятия; ученики без строки в entries не меняются
+type JournalUpdateDTO struct {
+	Topic   *string          `json:"topic,omitempty" validate:"omitempty,max=255"`
+	Notes   *string          `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	Entries []JournalMarkDTO `json:"entries" validate:"dive"`
+}
+
+// JournalEntryResponseDTO строка журнала в ответе API
+type JournalEntryResponseDTO struct {
+	StudentID        int                             `json:"student_id"`
+	Surname          string                          `json:"surname"`
+	Name             string                          `json:"name"`
+	PresenceMark     *bool                           `json:"presence_mark"` // null - присутствие не отмечено
+	AttendanceNoteID *int                            `json:"attendance_note_id,omitempty"`
+	Grades           []*StudentAssessmentResponseDTO `json:"grades"`
+}
+
+// JournalResponseDTO журнал занятия в ответе API
+type JournalResponseDTO struct {
+	LessonID   int                          `json:"lesson_id"`
+	LessonName string                       `json:"lesson_name"`
+	Occurrence *LessonOccurrenceResponseDTO `json:"occurrence"`
+	Entries    []*JournalEntryResponseDTO   `json:"entries"`
+}
+
+// JournalMapper реализует маппинг для журнала занятия
+type JournalMapper struct {
+	occurrences *LessonOccurrenceMapper
+	assessments *AssessmentMapper
+}
+
+func NewJournalMapper() *JournalMapper {
+	return &JournalMapper{
+		occurrences: NewLessonOccurrenceMapper(),
+		assessments: NewAssessmentMapper(),
+	}
+}
+
+func (m *JournalMapper) ToResponse(journal *domain.Journal) *JournalResponseDTO {
+	entries := make([]*JournalEntryResponseDTO, len(journal.Entries))
+	for i, entry := range journal.Entries {
+		entries[i] = &JournalEntryResponseDTO{
+			StudentID: entry.Student.StudentID,
+			Surname:   entry.Student.Surname,
+			Name:      entry.Student.Name,
+			Grades:    m.assessments.ToResponseList(entry.Grades),
+		}
+		if entry.Attendance != nil {
+			entries[i].PresenceMark = &entry.Attendance.PresenceMark
+			entries[i].AttendanceNoteID = &entry.Attendance.AttendanceNoteID
+		}
+	}
+	return &JournalResponseDTO{
+		LessonID:   journal.Lesson.LessonID,
+		LessonName: journal.Lesson.LessonName,
+		Occurrence: m.occurrences.ToResponse(journal.Occurrence),
+		Entries:    entries,
+	}
+}
@@ -26,13 +26,21 @@ type LessonUpdateDTO struct {
 
 // LessonResponseDTO для ответа API
 type LessonResponseDTO struct {
-	LessonID   int    `json:"lesson_id"`
-	AudienceID *int   `json:"audience_id,omitempty"`
-	EmployeeID int    `json:"employee_id"`
-	GroupID    int    `json:"group_id"`
-	StudentID  *int   `json:"student_id,omitempty"`
-	LessonName string `json:"lesson_name"`
-	SubjectID  int    `json:"subject_id"`
+	LessonID   int     `json:"lesson_id"`
+	AudienceID *int    `json:"audience_id,omitempty"`
+	EmployeeID int     `json:"employee_id"`
+	GroupID    int     `json:"group_id"`
+	StudentID  *int    `json:"student_id,omitempty"`
+	LessonName string  `json:"lesson_name"`
+	SubjectID  int     `json:"subject_id"`
+	Version    int     `json:"version"`
+	DeletedAt  *string `json:"deleted_at,omitempty"`
+
+	// Связанные сущности, только при ?include=
+	Employee *EmployeeResponseDTO   `json:"employee,omitempty"`
+	Group    *StudyGroupResponseDTO `json:"group,omitempty"`
+	Subject  *SubjectResponseDTO    `json:"subject,omitempty"`
+	Audience *AudienceResponseDTO   `json:"audience,omitempty"`
 }
 
 // LessonMapper реализует маппинг для занятий
@@ -75,7 +83,7 @@ func (m *LessonMapper) UpdateDomain(lesson *domain.Lesson, dto *LessonUpdateDTO)
 }
 
 func (m *LessonMapper) ToResponse(lesson *domain.Lesson) *LessonResponseDTO {
-	return &LessonResponseDTO{
+	response := &LessonResponseDTO{
 		LessonID:   lesson.LessonID,
 		AudienceID: lesson.AudienceID,
 		EmployeeID: lesson.EmployeeID,
@@ -83,7 +91,22 @@ func (m *LessonMapper) ToResponse(lesson *domain.Lesson) *LessonResponseDTO {
 		StudentID:  lesson.StudentID,
 		LessonName: lesson.LessonName,
 		SubjectID:  lesson.SubjectID,
+		Version:    lesson.Version,
+		DeletedAt:  domain.ToDateTimePtr(lesson.DeletedAt),
+	}
+	if lesson.Employee != nil {
+		response.Employee = NewEmployeeMapper().ToResponse(lesson.Employee)
+	}
+	if lesson.Group != nil {
+		response.Group = NewStudyGroupMapper().ToResponse(lesson.Group)
+	}
+	if lesson.Subject != nil {
+		response.Subject = NewSubjectMapper().ToResponse(lesson.Subject)
+	}
+	if lesson.Audience != nil {
+		response.Audience = NewAudienceMapper().ToResponse(lesson.Audience)
 	}
+	return response
 }
 
 func (m *LessonMapper) ToResponseList(lessons []*domain.Lesson) []*LessonResponseDTO {
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"GO_Music/domain"
+)
+
+// LessonOccurrenceCreateDTO для получения (создания) занятия серии в дату по правилу
+type LessonOccurrenceCreateDTO struct {
+	ScheduleID int    `json:"schedule_id" validate:"required"`
+	Date       string `json:"date" validate:"required,datetime=02.01.2006"` // Формат "DD.MM.YYYY"; дата занятия по правилу
+}
+
+// [RU] LessonOccurrenceUpdateDTO для отметки занятия: held - проведено, planned - снова следует за расписанием.
+// Отмена и перенос делаются исключениями расписания <--->
+// [ENG] LessonOccurrenceUpdateDTO marks an occurrence: held - it took place, planned - it follows the schedule again.
+// Cancellations and moves are made with schedule exceptions
+type LessonOccurrenceUpdateDTO struct {
+	Status     *string `json:"status,omitempty" validate:"omitempty,oneof=planned held"`
+	EmployeeID *int    `json:"employee_id,omitempty" validate:"omitempty"` // Фактический преподаватель
+	AudienceID *int    `json:"audience_id,omitempty" validate:"omitempty"` // Фактическая аудитория
+	Topic      *string `json:"topic,omitempty" validate:"omitempty,max=255"`
+	Notes      *string `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// LessonOccurrenceResponseDTO для ответа API
+type LessonOccurrenceResponseDTO struct {
+	OccurrenceID   int     `json:"occurrence_id"`
+	ScheduleID     int     `json:"schedule_id"`
+	LessonID       int     `json:"lesson_id"`
+	OccurrenceDate string  `json:"occurrence_date"` // Формат "DD.MM.YYYY"
+	Date           string  `json:"date"`            // Формат "DD.MM.YYYY"
+	TimeBegin      string  `json:"time_begin"`      // Формат "15:04"
+	TimeEnd        string  `json:"time_end"`        // Формат "15:04"
+	Status         string  `json:"status"`
+	EmployeeID     int     `json:"employee_id"`
+	AudienceID     *int    `json:"audience_id,omitempty"`
+	Topic          *string `json:"topic,omitempty"`
+	Notes          *string `json:"notes,omitempty"`
+	Version        int     `json:"version"`
+}
+
+// LessonSessionResponseDTO журнал одного занятия
+type LessonSessionResponseDTO struct {
+	Occurrence  *LessonOccurrenceResponseDTO    `json:"occurrence"`
+	Attendance  []*StudentAttendanceResponseDTO `json:"attendance"`
+	Assessments []*StudentAssessmentResponseDTO `json:"assessments"`
+}
+
+// LessonOccurrenceMapper реализует маппинг для проведенных занятий
+type LessonOccurrenceMapper struct {
+	attendance  *StudentAttendanceMapper
+	assessments *AssessmentMapper
+}
+
+func NewLessonOccurrenceMapper() *LessonOccurrenceMapper {
+	return &LessonOccurrenceMapper{
+		attendance:  NewStudentAttendanceMapper(),
+		assessments: NewAssessmentMapper(),
+	}
+}
+
+func (m *LessonOccurrenceMapper) UpdateDomain(occurrence *domain.LessonOccurrence, dto *LessonOccurrenceUpdateDTO) {
+	if dto.Status != nil {
+		occurrence.Status = *dto.Status
+	}
+	if dto.EmployeeID != nil {
+		occurrence.EmployeeID = *dto.EmployeeID
+	}
+	if dto.AudienceID != nil {
+		occurrence.AudienceID = dto.AudienceID
+	}
+	if dto.Topic != nil {
+		occurrence.Topic = dto.Topic
+	}
+	if dto.Notes != nil {
+		occurrence.Notes = dto.Notes
+	}
+}
+
+func (m *LessonOccurrenceMapper) ToResponse(occurrence *domain.LessonOccurrence) *LessonOccurrenceResponseDTO {
+	return &LessonOccurrenceResponseDTO{
+		OccurrenceID:   occurrence.OccurrenceID,
+		ScheduleID:     occurrence.ScheduleID,
+		LessonID:       occurrence.LessonID,
+		OccurrenceDate: domain.ToDMY(occurrence.OccurrenceDate),
+		Date:           domain.ToDMY(occurrence.Date),
+		TimeBegin:      domain.ToTimeHM(occurrence.TimeBegin),
+		TimeEnd:        domain.ToTimeHM(occurrence.TimeEnd),
+		Status:         occurrence.Status,
+		EmployeeID:     occurrence.EmployeeID,
+		AudienceID:     occurrence.AudienceID,
+		Topic:          occurrence.Topic,
+		Notes:          occurrence.Notes,
+		Version:        occurrence.Version,
+	}
+}
+
+// ToSessionResponse преобразует журнал занятия в ответ API
+func (m *LessonOccurrenceMapper) ToSessionResponse(session *domain.LessonSession) *LessonSessionResponseDTO {
+	return &LessonSessionResponseDTO{
+		Occurrence:  m.ToResponse(session.Occurrence),
+		Attendance:  m.attendance.ToResponseListWithFormattedDate(session.Attendance),
+		Assessments: m.assessments.ToResponseList(session.Assessments),
+	}
+}
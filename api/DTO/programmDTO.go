@@ -36,6 +36,8 @@ type ProgrammResponseDTO struct {
 	Description            *string `json:"description,omitempty"`
 	StudyLoad              int     `json:"study_load"`
 	FinalCertificationForm string  `json:"final_certification_form"`
+	Version                int     `json:"version"`
+	DeletedAt              *string `json:"deleted_at,omitempty"`
 }
 
 // ProgrammMapper реализует маппинг для музыкальных программ
@@ -91,6 +93,8 @@ func (m *ProgrammMapper) ToResponse(programm *domain.Programm) *ProgrammResponse
 		Description:            programm.Description,
 		StudyLoad:              programm.StudyLoad,
 		FinalCertificationForm: programm.FinalCertificationForm,
+		Version:                programm.Version,
+		DeletedAt:              domain.ToDateTimePtr(programm.DeletedAt),
 	}
 }
 
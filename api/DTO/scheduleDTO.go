@@ -2,39 +2,50 @@ package dto
 
 import (
 	"GO_Music/domain"
+	"strings"
+	"time"
 )
 
 // ScheduleCreateDTO DTO для создания расписания
+// С rrule day_week выводится из правила, а schd_date_end нужна только правилу без UNTIL и COUNT
 type ScheduleCreateDTO struct {
-	LessonID      int    `json:"lesson_id" validate:"required"`
-	DayWeek       string `json:"day_week" validate:"required,min=1,max=20"`
-	TimeBegin     string `json:"time_begin" validate:"required"`      // Формат "15:04"
-	TimeEnd       string `json:"time_end" validate:"required"`        // Формат "15:04"
-	SchdDateStart string `json:"schd_date_start" validate:"required"` // Формат "DD.MM.YYYY"
-	SchdDateEnd   string `json:"schd_date_end" validate:"required"`   // Формат "DD.MM.YYYY"
+	LessonID      int      `json:"lesson_id" validate:"required"`
+	DayWeek       string   `json:"day_week" validate:"required_without=RRule,omitempty,max=100"`
+	TimeBegin     string   `json:"time_begin" validate:"required"`                      // Формат "15:04"
+	TimeEnd       string   `json:"time_end" validate:"required"`                        // Формат "15:04"
+	SchdDateStart string   `json:"schd_date_start" validate:"required"`                 // Формат "DD.MM.YYYY"
+	SchdDateEnd   string   `json:"schd_date_end" validate:"required_without=RRule"`     // Формат "DD.MM.YYYY"
+	RRule         *string  `json:"rrule,omitempty"`                                     // Например "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10"
+	ExDate        []string `json:"exdate,omitempty" validate:"omitempty,dive,required"` // Исключенные даты "DD.MM.YYYY"
 }
 
 // ScheduleUpdateDTO DTO для обновления расписания
 type ScheduleUpdateDTO struct {
-	LessonID      *int    `json:"lesson_id,omitempty"`
-	DayWeek       *string `json:"day_week,omitempty"`
-	TimeBegin     *string `json:"time_begin,omitempty"`      // Формат "15:04"
-	TimeEnd       *string `json:"time_end,omitempty"`        // Формат "15:04"
-	SchdDateStart *string `json:"schd_date_start,omitempty"` // Формат "DD.MM.YYYY"
-	SchdDateEnd   *string `json:"schd_date_end,omitempty"`   // Формат "DD.MM.YYYY"
+	LessonID      *int     `json:"lesson_id,omitempty"`
+	DayWeek       *string  `json:"day_week,omitempty"`
+	TimeBegin     *string  `json:"time_begin,omitempty"`      // Формат "15:04"
+	TimeEnd       *string  `json:"time_end,omitempty"`        // Формат "15:04"
+	SchdDateStart *string  `json:"schd_date_start,omitempty"` // Формат "DD.MM.YYYY"
+	SchdDateEnd   *string  `json:"schd_date_end,omitempty"`   // Формат "DD.MM.YYYY"
+	RRule         *string  `json:"rrule,omitempty"`           // "" - удалить правило
+	ExDate        []string `json:"exdate"`                    // nil - без изменений, [] - очистить
 }
 
 // ScheduleResponseDTO DTO для ответа с расписанием
 type ScheduleResponseDTO struct {
-	ScheduleID    int    `json:"schedule_id"`
-	LessonID      int    `json:"lesson_id"`
-	DayWeek       string `json:"day_week"`
-	TimeBegin     string `json:"time_begin"`           // Формат "15:04"
-	TimeEnd       string `json:"time_end"`             // Формат "15:04"
-	SchdDateStart string `json:"schd_date_start"`      // Формат "DD.MM.YYYY"
-	SchdDateEnd   string `json:"schd_date_end"`        // Формат "DD.MM.YYYY"
-	CreatedAt     string `json:"created_at,omitempty"` // Формат "DD.MM.YYYY HH:MM:SS"
-	UpdatedAt     string `json:"updated_at,omitempty"` // Формат "DD.MM.YYYY HH:MM:SS"
+	ScheduleID    int      `json:"schedule_id"`
+	LessonID      int      `json:"lesson_id"`
+	DayWeek       string   `json:"day_week"`
+	TimeBegin     string   `json:"time_begin"`      // Формат "15:04"
+	TimeEnd       string   `json:"time_end"`        // Формат "15:04"
+	SchdDateStart string   `json:"schd_date_start"` // Формат "DD.MM.YYYY"
+	SchdDateEnd   string   `json:"schd_date_end"`   // Формат "DD.MM.YYYY"
+	RRule         *string  `json:"rrule,omitempty"`
+	ExDate        []string `json:"exdate,omitempty"`     // Формат "DD.MM.YYYY"
+	CreatedAt     string   `json:"created_at,omitempty"` // Формат "DD.MM.YYYY HH:MM:SS"
+	UpdatedAt     string   `json:"updated_at,omitempty"` // Формат "DD.MM.YYYY HH:MM:SS"
+	Version       int      `json:"version"`
+	DeletedAt     *string  `json:"deleted_at,omitempty"`
 }
 
 // ScheduleMapper маппер для расписания
@@ -47,14 +58,19 @@ func NewScheduleMapper() *ScheduleMapper {
 
 // ToDomain преобразует CreateDTO в доменную модель
 func (m *ScheduleMapper) ToDomain(dto *ScheduleCreateDTO) *domain.Schedule {
-	return &domain.Schedule{
+	schedule := &domain.Schedule{
 		LessonID:      dto.LessonID,
 		DayWeek:       dto.DayWeek,
 		TimeBegin:     domain.ParseTimeHM(dto.TimeBegin),
 		TimeEnd:       domain.ParseTimeHM(dto.TimeEnd),
 		SchdDateStart: domain.ParseDMY(dto.SchdDateStart),
 		SchdDateEnd:   domain.ParseDMY(dto.SchdDateEnd),
+		RRule:         dto.RRule,
+		ExDate:        toExDate(dto.ExDate),
 	}
+	// Выводит day_week и schd_date_end до проверки менеджером; ошибку правила вернет его Validate
+	_ = schedule.Normalize()
+	return schedule
 }
 
 // UpdateDomain обновляет доменную модель из UpdateDTO
@@ -77,6 +93,13 @@ func (m *ScheduleMapper) UpdateDomain(schedule *domain.Schedule, dto *ScheduleUp
 	if dto.SchdDateEnd != nil {
 		schedule.SchdDateEnd = domain.ParseDMY(*dto.SchdDateEnd)
 	}
+	if dto.RRule != nil {
+		schedule.RRule = dto.RRule
+	}
+	if dto.ExDate != nil {
+		schedule.ExDate = toExDate(dto.ExDate)
+	}
+	_ = schedule.Normalize()
 }
 
 // ToResponse преобразует доменную модель в ResponseDTO
@@ -89,6 +112,10 @@ func (m *ScheduleMapper) ToResponse(schedule *domain.Schedule) *ScheduleResponse
 		TimeEnd:       domain.ToTimeHM(schedule.TimeEnd),
 		SchdDateStart: domain.ToDMY(schedule.SchdDateStart),
 		SchdDateEnd:   domain.ToDMY(schedule.SchdDateEnd),
+		RRule:         schedule.RRule,
+		ExDate:        fromExDate(schedule.ExDate),
+		Version:       schedule.Version,
+		DeletedAt:     domain.ToDateTimePtr(schedule.DeletedAt),
 	}
 }
 
@@ -100,3 +127,146 @@ func (m *ScheduleMapper) ToResponseList(schedules []*domain.Schedule) []*Schedul
 	}
 	return response
 }
+
+// toExDate преобразует даты "DD.MM.YYYY" в значение EXDATE; нераспознанная дата остается как есть,
+// чтобы проверка правила отклонила запись
+func toExDate(dates []string) *string {
+	if len(dates) == 0 {
+		return nil
+	}
+	parts := make([]string, len(dates))
+	for i, date := range dates {
+		parsed, err := time.Parse("02.01.2006", date)
+		if err != nil {
+			parts[i] = date
+			continue
+		}
+		parts[i] = domain.FormatRuleDate(parsed)
+	}
+	value := strings.Join(parts, ",")
+	return &value
+}
+
+// fromExDate преобразует значение EXDATE в даты "DD.MM.YYYY"
+func fromExDate(value *string) []string {
+	if value == nil {
+		return nil
+	}
+	parsed, err := domain.ParseExDates(*value)
+	if err != nil {
+		return nil
+	}
+	dates := make([]string, len(parsed))
+	for i, date := range parsed {
+		dates[i] = domain.ToDMY(date)
+	}
+	return dates
+}
+
+// OccurrenceEditDTO DTO для переноса одного занятия серии; пустое поле - значение из правила
+type OccurrenceEditDTO struct {
+	NewDate   *string `json:"new_date,omitempty"`   // Формат "DD.MM.YYYY"
+	TimeBegin *string `json:"time_begin,omitempty"` // Формат "15:04"
+	TimeEnd   *string `json:"time_end,omitempty"`   // Формат "15:04"
+	Reason    *string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// ToDomain преобразует OccurrenceEditDTO в изменение занятия для Managers.EditOccurrence
+func (dto *OccurrenceEditDTO) ToDomain() domain.ScheduleException {
+	var patch domain.ScheduleException
+	if dto.NewDate != nil {
+		date := domain.ParseDMY(*dto.NewDate)
+		patch.NewDate = &date
+	}
+	if dto.TimeBegin != nil {
+		begin := domain.ParseTimeHM(*dto.TimeBegin)
+		patch.TimeBegin = &begin
+	}
+	if dto.TimeEnd != nil {
+		end := domain.ParseTimeHM(*dto.TimeEnd)
+		patch.TimeEnd = &end
+	}
+	patch.Reason = dto.Reason
+	return patch
+}
+
+// OccurrenceResponseDTO DTO для ответа с занятием серии
+type OccurrenceResponseDTO struct {
+	ScheduleID   int     `json:"schedule_id"`
+	LessonID     int     `json:"lesson_id"`
+	Date         string  `json:"date"`          // Формат "DD.MM.YYYY"
+	OriginalDate string  `json:"original_date"` // Формат "DD.MM.YYYY"; адрес занятия в /schedules/{id}/occurrences/{date}
+	TimeBegin    string  `json:"time_begin"`    // Формат "15:04"
+	TimeEnd      string  `json:"time_end"`      // Формат "15:04"
+	Cancelled    bool    `json:"cancelled"`
+	Moved        bool    `json:"moved"`   // дата или время изменены исключением
+	Closed       bool    `json:"closed"`  // отменено учебным календарем
+	Swapped      bool    `json:"swapped"` // перенесено календарем на день переноса
+	ExceptionID  *int    `json:"exception_id,omitempty"`
+	Reason       *string `json:"reason,omitempty"`
+}
+
+// ToOccurrenceResponse преобразует занятие серии в ResponseDTO
+func (m *ScheduleMapper) ToOccurrenceResponse(occurrence domain.Occurrence) *OccurrenceResponseDTO {
+	return &OccurrenceResponseDTO{
+		ScheduleID:   occurrence.ScheduleID,
+		LessonID:     occurrence.LessonID,
+		Date:         domain.ToDMY(occurrence.Date),
+		OriginalDate: domain.ToDMY(occurrence.OriginalDate),
+		TimeBegin:    domain.ToTimeHM(occurrence.TimeBegin),
+		TimeEnd:      domain.ToTimeHM(occurrence.TimeEnd),
+		Cancelled:    occurrence.Cancelled,
+		Moved:        occurrence.ExceptionID != nil && !occurrence.Cancelled,
+		Closed:       occurrence.Closed,
+		Swapped:      occurrence.Swapped,
+		ExceptionID:  occurrence.ExceptionID,
+		Reason:       occurrence.Reason,
+	}
+}
+
+// ToOccurrenceResponseList преобразует список занятий в список ResponseDTO
+func (m *ScheduleMapper) ToOccurrenceResponseList(occurrences []domain.Occurrence) []*OccurrenceResponseDTO {
+	response := make([]*OccurrenceResponseDTO, len(occurrences))
+	for i, occurrence := range occurrences {
+		response[i] = m.ToOccurrenceResponse(occurrence)
+	}
+	return response
+}
+
+// ScheduleExceptionResponseDTO DTO для ответа с исключением занятия серии
+type ScheduleExceptionResponseDTO struct {
+	ExceptionID    int     `json:"exception_id"`
+	ScheduleID     int     `json:"schedule_id"`
+	OccurrenceDate string  `json:"occurrence_date"` // Формат "DD.MM.YYYY"
+	Cancelled      bool    `json:"cancelled"`
+	NewDate        *string `json:"new_date,omitempty"`   // Формат "DD.MM.YYYY"
+	TimeBegin      *string `json:"time_begin,omitempty"` // Формат "15:04"
+	TimeEnd        *string `json:"time_end,omitempty"`   // Формат "15:04"
+	Reason         *string `json:"reason,omitempty"`
+	Version        int     `json:"version"`
+}
+
+// ToExceptionResponse преобразует исключение занятия в ResponseDTO
+func (m *ScheduleMapper) ToExceptionResponse(exception *domain.ScheduleException) *ScheduleExceptionResponseDTO {
+	response := &ScheduleExceptionResponseDTO{
+		ExceptionID:    exception.ExceptionID,
+		ScheduleID:     exception.ScheduleID,
+		OccurrenceDate: domain.ToDMY(exception.OccurrenceDate),
+		Cancelled:      exception.Cancelled,
+		Reason:         exception.Reason,
+		Version:        exception.Version,
+	}
+	if exception.NewDate != nil {
+		date := domain.ToDMY(*exception.NewDate)
+		response.NewDate = &date
+	}
+	if exception.TimeBegin != nil {
+		begin := domain.ToTimeHM(*exception.TimeBegin)
+		response.TimeBegin = &begin
+	}
+	if exception.TimeEnd != nil {
+		end := domain.ToTimeHM(*exception.TimeEnd)
+		response.TimeEnd = &end
+	}
+	return response
+}
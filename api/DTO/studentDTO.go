@@ -40,6 +40,12 @@ type StudentResponseDTO struct {
 	PhoneNumber   *string `json:"phone_number,omitempty"`
 	GroupID       int     `json:"group_id"`
 	MusprogrammID int     `json:"musprogramm_id"`
+	Version       int     `json:"version"`
+	DeletedAt     *string `json:"deleted_at,omitempty"`
+
+	// Связанные сущности, только при ?include=
+	Group    *StudyGroupResponseDTO `json:"group,omitempty"`
+	Programm *ProgrammResponseDTO   `json:"programm,omitempty"`
 }
 
 // StudentMapper реализует маппинг для студентов
@@ -90,7 +96,7 @@ func (m *StudentMapper) UpdateDomain(student *domain.Student, dto *StudentUpdate
 }
 
 func (m *StudentMapper) ToResponse(student *domain.Student) *StudentResponseDTO {
-	return &StudentResponseDTO{
+	response := &StudentResponseDTO{
 		StudentID:     student.StudentID,
 		UserID:        student.UserID,
 		Surname:       student.Surname,
@@ -100,7 +106,16 @@ func (m *StudentMapper) ToResponse(student *domain.Student) *StudentResponseDTO
 		PhoneNumber:   student.PhoneNumber,
 		GroupID:       student.GroupID,
 		MusprogrammID: student.MusprogrammID,
+		Version:       student.Version,
+		DeletedAt:     domain.ToDateTimePtr(student.DeletedAt),
+	}
+	if student.Group != nil {
+		response.Group = NewStudyGroupMapper().ToResponse(student.Group)
+	}
+	if student.Programm != nil {
+		response.Programm = NewProgrammMapper().ToResponse(student.Programm)
 	}
+	return response
 }
 
 func (m *StudentMapper) ToResponseList(students []*domain.Student) []*StudentResponseDTO {
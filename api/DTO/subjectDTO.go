@@ -20,10 +20,12 @@ type SubjectUpdateDTO struct {
 
 // SubjectResponseDTO для ответа API
 type SubjectResponseDTO struct {
-	SubjectID   int    `json:"subject_id"`
-	SubjectName string `json:"subject_name"`
-	SubjectType string `json:"subject_type"`
-	ShortDesc   string `json:"short_desc"`
+	SubjectID   int     `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	SubjectType string  `json:"subject_type"`
+	ShortDesc   string  `json:"short_desc"`
+	Version     int     `json:"version"`
+	DeletedAt   *string `json:"deleted_at,omitempty"`
 }
 
 // SubjectMapper реализует маппинг для предметов
@@ -59,6 +61,8 @@ func (m *SubjectMapper) ToResponse(subject *domain.Subject) *SubjectResponseDTO
 		SubjectName: subject.SubjectName,
 		SubjectType: subject.SubjectType,
 		ShortDesc:   subject.ShortDesc,
+		Version:     subject.Version,
+		DeletedAt:   domain.ToDateTimePtr(subject.DeletedAt),
 	}
 }
 
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"GO_Music/domain"
+	"time"
+)
+
+// CoverRequestDTO для отметки занятий, которым нужна замена: lesson_id - одно занятие, employee_id - все занятия преподавателя
+type CoverRequestDTO struct {
+	LessonID   int     `json:"lesson_id,omitempty" validate:"required_without=EmployeeID"`
+	EmployeeID int     `json:"employee_id,omitempty" validate:"required_without=LessonID"`
+	DateFrom   string  `json:"date_from" validate:"required,datetime=02.01.2006"`          // Формат "DD.MM.YYYY"
+	DateTo     *string `json:"date_to,omitempty" validate:"omitempty,datetime=02.01.2006"` // Формат "DD.MM.YYYY"; без него - один день
+	Reason     *string `json:"reason,omitempty" validate:"omitempty,max=255"`              // Например "Больничный"
+}
+
+// Period возвращает период запроса; без date_to - один день date_from
+func (dto *CoverRequestDTO) Period() (time.Time, time.Time) {
+	from := domain.ParseDMY(dto.DateFrom)
+	if dto.DateTo == nil {
+		return from, from
+	}
+	return from, domain.ParseDMY(*dto.DateTo)
+}
+
+// SubstituteAssignDTO для назначения заменяющего преподавателя
+type SubstituteAssignDTO struct {
+	EmployeeID int `json:"employee_id" validate:"required"`
+}
+
+// SubstitutionResponseDTO для ответа API
+type SubstitutionResponseDTO struct {
+	SubstitutionID int     `json:"substitution_id"`
+	ScheduleID     int     `json:"schedule_id"`
+	LessonID       int     `json:"lesson_id"`
+	OccurrenceDate string  `json:"occurrence_date"` // Формат "DD.MM.YYYY"
+	Date           string  `json:"date"`            // Формат "DD.MM.YYYY"
+	TimeBegin      string  `json:"time_begin"`      // Формат "15:04"
+	TimeEnd        string  `json:"time_end"`        // Формат "15:04"
+	EmployeeID     int     `json:"employee_id"`
+	SubstituteID   *int    `json:"substitute_id,omitempty"`
+	Status         string  `json:"status"`
+	Reason         *string `json:"reason,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	AssignedAt     *string `json:"assigned_at,omitempty"`
+	Version        int     `json:"version"`
+}
+
+// SubstitutionReportResponseDTO отчет о заменах преподавателя за период
+type SubstitutionReportResponseDTO struct {
+	EmployeeID     int                        `json:"employee_id"`
+	From           string                     `json:"from"`
+	To             string                     `json:"to"`
+	Covered        []*SubstitutionResponseDTO `json:"covered"`
+	CoveredMinutes int                        `json:"covered_minutes"`
+	Absent         []*SubstitutionResponseDTO `json:"absent"`
+	AbsentMinutes  int                        `json:"absent_minutes"`
+}
+
+// SubstitutionMapper реализует маппинг для замен преподавателей
+type SubstitutionMapper struct{}
+
+func NewSubstitutionMapper() *SubstitutionMapper {
+	return &SubstitutionMapper{}
+}
+
+func (m *SubstitutionMapper) ToResponse(substitution *domain.Substitution) *SubstitutionResponseDTO {
+	return &SubstitutionResponseDTO{
+		SubstitutionID: substitution.SubstitutionID,
+		ScheduleID:     substitution.ScheduleID,
+		LessonID:       substitution.LessonID,
+		OccurrenceDate: domain.ToDMY(substitution.OccurrenceDate),
+		Date:           domain.ToDMY(substitution.Date),
+		TimeBegin:      domain.ToTimeHM(substitution.TimeBegin),
+		TimeEnd:        domain.ToTimeHM(substitution.TimeEnd),
+		EmployeeID:     substitution.EmployeeID,
+		SubstituteID:   substitution.SubstituteID,
+		Status:         substitution.Status,
+		Reason:         substitution.Reason,
+		CreatedAt:      domain.ToDateTime(substitution.CreatedAt),
+		AssignedAt:     domain.ToDateTimePtr(substitution.AssignedAt),
+		Version:        substitution.Version,
+	}
+}
+
+func (m *SubstitutionMapper) ToResponseList(substitutions []*domain.Substitution) []*SubstitutionResponseDTO {
+	result := make([]*SubstitutionResponseDTO, len(substitutions))
+	for i, substitution := range substitutions {
+		result[i] = m.ToResponse(substitution)
+	}
+	return result
+}
+
+// ToReportResponse преобразует отчет о заменах в ответ API
+func (m *SubstitutionMapper) ToReportResponse(report *domain.SubstitutionReport) *SubstitutionReportResponseDTO {
+	return &SubstitutionReportResponseDTO{
+		EmployeeID:     report.EmployeeID,
+		From:           domain.ToDMY(report.From),
+		To:             domain.ToDMY(report.To),
+		Covered:        m.ToResponseList(report.Covered),
+		CoveredMinutes: report.CoveredMinutes,
+		Absent:         m.ToResponseList(report.Absent),
+		AbsentMinutes:  report.AbsentMinutes,
+	}
+}
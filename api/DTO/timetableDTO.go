@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"GO_Music/domain"
+	"fmt"
+	"time"
+)
+
+// TimetableSolveDTO параметры решателя; пустые поля сетки получают значения по умолчанию
+type TimetableSolveDTO struct {
+	DateStart    string   `json:"date_start" validate:"required,datetime=02.01.2006"`         // Формат "DD.MM.YYYY"
+	DateEnd      string   `json:"date_end" validate:"required,datetime=02.01.2006"`           // Формат "DD.MM.YYYY"
+	Days         []string `json:"days,omitempty" validate:"omitempty,dive,required"`          // "Понедельник" или "Monday"; по умолчанию пн - сб
+	DayBegin     string   `json:"day_begin,omitempty" validate:"omitempty,datetime=15:04"`    // Формат "15:04", по умолчанию 08:00
+	DayEnd       string   `json:"day_end,omitempty" validate:"omitempty,datetime=15:04"`      // Формат "15:04", по умолчанию 20:00
+	SlotMinutes  int      `json:"slot_minutes,omitempty" validate:"omitempty,min=10,max=240"` // по умолчанию 45
+	BreakMinutes *int     `json:"break_minutes,omitempty" validate:"omitempty,min=0,max=120"` // по умолчанию 10
+	LessonIDs    []int    `json:"lesson_ids,omitempty" validate:"omitempty,dive,min=1"`       // пусто - все занятия без расписания в периоде
+}
+
+// Weekdays разбирает учебные дни; nil - значение по умолчанию
+func (dto *TimetableSolveDTO) Weekdays() ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range dto.Days {
+		day, ok := domain.ParseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// TimetableSlotDTO час занятия в предложенном расписании
+type TimetableSlotDTO struct {
+	LessonID   int    `json:"lesson_id"`
+	LessonName string `json:"lesson_name"`
+	EmployeeID int    `json:"employee_id"`
+	AudienceID int    `json:"audience_id"`
+	GroupID    int    `json:"group_id"`
+	StudentID  *int   `json:"student_id,omitempty"`
+	DayWeek    string `json:"day_week"`
+	TimeBegin  string `json:"time_begin"` // Формат "15:04"
+	TimeEnd    string `json:"time_end"`   // Формат "15:04"
+}
+
+// TimetableDraftResponseDTO черновик расписания: предложение и невыполненные ограничения
+type TimetableDraftResponseDTO struct {
+	DraftID     int                            `json:"draft_id"`
+	DateStart   string                         `json:"date_start"` // Формат "DD.MM.YYYY"
+	DateEnd     string                         `json:"date_end"`   // Формат "DD.MM.YYYY"
+	Status      string                         `json:"status"`
+	Slots       []*TimetableSlotDTO            `json:"slots"`
+	Unsatisfied []domain.UnsatisfiedConstraint `json:"unsatisfied"`
+	CreatedAt   string                         `json:"created_at"`           // Формат "DD.MM.YYYY HH:MM:SS"
+	AppliedAt   *string                        `json:"applied_at,omitempty"` // Формат "DD.MM.YYYY HH:MM:SS"
+	Version     int                            `json:"version"`
+}
+
+// TimetableApplyResponseDTO примененный черновик и созданные записи расписания
+type TimetableApplyResponseDTO struct {
+	DraftID   int                    `json:"draft_id"`
+	Schedules []*ScheduleResponseDTO `json:"schedules"`
+}
+
+// TimetableMapper маппер для черновиков расписания
+type TimetableMapper struct {
+	schedules *ScheduleMapper
+}
+
+func NewTimetableMapper() *TimetableMapper {
+	return &TimetableMapper{schedules: NewScheduleMapper()}
+}
+
+// ToResponse преобразует черновик в ответ API, разбирая сохраненное предложение
+func (m *TimetableMapper) ToResponse(draft *domain.TimetableDraft) (*TimetableDraftResponseDTO, error) {
+	proposal, err := draft.Proposal()
+	if err != nil {
+		return nil, err
+	}
+	response := &TimetableDraftResponseDTO{
+		DraftID:     draft.DraftID,
+		DateStart:   domain.ToDMY(draft.DateStart),
+		DateEnd:     domain.ToDMY(draft.DateEnd),
+		Status:      draft.Status,
+		Slots:       make([]*TimetableSlotDTO, len(proposal.Slots)),
+		Unsatisfied: proposal.Unsatisfied,
+		CreatedAt:   domain.ToDateTime(draft.CreatedAt),
+		AppliedAt:   domain.ToDateTimePtr(draft.AppliedAt),
+		Version:     draft.Version,
+	}
+	if response.Unsatisfied == nil {
+		response.Unsatisfied = []domain.UnsatisfiedConstraint{}
+	}
+	for i, slot := range proposal.Slots {
+		response.Slots[i] = &TimetableSlotDTO{
+			LessonID:   slot.LessonID,
+			LessonName: slot.LessonName,
+			EmployeeID: slot.EmployeeID,
+			AudienceID: slot.AudienceID,
+			GroupID:    slot.GroupID,
+			StudentID:  slot.StudentID,
+			DayWeek:    slot.DayWeek,
+			TimeBegin:  domain.ToTimeHM(slot.TimeBegin),
+			TimeEnd:    domain.ToTimeHM(slot.TimeEnd),
+		}
+	}
+	return response, nil
+}
+
+// ToApplyResponse преобразует итог применения черновика в ответ API
+func (m *TimetableMapper) ToApplyResponse(draftID int, schedules []*domain.Schedule) *TimetableApplyResponseDTO {
+	return &TimetableApplyResponseDTO{
+		DraftID:   draftID,
+		Schedules: m.schedules.ToResponseList(schedules),
+	}
+}
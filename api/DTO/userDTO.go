@@ -29,14 +29,16 @@ type UserUpdateDTO struct {
 
 // UserResponseDTO для ответа API
 type UserResponseDTO struct {
-	UserID           int    `json:"user_id"`
-	Login            string `json:"login"`
-	Role             string `json:"role"`
-	Surname          string `json:"surname"`
-	Name             string `json:"name"`
-	RegistrationDate string `json:"registration_date"`
-	Email            string `json:"email"`
-	Image            []byte `json:"image,omitempty"`
+	UserID           int     `json:"user_id"`
+	Login            string  `json:"login"`
+	Role             string  `json:"role"`
+	Surname          string  `json:"surname"`
+	Name             string  `json:"name"`
+	RegistrationDate string  `json:"registration_date"`
+	Email            string  `json:"email"`
+	Image            []byte  `json:"image,omitempty"`
+	Version          int     `json:"version"`
+	DeletedAt        *string `json:"deleted_at,omitempty"`
 }
 
 // UserLoginDTO для аутентификации
@@ -110,6 +112,8 @@ func (m *UserMapper) ToResponse(user *domain.User) *UserResponseDTO {
 		RegistrationDate: domain.ToDateTime(user.RegistrationDate),
 		Email:            user.Email,
 		Image:            image,
+		Version:          user.Version,
+		DeletedAt:        domain.ToDateTimePtr(user.DeletedAt),
 	}
 }
 
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"GO_Music/domain"
+)
+
+// WaitlistEnrollDTO для постановки студента в лист ожидания группы
+type WaitlistEnrollDTO struct {
+	GroupID   int `json:"group_id" validate:"required"`
+	StudentID int `json:"student_id" validate:"required"`
+}
+
+// WaitlistResponseDTO для ответа API; position есть только у ожидающих записей в /by-group и /by-student
+type WaitlistResponseDTO struct {
+	WaitlistID int     `json:"waitlist_id"`
+	GroupID    int     `json:"group_id"`
+	StudentID  int     `json:"student_id"`
+	Status     string  `json:"status"`
+	Position   int     `json:"position,omitempty"`
+	EnlistedAt string  `json:"enlisted_at"`
+	OfferedAt  *string `json:"offered_at,omitempty"`
+	ResolvedAt *string `json:"resolved_at,omitempty"`
+	Version    int     `json:"version"`
+}
+
+// WaitlistMapper реализует маппинг для листа ожидания
+type WaitlistMapper struct{}
+
+func NewWaitlistMapper() *WaitlistMapper {
+	return &WaitlistMapper{}
+}
+
+func (m *WaitlistMapper) ToResponse(entry *domain.WaitlistEntry) *WaitlistResponseDTO {
+	return &WaitlistResponseDTO{
+		WaitlistID: entry.WaitlistID,
+		GroupID:    entry.GroupID,
+		StudentID:  entry.StudentID,
+		Status:     entry.Status,
+		Position:   entry.Position,
+		EnlistedAt: domain.ToDateTime(entry.EnlistedAt),
+		OfferedAt:  domain.ToDateTimePtr(entry.OfferedAt),
+		ResolvedAt: domain.ToDateTimePtr(entry.ResolvedAt),
+		Version:    entry.Version,
+	}
+}
+
+func (m *WaitlistMapper) ToResponseList(entries []*domain.WaitlistEntry) []*WaitlistResponseDTO {
+	result := make([]*WaitlistResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = m.ToResponse(entry)
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"GO_Music/db"
 	"GO_Music/domain"
 	"GO_Music/engine"
 
@@ -17,6 +18,9 @@ import (
 type BaseHandlerConfig struct {
 	DefaultPageSize int
 	MaxPageSize     int
+	CursorSecret    []byte            // ключ подписи курсоров ?cursor=
+	FilterParams    map[string]string // query-параметр -> колонка: ?entity=x работает как ?filter[entity]=x
+	DefaultSort     string            // сортировка списка без ?sort=
 }
 
 // BaseHandler базовый обработчик для CRUD операций
@@ -61,6 +65,12 @@ func NewBaseHandler[ID comparable, T any, PT interface {
 	}
 }
 
+// SetPageSizes задает размеры страниц для списковых эндпоинтов
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) SetPageSizes(defaultPageSize, maxPageSize int) {
+	h.Config.DefaultPageSize = defaultPageSize
+	h.Config.MaxPageSize = maxPageSize
+}
+
 // Routes возвращает стандартные CRUD маршруты
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Routes() chi.Router {
 	r := chi.NewRouter()
@@ -71,10 +81,18 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Routes() chi
 	r.Put("/{id}", h.Update)
 	r.Patch("/{id}", h.PartialUpdate)
 	r.Delete("/{id}", h.Delete)
+	h.TrashRoutes(r)
 
 	return r
 }
 
+// TrashRoutes добавляет маршруты корзины; их подключают и обработчики со своими Routes
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) TrashRoutes(r chi.Router) {
+	r.Get("/trash", h.Trash)
+	r.Post("/{id}/restore", h.Restore)
+	r.With(RequireRole(RoleAdmin)).Delete("/{id}/purge", h.Purge)
+}
+
 // Create обрабатывает создание новой сущности
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Create(w http.ResponseWriter, r *http.Request) {
 	var dto CreateDTO
@@ -93,10 +111,11 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Create(w htt
 	entity := h.ToDomain(&dto)
 	if err := h.Manager.Create(r.Context(), entity); err != nil {
 		h.Logger.Error("Create failed", logger.Error(err))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrConflictOrInternal(err))
 		return
 	}
 
+	setETag(w, entity)
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, h.ToResponse(entity))
 }
@@ -117,55 +136,161 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Get(w http.R
 		return
 	}
 
+	if includes := parseIncludes(r); len(includes) > 0 {
+		if err := h.Manager.Preload(r.Context(), []*T{entity}, includes); err != nil {
+			h.Logger.Error("Preload failed", logger.Error(err))
+			render.Render(w, r, ErrInvalidFilterOrInternal(err))
+			return
+		}
+	}
+
+	setETag(w, entity)
 	render.JSON(w, r, h.ToResponse(entity))
 }
 
-// List обрабатывает получение списка сущностей
+// List обрабатывает получение списка сущностей: постранично (?page=) или по курсору (?cursor=)
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, false)
+}
+
+// Trash обрабатывает получение списка удаленных сущностей; параметры те же, что у List
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Trash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, true)
+}
+
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) list(w http.ResponseWriter, r *http.Request, trash bool) {
 	filter, err := h.parseFilter(r)
 	if err != nil {
 		h.Logger.Error("Failed to parse filter", logger.Error(err))
 		render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
+	filter.Trash = trash
 
-	count, err := h.Manager.Count(r.Context(), filter)
+	// Общее количество по умолчанию считается только в постраничном режиме
+	withTotal, err := parseWithTotal(r, filter.Cursor == nil)
 	if err != nil {
-		h.Logger.Error("Count failed", logger.Error(err))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
 
+	if filter.Cursor != nil {
+		h.listByCursor(w, r, filter, withTotal)
+		return
+	}
+
+	count := 0
+	if withTotal {
+		count, err = h.Manager.Count(r.Context(), filter)
+		if err != nil {
+			h.Logger.Error("Count failed", logger.Error(err))
+			render.Render(w, r, ErrInvalidFilterOrInternal(err))
+			return
+		}
+	}
+
 	entities, err := h.Manager.List(r.Context(), filter)
 	if err != nil {
 		h.Logger.Error("List failed", logger.Error(err))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrInvalidFilterOrInternal(err))
 		return
 	}
 
-	response := make([]*ResponseDTO, len(entities))
-	for i, entity := range entities {
-		response[i] = h.ToResponse(entity)
-	}
-
 	currentPage := 1
 	if filter.Offset > 0 {
 		currentPage = (filter.Offset / filter.Limit) + 1
 	}
-	totalPages := (count + filter.Limit - 1) / filter.Limit
+	pagination := map[string]int{
+		"current_page": currentPage,
+		"per_page":     filter.Limit,
+	}
+	response := map[string]interface{}{
+		"items":      h.toResponses(entities),
+		"pagination": pagination,
+	}
+	if withTotal {
+		response["total"] = count
+		pagination["total_pages"] = (count + filter.Limit - 1) / filter.Limit
+	}
+
+	render.JSON(w, r, response)
+}
+
+// listByCursor отдает страницу после (или перед) курсором; лишняя строка в выборке показывает, есть ли следующая страница
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) listByCursor(w http.ResponseWriter, r *http.Request, filter db.Filter, withTotal bool) {
+	limit := filter.Limit
+	filter.Limit = limit + 1
 
-	render.JSON(w, r, map[string]interface{}{
-		"items": response,
-		"total": count,
-		"pagination": map[string]int{
-			"current_page": currentPage,
-			"per_page":     filter.Limit,
-			"total_pages":  totalPages,
+	entities, err := h.Manager.List(r.Context(), filter)
+	if err != nil {
+		h.Logger.Error("List failed", logger.Error(err))
+		render.Render(w, r, ErrInvalidFilterOrInternal(err))
+		return
+	}
+
+	backward := filter.Cursor.Backward
+	hasMore := len(entities) > limit
+	if hasMore {
+		// При выборке назад лишняя строка оказывается первой
+		if backward {
+			entities = entities[1:]
+		} else {
+			entities = entities[:limit]
+		}
+	}
+
+	var next, prev interface{}
+	if len(entities) > 0 {
+		// В сторону, откуда пришел курсор, страница есть всегда (кроме первой страницы)
+		hasNext := hasMore || backward
+		hasPrev := (hasMore && backward) || (!backward && filter.Cursor.ID != nil)
+		if hasNext {
+			if next, err = h.encodeCursor(entities[len(entities)-1], filter.OrderBy, false); err != nil {
+				h.Logger.Error("Cursor encoding failed", logger.Error(err))
+				render.Render(w, r, ErrInternalServer(err))
+				return
+			}
+		}
+		if hasPrev {
+			if prev, err = h.encodeCursor(entities[0], filter.OrderBy, true); err != nil {
+				h.Logger.Error("Cursor encoding failed", logger.Error(err))
+				render.Render(w, r, ErrInternalServer(err))
+				return
+			}
+		}
+	}
+
+	response := map[string]interface{}{
+		"items": h.toResponses(entities),
+		"pagination": map[string]interface{}{
+			"per_page":    limit,
+			"next_cursor": next,
+			"prev_cursor": prev,
 		},
-	})
+	}
+	if withTotal {
+		count, err := h.Manager.Count(r.Context(), filter)
+		if err != nil {
+			h.Logger.Error("Count failed", logger.Error(err))
+			render.Render(w, r, ErrInvalidFilterOrInternal(err))
+			return
+		}
+		response["total"] = count
+	}
+
+	render.JSON(w, r, response)
+}
+
+// toResponses переводит список сущностей в ResponseDTO
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) toResponses(entities []*T) []*ResponseDTO {
+	response := make([]*ResponseDTO, len(entities))
+	for i, entity := range entities {
+		response[i] = h.ToResponse(entity)
+	}
+	return response
 }
 
-// Update обрабатывает полное обновление сущности
+// Update обрабатывает полное обновление сущности; для сущностей с версией If-Match обязателен (см. CheckIfMatch)
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Update(w http.ResponseWriter, r *http.Request) {
 	id, err := h.parseID(chi.URLParam(r, "id"))
 	if err != nil {
@@ -193,18 +318,22 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Update(w htt
 		render.Render(w, r, ErrNotFoundOrInternal(err))
 		return
 	}
+	if !CheckIfMatch(w, r, entity) {
+		return
+	}
 
 	h.UpdateDomain(entity, &dto)
 	if err := h.Manager.Update(r.Context(), entity); err != nil {
 		h.Logger.Error("Update failed", logger.Error(err))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrConflictOrInternal(err))
 		return
 	}
 
+	setETag(w, entity)
 	render.JSON(w, r, h.ToResponse(entity))
 }
 
-// PartialUpdate обрабатывает частичное обновление сущности
+// PartialUpdate обрабатывает частичное обновление сущности; If-Match сверяется с версией сущности
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) PartialUpdate(w http.ResponseWriter, r *http.Request) {
 	id, err := h.parseID(chi.URLParam(r, "id"))
 	if err != nil {
@@ -226,14 +355,18 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) PartialUpdat
 		render.Render(w, r, ErrNotFoundOrInternal(err))
 		return
 	}
+	if !CheckIfMatch(w, r, entity) {
+		return
+	}
 
 	h.UpdateDomain(entity, &dto)
 	if err := h.Manager.Update(r.Context(), entity); err != nil {
 		h.Logger.Error("Update failed", logger.Error(err))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrConflictOrInternal(err))
 		return
 	}
 
+	setETag(w, entity)
 	render.JSON(w, r, h.ToResponse(entity))
 }
 
@@ -248,7 +381,45 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Delete(w htt
 
 	if err := h.Manager.Delete(r.Context(), id); err != nil {
 		h.Logger.Error("Delete failed", logger.Error(err), logger.Any("id", id))
-		render.Render(w, r, ErrInternalServer(err))
+		render.Render(w, r, ErrConflictOrInternal(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Restore обрабатывает восстановление сущности из корзины
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error("Invalid ID", logger.Error(err))
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	entity, err := h.Manager.Restore(r.Context(), id)
+	if err != nil {
+		h.Logger.Error("Restore failed", logger.Error(err), logger.Any("id", id))
+		render.Render(w, r, ErrTrashOrInternal(err))
+		return
+	}
+
+	setETag(w, entity)
+	render.JSON(w, r, h.ToResponse(entity))
+}
+
+// Purge обрабатывает окончательное удаление сущности из корзины (только для администратора)
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) Purge(w http.ResponseWriter, r *http.Request) {
+	id, err := h.parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error("Invalid ID", logger.Error(err))
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := h.Manager.Purge(r.Context(), id); err != nil {
+		h.Logger.Error("Purge failed", logger.Error(err), logger.Any("id", id))
+		render.Render(w, r, ErrTrashOrInternal(err))
 		return
 	}
 
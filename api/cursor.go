@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GO_Music/db"
+)
+
+// errInvalidCursor курсор поврежден, подделан или выдан для другого ресурса
+var errInvalidCursor = errors.New("invalid cursor")
+
+// fallbackCursorSecret ключ подписи курсоров, если он не задан в конфигурации;
+// такие курсоры перестают работать после перезапуска
+var fallbackCursorSecret = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("api: failed to generate cursor secret: %v", err))
+	}
+	return key
+}()
+
+// cursorPayload содержимое курсора; сортировка хранится, чтобы курсор нельзя было применить к другому порядку строк
+type cursorPayload struct {
+	Sort     string        `json:"s,omitempty"`
+	Values   []interface{} `json:"v,omitempty"`
+	ID       interface{}   `json:"id"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// SetCursorSecret задает ключ подписи курсоров
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) SetCursorSecret(secret []byte) {
+	h.Config.CursorSecret = secret
+}
+
+// [RU] cursorScope отделяет курсоры разных ресурсов: курсор списка учеников не подходит к списку групп <--->
+// [ENG] cursorScope separates cursors of different resources: a student list cursor does not fit the group list
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) cursorScope() string {
+	var zero T
+	return fmt.Sprintf("%T", zero)
+}
+
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) cursorMAC(payload []byte) []byte {
+	secret := h.Config.CursorSecret
+	if len(secret) == 0 {
+		secret = fallbackCursorSecret
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(h.cursorScope()))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// [RU] encodeCursor строит непрозрачный подписанный курсор от граничной строки страницы <--->
+// [ENG] encodeCursor builds an opaque signed cursor from the boundary row of a page
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) encodeCursor(entity PT, orderBy string, backward bool) (string, error) {
+	row, err := db.StructToMap(entity)
+	if err != nil {
+		return "", err
+	}
+
+	payload := cursorPayload{Sort: orderBy, ID: entity.GetID(), Backward: backward}
+	if orderBy != "" {
+		keys, err := db.ColumnsOf[T]().Sort(orderBy)
+		if err != nil {
+			return "", err
+		}
+		for _, key := range keys {
+			payload.Values = append(payload.Values, row[key.Column])
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(data) + "." + enc.EncodeToString(h.cursorMAC(data)), nil
+}
+
+// [RU] decodeCursor проверяет подпись курсора и его сортировку; пустой курсор - первая страница <--->
+// [ENG] decodeCursor checks the cursor signature and sort; an empty cursor is the first page
+func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) decodeCursor(token string, orderBy string) (*db.Cursor, error) {
+	if token == "" {
+		return &db.Cursor{}, nil
+	}
+
+	enc := base64.RawURLEncoding
+	encodedData, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	data, err := enc.DecodeString(encodedData)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	mac, err := enc.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, h.cursorMAC(data)) {
+		return nil, errInvalidCursor
+	}
+
+	var payload cursorPayload
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || payload.ID == nil {
+		return nil, errInvalidCursor
+	}
+	if payload.Sort != orderBy {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", errInvalidCursor, payload.Sort)
+	}
+
+	// Числа остаются строками: как и значения из query-параметров, их приводит к типу колонки БД
+	for i, v := range payload.Values {
+		payload.Values[i] = cursorValue(v)
+	}
+	return &db.Cursor{Values: payload.Values, ID: cursorValue(payload.ID), Backward: payload.Backward}, nil
+}
+
+func cursorValue(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"GO_Music/db"
+
+	"github.com/go-chi/render"
+)
+
+// [RU] setETag отдает версию сущности в заголовке ETag; сущности без версии заголовок не получают <--->
+// [ENG] setETag sends the entity version in the ETag header; entities without a version get no header
+func setETag(w http.ResponseWriter, entity interface{}) {
+	if versioned, ok := entity.(db.Versioned); ok {
+		w.Header().Set("ETag", strconv.Quote(strconv.Itoa(versioned.GetVersion())))
+	}
+}
+
+// errIfMatchRequired PUT сущности с версией без If-Match
+var errIfMatchRequired = errors.New("missing If-Match header: a versioned entity is replaced only at its current version")
+
+// [RU] CheckIfMatch сверяет If-Match с версией сущности: 412, если версия устарела, 400 для некорректного заголовка.
+// PUT сущности с версией без заголовка отклоняется (428): полная замена не должна затирать чужие изменения.
+// Для PATCH заголовок необязателен, Update все равно проверяет версию, прочитанную из БД <--->
+// [ENG] CheckIfMatch compares If-Match with the entity version: 412 for a stale version, 400 for a malformed header.
+// A PUT of a versioned entity without the header is rejected (428): a full replace must not overwrite others' changes.
+// For PATCH the header is optional, Update still checks the version read from the DB
+func CheckIfMatch(w http.ResponseWriter, r *http.Request, entity interface{}) bool {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	versioned, ok := entity.(db.Versioned)
+	if !ok || header == "*" {
+		return true
+	}
+	if header == "" {
+		if r.Method != http.MethodPut {
+			return true
+		}
+		setETag(w, entity)
+		render.Render(w, r, ErrPreconditionRequired(errIfMatchRequired))
+		return false
+	}
+
+	current := strconv.Itoa(versioned.GetVersion())
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		// If-Match использует строгое сравнение: слабые теги не совпадают никогда
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		value, err := strconv.Unquote(tag)
+		if err != nil {
+			render.Render(w, r, ErrInvalidRequest(errors.New("invalid If-Match header")))
+			return false
+		}
+		if value == current {
+			return true
+		}
+	}
+
+	setETag(w, entity)
+	render.Render(w, r, ErrPreconditionFailed(db.ErrVersionConflict))
+	return false
+}
@@ -60,6 +60,7 @@ func (h *StudentAssessmentHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	return r
 }
@@ -53,6 +53,7 @@ func (h *AudienceHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-number/{number}", h.GetByNumber)
 	r.Get("/by-capacity/{min_capacity}", h.ListByCapacity)
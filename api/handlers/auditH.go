@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler журнал изменений: GET /audit?entity=&entity_id=&user_id=&range[created_at][from]=
+type AuditHandler struct {
+	*api.BaseHandler[int, domain.AuditEntry, *domain.AuditEntry,
+		struct{}, struct{}, dto.AuditEntryResponseDTO]
+	manager *m.AuditManager
+	mapper  *dto.AuditMapper
+}
+
+func NewAuditHandler(
+	manager *m.AuditManager,
+	logger *logger.LevelLogger,
+) *AuditHandler {
+	mapper := dto.NewAuditMapper()
+
+	return &AuditHandler{
+		BaseHandler: api.NewBaseHandler[int, domain.AuditEntry, *domain.AuditEntry, struct{}, struct{}](
+			manager.BaseManager,
+			logger,
+			nil,
+			nil,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+				FilterParams: map[string]string{
+					"entity":    "entity",
+					"entity_id": "entity_id",
+					"user_id":   "user_id",
+					"action":    "action",
+				},
+				DefaultSort: "-created_at,-audit_id",
+			},
+		),
+		manager: manager,
+		mapper:  mapper,
+	}
+}
+
+// [RU] Routes журнал только для чтения: записи создаются в той же транзакции, что и изменения <--->
+// [ENG] Routes the log is read-only: entries are created in the same transaction as the changes
+func (h *AuditHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.BaseHandler.List)
+	r.Get("/{id}", h.BaseHandler.Get)
+
+	return r
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// AvailabilityHandler доступность преподавателя: /employees/{id}/availability - окна и отсутствия
+type AvailabilityHandler struct {
+	*api.BaseHandler[int, domain.EmployeeAvailability, *domain.EmployeeAvailability,
+		dto.AvailabilityCreateDTO, dto.AvailabilityUpdateDTO, dto.AvailabilityResponseDTO]
+	managers *m.Managers
+	mapper   *dto.AvailabilityMapper
+}
+
+func NewAvailabilityHandler(
+	managers *m.Managers,
+	logger *logger.LevelLogger,
+) *AvailabilityHandler {
+	mapper := dto.NewAvailabilityMapper()
+
+	return &AvailabilityHandler{
+		BaseHandler: api.NewBaseHandler[int, domain.EmployeeAvailability, *domain.EmployeeAvailability, dto.AvailabilityCreateDTO](
+			managers.Availability.BaseManager,
+			logger,
+			nil,
+			mapper.UpdateDomain,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+			},
+		),
+		managers: managers,
+		mapper:   mapper,
+	}
+}
+
+// [RU] Routes маршруты монтируются в /employees/{id}/availability; запись ищется по {availability_id},
+// так как {id} занят сотрудником <--->
+// [ENG] Routes are mounted at /employees/{id}/availability; an entry is addressed by {availability_id}
+// since {id} is taken by the employee
+func (h *AvailabilityHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{availability_id}", h.Get)
+	r.Put("/{availability_id}", h.Update)
+	r.Delete("/{availability_id}", h.Delete)
+
+	return r
+}
+
+// [RU] List возвращает окна и отсутствия преподавателя <--->
+// [ENG] List returns the teacher's windows and absences
+func (h *AvailabilityHandler) List(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := h.employee(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.managers.Availability.GetByEmployee(r.Context(), employeeID)
+	if err != nil {
+		h.Logger.Error("GetByEmployee failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToResponseList(entries))
+}
+
+// [RU] Create добавляет преподавателю окно или отсутствие <--->
+// [ENG] Create adds a window or an absence to the teacher
+func (h *AvailabilityHandler) Create(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := h.employee(w, r)
+	if !ok {
+		return
+	}
+
+	var request dto.AvailabilityCreateDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	entry := h.mapper.ToDomain(employeeID, &request)
+	if err := h.managers.Availability.Create(r.Context(), entry); err != nil {
+		h.Logger.Error("Create failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.mapper.ToResponse(entry))
+}
+
+// [RU] Get возвращает запись доступности преподавателя <--->
+// [ENG] Get returns the teacher's availability entry
+func (h *AvailabilityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.entry(w, r)
+	if !ok {
+		return
+	}
+	api.SendSuccess(w, r, h.mapper.ToResponse(entry))
+}
+
+// [RU] Update изменяет запись доступности; уже стоящие занятия не перепроверяются <--->
+// [ENG] Update changes the availability entry; lessons already scheduled are not rechecked
+func (h *AvailabilityHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var request dto.AvailabilityUpdateDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	entry, ok := h.entry(w, r)
+	if !ok || !api.CheckIfMatch(w, r, entry) {
+		return
+	}
+
+	h.mapper.UpdateDomain(entry, &request)
+	if err := h.managers.Availability.Update(r.Context(), entry); err != nil {
+		h.Logger.Error("Update failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToResponse(entry))
+}
+
+// [RU] Delete удаляет запись доступности <--->
+// [ENG] Delete removes the availability entry
+func (h *AvailabilityHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.entry(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.managers.Availability.Delete(r.Context(), entry.AvailabilityID); err != nil {
+		h.Logger.Error("Delete failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// employee разбирает {id} и проверяет, что сотрудник существует
+func (h *AvailabilityHandler) employee(w http.ResponseWriter, r *http.Request) (int, bool) {
+	employeeID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return 0, false
+	}
+	if _, err := h.managers.Employee.GetByID(r.Context(), employeeID); err != nil {
+		h.Logger.Error("GetByID failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return 0, false
+	}
+	return employeeID, true
+}
+
+// entry загружает запись {availability_id}; запись другого сотрудника не найдена
+func (h *AvailabilityHandler) entry(w http.ResponseWriter, r *http.Request) (*domain.EmployeeAvailability, bool) {
+	employeeID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return nil, false
+	}
+	availabilityID, ok := api.ParseIntParam(w, r, h.Logger, "availability_id")
+	if !ok {
+		return nil, false
+	}
+
+	entry, err := h.managers.Availability.GetByID(r.Context(), availabilityID)
+	if err == nil && entry.EmployeeID != employeeID {
+		err = fmt.Errorf("availability %d of employee %d: %w", availabilityID, employeeID, sql.ErrNoRows)
+	}
+	if err != nil {
+		h.Logger.Error("GetByID failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return nil, false
+	}
+	return entry, true
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// maxCalendarImportSize наибольший размер файла импорта календаря
+const maxCalendarImportSize = 1 << 20
+
+// CalendarHandler учебный календарь: GET /calendar?kind= - записи вида term, holiday или swap
+type CalendarHandler struct {
+	*api.BaseHandler[int, domain.CalendarEntry, *domain.CalendarEntry,
+		dto.CalendarCreateDTO, dto.CalendarUpdateDTO, dto.CalendarResponseDTO]
+	managers *m.Managers
+	mapper   *dto.CalendarMapper
+}
+
+func NewCalendarHandler(
+	managers *m.Managers,
+	logger *logger.LevelLogger,
+) *CalendarHandler {
+	mapper := dto.NewCalendarMapper()
+
+	return &CalendarHandler{
+		BaseHandler: api.NewBaseHandler(
+			managers.Calendar.BaseManager,
+			logger,
+			mapper.ToDomain,
+			mapper.UpdateDomain,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+				FilterParams: map[string]string{
+					"kind": "kind",
+				},
+				DefaultSort: "date_start,calendar_id",
+			},
+		),
+		managers: managers,
+		mapper:   mapper,
+	}
+}
+
+// Routes возвращает маршруты для учебного календаря
+func (h *CalendarHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.BaseHandler.List)
+	r.Post("/", h.Create)
+	r.Post("/import", h.Import)
+	r.Get("/{id}", h.BaseHandler.Get)
+	r.Put("/{id}", h.BaseHandler.Update)
+	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
+	r.Delete("/{id}", h.BaseHandler.Delete)
+	r.Get("/{id}/affected", h.GetAffected)
+
+	return r
+}
+
+// [RU] Create создает запись календаря и возвращает ее вместе с отмененными или перенесенными ею занятиями <--->
+// [ENG] Create creates a calendar entry and returns it along with the occurrences it cancels or moves
+func (h *CalendarHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var request dto.CalendarCreateDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	entry := h.mapper.ToDomain(&request)
+	affected, err := h.managers.AddCalendarEntry(r.Context(), entry)
+	if err != nil {
+		h.Logger.Error("Create failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.mapper.ToChangeResponse(entry, affected))
+}
+
+// [RU] GetAffected возвращает занятия, которые запись календаря отменяет или переносит <--->
+// [ENG] GetAffected returns the occurrences the calendar entry cancels or moves
+func (h *CalendarHandler) GetAffected(w http.ResponseWriter, r *http.Request) {
+	calendarID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.managers.Calendar.GetByID(r.Context(), calendarID)
+	if err != nil {
+		h.Logger.Error("GetByID failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	affected, err := h.managers.CalendarImpact(r.Context(), entry)
+	if err != nil {
+		h.Logger.Error("GetAffected failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToChangeResponse(entry, affected))
+}
+
+// [RU] Import загружает календарь из файла iCalendar или CSV. Формат задает ?format=ics|csv,
+// без него - Content-Type (text/calendar или text/csv) <--->
+// [ENG] Import loads the calendar from an iCalendar or CSV file. ?format=ics|csv sets the format,
+// without it the Content-Type does (text/calendar or text/csv)
+func (h *CalendarHandler) Import(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		switch contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";"); strings.TrimSpace(contentType) {
+		case "text/calendar":
+			format = "ics"
+		case "text/csv":
+			format = "csv"
+		}
+	}
+
+	var parse func(io.Reader) ([]*domain.CalendarEntry, error)
+	switch format {
+	case "ics", "ical":
+		parse = domain.ParseICalendar
+	case "csv":
+		parse = domain.ParseCalendarCSV
+	default:
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("format must be ics or csv")))
+		return
+	}
+
+	entries, err := parse(http.MaxBytesReader(w, r.Body, maxCalendarImportSize))
+	if err != nil {
+		h.Logger.Error("Failed to parse calendar", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	result, err := h.managers.ImportCalendar(r.Context(), entries)
+	if err != nil {
+		h.Logger.Error("Import failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToImportResponse(result.Created, result.Updated, result.Affected))
+}
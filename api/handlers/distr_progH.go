@@ -53,6 +53,7 @@ func (h *ProgrammDistributionHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-programm/{programm_id}", h.GetByProgramm)
 	r.Get("/by-subject/{subject_id}", h.GetBySubject)
@@ -53,6 +53,7 @@ func (h *SubjectDistributionHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-employee/{employee_id}", h.GetByEmployee)
 	r.Get("/by-subject/{subject_id}", h.GetBySubject)
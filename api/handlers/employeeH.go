@@ -18,14 +18,16 @@ import (
 type EmployeeHandler struct {
 	*api.BaseHandler[int, domain.Employee, *domain.Employee,
 		dto.EmployeeCreateDTO, dto.EmployeeUpdateDTO, dto.EmployeeResponseDTO]
-	manager *m.EmployeeManager
-	mapper  *dto.EmployeeMapper
+	manager      *m.EmployeeManager
+	mapper       *dto.EmployeeMapper
+	availability *AvailabilityHandler
 }
 
 func NewEmployeeHandler(
-	manager *m.EmployeeManager,
+	managers *m.Managers,
 	logger *logger.LevelLogger,
 ) *EmployeeHandler {
+	manager := managers.Employee
 	mapper := dto.NewEmployeeMapper()
 
 	return &EmployeeHandler{
@@ -41,8 +43,9 @@ func NewEmployeeHandler(
 				MaxPageSize:     100,
 			},
 		),
-		manager: manager,
-		mapper:  mapper,
+		manager:      manager,
+		mapper:       mapper,
+		availability: NewAvailabilityHandler(managers, logger),
 	}
 }
 
@@ -54,6 +57,7 @@ func (h *EmployeeHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-phone/{phone}", h.GetByPhone)
 	r.Get("/by-user/{user_id}", h.GetByUserID)
@@ -61,6 +65,7 @@ func (h *EmployeeHandler) Routes() chi.Router {
 	r.Get("/by-birthday-range", h.ListByBirthdayRange)
 	r.Post("/bulk-create", h.BulkCreate)
 	r.Get("/check-phone-unique", h.CheckPhoneUnique)
+	r.Mount("/{id}/availability", h.availability.Routes())
 
 	return r
 }
@@ -53,12 +53,12 @@ func (h *StudyGroupHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-program/{program_id}", h.GetByProgram)
 	r.Get("/by-name/{name}", h.GetByName)
 	r.Get("/by-year/{year}", h.GetByYear)
 	r.Get("/check-name-unique", h.CheckNameUnique)
-	r.Patch("/{id}/student-count", h.UpdateStudentCount)
 	r.Post("/bulk-create", h.BulkCreate)
 
 	return r
@@ -158,37 +158,6 @@ func (h *StudyGroupHandler) CheckNameUnique(w http.ResponseWriter, r *http.Reque
 	})
 }
 
-// [RU] UpdateStudentCount обновляет количество студентов в группе <--->
-// [ENG] UpdateStudentCount updates the number of students in the group
-func (h *StudyGroupHandler) UpdateStudentCount(w http.ResponseWriter, r *http.Request) {
-	groupID, ok := api.ParseIntParam(w, r, h.Logger, "id")
-	if !ok {
-		return
-	}
-
-	var request struct {
-		NumberOfStudents int `json:"number_of_students" validate:"required"`
-	}
-
-	if err := render.DecodeJSON(r.Body, &request); err != nil {
-		h.Logger.Error("Failed to decode request body", logger.Error(err))
-		render.Render(w, r, api.ErrInvalidRequest(err))
-		return
-	}
-
-	if err := h.manager.UpdateStudentCount(r.Context(), groupID, request.NumberOfStudents); err != nil {
-		h.Logger.Error("UpdateStudentCount failed", logger.Error(err))
-		render.Render(w, r, api.ErrInternalServer(err))
-		return
-	}
-
-	api.SendSuccess(w, r, map[string]interface{}{
-		"status":             "success",
-		"group_id":           groupID,
-		"number_of_students": request.NumberOfStudents,
-	})
-}
-
 // [RU] BulkCreate массово создает учебные группы <--->
 // [ENG] BulkCreate creates multiple study groups
 func (h *StudyGroupHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"GO_Music/config"
 	"GO_Music/engine/managers"
 
 	"github.com/SerMoskvin/logger"
@@ -23,6 +24,11 @@ type Handlers struct {
 	Student       *StudentHandler
 	Subject       *SubjectHandler
 	User          *UserHandler
+	Audit         *AuditHandler
+	Waitlist      *WaitlistHandler
+	Calendar      *CalendarHandler
+	Substitution  *SubstitutionHandler
+	Occurrence    *LessonOccurrenceHandler
 }
 
 // NewHandlers создает все хендлеры
@@ -31,17 +37,22 @@ func NewHandlers(managers *managers.Managers, logger *logger.LevelLogger) *Handl
 		Assessment:    NewStudentAssessmentHandler(managers.Assessment, logger),
 		Attendance:    NewStudentAttendanceHandler(managers.Attendance, logger),
 		Audience:      NewAudienceHandler(managers.Audience, logger),
-		Employee:      NewEmployeeHandler(managers.Employee, logger),
+		Employee:      NewEmployeeHandler(managers, logger),
 		StudyGroup:    NewStudyGroupHandler(managers.StudyGroup, logger),
-		Schedule:      NewScheduleHandler(managers.Schedule, logger),
+		Schedule:      NewScheduleHandler(managers, logger),
 		Instrument:    NewInstrumentHandler(managers.Instrument, logger),
 		ProgrammDistr: NewProgrammDistributionHandler(managers.ProgrammDistr, logger),
 		SubjectDistr:  NewSubjectDistributionHandler(managers.SubjectDistr, logger),
-		Lesson:        NewLessonHandler(managers.Lesson, logger),
+		Lesson:        NewLessonHandler(managers, logger),
 		Programm:      NewProgrammHandler(managers.Programm, logger),
 		Student:       NewStudentHandler(managers.Student, logger),
 		Subject:       NewSubjectHandler(managers.Subject, logger),
 		User:          NewUserHandler(managers.User, logger),
+		Audit:         NewAuditHandler(managers.Audit, logger),
+		Waitlist:      NewWaitlistHandler(managers, logger),
+		Calendar:      NewCalendarHandler(managers, logger),
+		Substitution:  NewSubstitutionHandler(managers, logger),
+		Occurrence:    NewLessonOccurrenceHandler(managers, logger),
 	}
 }
 
@@ -61,5 +72,29 @@ func (h *Handlers) ToMap() map[string]interface{ Routes() chi.Router } {
 		"students":               h.Student,
 		"subjects":               h.Subject,
 		"users":                  h.User,
+		"audit":                  h.Audit,
+		"waitlist":               h.Waitlist,
+		"calendar":               h.Calendar,
+		"substitutions":          h.Substitution,
+		"lesson-occurrences":     h.Occurrence,
+	}
+}
+
+// ApplyPageSizes применяет размеры страниц из конфигурации ко всем хендлерам
+func (h *Handlers) ApplyPageSizes(cfg config.HandlersConfig) {
+	for path, handler := range h.ToMap() {
+		if sizer, ok := handler.(interface{ SetPageSizes(int, int) }); ok {
+			sizes := cfg.PageSizes(path)
+			sizer.SetPageSizes(sizes.DefaultPageSize, sizes.MaxPageSize)
+		}
+	}
+}
+
+// ApplyCursorSecret задает ключ подписи курсоров всем хендлерам
+func (h *Handlers) ApplyCursorSecret(secret string) {
+	for _, handler := range h.ToMap() {
+		if signer, ok := handler.(interface{ SetCursorSecret([]byte) }); ok {
+			signer.SetCursorSecret([]byte(secret))
+		}
 	}
 }
@@ -18,14 +18,17 @@ import (
 type LessonHandler struct {
 	*api.BaseHandler[int, domain.Lesson, *domain.Lesson,
 		dto.LessonCreateDTO, dto.LessonUpdateDTO, dto.LessonResponseDTO]
-	manager *m.LessonManager
-	mapper  *dto.LessonMapper
+	manager  *m.LessonManager
+	mapper   *dto.LessonMapper
+	managers *m.Managers
+	journals *dto.JournalMapper
 }
 
 func NewLessonHandler(
-	manager *m.LessonManager,
+	managers *m.Managers,
 	logger *logger.LevelLogger,
 ) *LessonHandler {
+	manager := managers.Lesson
 	mapper := dto.NewLessonMapper()
 
 	return &LessonHandler{
@@ -41,8 +44,10 @@ func NewLessonHandler(
 				MaxPageSize:     100,
 			},
 		),
-		manager: manager,
-		mapper:  mapper,
+		manager:  manager,
+		mapper:   mapper,
+		managers: managers,
+		journals: dto.NewJournalMapper(),
 	}
 }
 
@@ -54,6 +59,7 @@ func (h *LessonHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-employee/{employee_id}", h.GetByEmployee)
 	r.Get("/by-group/{group_id}", h.GetByGroup)
@@ -63,6 +69,8 @@ func (h *LessonHandler) Routes() chi.Router {
 	r.Get("/check-employee-availability", h.CheckEmployeeAvailability)
 	r.Get("/check-audience-availability", h.CheckAudienceAvailability)
 	r.Post("/bulk-create", h.BulkCreate)
+	r.Get("/{id}/journal", h.GetJournal)
+	r.Put("/{id}/journal", h.SaveJournal)
 
 	return r
 }
@@ -182,8 +190,8 @@ func (h *LessonHandler) GetByAudience(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
-// [RU] CheckEmployeeAvailability проверяет, свободен ли преподаватель в указанное время <--->
-// [ENG] CheckEmployeeAvailability checks if the employee is available during the specified time
+// [RU] CheckEmployeeAvailability проверяет, свободен ли преподаватель в указанное время (см. Managers.ResourceAvailable) <--->
+// [ENG] CheckEmployeeAvailability checks if the employee is available during the specified time (see Managers.ResourceAvailable)
 func (h *LessonHandler) CheckEmployeeAvailability(w http.ResponseWriter, r *http.Request) {
 	employeeID, err := strconv.Atoi(r.URL.Query().Get("employee_id"))
 	if err != nil {
@@ -205,7 +213,7 @@ func (h *LessonHandler) CheckEmployeeAvailability(w http.ResponseWriter, r *http
 
 	excludeLessonID, _ := strconv.Atoi(r.URL.Query().Get("exclude_lesson_id"))
 
-	isAvailable, err := h.manager.CheckEmployeeAvailability(r.Context(), employeeID, startTime, endTime, excludeLessonID)
+	isAvailable, err := h.managers.ResourceAvailable(r.Context(), domain.Resource{Kind: domain.ResourceEmployee, ID: employeeID}, startTime, endTime, excludeLessonID)
 	if err != nil {
 		h.Logger.Error("CheckEmployeeAvailability failed", logger.Error(err))
 		render.Render(w, r, api.ErrInternalServer(err))
@@ -218,8 +226,8 @@ func (h *LessonHandler) CheckEmployeeAvailability(w http.ResponseWriter, r *http
 	})
 }
 
-// [RU] CheckAudienceAvailability проверяет, свободна ли аудитория в указанное время <--->
-// [ENG] CheckAudienceAvailability checks if the audience is available during the specified time
+// [RU] CheckAudienceAvailability проверяет, свободна ли аудитория в указанное время (см. Managers.ResourceAvailable) <--->
+// [ENG] CheckAudienceAvailability checks if the audience is available during the specified time (see Managers.ResourceAvailable)
 func (h *LessonHandler) CheckAudienceAvailability(w http.ResponseWriter, r *http.Request) {
 	audienceID, err := strconv.Atoi(r.URL.Query().Get("audience_id"))
 	if err != nil {
@@ -241,7 +249,7 @@ func (h *LessonHandler) CheckAudienceAvailability(w http.ResponseWriter, r *http
 
 	excludeLessonID, _ := strconv.Atoi(r.URL.Query().Get("exclude_lesson_id"))
 
-	isAvailable, err := h.manager.CheckAudienceAvailability(r.Context(), audienceID, startTime, endTime, excludeLessonID)
+	isAvailable, err := h.managers.ResourceAvailable(r.Context(), domain.Resource{Kind: domain.ResourceAudience, ID: audienceID}, startTime, endTime, excludeLessonID)
 	if err != nil {
 		h.Logger.Error("CheckAudienceAvailability failed", logger.Error(err))
 		render.Render(w, r, api.ErrInternalServer(err))
@@ -280,3 +288,74 @@ func (h *LessonHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
 
 	api.SendCreated(w, r, map[string]string{"status": "success"})
 }
+
+// [RU] GetJournal возвращает журнал занятия в дату ?date= (DD.MM.YYYY): состав с отметками присутствия и оценками <--->
+// [ENG] GetJournal returns the journal of the lesson on ?date= (DD.MM.YYYY): the roster with presence marks and grades
+func (h *LessonHandler) GetJournal(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+	date, err := time.Parse("02.01.2006", r.URL.Query().Get("date"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("date is required, expected DD.MM.YYYY")))
+		return
+	}
+
+	journal, err := h.managers.LessonJournal(r.Context(), lessonID, date)
+	if err != nil {
+		h.Logger.Error("LessonJournal failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.journals.ToResponse(journal))
+}
+
+// [RU] SaveJournal сохраняет журнал занятия в дату ?date= одной транзакцией; повторное сохранение ничего не меняет <--->
+// [ENG] SaveJournal saves the journal of the lesson on ?date= in a single transaction; saving it again changes nothing
+func (h *LessonHandler) SaveJournal(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+	date, err := time.Parse("02.01.2006", r.URL.Query().Get("date"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("date is required, expected DD.MM.YYYY")))
+		return
+	}
+
+	var request dto.JournalUpdateDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	changes := m.JournalChanges{Topic: request.Topic, Notes: request.Notes}
+	for _, entry := range request.Entries {
+		mark := m.JournalMark{StudentID: entry.StudentID, Present: entry.PresenceMark}
+		for _, grade := range entry.Grades {
+			mark.Grades = append(mark.Grades, m.JournalGrade{
+				AssessmentNoteID: grade.AssessmentNoteID,
+				TaskType:         grade.TaskType,
+				Grade:            grade.Grade,
+			})
+		}
+		changes.Marks = append(changes.Marks, mark)
+	}
+
+	journal, err := h.managers.SaveLessonJournal(r.Context(), lessonID, date, changes)
+	if err != nil {
+		h.Logger.Error("SaveLessonJournal failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.journals.ToResponse(journal))
+}
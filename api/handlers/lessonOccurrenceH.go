@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+	"net/http"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// LessonOccurrenceHandler проведенные занятия: GET /lesson-occurrences?lesson_id=&schedule_id=&employee_id=&status=
+type LessonOccurrenceHandler struct {
+	*api.BaseHandler[int, domain.LessonOccurrence, *domain.LessonOccurrence,
+		struct{}, dto.LessonOccurrenceUpdateDTO, dto.LessonOccurrenceResponseDTO]
+	managers *m.Managers
+	mapper   *dto.LessonOccurrenceMapper
+}
+
+func NewLessonOccurrenceHandler(
+	managers *m.Managers,
+	logger *logger.LevelLogger,
+) *LessonOccurrenceHandler {
+	mapper := dto.NewLessonOccurrenceMapper()
+
+	return &LessonOccurrenceHandler{
+		BaseHandler: api.NewBaseHandler[int, domain.LessonOccurrence, *domain.LessonOccurrence, struct{}](
+			managers.Occurrence.BaseManager,
+			logger,
+			nil,
+			mapper.UpdateDomain,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+				FilterParams: map[string]string{
+					"lesson_id":   "lesson_id",
+					"schedule_id": "schedule_id",
+					"employee_id": "employee_id",
+					"status":      "status",
+				},
+				DefaultSort: "date,time_begin,occurrence_id",
+			},
+		),
+		managers: managers,
+		mapper:   mapper,
+	}
+}
+
+// [RU] Routes занятия создаются из расписания через POST / и обновляются по нему при чтении GET /{id};
+// список возвращает сохраненное состояние <--->
+// [ENG] Routes occurrences are created from the schedule via POST / and brought up to date on GET /{id};
+// the list returns the stored state
+func (h *LessonOccurrenceHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.BaseHandler.List)
+	r.Post("/", h.Materialize)
+	r.Get("/{id}", h.GetOccurrence)
+	r.Put("/{id}", h.BaseHandler.Update)
+	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
+	r.Get("/{id}/session", h.GetSession)
+
+	return r
+}
+
+// [RU] Materialize возвращает занятие серии в дату по правилу, создавая его при первом обращении <--->
+// [ENG] Materialize returns the occurrence of the series on the rule's date, creating it on first access
+func (h *LessonOccurrenceHandler) Materialize(w http.ResponseWriter, r *http.Request) {
+	var request dto.LessonOccurrenceCreateDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	occurrence, err := h.managers.OccurrenceAt(r.Context(), request.ScheduleID, domain.ParseDMY(request.Date))
+	if err != nil {
+		h.Logger.Error("OccurrenceAt failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToResponse(occurrence))
+}
+
+// [RU] GetOccurrence возвращает занятие, обновленное по расписанию <--->
+// [ENG] GetOccurrence returns the occurrence brought up to date with the schedule
+func (h *LessonOccurrenceHandler) GetOccurrence(w http.ResponseWriter, r *http.Request) {
+	occurrenceID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	occurrence, err := h.managers.LessonOccurrence(r.Context(), occurrenceID)
+	if err != nil {
+		h.Logger.Error("LessonOccurrence failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToResponse(occurrence))
+}
+
+// [RU] GetSession возвращает журнал занятия: занятие, посещаемость и оценки <--->
+// [ENG] GetSession returns the journal of the occurrence: the occurrence, its attendance and grades
+func (h *LessonOccurrenceHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	occurrenceID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	session, err := h.managers.LessonSession(r.Context(), occurrenceID)
+	if err != nil {
+		h.Logger.Error("LessonSession failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToSessionResponse(session))
+}
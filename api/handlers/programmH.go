@@ -53,6 +53,7 @@ func (h *ProgrammHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-type/{type}", h.GetByType)
 	r.Get("/by-instrument/{instrument}", h.GetByInstrument)
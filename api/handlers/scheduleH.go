@@ -6,27 +6,35 @@ import (
 	"GO_Music/domain"
 	m "GO_Music/engine/managers"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/SerMoskvin/logger"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
+// maxOccurrenceRange наибольший диапазон дат, на который разворачиваются занятия за один запрос
+const maxOccurrenceRange = 366 * 24 * time.Hour
+
 // ScheduleHandler обработчик для расписания
 type ScheduleHandler struct {
 	*api.BaseHandler[int, domain.Schedule, *domain.Schedule,
 		dto.ScheduleCreateDTO, dto.ScheduleUpdateDTO, dto.ScheduleResponseDTO]
-	manager *m.ScheduleManager
-	mapper  *dto.ScheduleMapper
+	manager   *m.ScheduleManager
+	managers  *m.Managers
+	mapper    *dto.ScheduleMapper
+	timetable *dto.TimetableMapper
 }
 
 // NewScheduleHandler создает новый обработчик расписания
 func NewScheduleHandler(
-	manager *m.ScheduleManager,
+	managers *m.Managers,
 	logger *logger.LevelLogger,
 ) *ScheduleHandler {
+	manager := managers.Schedule
 	mapper := dto.NewScheduleMapper()
 
 	return &ScheduleHandler{
@@ -42,8 +50,10 @@ func NewScheduleHandler(
 				MaxPageSize:     100,
 			},
 		),
-		manager: manager,
-		mapper:  mapper,
+		manager:   manager,
+		managers:  managers,
+		mapper:    mapper,
+		timetable: dto.NewTimetableMapper(),
 	}
 }
 
@@ -56,6 +66,7 @@ func (h *ScheduleHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-lesson/{lesson_id}", h.GetByLesson)
 	r.Get("/by-day/{day_week}", h.GetByDay)
@@ -64,6 +75,16 @@ func (h *ScheduleHandler) Routes() chi.Router {
 	r.Get("/by-date-range", h.GetByDateRange)
 	r.Post("/generate", h.GenerateSchedule)
 
+	r.Post("/solve", h.SolveTimetable)
+	r.Get("/solve/{id}", h.GetTimetableDraft)
+	r.Post("/solve/{id}/apply", h.ApplyTimetable)
+
+	r.Get("/occurrences", h.GetOccurrences)
+	r.Get("/{id}/occurrences", h.GetScheduleOccurrences)
+	r.Put("/{id}/occurrences/{date}", h.EditOccurrence)
+	r.Delete("/{id}/occurrences/{date}", h.CancelOccurrence)
+	r.Post("/{id}/occurrences/{date}/reset", h.ResetOccurrence)
+
 	return r
 }
 
@@ -132,20 +153,45 @@ func (h *ScheduleHandler) GetCurrentSchedule(w http.ResponseWriter, r *http.Requ
 	)
 }
 
-// [RU] CheckTimeConflict проверяет наличие конфликтов в расписании <--->
-// [ENG] CheckTimeConflict checks for conflicts in the schedule
+// [RU] CheckTimeConflict проверяет запись расписания до сохранения: ?lesson_id=&time_begin=&time_end=&date_start=
+// и day_week с date_end или rrule; exclude_id - изменяемая запись. Возвращает занятые ресурсы и занятия, которые их заняли <--->
+// [ENG] CheckTimeConflict checks a schedule entry before saving: ?lesson_id=&time_begin=&time_end=&date_start=
+// and day_week with date_end or rrule; exclude_id is the entry being changed. Returns the busy resources and the lessons holding them
 func (h *ScheduleHandler) CheckTimeConflict(w http.ResponseWriter, r *http.Request) {
-	dayWeek := r.URL.Query().Get("day_week")
-	timeBegin := r.URL.Query().Get("time_begin")
-	timeEnd := r.URL.Query().Get("time_end")
-	excludeID, _ := strconv.Atoi(r.URL.Query().Get("exclude_id"))
+	query := r.URL.Query()
+	lessonID, err := strconv.Atoi(query.Get("lesson_id"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("lesson_id is required")))
+		return
+	}
+	if query.Get("time_begin") == "" || query.Get("time_end") == "" || query.Get("date_start") == "" {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("time_begin, time_end and date_start are required")))
+		return
+	}
+	excludeID, _ := strconv.Atoi(query.Get("exclude_id"))
 
-	if dayWeek == "" || timeBegin == "" || timeEnd == "" {
-		render.Render(w, r, api.ErrInvalidRequest(errors.New("day_week, time_begin and time_end are required")))
+	schedule := &domain.Schedule{
+		ScheduleID:    excludeID,
+		LessonID:      lessonID,
+		DayWeek:       query.Get("day_week"),
+		TimeBegin:     domain.ParseTimeHM(query.Get("time_begin")),
+		TimeEnd:       domain.ParseTimeHM(query.Get("time_end")),
+		SchdDateStart: domain.ParseDMY(query.Get("date_start")),
+		SchdDateEnd:   domain.ParseDMY(query.Get("date_end")),
+	}
+	if rrule := query.Get("rrule"); rrule != "" {
+		schedule.RRule = &rrule
+	}
+	if err := schedule.Normalize(); err != nil {
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+	if err := schedule.Validate(); err != nil {
+		render.Render(w, r, api.ErrValidation(err))
 		return
 	}
 
-	hasConflict, err := h.manager.CheckTimeConflict(r.Context(), dayWeek, timeBegin, timeEnd, excludeID)
+	conflicts, err := h.managers.ScheduleConflicts(r.Context(), schedule)
 	if err != nil {
 		h.Logger.Error("CheckTimeConflict failed", logger.Error(err))
 		render.Render(w, r, api.ErrInternalServer(err))
@@ -153,10 +199,8 @@ func (h *ScheduleHandler) CheckTimeConflict(w http.ResponseWriter, r *http.Reque
 	}
 
 	api.SendSuccess(w, r, map[string]interface{}{
-		"has_conflict": hasConflict,
-		"day_week":     dayWeek,
-		"time_begin":   timeBegin,
-		"time_end":     timeEnd,
+		"has_conflict": len(conflicts) > 0,
+		"conflicts":    api.ConflictDetails(conflicts),
 	})
 }
 
@@ -189,8 +233,8 @@ func (h *ScheduleHandler) GetByDateRange(w http.ResponseWriter, r *http.Request)
 	)
 }
 
-// [RU] GenerateSchedule генерирует расписание на основе шаблона <--->
-// [ENG] GenerateSchedule generates a schedule based on a template
+// [RU] GenerateSchedule создает по шаблону еженедельную серию до даты until одной записью с правилом <--->
+// [ENG] GenerateSchedule creates a weekly series until the until date from a template as a single rule entry
 func (h *ScheduleHandler) GenerateSchedule(w http.ResponseWriter, r *http.Request) {
 	var template domain.Schedule
 	if err := render.DecodeJSON(r.Body, &template); err != nil {
@@ -215,9 +259,295 @@ func (h *ScheduleHandler) GenerateSchedule(w http.ResponseWriter, r *http.Reques
 
 	if err := h.manager.GenerateSchedule(r.Context(), &template, until); err != nil {
 		h.Logger.Error("GenerateSchedule failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.mapper.ToResponse(&template))
+}
+
+// [RU] SolveTimetable составляет недельное расписание на период и сохраняет его черновиком; расписание не меняется.
+// Возвращает предложенные часы занятий и ограничения, которые не удалось выполнить <--->
+// [ENG] SolveTimetable builds a weekly timetable for the period and saves it as a draft; the schedule is not changed.
+// Returns the proposed lesson hours and the constraints that could not be met
+func (h *ScheduleHandler) SolveTimetable(w http.ResponseWriter, r *http.Request) {
+	var request dto.TimetableSolveDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+	days, err := request.Weekdays()
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+
+	opts := m.TimetableOptions{
+		DateStart:    domain.ParseDMY(request.DateStart),
+		DateEnd:      domain.ParseDMY(request.DateEnd),
+		Days:         days,
+		SlotMinutes:  request.SlotMinutes,
+		BreakMinutes: 10,
+		LessonIDs:    request.LessonIDs,
+	}
+	if request.DayBegin != "" {
+		opts.DayBegin = domain.ParseTimeHM(request.DayBegin)
+	}
+	if request.DayEnd != "" {
+		opts.DayEnd = domain.ParseTimeHM(request.DayEnd)
+	}
+	if request.BreakMinutes != nil {
+		opts.BreakMinutes = *request.BreakMinutes
+	}
+
+	draft, err := h.managers.SolveTimetable(r.Context(), opts)
+	if err != nil {
+		h.Logger.Error("SolveTimetable failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+	h.sendDraft(w, r, draft, http.StatusCreated)
+}
+
+// [RU] GetTimetableDraft возвращает черновик расписания <--->
+// [ENG] GetTimetableDraft returns a timetable draft
+func (h *ScheduleHandler) GetTimetableDraft(w http.ResponseWriter, r *http.Request) {
+	draftID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	draft, err := h.managers.Timetable.GetByID(r.Context(), draftID)
+	if err != nil {
+		h.Logger.Error("GetByID failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+	h.sendDraft(w, r, draft, http.StatusOK)
+}
+
+// [RU] ApplyTimetable создает записи расписания из черновика в одной транзакции; занятый с тех пор ресурс - 409 <--->
+// [ENG] ApplyTimetable creates schedule entries from the draft in a single transaction; a resource taken since - 409
+func (h *ScheduleHandler) ApplyTimetable(w http.ResponseWriter, r *http.Request) {
+	draftID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	schedules, err := h.managers.ApplyTimetable(r.Context(), draftID)
+	if err != nil {
+		h.Logger.Error("ApplyTimetable failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.timetable.ToApplyResponse(draftID, schedules))
+}
+
+// sendDraft отправляет черновик расписания с указанным статусом
+func (h *ScheduleHandler) sendDraft(w http.ResponseWriter, r *http.Request, draft *domain.TimetableDraft, status int) {
+	response, err := h.timetable.ToResponse(draft)
+	if err != nil {
+		h.Logger.Error("Failed to decode timetable draft", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+	if status == http.StatusCreated {
+		api.SendCreated(w, r, response)
+		return
+	}
+	api.SendSuccess(w, r, response)
+}
+
+// [RU] GetOccurrences разворачивает серии в занятия за период: ?from=&to= (DD.MM.YYYY, не больше года),
+// lesson_id - одно занятие, include_cancelled=true - вместе с отмененными <--->
+// [ENG] GetOccurrences expands the series into occurrences for a period: ?from=&to= (DD.MM.YYYY, at most a year),
+// lesson_id - a single lesson, include_cancelled=true - cancelled occurrences included
+func (h *ScheduleHandler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("from") == "" || query.Get("to") == "" {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("from and to are required")))
+		return
+	}
+	from, to, err := parseOccurrenceRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	lessonID := 0
+	if raw := query.Get("lesson_id"); raw != "" {
+		if lessonID, err = strconv.Atoi(raw); err != nil {
+			render.Render(w, r, api.ErrInvalidRequest(fmt.Errorf("invalid lesson_id: %w", err)))
+			return
+		}
+	}
+	includeCancelled, _ := strconv.ParseBool(query.Get("include_cancelled"))
+
+	occurrences, err := h.managers.ScheduleOccurrences(r.Context(), from, to, lessonID, includeCancelled)
+	if err != nil {
+		h.Logger.Error("GetOccurrences failed", logger.Error(err))
 		render.Render(w, r, api.ErrInternalServer(err))
 		return
 	}
 
-	api.SendSuccess(w, r, map[string]string{"status": "schedule_generated"})
+	api.SendPaginated(w, r,
+		h.mapper.ToOccurrenceResponseList(occurrences),
+		len(occurrences),
+		1,
+		len(occurrences),
+	)
+}
+
+// [RU] GetScheduleOccurrences возвращает занятия одной серии, включая отмененные; без from и to - вся серия <--->
+// [ENG] GetScheduleOccurrences returns the occurrences of one series, cancelled ones included; without from and to - the whole series
+func (h *ScheduleHandler) GetScheduleOccurrences(w http.ResponseWriter, r *http.Request) {
+	scheduleID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.manager.GetByID(r.Context(), scheduleID)
+	if err != nil {
+		h.Logger.Error("GetByID failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	from, to := schedule.SchdDateStart, schedule.SchdDateEnd
+	query := r.URL.Query()
+	if query.Get("from") != "" || query.Get("to") != "" {
+		if from, to, err = parseOccurrenceRange(query.Get("from"), query.Get("to")); err != nil {
+			render.Render(w, r, api.ErrInvalidRequest(err))
+			return
+		}
+	}
+
+	all, err := h.managers.ScheduleOccurrences(r.Context(), from, to, schedule.LessonID, true)
+	if err != nil {
+		h.Logger.Error("GetScheduleOccurrences failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+	occurrences := make([]domain.Occurrence, 0, len(all))
+	for _, occurrence := range all {
+		if occurrence.ScheduleID == scheduleID {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	api.SendPaginated(w, r,
+		h.mapper.ToOccurrenceResponseList(occurrences),
+		len(occurrences),
+		1,
+		len(occurrences),
+	)
+}
+
+// [RU] EditOccurrence переносит одно занятие серии; {date} - дата занятия по правилу (DD.MM.YYYY) <--->
+// [ENG] EditOccurrence moves a single occurrence of the series; {date} is the occurrence date by the rule (DD.MM.YYYY)
+func (h *ScheduleHandler) EditOccurrence(w http.ResponseWriter, r *http.Request) {
+	scheduleID, date, ok := h.parseOccurrence(w, r)
+	if !ok {
+		return
+	}
+
+	var request dto.OccurrenceEditDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	exception, err := h.managers.EditOccurrence(r.Context(), scheduleID, date, request.ToDomain())
+	if err != nil {
+		h.Logger.Error("EditOccurrence failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToExceptionResponse(exception))
+}
+
+// [RU] CancelOccurrence отменяет одно занятие серии; ?reason= - причина отмены <--->
+// [ENG] CancelOccurrence cancels a single occurrence of the series; ?reason= is the cancellation reason
+func (h *ScheduleHandler) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	scheduleID, date, ok := h.parseOccurrence(w, r)
+	if !ok {
+		return
+	}
+
+	var reason *string
+	if raw := r.URL.Query().Get("reason"); raw != "" {
+		reason = &raw
+	}
+
+	exception, err := h.managers.CancelOccurrence(r.Context(), scheduleID, date, reason)
+	if err != nil {
+		h.Logger.Error("CancelOccurrence failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToExceptionResponse(exception))
+}
+
+// [RU] ResetOccurrence удаляет перенос или отмену занятия: оно снова идет по правилу <--->
+// [ENG] ResetOccurrence removes the move or cancellation of the occurrence: it follows the rule again
+func (h *ScheduleHandler) ResetOccurrence(w http.ResponseWriter, r *http.Request) {
+	scheduleID, date, ok := h.parseOccurrence(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.managers.ResetOccurrence(r.Context(), scheduleID, date); err != nil {
+		h.Logger.Error("ResetOccurrence failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, map[string]string{"status": "success"})
+}
+
+// parseOccurrence разбирает адрес занятия: {id} серии и {date} по правилу
+func (h *ScheduleHandler) parseOccurrence(w http.ResponseWriter, r *http.Request) (int, time.Time, bool) {
+	scheduleID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	date, err := time.Parse("02.01.2006", chi.URLParam(r, "date"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(fmt.Errorf("invalid date, expected DD.MM.YYYY: %w", err)))
+		return 0, time.Time{}, false
+	}
+	return scheduleID, date, true
+}
+
+// parseOccurrenceRange разбирает период from..to в формате DD.MM.YYYY
+func parseOccurrenceRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	from, err := time.Parse("02.01.2006", fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from, expected DD.MM.YYYY: %w", err)
+	}
+	to, err := time.Parse("02.01.2006", toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to, expected DD.MM.YYYY: %w", err)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxOccurrenceRange {
+		return time.Time{}, time.Time{}, errors.New("the period must not exceed a year")
+	}
+	return from, to, nil
 }
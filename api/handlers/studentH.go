@@ -53,6 +53,7 @@ func (h *StudentHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-group/{group_id}", h.GetByGroup)
 	r.Get("/by-program/{program_id}", h.GetByProgram)
@@ -186,7 +187,7 @@ func (h *StudentHandler) TransferToGroup(w http.ResponseWriter, r *http.Request)
 
 	if err := h.manager.TransferToGroup(r.Context(), studentID, request.NewGroupID); err != nil {
 		h.Logger.Error("TransferToGroup failed", logger.Error(err))
-		render.Render(w, r, api.ErrInternalServer(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
 		return
 	}
 
@@ -282,7 +283,7 @@ func (h *StudentHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.manager.BulkCreate(r.Context(), students); err != nil {
 		h.Logger.Error("BulkCreate failed", logger.Error(err))
-		render.Render(w, r, api.ErrInternalServer(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
 		return
 	}
 
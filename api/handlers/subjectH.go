@@ -53,6 +53,7 @@ func (h *SubjectHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Get("/by-type/{type}", h.GetByType)
 	r.Get("/search-by-name", h.SearchByName)
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+	"errors"
+	"net/http"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SubstitutionHandler замены преподавателей: GET /substitutions?status=&lesson_id=&employee_id=&substitute_id=
+type SubstitutionHandler struct {
+	*api.BaseHandler[int, domain.Substitution, *domain.Substitution,
+		struct{}, struct{}, dto.SubstitutionResponseDTO]
+	managers  *m.Managers
+	mapper    *dto.SubstitutionMapper
+	employees *dto.EmployeeMapper
+}
+
+func NewSubstitutionHandler(
+	managers *m.Managers,
+	logger *logger.LevelLogger,
+) *SubstitutionHandler {
+	mapper := dto.NewSubstitutionMapper()
+
+	return &SubstitutionHandler{
+		BaseHandler: api.NewBaseHandler[int, domain.Substitution, *domain.Substitution, struct{}, struct{}](
+			managers.Substitution.BaseManager,
+			logger,
+			nil,
+			nil,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+				FilterParams: map[string]string{
+					"status":        "status",
+					"lesson_id":     "lesson_id",
+					"employee_id":   "employee_id",
+					"substitute_id": "substitute_id",
+				},
+				DefaultSort: "date,time_begin,substitution_id",
+			},
+		),
+		managers:  managers,
+		mapper:    mapper,
+		employees: dto.NewEmployeeMapper(),
+	}
+}
+
+// [RU] Routes замены создаются через POST / и назначаются через /{id}/assign; занятие при этом не меняется <--->
+// [ENG] Routes substitutions are created via POST / and assigned via /{id}/assign; the lesson itself is not changed
+func (h *SubstitutionHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.BaseHandler.List)
+	r.Post("/", h.RequestCover)
+	r.Get("/{id}", h.BaseHandler.Get)
+	r.Delete("/{id}", h.BaseHandler.Delete)
+	r.Get("/{id}/candidates", h.GetCandidates)
+	r.Post("/{id}/assign", h.Assign)
+	r.Get("/by-employee/{employee_id}", h.GetReport)
+
+	return r
+}
+
+// [RU] RequestCover отмечает занятия, которым нужна замена: одно занятие или все занятия преподавателя за период <--->
+// [ENG] RequestCover marks the occurrences needing cover: a single lesson or all of a teacher's lessons for a period
+func (h *SubstitutionHandler) RequestCover(w http.ResponseWriter, r *http.Request) {
+	var request dto.CoverRequestDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	from, to := request.Period()
+	if to.Sub(from) > maxOccurrenceRange {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("the period must not exceed a year")))
+		return
+	}
+
+	substitutions, err := h.managers.RequestCover(r.Context(), m.CoverOptions{
+		LessonID:   request.LessonID,
+		EmployeeID: request.EmployeeID,
+		DateFrom:   from,
+		DateTo:     to,
+		Reason:     request.Reason,
+	})
+	if err != nil {
+		h.Logger.Error("RequestCover failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.mapper.ToResponseList(substitutions))
+}
+
+// [RU] GetCandidates возвращает преподавателей предмета, свободных во время занятия <--->
+// [ENG] GetCandidates returns the subject's teachers who are free at the time of the occurrence
+func (h *SubstitutionHandler) GetCandidates(w http.ResponseWriter, r *http.Request) {
+	substitutionID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	candidates, err := h.managers.SubstituteCandidates(r.Context(), substitutionID)
+	if err != nil {
+		h.Logger.Error("SubstituteCandidates failed", logger.Error(err))
+		render.Render(w, r, api.ErrNotFoundOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.employees.ToResponseList(candidates))
+}
+
+// [RU] Assign назначает заменяющего преподавателя <--->
+// [ENG] Assign assigns the substitute teacher
+func (h *SubstitutionHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	substitutionID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	var request dto.SubstituteAssignDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	substitution, err := h.managers.AssignSubstitute(r.Context(), substitutionID, request.EmployeeID)
+	if err != nil {
+		h.Logger.Error("AssignSubstitute failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToResponse(substitution))
+}
+
+// [RU] GetReport возвращает замены преподавателя за период ?from=&to= (DD.MM.YYYY) для расчета оплаты <--->
+// [ENG] GetReport returns the employee's substitutions for the ?from=&to= period (DD.MM.YYYY) for payroll
+func (h *SubstitutionHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := api.ParseIntParam(w, r, h.Logger, "employee_id")
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("from") == "" || query.Get("to") == "" {
+		render.Render(w, r, api.ErrInvalidRequest(errors.New("from and to are required")))
+		return
+	}
+	from, to, err := parseOccurrenceRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+
+	report, err := h.managers.SubstitutionReport(r.Context(), employeeID, from, to)
+	if err != nil {
+		h.Logger.Error("SubstitutionReport failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	api.SendSuccess(w, r, h.mapper.ToReportResponse(report))
+}
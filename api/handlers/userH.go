@@ -54,6 +54,7 @@ func (h *UserHandler) Routes() chi.Router {
 	r.Put("/{id}", h.BaseHandler.Update)
 	r.Patch("/{id}", h.BaseHandler.PartialUpdate)
 	r.Delete("/{id}", h.BaseHandler.Delete)
+	h.BaseHandler.TrashRoutes(r)
 
 	r.Post("/register", h.Register)
 	r.Post("/login", h.Login)
@@ -257,7 +258,7 @@ func (h *UserHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 	user.Image = imageData
 	if err := h.manager.Update(r.Context(), user); err != nil {
 		h.Logger.Error("Update failed", logger.Error(err))
-		render.Render(w, r, api.ErrInternalServer(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
 		return
 	}
 
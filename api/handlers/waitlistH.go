@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"GO_Music/api"
+	dto "GO_Music/api/DTO"
+	"GO_Music/domain"
+	m "GO_Music/engine/managers"
+	"net/http"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// WaitlistHandler лист ожидания групп: GET /waitlist?group_id=&student_id=&status= - история записей
+type WaitlistHandler struct {
+	*api.BaseHandler[int, domain.WaitlistEntry, *domain.WaitlistEntry,
+		struct{}, struct{}, dto.WaitlistResponseDTO]
+	managers *m.Managers
+	mapper   *dto.WaitlistMapper
+}
+
+func NewWaitlistHandler(
+	managers *m.Managers,
+	logger *logger.LevelLogger,
+) *WaitlistHandler {
+	mapper := dto.NewWaitlistMapper()
+
+	return &WaitlistHandler{
+		BaseHandler: api.NewBaseHandler[int, domain.WaitlistEntry, *domain.WaitlistEntry, struct{}, struct{}](
+			managers.Waitlist.BaseManager,
+			logger,
+			nil,
+			nil,
+			mapper.ToResponse,
+			nil,
+			api.BaseHandlerConfig{
+				DefaultPageSize: 50,
+				MaxPageSize:     200,
+				FilterParams: map[string]string{
+					"group_id":   "group_id",
+					"student_id": "student_id",
+					"status":     "status",
+				},
+				DefaultSort: "enlisted_at,waitlist_id",
+			},
+		),
+		managers: managers,
+		mapper:   mapper,
+	}
+}
+
+// [RU] Routes записи меняются только через enroll/withdraw/accept и перевод студентов, поэтому PUT и DELETE нет <--->
+// [ENG] Routes entries change only through enroll/withdraw/accept and student transfers, so there is no PUT or DELETE
+func (h *WaitlistHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.BaseHandler.List)
+	r.Post("/", h.Enroll)
+	r.Get("/{id}", h.BaseHandler.Get)
+	r.Get("/by-group/{group_id}", h.GetByGroup)
+	r.Get("/by-student/{student_id}", h.GetByStudent)
+	r.Post("/{id}/withdraw", h.Withdraw)
+	r.Post("/{id}/accept", h.Accept)
+
+	return r
+}
+
+// [RU] Enroll ставит студента в лист ожидания группы; свободное место предлагается сразу <--->
+// [ENG] Enroll puts a student on the group's waitlist; a free place is offered right away
+func (h *WaitlistHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	var request dto.WaitlistEnrollDTO
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		h.Logger.Error("Failed to decode request body", logger.Error(err))
+		render.Render(w, r, api.ErrInvalidRequest(err))
+		return
+	}
+	if err := h.Validate(&request); err != nil {
+		h.Logger.Error("Validation failed", logger.Error(err))
+		render.Render(w, r, api.ErrValidation(err))
+		return
+	}
+
+	entry, err := h.managers.EnrollWaitlist(r.Context(), request.GroupID, request.StudentID)
+	if err != nil {
+		h.Logger.Error("Enroll failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendCreated(w, r, h.mapper.ToResponse(entry))
+}
+
+// [RU] GetByGroup возвращает записи группы с историей; у ожидающих - место в очереди <--->
+// [ENG] GetByGroup returns the group's entries with history; waiting entries carry their queue position
+func (h *WaitlistHandler) GetByGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, ok := api.ParseIntParam(w, r, h.Logger, "group_id")
+	if !ok {
+		return
+	}
+
+	entries, err := h.managers.Waitlist.GetByGroup(r.Context(), groupID)
+	if err != nil {
+		h.Logger.Error("GetByGroup failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	api.SendPaginated(w, r,
+		h.mapper.ToResponseList(entries),
+		len(entries),
+		1,
+		h.Config.DefaultPageSize,
+	)
+}
+
+// [RU] GetByStudent возвращает записи студента с историей; у ожидающих - место в очереди группы <--->
+// [ENG] GetByStudent returns the student's entries with history; waiting entries carry their queue position
+func (h *WaitlistHandler) GetByStudent(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := api.ParseIntParam(w, r, h.Logger, "student_id")
+	if !ok {
+		return
+	}
+
+	entries, err := h.managers.Waitlist.GetByStudent(r.Context(), studentID)
+	if err != nil {
+		h.Logger.Error("GetByStudent failed", logger.Error(err))
+		render.Render(w, r, api.ErrInternalServer(err))
+		return
+	}
+
+	api.SendPaginated(w, r,
+		h.mapper.ToResponseList(entries),
+		len(entries),
+		1,
+		h.Config.DefaultPageSize,
+	)
+}
+
+// [RU] Withdraw снимает запись с листа ожидания; предложенное ей место переходит следующему <--->
+// [ENG] Withdraw takes the entry off the waitlist; a place offered to it goes to the next in the queue
+func (h *WaitlistHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	waitlistID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.managers.WithdrawWaitlist(r.Context(), waitlistID); err != nil {
+		h.Logger.Error("Withdraw failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, map[string]string{"status": "success"})
+}
+
+// [RU] Accept принимает предложенное место: студент переводится в группу <--->
+// [ENG] Accept accepts the offered place: the student is transferred to the group
+func (h *WaitlistHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	waitlistID, ok := api.ParseIntParam(w, r, h.Logger, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.managers.AcceptWaitlistOffer(r.Context(), waitlistID); err != nil {
+		h.Logger.Error("Accept failed", logger.Error(err))
+		render.Render(w, r, api.ErrConflictOrInternal(err))
+		return
+	}
+
+	api.SendSuccess(w, r, map[string]string{"status": "success"})
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"GO_Music/db"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -67,10 +68,17 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) parseFilter(
 		filter.Offset = (page - 1) * filter.Limit
 	}
 
+	filter.OrderBy = h.Config.DefaultSort
 	if sort := query.Get("sort"); sort != "" {
 		filter.OrderBy = sort
 	}
 
+	for param, column := range h.Config.FilterParams {
+		if value := query.Get(param); value != "" {
+			filter.Conditions = append(filter.Conditions, db.Condition{Field: column, Operator: "=", Value: value})
+		}
+	}
+
 	for key, values := range query {
 		if len(values) == 0 || values[0] == "" {
 			continue
@@ -90,35 +98,82 @@ func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) parseFilter(
 			field := strings.TrimPrefix(key, "range[")
 			field = strings.TrimSuffix(field, "]")
 			parts := strings.Split(field, "][")
-			if len(parts) == 2 {
-				switch parts[1] {
-				case "from":
-					if t, err := time.Parse(time.RFC3339, values[0]); err == nil {
-						filter.Conditions = append(filter.Conditions, db.Condition{
-							Field:    parts[0],
-							Operator: ">=",
-							Value:    t,
-						})
-					}
-				case "to":
-					if t, err := time.Parse(time.RFC3339, values[0]); err == nil {
-						filter.Conditions = append(filter.Conditions, db.Condition{
-							Field:    parts[0],
-							Operator: "<=",
-							Value:    t,
-						})
-					}
-				}
+			if len(parts) != 2 {
+				return filter, fmt.Errorf("%w: invalid range parameter %q", db.ErrInvalidFilter, key)
 			}
+			var operator string
+			switch parts[1] {
+			case "from":
+				operator = ">="
+			case "to":
+				operator = "<="
+			default:
+				return filter, fmt.Errorf("%w: invalid range bound %q, expected from or to", db.ErrInvalidFilter, parts[1])
+			}
+			t, err := time.Parse(time.RFC3339, values[0])
+			if err != nil {
+				return filter, fmt.Errorf("%w: %s must be an RFC 3339 time", db.ErrInvalidFilter, key)
+			}
+			filter.Conditions = append(filter.Conditions, db.Condition{
+				Field:    parts[0],
+				Operator: operator,
+				Value:    t,
+			})
 
 		case key == "search":
 			filter.Search = values[0]
+
+		case key == "include":
+			filter.Preloads = parseIncludes(r)
 		}
 	}
 
+	// Поля и сортировка проверяются по колонкам сущности до обращения к БД
+	filter, err := db.ColumnsOf[T]().Normalize(filter)
+	if err != nil {
+		return filter, err
+	}
+
+	// Пустой ?cursor= включает курсорный режим с первой страницы
+	if _, ok := query["cursor"]; ok {
+		if query.Get("page") != "" {
+			return filter, errors.New("cursor and page parameters cannot be combined")
+		}
+		cursor, err := h.decodeCursor(query.Get("cursor"), filter.OrderBy)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
 	return filter, nil
 }
 
+// [RU] parseWithTotal читает ?with_total=true|false; без параметра возвращает defaultValue <--->
+// [ENG] parseWithTotal reads ?with_total=true|false; returns defaultValue when the parameter is absent
+func parseWithTotal(r *http.Request, defaultValue bool) (bool, error) {
+	raw := r.URL.Query().Get("with_total")
+	if raw == "" {
+		return defaultValue, nil
+	}
+	withTotal, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.New("invalid with_total parameter")
+	}
+	return withTotal, nil
+}
+
+// [RU] parseIncludes читает список связей из ?include=employee,subject <--->
+// [ENG] parseIncludes reads the relation list from ?include=employee,subject
+func parseIncludes(r *http.Request) []string {
+	var includes []string
+	for _, name := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			includes = append(includes, name)
+		}
+	}
+	return includes
+}
+
 // [RU] parseIDFromRequest универсальный парсер ID из URL параметров <--->
 // [ENG] parseIDFromRequest universal ID parser from URL parameters
 func (h *BaseHandler[ID, T, PT, CreateDTO, UpdateDTO, ResponseDTO]) parseIDFromRequest(r *http.Request, paramName string) (ID, bool) {
@@ -5,6 +5,10 @@ import (
 	"errors"
 	"net/http"
 
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
 	"github.com/SerMoskvin/logger"
 	"github.com/SerMoskvin/validate"
 	"github.com/go-chi/render"
@@ -20,6 +24,38 @@ type ErrResponse struct {
 	AppCode    int64             `json:"code,omitempty"`
 	ErrorText  string            `json:"error,omitempty"`
 	Validation map[string]string `json:"validation,omitempty"`
+	Conflicts  []ConflictDetail  `json:"conflicts,omitempty"` // занятые ресурсы при domain.ErrScheduleConflict
+}
+
+// [RU] ConflictDetail конфликт расписания в ответе API: какой ресурс занят и каким занятием <--->
+// [ENG] ConflictDetail is a schedule conflict in an API response: which resource is busy and with which lesson
+type ConflictDetail struct {
+	Resource   string `json:"resource"` // employee, audience, group или student
+	ResourceID int    `json:"resource_id"`
+	LessonID   int    `json:"lesson_id"`
+	LessonName string `json:"lesson_name"`
+	ScheduleID int    `json:"schedule_id"`
+	Date       string `json:"date"`       // Формат "DD.MM.YYYY": первая общая дата
+	TimeBegin  string `json:"time_begin"` // Формат "15:04"
+	TimeEnd    string `json:"time_end"`   // Формат "15:04"
+}
+
+// ConflictDetails преобразует конфликты расписания для ответа API
+func ConflictDetails(conflicts []domain.Conflict) []ConflictDetail {
+	details := make([]ConflictDetail, len(conflicts))
+	for i, c := range conflicts {
+		details[i] = ConflictDetail{
+			Resource:   c.Kind,
+			ResourceID: c.ID,
+			LessonID:   c.LessonID,
+			LessonName: c.LessonName,
+			ScheduleID: c.ScheduleID,
+			Date:       domain.ToDMY(c.Date),
+			TimeBegin:  domain.ToTimeHM(c.TimeBegin),
+			TimeEnd:    domain.ToTimeHM(c.TimeEnd),
+		}
+	}
+	return details
 }
 
 func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -64,7 +100,7 @@ func ErrValidation(err error) render.Renderer {
 // [RU] ErrNotFoundOrInternal создает ответ для отсутствующих ресурсов (404) или внутренних ошибок (500) <--->
 // [ENG] ErrNotFoundOrInternal creates response for not found (404) or internal errors (500)
 func ErrNotFoundOrInternal(err error) render.Renderer {
-	if errors.Is(err, sql.ErrNoRows) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, domain.ErrNoOccurrence) {
 		return &ErrResponse{
 			Err:            err,
 			HTTPStatusCode: 404,
@@ -74,6 +110,122 @@ func ErrNotFoundOrInternal(err error) render.Renderer {
 	return ErrInternalServer(err)
 }
 
+// [RU] ErrInvalidFilterOrInternal создает ответ для некорректных фильтров (400) или внутренних ошибок (500) <--->
+// [ENG] ErrInvalidFilterOrInternal creates response for invalid filters (400) or internal errors (500)
+func ErrInvalidFilterOrInternal(err error) render.Renderer {
+	if errors.Is(err, db.ErrInvalidFilter) {
+		return ErrInvalidRequest(err)
+	}
+	return ErrInternalServer(err)
+}
+
+// [RU] ErrPreconditionFailed создает ответ для устаревшего If-Match (412) <--->
+// [ENG] ErrPreconditionFailed creates response for a stale If-Match (412)
+func ErrPreconditionFailed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 412,
+		StatusText:     "Precondition failed",
+		ErrorText:      err.Error(),
+	}
+}
+
+// [RU] ErrPreconditionRequired создает ответ для PUT без If-Match (428) <--->
+// [ENG] ErrPreconditionRequired creates response for a PUT without If-Match (428)
+func ErrPreconditionRequired(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 428,
+		StatusText:     "Precondition required",
+		ErrorText:      err.Error(),
+	}
+}
+
+// conflictStatuses коды ответа ErrConflictOrInternal для ошибок менеджеров
+var conflictStatuses = []struct {
+	err    error
+	status int
+}{
+	{db.ErrVersionConflict, 409},
+	{db.ErrReferenced, 409},
+	{db.ErrDuplicate, 409},
+	{domain.ErrGroupFull, 409},
+	{domain.ErrWaitlistConflict, 409},
+	{domain.ErrDraftApplied, 409},
+	{domain.ErrUnavailable, 409},
+	{domain.ErrInvalidRule, 422},
+	{domain.ErrInvalidCalendar, 422},
+	{domain.ErrInvalidTimetable, 422},
+	{domain.ErrInvalidAvailability, 422},
+	{domain.ErrInvalidSubstitution, 422},
+	{domain.ErrInvalidOccurrence, 422},
+	{domain.ErrInvalidJournal, 422},
+	{engine.ErrVetoed, 409}, // последней: обработчик мог отклонить операцию одной из ошибок выше
+}
+
+// [RU] ErrConflictOrInternal создает ответ для конфликтов (409), ошибок из conflictStatuses, отсутствующих ресурсов (404) или внутренних ошибок (500) <--->
+// [ENG] ErrConflictOrInternal creates response for conflicts (409), errors from conflictStatuses, not found (404) or internal errors (500)
+func ErrConflictOrInternal(err error) render.Renderer {
+	var conflict *domain.ConflictError
+	if errors.As(err, &conflict) {
+		return &ErrResponse{
+			Err:            err,
+			HTTPStatusCode: 409,
+			StatusText:     "Conflict",
+			ErrorText:      err.Error(),
+			Conflicts:      ConflictDetails(conflict.Conflicts),
+		}
+	}
+	for _, c := range conflictStatuses {
+		if !errors.Is(err, c.err) {
+			continue
+		}
+		if c.status == 422 {
+			return ErrValidation(err)
+		}
+		return &ErrResponse{
+			Err:            err,
+			HTTPStatusCode: c.status,
+			StatusText:     "Conflict",
+			ErrorText:      err.Error(),
+		}
+	}
+	return ErrNotFoundOrInternal(err)
+}
+
+// [RU] ErrTrashOrInternal создает ответ для операций корзины: сущность без мягкого удаления (400), ссылки на строку (409),
+// строки нет в корзине (404) или внутренняя ошибка (500) <--->
+// [ENG] ErrTrashOrInternal creates response for trash operations: entity without soft delete (400), referenced row (409),
+// row not in the trash (404) or internal error (500)
+func ErrTrashOrInternal(err error) render.Renderer {
+	if errors.Is(err, db.ErrInvalidFilter) {
+		return ErrInvalidRequest(err)
+	}
+	return ErrConflictOrInternal(err)
+}
+
+// [RU] ErrUnauthorized создает ответ для запросов без аутентификации (401) <--->
+// [ENG] ErrUnauthorized creates response for unauthenticated requests (401)
+func ErrUnauthorized(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 401,
+		StatusText:     "Unauthorized",
+		ErrorText:      err.Error(),
+	}
+}
+
+// [RU] ErrForbidden создает ответ для запросов без нужной роли (403) <--->
+// [ENG] ErrForbidden creates response for requests without the required role (403)
+func ErrForbidden(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 403,
+		StatusText:     "Forbidden",
+		ErrorText:      err.Error(),
+	}
+}
+
 // [RU] ErrInternalServer создает ответ для внутренних ошибок сервера (500) <--->
 // [ENG] ErrInternalServer creates response for internal server errors (500)
 func ErrInternalServer(err error) render.Renderer {
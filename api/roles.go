@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/SerMoskvin/access"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chi/render"
+)
+
+// RoleAdmin роль администратора из config/perm_config.yml
+const RoleAdmin = "admin"
+
+// [RU] RequireRole пропускает запрос, только если роль из JWT входит в roles; используется для операций,
+// которые не покрываются правами раздела (can_read/can_write) <--->
+// [ENG] RequireRole lets the request through only if the JWT role is one of roles; used for operations
+// that section permissions (can_read/can_write) do not cover
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(access.UserClaimsKey).(jwt.MapClaims)
+			if !ok {
+				render.Render(w, r, ErrUnauthorized(errors.New("authentication required")))
+				return
+			}
+			role, _ := claims["role"].(string)
+			if !slices.Contains(roles, role) {
+				render.Render(w, r, ErrForbidden(errors.New("operation is not allowed for this role")))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
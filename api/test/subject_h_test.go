@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"GO_Music/api/handlers"
+	"GO_Music/db/memory"
+	"GO_Music/db/repositories"
+	"GO_Music/domain"
+	"GO_Music/engine"
+	"GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSubjectRoutes маршруты предметов поверх пустого хранилища в памяти
+func newSubjectRoutes(t *testing.T) (chi.Router, *managers.Managers) {
+	t.Helper()
+
+	levelLogger, err := logger.NewLevel("../../config/logger_config.yml")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { levelLogger.Sync() })
+
+	store := memory.NewStore()
+	mgrs := managers.NewManagers(store.DB(), repositories.NewMemoryRepositories(store), levelLogger, nil)
+	return handlers.NewSubjectHandler(mgrs.Subject, levelLogger).Routes(), mgrs
+}
+
+// serve выполняет запрос к маршрутам; headers - пары имя, значение
+func serve(routes chi.Router, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	routes.ServeHTTP(w, r)
+	return w
+}
+
+func TestSubjectHandler_Update(t *testing.T) {
+	routes, mgrs := newSubjectRoutes(t)
+	ctx := context.Background()
+
+	subject := &domain.Subject{SubjectName: "Сольфеджио", SubjectType: "Теория", ShortDesc: "Теория музыки"}
+	if err := mgrs.Subject.Create(ctx, subject); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	target := "/" + strconv.Itoa(subject.SubjectID)
+	body := `{"short_desc": "Основы теории музыки"}`
+
+	t.Run("PUT without If-Match", func(t *testing.T) {
+		w := serve(routes, http.MethodPut, target, body)
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("PUT with a stale version", func(t *testing.T) {
+		w := serve(routes, http.MethodPut, target, body, "If-Match", `"0"`)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("PUT with the current version", func(t *testing.T) {
+		w := serve(routes, http.MethodPut, target, body, "If-Match", `"1"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("PATCH without If-Match", func(t *testing.T) {
+		w := serve(routes, http.MethodPatch, target, `{"subject_type": "Практика"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	stored, err := mgrs.Subject.GetByID(ctx, subject.SubjectID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Основы теории музыки", stored.ShortDesc)
+		assert.Equal(t, "Практика", stored.SubjectType)
+	}
+}
+
+func TestSubjectHandler_Delete(t *testing.T) {
+	routes, mgrs := newSubjectRoutes(t)
+	ctx := context.Background()
+
+	errProtected := errors.New("subject is protected")
+	mgrs.Subject.Events().Subscribe(engine.BeforeDelete, func(ctx context.Context, e engine.Event[domain.Subject]) error {
+		if e.Old.SubjectName == "Сольфеджио" {
+			return errProtected
+		}
+		return nil
+	})
+
+	protected := &domain.Subject{SubjectName: "Сольфеджио", SubjectType: "Теория", ShortDesc: "Теория музыки"}
+	other := &domain.Subject{SubjectName: "Хор", SubjectType: "Практика", ShortDesc: "Хоровое пение"}
+	for _, subject := range []*domain.Subject{protected, other} {
+		if err := mgrs.Subject.Create(ctx, subject); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	t.Run("Vetoed delete is a conflict", func(t *testing.T) {
+		w := serve(routes, http.MethodDelete, "/"+strconv.Itoa(protected.SubjectID), "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var body struct {
+			Error string `json:"error"`
+		}
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body)) {
+			assert.Contains(t, body.Error, errProtected.Error())
+		}
+
+		exists, err := mgrs.Subject.Exists(ctx, protected.SubjectID)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Delete without veto", func(t *testing.T) {
+		w := serve(routes, http.MethodDelete, "/"+strconv.Itoa(other.SubjectID), "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		exists, err := mgrs.Subject.Exists(ctx, other.SubjectID)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
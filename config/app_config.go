@@ -0,0 +1,323 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration - time.Duration, который читается из YAML и окружения в виде строки ("20m", "24h")
+type Duration time.Duration
+
+// ParseDuration разбирает строку длительности с понятным сообщением об ошибке
+func ParseDuration(s string) (Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (expected e.g. \"20m\" or \"24h\")", s)
+	}
+	return Duration(d), nil
+}
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+// JWTConfig параметры выпуска и ротации JWT
+type JWTConfig struct {
+	Secret         string   `yaml:"secret" env:"JWT_SECRET"`
+	RotationPeriod Duration `yaml:"rotation_period" env:"JWT_ROTATION_PERIOD"`
+	TTL            Duration `yaml:"ttl" env:"JWT_TTL"`
+	OldKeysToKeep  int      `yaml:"old_keys_to_keep" env:"JWT_OLD_KEYS_TO_KEEP"`
+}
+
+// PermissionsConfig путь к файлу ролей и разделов
+type PermissionsConfig struct {
+	Path string `yaml:"path" env:"PERMISSIONS_PATH"`
+}
+
+// PasswordConfig параметры хеширования паролей
+type PasswordConfig struct {
+	Cost int `yaml:"cost" env:"PASSWORD_COST"`
+}
+
+// CacheConfig время жизни кешей авторизации
+type CacheConfig struct {
+	TokenTTL      Duration `yaml:"token_ttl" env:"CACHE_TOKEN_TTL"`
+	PasswordTTL   Duration `yaml:"password_ttl" env:"CACHE_PASSWORD_TTL"`
+	PermissionTTL Duration `yaml:"permission_ttl" env:"CACHE_PERMISSION_TTL"`
+}
+
+// LoggerConfig путь к конфигурации уровневого логгера
+type LoggerConfig struct {
+	ConfigPath string `yaml:"config_path" env:"LOGGER_CONFIG_PATH"`
+}
+
+// PageSizeConfig размеры страниц списковых эндпоинтов
+type PageSizeConfig struct {
+	DefaultPageSize int `yaml:"default_page_size"`
+	MaxPageSize     int `yaml:"max_page_size"`
+}
+
+// HandlersConfig размеры страниц по умолчанию и переопределения для отдельных ресурсов
+type HandlersConfig struct {
+	DefaultPageSize int                       `yaml:"default_page_size" env:"HANDLERS_DEFAULT_PAGE_SIZE"`
+	MaxPageSize     int                       `yaml:"max_page_size" env:"HANDLERS_MAX_PAGE_SIZE"`
+	Overrides       map[string]PageSizeConfig `yaml:"overrides"`
+	CursorSecret    string                    `yaml:"cursor_secret" env:"HANDLERS_CURSOR_SECRET"`
+}
+
+// PageSizes возвращает размеры страниц для ресурса (например "attendances")
+func (c HandlersConfig) PageSizes(resource string) PageSizeConfig {
+	if o, ok := c.Overrides[resource]; ok {
+		return o
+	}
+	return PageSizeConfig{DefaultPageSize: c.DefaultPageSize, MaxPageSize: c.MaxPageSize}
+}
+
+// OutboxConfig параметры фоновой доставки сообщений outbox; 0 - значение по умолчанию диспетчера
+type OutboxConfig struct {
+	PollInterval Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize    int      `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	MaxAttempts  int      `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+	RetryBase    Duration `yaml:"retry_base" env:"OUTBOX_RETRY_BASE"`
+	RetryMax     Duration `yaml:"retry_max" env:"OUTBOX_RETRY_MAX"`
+	Lease        Duration `yaml:"lease" env:"OUTBOX_LEASE"`
+}
+
+// AppConfig единая конфигурация приложения
+type AppConfig struct {
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	JWT         JWTConfig         `yaml:"jwt"`
+	Permissions PermissionsConfig `yaml:"permissions"`
+	Password    PasswordConfig    `yaml:"password"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Logger      LoggerConfig      `yaml:"logger"`
+	Handlers    HandlersConfig    `yaml:"handlers"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+}
+
+// accessConfig - подмножество AppConfig в формате, который ожидает access.NewAuthenticator
+type accessConfig struct {
+	JWT         JWTConfig         `yaml:"jwt"`
+	Permissions PermissionsConfig `yaml:"permissions"`
+	Password    PasswordConfig    `yaml:"password"`
+	Cache       CacheConfig       `yaml:"cache"`
+}
+
+// [RU] LoadAppConfig читает YAML, применяет переменные окружения и проверяет конфигурацию <--->
+// [ENG] LoadAppConfig reads YAML, applies environment overrides and validates the config
+func LoadAppConfig(path string) (*AppConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg AppConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.applyHandlerOverridesEnv(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	cfg.resolvePaths(filepath.Dir(path))
+	return &cfg, nil
+}
+
+// DB возвращает конфигурацию в формате, который принимает db.InitPostgresDB
+func (c *AppConfig) DB() *DBConfig {
+	return &DBConfig{Server: c.Server, Database: c.Database}
+}
+
+// CursorSecret ключ подписи курсоров списков; если не задан, используется jwt.secret
+func (c *AppConfig) CursorSecret() string {
+	if c.Handlers.CursorSecret != "" {
+		return c.Handlers.CursorSecret
+	}
+	return c.JWT.Secret
+}
+
+// [RU] WriteAccessConfig сохраняет jwt/permissions/password/cache в файл для access.NewAuthenticator <--->
+// [ENG] WriteAccessConfig writes the jwt/permissions/password/cache sections to a file for access.NewAuthenticator
+func (c *AppConfig) WriteAccessConfig(path string) error {
+	data, err := yaml.Marshal(accessConfig{
+		JWT:         c.JWT,
+		Permissions: c.Permissions,
+		Password:    c.Password,
+		Cache:       c.Cache,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal access config: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// applyHandlerOverridesEnv переопределяет размеры страниц ресурсов из HANDLERS_<RESOURCE>_*
+func (c *AppConfig) applyHandlerOverridesEnv() error {
+	for resource, sizes := range c.Handlers.Overrides {
+		prefix := "HANDLERS_" + strings.ToUpper(strings.ReplaceAll(resource, "-", "_"))
+		for name, target := range map[string]*int{
+			prefix + "_DEFAULT_PAGE_SIZE": &sizes.DefaultPageSize,
+			prefix + "_MAX_PAGE_SIZE":     &sizes.MaxPageSize,
+		} {
+			raw, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("env %s: invalid integer %q", name, raw)
+			}
+			*target = n
+		}
+		c.Handlers.Overrides[resource] = sizes
+	}
+	return nil
+}
+
+var windowsAbsPath = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\)`)
+
+// [RU] Validate проверяет обязательные поля и допустимые значения <--->
+// [ENG] Validate checks required fields and allowed values
+func (c *AppConfig) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		add("server.port must be in range 1..65535, got %d (SERVER_PORT)", c.Server.Port)
+	}
+
+	if c.Database.Host == "" {
+		add("database.host is required (DB_HOST)")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		add("database.port must be in range 1..65535, got %d (DB_PORT)", c.Database.Port)
+	}
+	if c.Database.User == "" {
+		add("database.user is required (DB_USER)")
+	}
+	if c.Database.DBName == "" {
+		add("database.dbname is required (DB_NAME)")
+	}
+
+	if strings.TrimSpace(c.JWT.Secret) == "" {
+		add("jwt.secret is required (JWT_SECRET)")
+	}
+	if c.JWT.TTL <= 0 {
+		add("jwt.ttl must be a positive duration (JWT_TTL)")
+	}
+	if c.JWT.RotationPeriod <= 0 {
+		add("jwt.rotation_period must be a positive duration (JWT_ROTATION_PERIOD)")
+	}
+	if c.JWT.OldKeysToKeep < 0 {
+		add("jwt.old_keys_to_keep must not be negative (JWT_OLD_KEYS_TO_KEEP)")
+	}
+
+	if c.Permissions.Path == "" {
+		add("permissions.path is required (PERMISSIONS_PATH)")
+	} else if runtime.GOOS != "windows" && windowsAbsPath.MatchString(c.Permissions.Path) {
+		add("permissions.path %q is a Windows-only absolute path; use a path relative to the config file (PERMISSIONS_PATH)", c.Permissions.Path)
+	}
+
+	if c.Password.Cost < 4 || c.Password.Cost > 31 {
+		add("password.cost must be in range 4..31, got %d (PASSWORD_COST)", c.Password.Cost)
+	}
+
+	if c.Cache.TokenTTL <= 0 {
+		add("cache.token_ttl must be a positive duration (CACHE_TOKEN_TTL)")
+	}
+	if c.Cache.PasswordTTL <= 0 {
+		add("cache.password_ttl must be a positive duration (CACHE_PASSWORD_TTL)")
+	}
+	if c.Cache.PermissionTTL <= 0 {
+		add("cache.permission_ttl must be a positive duration (CACHE_PERMISSION_TTL)")
+	}
+
+	if c.Logger.ConfigPath == "" {
+		add("logger.config_path is required (LOGGER_CONFIG_PATH)")
+	} else if runtime.GOOS != "windows" && windowsAbsPath.MatchString(c.Logger.ConfigPath) {
+		add("logger.config_path %q is a Windows-only absolute path (LOGGER_CONFIG_PATH)", c.Logger.ConfigPath)
+	}
+
+	if err := validatePageSizes("handlers", PageSizeConfig{
+		DefaultPageSize: c.Handlers.DefaultPageSize,
+		MaxPageSize:     c.Handlers.MaxPageSize,
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	for resource, sizes := range c.Handlers.Overrides {
+		if err := validatePageSizes("handlers.overrides."+resource, sizes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, f := range []struct {
+		name  string
+		value int64
+	}{
+		{"outbox.poll_interval (OUTBOX_POLL_INTERVAL)", int64(c.Outbox.PollInterval)},
+		{"outbox.batch_size (OUTBOX_BATCH_SIZE)", int64(c.Outbox.BatchSize)},
+		{"outbox.max_attempts (OUTBOX_MAX_ATTEMPTS)", int64(c.Outbox.MaxAttempts)},
+		{"outbox.retry_base (OUTBOX_RETRY_BASE)", int64(c.Outbox.RetryBase)},
+		{"outbox.retry_max (OUTBOX_RETRY_MAX)", int64(c.Outbox.RetryMax)},
+		{"outbox.lease (OUTBOX_LEASE)", int64(c.Outbox.Lease)},
+	} {
+		if f.value < 0 {
+			add("%s must not be negative", f.name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validatePageSizes(section string, sizes PageSizeConfig) error {
+	if sizes.DefaultPageSize <= 0 {
+		return fmt.Errorf("%s.default_page_size must be positive, got %d", section, sizes.DefaultPageSize)
+	}
+	if sizes.MaxPageSize < sizes.DefaultPageSize {
+		return fmt.Errorf("%s.max_page_size (%d) must not be less than default_page_size (%d)",
+			section, sizes.MaxPageSize, sizes.DefaultPageSize)
+	}
+	return nil
+}
+
+// resolvePaths делает относительные пути абсолютными относительно каталога конфига
+func (c *AppConfig) resolvePaths(baseDir string) {
+	for _, p := range []*string{&c.Permissions.Path, &c.Logger.ConfigPath} {
+		if *p != "" && !filepath.IsAbs(*p) && !windowsAbsPath.MatchString(*p) {
+			*p = filepath.Join(baseDir, *p)
+		}
+	}
+}
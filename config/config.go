@@ -1,24 +1,30 @@
 package config
 
 import (
+	"fmt"
 	"os"
 
 	"gopkg.in/yaml.v3"
 )
 
+// ServerConfig параметры HTTP-сервера
+type ServerConfig struct {
+	Port int `yaml:"port" env:"SERVER_PORT"`
+}
+
+// DatabaseConfig параметры подключения к PostgreSQL
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	DBName   string `yaml:"dbname" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE"`
+}
+
 type DBConfig struct {
-	Server struct {
-		Port int `yaml:"port"`
-	} `yaml:"server"`
-
-	Database struct {
-		Host     string `yaml:"host"`
-		Port     int    `yaml:"port"`
-		User     string `yaml:"user"`
-		Password string `yaml:"password"`
-		DBName   string `yaml:"dbname"`
-		SSLMode  string `yaml:"sslmode"`
-	} `yaml:"database"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
 }
 
 func LoadDBConfig(path string) (*DBConfig, error) {
@@ -33,5 +39,9 @@ func LoadDBConfig(path string) (*DBConfig, error) {
 		return nil, err
 	}
 
+	if err := applyEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// [RU] applyEnv переопределяет поля структуры значениями из переменных окружения по тегу `env` <--->
+// [ENG] applyEnv overrides struct fields with environment variables named by the `env` tag
+func applyEnv(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("applyEnv: target must be pointer to struct")
+	}
+	return applyEnvValue(v.Elem())
+}
+
+func applyEnvValue(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+
+		name := field.Tag.Get("env")
+		if name == "" {
+			if fv.Kind() == reflect.Struct {
+				if err := applyEnvValue(fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(fv, raw); err != nil {
+			return fmt.Errorf("env %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setFromString записывает строковое значение в поле с учетом его типа
+func setFromString(fv reflect.Value, raw string) error {
+	if fv.Type() == reflect.TypeOf(Duration(0)) {
+		d, err := ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		fv.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GO_Music/config"
+)
+
+const validConfig = `
+server:
+  port: 8080
+database:
+  host: "localhost"
+  port: 5432
+  user: "postgres"
+  password: "student"
+  dbname: "KP"
+  sslmode: "disable"
+jwt:
+  secret: "secret"
+  rotation_period: "24h"
+  ttl: "20m"
+  old_keys_to_keep: 2
+permissions:
+  path: "perm_config.yml"
+password:
+  cost: 12
+cache:
+  token_ttl: "20m"
+  password_ttl: "5m"
+  permission_ttl: "5m"
+logger:
+  config_path: "logger_config.yml"
+handlers:
+  default_page_size: 20
+  max_page_size: 100
+  overrides:
+    attendances:
+      default_page_size: 50
+      max_page_size: 200
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadAppConfig_ProjectFile(t *testing.T) {
+	cfg, err := config.LoadAppConfig("../config.yml")
+	if err != nil {
+		t.Fatalf("LoadAppConfig failed: %v", err)
+	}
+	if _, err := os.Stat(cfg.Permissions.Path); err != nil {
+		t.Errorf("permissions.path should resolve to existing file, got %q: %v", cfg.Permissions.Path, err)
+	}
+	if _, err := os.Stat(cfg.Logger.ConfigPath); err != nil {
+		t.Errorf("logger.config_path should resolve to existing file, got %q: %v", cfg.Logger.ConfigPath, err)
+	}
+}
+
+func TestLoadAppConfig_Valid(t *testing.T) {
+	path := writeConfig(t, validConfig)
+
+	cfg, err := config.LoadAppConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAppConfig failed: %v", err)
+	}
+
+	if cfg.JWT.TTL.Std() != 20*time.Minute {
+		t.Errorf("Expected jwt.ttl 20m, got %v", cfg.JWT.TTL.Std())
+	}
+	if cfg.Permissions.Path != filepath.Join(filepath.Dir(path), "perm_config.yml") {
+		t.Errorf("Expected permissions.path to be resolved against config dir, got %q", cfg.Permissions.Path)
+	}
+	if got := cfg.Handlers.PageSizes("attendances"); got.DefaultPageSize != 50 || got.MaxPageSize != 200 {
+		t.Errorf("Expected attendances override 50/200, got %+v", got)
+	}
+	if got := cfg.Handlers.PageSizes("students"); got.DefaultPageSize != 20 || got.MaxPageSize != 100 {
+		t.Errorf("Expected default page sizes 20/100, got %+v", got)
+	}
+	if db := cfg.DB(); db.Database.DBName != "KP" || db.Server.Port != 8080 {
+		t.Errorf("Unexpected DB config: %+v", db)
+	}
+	if got := cfg.CursorSecret(); got != "secret" {
+		t.Errorf("Expected cursor secret to fall back to jwt.secret, got %q", got)
+	}
+}
+
+func TestLoadAppConfig_EnvOverrides(t *testing.T) {
+	path := writeConfig(t, validConfig)
+
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_PORT", "6543")
+	t.Setenv("JWT_TTL", "1h")
+	t.Setenv("PASSWORD_COST", "10")
+	t.Setenv("HANDLERS_ATTENDANCES_MAX_PAGE_SIZE", "500")
+	t.Setenv("HANDLERS_CURSOR_SECRET", "cursor-secret")
+	t.Setenv("OUTBOX_RETRY_MAX", "30m")
+
+	cfg, err := config.LoadAppConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAppConfig failed: %v", err)
+	}
+
+	if cfg.Database.Host != "db.internal" || cfg.Database.Port != 6543 {
+		t.Errorf("Expected DB env overrides, got %s:%d", cfg.Database.Host, cfg.Database.Port)
+	}
+	if cfg.JWT.TTL.Std() != time.Hour {
+		t.Errorf("Expected JWT_TTL override 1h, got %v", cfg.JWT.TTL.Std())
+	}
+	if cfg.Password.Cost != 10 {
+		t.Errorf("Expected PASSWORD_COST override 10, got %d", cfg.Password.Cost)
+	}
+	if got := cfg.Handlers.PageSizes("attendances").MaxPageSize; got != 500 {
+		t.Errorf("Expected attendances max page size 500, got %d", got)
+	}
+	if got := cfg.CursorSecret(); got != "cursor-secret" {
+		t.Errorf("Expected HANDLERS_CURSOR_SECRET override, got %q", got)
+	}
+	if got := cfg.Outbox.RetryMax.Std(); got != 30*time.Minute {
+		t.Errorf("Expected OUTBOX_RETRY_MAX override 30m, got %v", got)
+	}
+}
+
+func TestLoadAppConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "Bad duration in file",
+			content: strings.Replace(validConfig, `ttl: "20m"`, `ttl: "20 minutes"`, 1),
+			wantErr: `invalid duration "20 minutes"`,
+		},
+		{
+			name:    "Bad duration in env",
+			content: validConfig,
+			env:     map[string]string{"CACHE_TOKEN_TTL": "soon"},
+			wantErr: "env CACHE_TOKEN_TTL",
+		},
+		{
+			name:    "Missing secret",
+			content: strings.Replace(validConfig, `secret: "secret"`, `secret: ""`, 1),
+			wantErr: "jwt.secret is required",
+		},
+		{
+			name:    "Windows-only permissions path",
+			content: strings.Replace(validConfig, `path: "perm_config.yml"`, `path: "C:/Users/Joker/perm_config.yml"`, 1),
+			wantErr: "Windows-only absolute path",
+		},
+		{
+			name:    "Max page size below default",
+			content: strings.Replace(validConfig, "max_page_size: 100", "max_page_size: 10", 1),
+			wantErr: "handlers.max_page_size",
+		},
+		{
+			name:    "Negative outbox batch size",
+			content: validConfig,
+			env:     map[string]string{"OUTBOX_BATCH_SIZE": "-1"},
+			wantErr: "outbox.batch_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := config.LoadAppConfig(writeConfig(t, tt.content))
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadDBConfig_EnvOverrides(t *testing.T) {
+	t.Setenv("DB_HOST", "ci-postgres")
+	t.Setenv("DB_NAME", "ci_db")
+
+	cfg, err := config.LoadDBConfig("../DB_config.yml")
+	if err != nil {
+		t.Fatalf("LoadDBConfig failed: %v", err)
+	}
+	if cfg.Database.Host != "ci-postgres" || cfg.Database.DBName != "ci_db" {
+		t.Errorf("Expected env overrides, got host=%s dbname=%s", cfg.Database.Host, cfg.Database.DBName)
+	}
+}
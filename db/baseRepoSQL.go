@@ -12,6 +12,9 @@ type Filter struct {
 	Preloads   []string
 	Search     string
 	Conditions []Condition
+	Cursor     *Cursor // курсорный режим: строки после границы вместо Offset
+	Trash      bool    // только мягко удаленные строки (корзина)
+	SkipLocked bool    // FOR UPDATE SKIP LOCKED: строки блокируются до конца транзакции, занятые другими пропускаются
 }
 
 type Condition struct {
@@ -20,11 +23,16 @@ type Condition struct {
 	Value    interface{}
 }
 
-// Repository интерфейс
+// [RU] Repository интерфейс. Для сущностей с колонкой deleted_at Delete - мягкое удаление:
+// строка скрывается из выборок, Restore возвращает ее, Purge удаляет окончательно <--->
+// [ENG] Repository interface. For entities with the deleted_at column Delete is a soft delete:
+// the row is hidden from reads, Restore brings it back, Purge removes it for good
 type Repository[T any, ID comparable] interface {
 	Create(ctx context.Context, entity *T) error
 	Update(ctx context.Context, entity *T) error
 	Delete(ctx context.Context, id ID) error
+	Restore(ctx context.Context, id ID) error
+	Purge(ctx context.Context, id ID) error
 	GetByID(ctx context.Context, id ID) (*T, error)
 	GetByIDs(ctx context.Context, ids []ID) ([]*T, error)
 	List(ctx context.Context, filter Filter) ([]*T, error)
@@ -32,3 +40,12 @@ type Repository[T any, ID comparable] interface {
 	Exists(ctx context.Context, id ID) (bool, error)
 	WithTx(tx *sql.Tx) Repository[T, ID]
 }
+
+// [RU] SQLRepository - Repository с доступом к произвольным запросам для специфичных методов репозиториев <--->
+// [ENG] SQLRepository - Repository with raw query access used by entity-specific repository methods
+type SQLRepository[T any, ID comparable] interface {
+	Repository[T, ID]
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
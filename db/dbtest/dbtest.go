@@ -0,0 +1,185 @@
+// Package dbtest поднимает изолированную схему PostgreSQL для интеграционных тестов:
+// применяет миграции, наполняет детерминированными фикстурами и удаляет все после теста.
+//
+// Источник сервера (по порядку):
+//   - GO_MUSIC_TEST_DSN - DSN существующего сервера ("postgres://..." или "host=... dbname=...");
+//   - локальные initdb/pg_ctl (из PATH или из каталога GO_MUSIC_TEST_PG_BIN) - временный кластер на свободном порту.
+//
+// Если ни то ни другое недоступно, тест пропускается.
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"GO_Music/db/migrations"
+
+	_ "github.com/lib/pq"
+)
+
+const (
+	// EnvDSN DSN сервера, на котором создаются тестовые схемы
+	EnvDSN = "GO_MUSIC_TEST_DSN"
+	// EnvPGBin каталог с initdb/pg_ctl, если их нет в PATH
+	EnvPGBin = "GO_MUSIC_TEST_PG_BIN"
+
+	setupTimeout = 30 * time.Second
+)
+
+var errNoServer = errors.New("no PostgreSQL available: set " + EnvDSN + " or install postgres (initdb, pg_ctl)")
+
+var (
+	serverOnce sync.Once
+	serverDSN  string
+	serverErr  error
+	admin      *sql.DB
+	local      *localServer
+)
+
+// [RU] Main запускает тесты пакета и останавливает локальный кластер, если он был поднят.
+// Вызывается из TestMain: os.Exit(dbtest.Main(m)) <--->
+// [ENG] Main runs the package tests and stops the local cluster if one was started.
+// Call it from TestMain: os.Exit(dbtest.Main(m))
+func Main(m *testing.M) int {
+	code := m.Run()
+	if admin != nil {
+		admin.Close()
+	}
+	if local != nil {
+		if err := local.stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "dbtest: %v\n", err)
+		}
+	}
+	return code
+}
+
+// [RU] New создает пустую схему с примененными миграциями и возвращает подключение к ней.
+// Схема и подключение удаляются через t.Cleanup, поэтому тесты могут идти параллельно <--->
+// [ENG] New creates an empty schema with all migrations applied and returns a connection bound to it.
+// The schema and the connection are removed via t.Cleanup, so tests can run in parallel
+func New(t testing.TB) *sql.DB {
+	t.Helper()
+
+	dsn := server(t)
+
+	schema, err := schemaName()
+	if err != nil {
+		t.Fatalf("dbtest: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("dbtest: failed to create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+		defer cancel()
+		if _, err := admin.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+schema+" CASCADE"); err != nil {
+			t.Errorf("dbtest: failed to drop schema %s: %v", schema, err)
+		}
+	})
+
+	sqlDB, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("dbtest: failed to open db: %v", err)
+	}
+	// Регистрируется после DROP SCHEMA, значит выполнится раньше него (LIFO)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		t.Fatalf("dbtest: failed to ping db: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		t.Fatalf("dbtest: failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("dbtest: failed to apply migrations: %v", err)
+	}
+
+	return sqlDB
+}
+
+// NewSeeded - New + Seed
+func NewSeeded(t testing.TB) (*sql.DB, *Fixtures) {
+	t.Helper()
+	sqlDB := New(t)
+	return sqlDB, Seed(t, sqlDB)
+}
+
+// server возвращает DSN сервера, поднимая его при первом обращении
+func server(t testing.TB) string {
+	t.Helper()
+
+	serverOnce.Do(func() {
+		serverDSN, serverErr = resolveServer()
+		if serverErr != nil {
+			return
+		}
+		admin, serverErr = sql.Open("postgres", serverDSN)
+		if serverErr == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+			defer cancel()
+			serverErr = admin.PingContext(ctx)
+		}
+	})
+
+	if errors.Is(serverErr, errNoServer) {
+		t.Skipf("dbtest: %v", serverErr)
+	}
+	if serverErr != nil {
+		t.Fatalf("dbtest: %v", serverErr)
+	}
+	return serverDSN
+}
+
+func resolveServer() (string, error) {
+	if dsn := strings.TrimSpace(os.Getenv(EnvDSN)); dsn != "" {
+		return dsn, nil
+	}
+
+	srv, err := startLocal(os.Getenv(EnvPGBin))
+	if err != nil {
+		return "", err
+	}
+	local = srv
+	return srv.dsn, nil
+}
+
+// schemaName генерирует уникальное имя схемы, допустимое как идентификатор без кавычек
+func schemaName() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate schema name: %w", err)
+	}
+	return fmt.Sprintf("gomusic_test_%d_%s", os.Getpid(), hex.EncodeToString(buf)), nil
+}
+
+// withSearchPath добавляет search_path к DSN в URL- или key=value-формате;
+// public остается в пути ради расширений (pg_trgm), таблицы находятся в схеме теста раньше
+func withSearchPath(dsn, schema string) string {
+	path := schema + ",public"
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", path)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + path
+}
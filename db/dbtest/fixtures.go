@@ -0,0 +1,271 @@
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"GO_Music/domain"
+)
+
+// PerType количество фикстур каждого типа
+const PerType = 3
+
+// FixturePassword значение поля password у пользователей-фикстур (хранится как есть, без хеша)
+const FixturePassword = "fixture-password"
+
+// Fixtures детерминированный набор записей всех доменных типов.
+// Элемент i каждого среза связан с элементом i остальных: Students[i] учится в Groups[i] по Programms[i],
+// Lessons[i] ведет Employees[i] по Subjects[i] в Audiences[i] и т.д.
+// Users ни с кем не связаны, чтобы тесты могли свободно назначать их сотрудникам и студентам.
+type Fixtures struct {
+	Users                 []*domain.User
+	Programms             []*domain.Programm
+	Subjects              []*domain.Subject
+	Audiences             []*domain.Audience
+	Employees             []*domain.Employee
+	Groups                []*domain.StudyGroup
+	Students              []*domain.Student
+	Instruments           []*domain.Instrument
+	Lessons               []*domain.Lesson
+	Schedules             []*domain.Schedule
+	Assessments           []*domain.StudentAssessment
+	Attendances           []*domain.StudentAttendance
+	ProgrammDistributions []*domain.ProgrammDistribution
+	SubjectDistributions  []*domain.SubjectDistribution
+}
+
+// BaseDate дата, от которой отсчитываются все даты фикстур
+var BaseDate = time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+
+// [RU] Seed наполняет схему фикстурами (PerType записей каждого типа) и возвращает их с проставленными ID <--->
+// [ENG] Seed fills the schema with fixtures (PerType rows of each type) and returns them with IDs set
+func Seed(t testing.TB, sqlDB *sql.DB) *Fixtures {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("dbtest: failed to begin seed tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	s := &seeder{ctx: ctx, tx: tx}
+	fx := s.seed()
+	if s.err != nil {
+		t.Fatalf("dbtest: failed to seed fixtures: %v", s.err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("dbtest: failed to commit fixtures: %v", err)
+	}
+	return fx
+}
+
+// seeder запоминает первую ошибку, чтобы не проверять каждую вставку
+type seeder struct {
+	ctx context.Context
+	tx  *sql.Tx
+	err error
+}
+
+func (s *seeder) seed() *Fixtures {
+	fx := &Fixtures{}
+
+	for i := 0; i < PerType; i++ {
+		n := strconv.Itoa(i + 1)
+
+		u := &domain.User{
+			Login:            "fixture_user_" + n,
+			Password:         FixturePassword,
+			Role:             "student",
+			Surname:          "Фикстурова",
+			Name:             "Пользователь " + n,
+			RegistrationDate: BaseDate,
+			Email:            "fixture" + n + "@example.com",
+		}
+		u.UserID = s.insert("users", "user_id",
+			"login", u.Login, "password", u.Password, "role", u.Role, "surname", u.Surname,
+			"name", u.Name, "registration_date", u.RegistrationDate, "email", u.Email)
+		fx.Users = append(fx.Users, u)
+
+		instrument := "Инструмент " + n
+		description := "Программа-фикстура " + n
+		p := &domain.Programm{
+			ProgrammName:           "Программа " + n,
+			ProgrammType:           "Общеразвивающая",
+			Duration:               12 * (i + 1),
+			Instrument:             &instrument,
+			Description:            &description,
+			StudyLoad:              4 + i,
+			FinalCertificationForm: "Экзамен",
+		}
+		p.MusprogrammID = s.insert("programm", "musprogramm_id",
+			"programm_name", p.ProgrammName, "programm_type", p.ProgrammType, "duration", p.Duration,
+			"instrument", p.Instrument, "description", p.Description, "study_load", p.StudyLoad,
+			"final_certification_form", p.FinalCertificationForm)
+		fx.Programms = append(fx.Programms, p)
+
+		subj := &domain.Subject{
+			SubjectName: "Предмет " + n,
+			SubjectType: "Групповой",
+			ShortDesc:   "Предмет-фикстура " + n,
+		}
+		subj.SubjectID = s.insert("subject", "subject_id",
+			"subject_name", subj.SubjectName, "subject_type", subj.SubjectType, "short_desc", subj.ShortDesc)
+		fx.Subjects = append(fx.Subjects, subj)
+
+		a := &domain.Audience{
+			Name:        "Аудитория " + n,
+			AudinType:   "Класс",
+			AudinNumber: "F-10" + n,
+			Capacity:    10 * (i + 1),
+		}
+		a.AudienceID = s.insert("audience", "audience_id",
+			"name", a.Name, "audin_type", a.AudinType, "audin_number", a.AudinNumber, "capacity", a.Capacity)
+		fx.Audiences = append(fx.Audiences, a)
+
+		fatherName := "Фикстурович"
+		e := &domain.Employee{
+			Surname:        "Преподаватель",
+			Name:           "Сотрудник " + n,
+			FatherName:     &fatherName,
+			Birthday:       BaseDate.AddDate(-40+i, 0, 0),
+			PhoneNumber:    "7900000000" + n,
+			Job:            "Преподаватель",
+			WorkExperience: 5 * (i + 1),
+		}
+		e.EmployeeID = s.insert("employee", "employee_id",
+			"surname", e.Surname, "name", e.Name, "father_name", e.FatherName, "birthday", e.Birthday,
+			"phone_number", e.PhoneNumber, "job", e.Job, "work_experience", e.WorkExperience)
+		fx.Employees = append(fx.Employees, e)
+
+		g := &domain.StudyGroup{
+			MusProgrammID:    p.MusprogrammID,
+			GroupName:        "Группа " + n,
+			StudyYear:        i + 1,
+			NumberOfStudents: 1,
+		}
+		g.GroupID = s.insert("study_group", "group_id",
+			"mus_programm_id", g.MusProgrammID, "group_name", g.GroupName, "study_year", g.StudyYear,
+			"number_of_students", g.NumberOfStudents)
+		fx.Groups = append(fx.Groups, g)
+
+		phone := "7911000000" + n
+		st := &domain.Student{
+			Surname:       "Студентов",
+			Name:          "Студент " + n,
+			Birthday:      BaseDate.AddDate(-12+i, 0, 0),
+			PhoneNumber:   &phone,
+			GroupID:       g.GroupID,
+			MusprogrammID: p.MusprogrammID,
+		}
+		st.StudentID = s.insert("student", "student_id",
+			"surname", st.Surname, "name", st.Name, "birthday", st.Birthday, "phone_number", st.PhoneNumber,
+			"group_id", st.GroupID, "musprogramm_id", st.MusprogrammID)
+		fx.Students = append(fx.Students, st)
+
+		in := &domain.Instrument{
+			AudienceID: a.AudienceID,
+			Name:       "Инструмент-фикстура " + n,
+			InstrType:  "Клавишные",
+			Condition:  "Хорошее",
+		}
+		in.InstrumentID = s.insert("instrument", "instrument_id",
+			"audience_id", in.AudienceID, "name", in.Name, "instr_type", in.InstrType, "condition", in.Condition)
+		fx.Instruments = append(fx.Instruments, in)
+
+		audienceID, studentID := a.AudienceID, st.StudentID
+		l := &domain.Lesson{
+			AudienceID: &audienceID,
+			EmployeeID: e.EmployeeID,
+			GroupID:    g.GroupID,
+			StudentID:  &studentID,
+			LessonName: "Занятие " + n,
+			SubjectID:  subj.SubjectID,
+		}
+		l.LessonID = s.insert("lesson", "lesson_id",
+			"audience_id", l.AudienceID, "employee_id", l.EmployeeID, "group_id", l.GroupID,
+			"student_id", l.StudentID, "lesson_name", l.LessonName, "subject_id", l.SubjectID)
+		fx.Lessons = append(fx.Lessons, l)
+
+		sc := &domain.Schedule{
+			LessonID:      l.LessonID,
+			DayWeek:       []string{"Понедельник", "Вторник", "Среда"}[i%3],
+			TimeBegin:     time.Date(0, 1, 1, 9+2*i, 0, 0, 0, time.UTC),
+			TimeEnd:       time.Date(0, 1, 1, 10+2*i, 30, 0, 0, time.UTC),
+			SchdDateStart: BaseDate,
+			SchdDateEnd:   BaseDate.AddDate(0, 9, 0),
+		}
+		sc.ScheduleID = s.insert("schedule", "schedule_id",
+			"lesson_id", sc.LessonID, "day_week", sc.DayWeek,
+			"time_begin", sc.TimeBegin.Format("15:04:05"), "time_end", sc.TimeEnd.Format("15:04:05"),
+			"schd_date_start", sc.SchdDateStart, "schd_date_end", sc.SchdDateEnd)
+		fx.Schedules = append(fx.Schedules, sc)
+
+		as := &domain.StudentAssessment{
+			LessonID:       l.LessonID,
+			StudentID:      st.StudentID,
+			TaskType:       "Контрольная",
+			Grade:          5 - i,
+			AssessmentDate: BaseDate.AddDate(0, 0, 7*i),
+		}
+		as.AssessmentNoteID = s.insert("student_assessment", "assessment_note_id",
+			"lesson_id", as.LessonID, "student_id", as.StudentID, "task_type", as.TaskType,
+			"grade", as.Grade, "assessment_date", as.AssessmentDate)
+		fx.Assessments = append(fx.Assessments, as)
+
+		at := &domain.StudentAttendance{
+			StudentID:      st.StudentID,
+			LessonID:       l.LessonID,
+			PresenceMark:   i%2 == 0,
+			AttendanceDate: BaseDate.AddDate(0, 0, 7*i).Format("2006-01-02"),
+		}
+		at.AttendanceNoteID = s.insert("student_attendance", "attendance_note_id",
+			"student_id", at.StudentID, "lesson_id", at.LessonID, "presence_mark", at.PresenceMark,
+			"attendance_date", at.AttendanceDate)
+		fx.Attendances = append(fx.Attendances, at)
+
+		pd := &domain.ProgrammDistribution{MusprogrammID: p.MusprogrammID, SubjectID: subj.SubjectID}
+		pd.ProgrammDistrID = s.insert("programm_distribution", "programm_distr_id",
+			"musprogramm_id", pd.MusprogrammID, "subject_id", pd.SubjectID)
+		fx.ProgrammDistributions = append(fx.ProgrammDistributions, pd)
+
+		sd := &domain.SubjectDistribution{EmployeeID: e.EmployeeID, SubjectID: subj.SubjectID}
+		sd.SubjectDistrID = s.insert("subject_distribution", "subject_distr_id",
+			"employee_id", sd.EmployeeID, "subject_id", sd.SubjectID)
+		fx.SubjectDistributions = append(fx.SubjectDistributions, sd)
+	}
+
+	return fx
+}
+
+// insert вставляет строку из пар колонка/значение и возвращает сгенерированный ID
+func (s *seeder) insert(table, idColumn string, pairs ...interface{}) int {
+	if s.err != nil {
+		return 0
+	}
+
+	cols := make([]string, 0, len(pairs)/2)
+	placeholders := make([]string, 0, len(pairs)/2)
+	args := make([]interface{}, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		cols = append(cols, pairs[i].(string))
+		args = append(args, pairs[i+1])
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
+		table, strings.Join(cols, ", "), strings.Join(placeholders, ", "), idColumn)
+
+	var id int
+	if err := s.tx.QueryRowContext(s.ctx, query, args...).Scan(&id); err != nil {
+		s.err = fmt.Errorf("insert into %s: %w", table, err)
+	}
+	return id
+}
@@ -0,0 +1,105 @@
+package dbtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// localServer временный кластер PostgreSQL в каталоге во временной директории
+type localServer struct {
+	pgCtl string
+	dir   string
+	dsn   string
+}
+
+// [RU] startLocal инициализирует и запускает кластер через initdb/pg_ctl.
+// Если бинарников нет, возвращает errNoServer, чтобы тесты были пропущены <--->
+// [ENG] startLocal initialises and starts a cluster via initdb/pg_ctl.
+// Returns errNoServer when the binaries are missing so that tests are skipped
+func startLocal(binDir string) (*localServer, error) {
+	initdb, err := lookBin(binDir, "initdb")
+	if err != nil {
+		return nil, err
+	}
+	pgCtl, err := lookBin(binDir, "pg_ctl")
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "gomusic-pg-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster dir: %w", err)
+	}
+	srv := &localServer{pgCtl: pgCtl, dir: dir}
+	dataDir := filepath.Join(dir, "data")
+
+	if err := run(initdb, "-D", dataDir, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync"); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb failed: %w", err)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -F", port, dir)
+	if err := run(pgCtl, "-D", dataDir, "-o", opts, "-l", filepath.Join(dir, "postgres.log"), "-w", "start"); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pg_ctl start failed: %w", err)
+	}
+
+	srv.dsn = "host=127.0.0.1 port=" + strconv.Itoa(port) + " user=postgres dbname=postgres sslmode=disable"
+	return srv, nil
+}
+
+// stop останавливает кластер и удаляет его каталог
+func (s *localServer) stop() error {
+	err := run(s.pgCtl, "-D", filepath.Join(s.dir, "data"), "-m", "immediate", "-w", "stop")
+	if rmErr := os.RemoveAll(s.dir); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+func lookBin(binDir, name string) (string, error) {
+	if binDir != "" {
+		p := filepath.Join(binDir, name)
+		if _, err := os.Stat(p); err != nil {
+			return "", fmt.Errorf("%s: %w", EnvPGBin, err)
+		}
+		return p, nil
+	}
+	p, err := exec.LookPath(name)
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", errNoServer
+	}
+	return p, err
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func run(name string, args ...string) error {
+	var out bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out.Bytes()))
+	}
+	return nil
+}
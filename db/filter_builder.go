@@ -0,0 +1,235 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidFilter возвращается, если фильтр ссылается на неизвестную колонку,
+// использует неподдерживаемый оператор или некорректную сортировку
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// operators допустимые операторы Condition.Operator (в верхнем регистре)
+var operators = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true,
+	"LIKE": true, "ILIKE": true, "NOT LIKE": true, "NOT ILIKE": true,
+	"IS NULL": true, "IS NOT NULL": true,
+}
+
+// [RU] NormalizeOperator проверяет оператор по белому списку и приводит его к каноническому виду <--->
+// [ENG] NormalizeOperator checks the operator against the whitelist and returns its canonical form
+func NormalizeOperator(op string) (string, error) {
+	normalized := strings.ToUpper(strings.Join(strings.Fields(op), " "))
+	if !operators[normalized] {
+		return "", fmt.Errorf("%w: unsupported operator %q", ErrInvalidFilter, op)
+	}
+	return normalized, nil
+}
+
+// NoValue сообщает, что оператор не принимает значение (IS NULL, IS NOT NULL)
+func NoValue(op string) bool {
+	return op == "IS NULL" || op == "IS NOT NULL"
+}
+
+// Columns белый список колонок сущности
+type Columns struct {
+	names   []string          // колонки в порядке полей структуры
+	columns map[string]string // имя колонки или json-имя поля -> колонка
+}
+
+var columnsCache sync.Map // reflect.Type -> Columns
+
+// [RU] ColumnsOf возвращает колонки сущности T: тег db или имя поля в snake_case, как в StructToMap;
+// json-имена полей принимаются как синонимы колонок <--->
+// [ENG] ColumnsOf returns the columns of entity T: the db tag or the snake_case field name, as in StructToMap;
+// json field names are accepted as column aliases
+func ColumnsOf[T any]() Columns {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := columnsCache.Load(t); ok {
+		return cached.(Columns)
+	}
+
+	c := Columns{columns: make(map[string]string, t.NumField())}
+	aliases := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := ColumnName(field)
+		if field.PkgPath != "" || column == "-" {
+			continue
+		}
+		c.names = append(c.names, column)
+		c.columns[column] = column
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName != "" && jsonName != "-" {
+			aliases[jsonName] = column
+		}
+	}
+	// Имя колонки важнее совпадающего json-имени другого поля
+	for alias, column := range aliases {
+		if _, ok := c.columns[alias]; !ok {
+			c.columns[alias] = column
+		}
+	}
+
+	columnsCache.Store(t, c)
+	return c
+}
+
+// [RU] ColumnName возвращает имя колонки поля структуры; "-" - поле без колонки <--->
+// [ENG] ColumnName returns the column name of a struct field; "-" marks a field without a column
+func ColumnName(field reflect.StructField) string {
+	if name := field.Tag.Get("db"); name != "" {
+		return name
+	}
+	return ToSnakeCase(field.Name)
+}
+
+// Names возвращает колонки в порядке полей структуры
+func (c Columns) Names() []string {
+	return c.names
+}
+
+// [RU] Resolve возвращает колонку по имени колонки или json-имени поля <--->
+// [ENG] Resolve returns the column for a column name or json field name
+func (c Columns) Resolve(name string) (string, error) {
+	if column, ok := c.columns[strings.TrimSpace(name)]; ok {
+		return column, nil
+	}
+	allowed := make([]string, 0, len(c.columns))
+	for alias := range c.columns {
+		allowed = append(allowed, alias)
+	}
+	sort.Strings(allowed)
+	return "", fmt.Errorf("%w: unknown field %q (allowed: %s)", ErrInvalidFilter, name, strings.Join(allowed, ", "))
+}
+
+// SortField одна колонка сортировки
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// [RU] Sort разбирает сортировку "-field,field2" или "field DESC, field2 ASC" и проверяет колонки <--->
+// [ENG] Sort parses "-field,field2" or "field DESC, field2 ASC" and checks the columns
+func (c Columns) Sort(orderBy string) ([]SortField, error) {
+	var fields []SortField
+	for _, part := range strings.Split(orderBy, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("%w: invalid sort %q", ErrInvalidFilter, orderBy)
+		}
+
+		var field SortField
+		name := words[0]
+		if strings.HasPrefix(name, "-") {
+			field.Desc = true
+			name = name[1:]
+		}
+		if len(words) == 2 {
+			switch strings.ToUpper(words[1]) {
+			case "ASC":
+				if field.Desc {
+					return nil, fmt.Errorf("%w: invalid sort %q", ErrInvalidFilter, part)
+				}
+			case "DESC":
+				field.Desc = true
+			default:
+				return nil, fmt.Errorf("%w: invalid sort direction %q", ErrInvalidFilter, words[1])
+			}
+		}
+
+		column, err := c.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		field.Column = column
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// [RU] FormatSort собирает ORDER BY из проверенных колонок <--->
+// [ENG] FormatSort builds ORDER BY from checked columns
+func FormatSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Column
+		if field.Desc {
+			parts[i] += " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// [RU] Normalize проверяет условия и сортировку фильтра и заменяет json-имена колонками <--->
+// [ENG] Normalize checks the filter conditions and sort and replaces json names with columns
+func (c Columns) Normalize(filter Filter) (Filter, error) {
+	conds := make([]Condition, len(filter.Conditions))
+	for i, cond := range filter.Conditions {
+		column, err := c.Resolve(cond.Field)
+		if err != nil {
+			return filter, err
+		}
+		op, err := NormalizeOperator(cond.Operator)
+		if err != nil {
+			return filter, err
+		}
+		conds[i] = Condition{Field: column, Operator: op, Value: cond.Value}
+	}
+	filter.Conditions = conds
+
+	if filter.OrderBy != "" {
+		fields, err := c.Sort(filter.OrderBy)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrderBy = FormatSort(fields)
+	}
+	return filter, nil
+}
+
+// [RU] Where собирает " WHERE ..." с плейсхолдерами $1..$n; колонки и операторы берутся только из белых списков <--->
+// [ENG] Where builds " WHERE ..." with $1..$n placeholders; columns and operators come from the whitelists only
+func (c Columns) Where(conds []Condition) (string, []interface{}, error) {
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+
+	parts := make([]string, 0, len(conds))
+	var args []interface{}
+	for _, cond := range conds {
+		column, err := c.Resolve(cond.Field)
+		if err != nil {
+			return "", nil, err
+		}
+		op, err := NormalizeOperator(cond.Operator)
+		if err != nil {
+			return "", nil, err
+		}
+		if NoValue(op) {
+			parts = append(parts, fmt.Sprintf("%s %s", column, op))
+			continue
+		}
+		args = append(args, cond.Value)
+		parts = append(parts, fmt.Sprintf("%s %s $%d", column, op, len(args)))
+	}
+	return " WHERE " + strings.Join(parts, " AND "), args, nil
+}
+
+// [RU] OrderBy собирает " ORDER BY ..." из проверенной сортировки <--->
+// [ENG] OrderBy builds " ORDER BY ..." from the checked sort
+func (c Columns) OrderBy(orderBy string) (string, error) {
+	if orderBy == "" {
+		return "", nil
+	}
+	fields, err := c.Sort(orderBy)
+	if err != nil {
+		return "", err
+	}
+	return " ORDER BY " + FormatSort(fields), nil
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// [RU] Cursor граница страницы в курсорном (keyset) режиме: значения колонок сортировки и ID
+// последней (или первой, при Backward) строки предыдущей страницы; Cursor без ID - начало списка <--->
+// [ENG] Cursor is a page boundary in keyset mode: the sort column values and the ID
+// of the last (or first, with Backward) row of the previous page; a Cursor without ID starts the list
+type Cursor struct {
+	Values   []interface{} // значения колонок OrderBy в порядке сортировки
+	ID       interface{}   // ID граничной строки: разрешает равные значения сортировки
+	Backward bool          // выбрать страницу перед границей
+}
+
+// [RU] KeysetSort возвращает ключи сортировки курсорного режима: OrderBy и колонка ID последним ключом,
+// чтобы порядок строк был однозначным <--->
+// [ENG] KeysetSort returns the keyset mode sort keys: OrderBy plus the ID column as the last key,
+// so the row order is unambiguous
+func (c Columns) KeysetSort(orderBy, idColumn string) ([]SortField, error) {
+	var keys []SortField
+	if orderBy != "" {
+		fields, err := c.Sort(orderBy)
+		if err != nil {
+			return nil, err
+		}
+		keys = fields
+	}
+	return append(keys, SortField{Column: idColumn}), nil
+}
+
+// [RU] KeysetValues возвращает значения ключей KeysetSort из курсора (nil для начала списка);
+// ошибка, если курсор не подходит к сортировке <--->
+// [ENG] KeysetValues returns the KeysetSort key values from the cursor (nil for the start of the list);
+// fails if the cursor does not match the sort
+func KeysetValues(keys []SortField, cursor *Cursor) ([]interface{}, error) {
+	if cursor.ID == nil {
+		return nil, nil
+	}
+	if len(cursor.Values) != len(keys)-1 {
+		return nil, fmt.Errorf("%w: cursor does not match sort", ErrInvalidFilter)
+	}
+	return append(append([]interface{}{}, cursor.Values...), cursor.ID), nil
+}
+
+// [RU] ReverseSort меняет направление всех ключей: так выбирается страница перед курсором <--->
+// [ENG] ReverseSort flips the direction of every key: used to fetch the page before the cursor
+func ReverseSort(keys []SortField) []SortField {
+	reversed := make([]SortField, len(keys))
+	for i, key := range keys {
+		reversed[i] = SortField{Column: key.Column, Desc: !key.Desc}
+	}
+	return reversed
+}
+
+// [RU] KeysetWhere собирает условие "строка после values в порядке keys" с плейсхолдерами начиная с $firstArg.
+// NULL, как в PostgreSQL, больше любого значения: в конце при ASC и в начале при DESC <--->
+// [ENG] KeysetWhere builds the "row after values in keys order" condition with placeholders starting at $firstArg.
+// NULL sorts above any value, as in PostgreSQL: last with ASC and first with DESC
+func KeysetWhere(keys []SortField, values []interface{}, firstArg int) (string, []interface{}) {
+	var args []interface{}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", firstArg+len(args)-1)
+	}
+
+	var branches []string
+	var equal []string // равенство предыдущих ключей
+	for i, key := range keys {
+		value := values[i]
+		null := value == nil
+
+		// Строго после value по текущему ключу
+		var after string
+		switch {
+		case !key.Desc && !null:
+			after = fmt.Sprintf("(%s > %s OR %s IS NULL)", key.Column, placeholder(value), key.Column)
+		case key.Desc && !null:
+			after = fmt.Sprintf("%s < %s", key.Column, placeholder(value))
+		case key.Desc && null:
+			after = fmt.Sprintf("%s IS NOT NULL", key.Column)
+		}
+		if after != "" {
+			branches = append(branches, strings.Join(append(append([]string{}, equal...), after), " AND "))
+		}
+
+		if i == len(keys)-1 {
+			break
+		}
+		if null {
+			equal = append(equal, fmt.Sprintf("%s IS NULL", key.Column))
+		} else {
+			equal = append(equal, fmt.Sprintf("%s = %s", key.Column, placeholder(value)))
+		}
+	}
+
+	if len(branches) == 0 {
+		return "FALSE", nil
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
@@ -30,6 +30,9 @@ func MapToStruct(m map[string]interface{}, out interface{}) error {
 		}
 
 		colName := field.Tag.Get("db")
+		if colName == "-" { // поле без колонки (например, связанная сущность)
+			continue
+		}
 		if colName == "" {
 			colName = ToSnakeCase(field.Name)
 		}
@@ -96,6 +99,9 @@ func StructToMap(entity interface{}) (map[string]interface{}, error) {
 		}
 
 		colName := field.Tag.Get("db")
+		if colName == "-" { // поле без колонки (например, связанная сущность)
+			continue
+		}
 		if colName == "" {
 			colName = ToSnakeCase(field.Name)
 		}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// txIDQuery служебный запрос, которым MemoryRepository.WithTx узнает ID транзакции *sql.Tx
+const txIDQuery = "-- memory: current transaction"
+
+// connector отдает соединения, привязанные к хранилищу
+type connector struct {
+	store *Store
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{store: c.store}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return memDriver{}
+}
+
+type memDriver struct{}
+
+func (memDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("memory: use Store.DB() instead of sql.Open")
+}
+
+// conn соединение с не более чем одной активной транзакцией
+type conn struct {
+	store *Store
+	tx    *memTx
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("%w: %s", ErrRawSQL, query)
+}
+
+func (c *conn) Close() error {
+	if c.tx != nil {
+		return c.tx.Rollback()
+	}
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("memory: transaction already in progress")
+	}
+	c.tx = &memTx{conn: c, id: c.store.beginTx()}
+	return c.tx, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if query == txIDQuery && c.tx != nil {
+		return &txIDRows{id: c.tx.id}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRawSQL, query)
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return nil, fmt.Errorf("%w: %s", ErrRawSQL, query)
+}
+
+type memTx struct {
+	conn *conn
+	id   int64
+}
+
+func (t *memTx) Commit() error {
+	t.conn.tx = nil
+	return t.conn.store.endTx(t.id, true)
+}
+
+func (t *memTx) Rollback() error {
+	t.conn.tx = nil
+	return t.conn.store.endTx(t.id, false)
+}
+
+// txIDRows одна строка с ID транзакции
+type txIDRows struct {
+	id   int64
+	done bool
+}
+
+func (r *txIDRows) Columns() []string { return []string{"tx_id"} }
+
+func (r *txIDRows) Close() error { return nil }
+
+func (r *txIDRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.id
+	return nil
+}
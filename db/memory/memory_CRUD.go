@@ -0,0 +1,376 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"GO_Music/db"
+)
+
+// MemoryRepository реализация db.Repository поверх Store
+type MemoryRepository[T any, ID comparable] struct {
+	store     *Store
+	tableName string
+	idColumn  string
+	cols      db.Columns     // белый список колонок, общий с PostgresRepository
+	columns   map[string]int // колонка -> индекс поля T
+	search    []string       // колонки для Filter.Search
+	unique    [][]string     // уникальные ключи (см. Unique)
+	txID      int64
+	err       error // ошибка привязки к транзакции из WithTx
+}
+
+// [RU] NewMemoryRepository создает репозиторий таблицы tableName; колонки берутся из тега db или имени поля в snake_case <--->
+// [ENG] NewMemoryRepository creates a repository for tableName; columns come from the db tag or the snake_case field name
+func NewMemoryRepository[T any, ID comparable](store *Store, tableName string, idColumn string) *MemoryRepository[T, ID] {
+	return &MemoryRepository[T, ID]{
+		store:     store,
+		tableName: tableName,
+		idColumn:  idColumn,
+		cols:      db.ColumnsOf[T](),
+		columns:   columnsOf(reflect.TypeOf((*T)(nil)).Elem()),
+	}
+}
+
+func columnsOf(t reflect.Type) map[string]int {
+	cols := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := db.ColumnName(field)
+		if field.PkgPath != "" || column == "-" {
+			continue
+		}
+		cols[column] = i
+	}
+	return cols
+}
+
+// [RU] WithTx привязывает репозиторий к транзакции, открытой через Store.DB() <--->
+// [ENG] WithTx binds the repository to a transaction opened via Store.DB()
+func (r *MemoryRepository[T, ID]) WithTx(tx *sql.Tx) db.Repository[T, ID] {
+	bound := *r
+	var txID int64
+	if err := tx.QueryRowContext(context.Background(), txIDQuery).Scan(&txID); err != nil {
+		bound.err = fmt.Errorf("memory: transaction does not belong to the store: %w", err)
+		return &bound
+	}
+
+	r.store.mu.RLock()
+	_, ok := r.store.txs[txID]
+	r.store.mu.RUnlock()
+	if !ok {
+		bound.err = fmt.Errorf("memory: transaction does not belong to the store")
+	}
+	bound.txID = txID
+	return &bound
+}
+
+// ExecContext, QueryRowContext, QueryContext нужны специфичным методам репозиториев; в памяти всегда ErrRawSQL
+func (r *MemoryRepository[T, ID]) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.store.db.ExecContext(ctx, query, args...)
+}
+
+func (r *MemoryRepository[T, ID]) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.store.db.QueryRowContext(ctx, query, args...)
+}
+
+func (r *MemoryRepository[T, ID]) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.store.db.QueryContext(ctx, query, args...)
+}
+
+// check проверяет, что привязка к транзакции удалась и транзакция еще активна; вызывается под s.mu
+func (r *MemoryRepository[T, ID]) check() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.txID != 0 {
+		if _, ok := r.store.txs[r.txID]; !ok {
+			return sql.ErrTxDone
+		}
+	}
+	return nil
+}
+
+// Create сохраняет копию сущности; нулевой целочисленный ID генерируется как в SERIAL
+func (r *MemoryRepository[T, ID]) Create(ctx context.Context, entity *T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if err := r.check(); err != nil {
+		return err
+	}
+
+	t := r.store.table(r.tableName)
+	id, err := r.idOf(entity)
+	if err != nil {
+		return err
+	}
+
+	var zero ID
+	if id == zero {
+		t.nextID++
+		if id, err = r.generatedID(t.nextID); err != nil {
+			return err
+		}
+		if setter, ok := any(entity).(interface{ SetID(ID) }); ok {
+			setter.SetID(id)
+		} else {
+			return fmt.Errorf("memory: %s: entity must implement SetID", r.tableName)
+		}
+	} else {
+		if _, exists := t.rows[id]; exists {
+			return fmt.Errorf("memory: %s: duplicate key %s = %v", r.tableName, r.idColumn, id)
+		}
+		if n, ok := asInt64(id); ok && n > t.nextID {
+			t.nextID = n
+		}
+	}
+
+	if err := r.checkUnique(t, id, entity); err != nil {
+		return err
+	}
+	if versioned, ok := any(entity).(db.Versioned); ok {
+		versioned.SetVersion(1)
+	}
+
+	t.nextSeq++
+	r.store.put(r.txID, t, id, &row{seq: t.nextSeq, value: r.withDeletedAt(r.stored(entity), nil)})
+	return nil
+}
+
+// Update заменяет строку целиком; отсутствующая (или удаленная) строка, как и в SQL, не ошибка.
+// Для db.Versioned поведение совпадает с PostgresRepository: проверка версии, ConflictError и sql.ErrNoRows
+func (r *MemoryRepository[T, ID]) Update(ctx context.Context, entity *T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if err := r.check(); err != nil {
+		return err
+	}
+
+	id, err := r.idOf(entity)
+	if err != nil {
+		return err
+	}
+	t := r.store.table(r.tableName)
+	current, ok := r.active(t, id)
+	if ok {
+		if err := r.checkUnique(t, id, entity); err != nil {
+			return err
+		}
+	}
+	versioned, isVersioned := any(entity).(db.Versioned)
+	if !isVersioned {
+		if ok {
+			r.store.put(r.txID, t, id, &row{seq: current.seq, value: r.withDeletedAt(r.stored(entity), nil)})
+		}
+		return nil
+	}
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+	actual := current.value.(db.Versioned).GetVersion()
+	if expected := versioned.GetVersion(); expected != actual {
+		return &db.ConflictError{Table: r.tableName, ID: id, Expected: expected, Actual: actual}
+	}
+	versioned.SetVersion(actual + 1)
+	r.store.put(r.txID, t, id, &row{seq: current.seq, value: r.withDeletedAt(r.stored(entity), nil)})
+	return nil
+}
+
+// Delete удаляет строку; для сущностей с deleted_at только помечает ее удаленной
+func (r *MemoryRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if err := r.check(); err != nil {
+		return err
+	}
+
+	t := r.store.table(r.tableName)
+	current, ok := r.active(t, id)
+	if !ok {
+		return nil
+	}
+	if r.cols.SoftDelete() {
+		now := time.Now()
+		r.store.put(r.txID, t, id, &row{seq: current.seq, value: r.withDeletedAt(clone(current.value.(*T)), &now)})
+		return nil
+	}
+	r.store.put(r.txID, t, id, nil)
+	return nil
+}
+
+// GetByID возвращает копию сущности или sql.ErrNoRows
+func (r *MemoryRepository[T, ID]) GetByID(ctx context.Context, id ID) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+
+	t, ok := r.store.tables[r.tableName]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	current, ok := r.active(t, id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return clone(current.value.(*T)), nil
+}
+
+func (r *MemoryRepository[T, ID]) GetByIDs(ctx context.Context, ids []ID) ([]*T, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[ID]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+
+	var results []*T
+	for _, current := range r.sortedRows() {
+		entity := current.value.(*T)
+		if r.deleted(entity) {
+			continue
+		}
+		id, err := r.idOf(entity)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := wanted[id]; ok {
+			results = append(results, clone(entity))
+		}
+	}
+	return results, nil
+}
+
+// idOf читает значение ID-колонки сущности
+func (r *MemoryRepository[T, ID]) idOf(entity *T) (ID, error) {
+	var id ID
+	idx, ok := r.columns[r.idColumn]
+	if !ok {
+		return id, fmt.Errorf("entity must have field %s", r.idColumn)
+	}
+	v, ok := reflect.ValueOf(entity).Elem().Field(idx).Interface().(ID)
+	if !ok {
+		return id, fmt.Errorf("memory: %s: field %s is not of the ID type", r.tableName, r.idColumn)
+	}
+	return v, nil
+}
+
+// generatedID приводит счетчик к типу ID (поддерживаются целочисленные ID)
+func (r *MemoryRepository[T, ID]) generatedID(n int64) (ID, error) {
+	var id ID
+	v := reflect.ValueOf(&id).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(n))
+	default:
+		return id, fmt.Errorf("memory: %s: cannot generate %s of type %s, set it before Create", r.tableName, r.idColumn, v.Type())
+	}
+	return id, nil
+}
+
+func asInt64(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// stored копия сущности для хранилища: поля без колонки (связанные сущности) не сохраняются
+func (r *MemoryRepository[T, ID]) stored(entity *T) *T {
+	out := clone(entity)
+	v := reflect.ValueOf(out).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath == "" && db.ColumnName(field) == "-" {
+			v.Field(i).SetZero()
+		}
+	}
+	return out
+}
+
+// clone глубоко копирует сущность, чтобы вызывающий код не менял данные хранилища
+func clone[T any](entity *T) *T {
+	out := new(T)
+	reflect.ValueOf(out).Elem().Set(cloneValue(reflect.ValueOf(entity).Elem()))
+	return out
+}
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(cloneValue(v.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return m
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if s.Field(i).CanSet() {
+				s.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,568 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"GO_Music/db"
+)
+
+// [RU] List фильтрует по Conditions, сортирует по OrderBy и применяет Offset/Limit.
+// SkipLocked не действует: блокировок строк в памяти нет <--->
+// [ENG] List filters by Conditions, sorts by OrderBy and applies Offset/Limit.
+// SkipLocked has no effect: there are no row locks in memory
+func (r *MemoryRepository[T, ID]) List(ctx context.Context, filter db.Filter) ([]*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+
+	matched, ranks, err := r.filterRows(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Без явной сортировки результаты поиска упорядочиваются по релевантности
+	if filter.OrderBy == "" && ranks != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return ranks[matched[i]] > ranks[matched[j]]
+		})
+	}
+	if filter.OrderBy != "" {
+		keys, err := r.cols.Sort(filter.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return r.less(matched[i], matched[j], keys)
+		})
+	}
+
+	if filter.Cursor != nil {
+		if matched, err = r.keyset(matched, filter); err != nil {
+			return nil, err
+		}
+	} else if filter.Offset > 0 {
+		if filter.Offset >= len(matched) {
+			matched = nil
+		} else {
+			matched = matched[filter.Offset:]
+		}
+	}
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	var results []*T
+	for _, entity := range matched {
+		results = append(results, clone(entity))
+	}
+	// Страница перед курсором выбиралась в обратном порядке
+	if filter.Cursor != nil && filter.Cursor.Backward {
+		slices.Reverse(results)
+	}
+	return results, nil
+}
+
+// keyset сортирует строки по ключам курсорного режима и оставляет строки после курсора
+func (r *MemoryRepository[T, ID]) keyset(matched []*T, filter db.Filter) ([]*T, error) {
+	keys, err := r.cols.KeysetSort(filter.OrderBy, r.idColumn)
+	if err != nil {
+		return nil, err
+	}
+	values, err := db.KeysetValues(keys, filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Cursor.Backward {
+		keys = db.ReverseSort(keys)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return compareKeys(r.values(matched[i], keys), r.values(matched[j], keys), keys) < 0
+	})
+	if values == nil {
+		return matched, nil
+	}
+	after := sort.Search(len(matched), func(i int) bool {
+		return compareKeys(r.values(matched[i], keys), values, keys) > 0
+	})
+	return matched[after:], nil
+}
+
+// values возвращает значения колонок keys строки
+func (r *MemoryRepository[T, ID]) values(entity *T, keys []db.SortField) []interface{} {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = r.field(entity, key.Column)
+	}
+	return values
+}
+
+// Count считает строки, подходящие под Conditions (Limit/Offset не учитываются)
+func (r *MemoryRepository[T, ID]) Count(ctx context.Context, filter db.Filter) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if err := r.check(); err != nil {
+		return 0, err
+	}
+
+	matched, _, err := r.filterRows(filter)
+	return len(matched), err
+}
+
+func (r *MemoryRepository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if err := r.check(); err != nil {
+		return false, err
+	}
+
+	t, ok := r.store.tables[r.tableName]
+	if !ok {
+		return false, nil
+	}
+	_, ok = r.active(t, id)
+	return ok, nil
+}
+
+// sortedRows возвращает строки в порядке вставки; вызывается под s.mu
+func (r *MemoryRepository[T, ID]) sortedRows() []*row {
+	t, ok := r.store.tables[r.tableName]
+	if !ok {
+		return nil
+	}
+	rows := make([]*row, 0, len(t.rows))
+	for _, current := range t.rows {
+		rows = append(rows, current)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq < rows[j].seq })
+	return rows
+}
+
+// filterRows отбирает строки по условиям, Search и Trash; ranks - релевантность строк при поиске
+func (r *MemoryRepository[T, ID]) filterRows(filter db.Filter) ([]*T, map[*T]int, error) {
+	if _, err := r.cols.Scope(filter.Trash); err != nil {
+		return nil, nil, err
+	}
+
+	terms := strings.Fields(strings.ToLower(filter.Search))
+	var ranks map[*T]int
+	if len(terms) > 0 {
+		if len(r.search) == 0 {
+			return nil, nil, fmt.Errorf("%w: search is not supported for %s", db.ErrInvalidFilter, r.tableName)
+		}
+		ranks = map[*T]int{}
+	}
+
+	var matched []*T
+	for _, current := range r.sortedRows() {
+		entity := current.value.(*T)
+		if r.deleted(entity) != filter.Trash {
+			continue
+		}
+		ok, err := r.matches(entity, filter.Conditions)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			continue
+		}
+		if ranks != nil {
+			rank, found := r.searchRank(entity, terms)
+			if !found {
+				continue
+			}
+			ranks[entity] = rank
+		}
+		matched = append(matched, entity)
+	}
+	return matched, ranks, nil
+}
+
+// [RU] Searchable задает колонки для Filter.Search; panic, если колонки нет в сущности <--->
+// [ENG] Searchable sets the columns used by Filter.Search; panics if a column is not part of the entity
+func (r *MemoryRepository[T, ID]) Searchable(columns ...string) *MemoryRepository[T, ID] {
+	resolved := make([]string, len(columns))
+	for i, name := range columns {
+		column, err := r.cols.Resolve(name)
+		if err != nil {
+			panic(fmt.Sprintf("memory: %s: searchable column: %v", r.tableName, err))
+		}
+		resolved[i] = column
+	}
+	r.search = resolved
+	return r
+}
+
+// [RU] Unique задает уникальный ключ из колонок columns: Create и Update возвращают db.ErrDuplicate, если ключ
+// занят другой активной строкой. Как частичный индекс WHERE deleted_at IS NULL, удаленные строки не учитываются;
+// строки с NULL в колонке ключа не сравниваются. panic, если колонки нет в сущности <--->
+// [ENG] Unique adds a unique key of columns: Create and Update return db.ErrDuplicate if another active row holds
+// the key. Like a partial index WHERE deleted_at IS NULL, deleted rows are not counted; rows with NULL in a key column
+// are not compared. Panics if a column is not part of the entity
+func (r *MemoryRepository[T, ID]) Unique(columns ...string) *MemoryRepository[T, ID] {
+	resolved := make([]string, len(columns))
+	for i, name := range columns {
+		column, err := r.cols.Resolve(name)
+		if err != nil {
+			panic(fmt.Sprintf("memory: %s: unique column: %v", r.tableName, err))
+		}
+		resolved[i] = column
+	}
+	r.unique = append(r.unique, resolved)
+	return r
+}
+
+// checkUnique проверяет уникальные ключи сущности с идентификатором id; вызывается под s.mu
+func (r *MemoryRepository[T, ID]) checkUnique(t *table, id ID, entity *T) error {
+	for _, key := range r.unique {
+		values := make([]interface{}, len(key))
+		for i, column := range key {
+			values[i] = r.field(entity, column)
+		}
+		if slices.ContainsFunc(values, func(v interface{}) bool { return v == nil }) {
+			continue
+		}
+		for otherID, other := range t.rows {
+			if otherID == any(id) || r.deleted(other.value.(*T)) {
+				continue
+			}
+			same := true
+			for i, column := range key {
+				if equal, err := evalCondition(r.field(other.value.(*T), column), "=", values[i]); err != nil || !equal {
+					same = false
+					break
+				}
+			}
+			if same {
+				return fmt.Errorf("%w: %s: key (%s)=%v already exists", db.ErrDuplicate, r.tableName, strings.Join(key, ", "), values)
+			}
+		}
+	}
+	return nil
+}
+
+// searchRank упрощенный аналог поиска PostgreSQL: каждое слово запроса должно встречаться
+// в искомых колонках как подстрока; совпадение целого слова весит больше, чем начала слова
+func (r *MemoryRepository[T, ID]) searchRank(entity *T, terms []string) (int, bool) {
+	var words []string
+	for _, column := range r.search {
+		if value := r.field(entity, column); value != nil {
+			words = append(words, strings.Fields(strings.ToLower(toString(value)))...)
+		}
+	}
+
+	rank := 0
+	for _, term := range terms {
+		best := -1
+		for _, word := range words {
+			switch {
+			case word == term:
+				best = max(best, 2)
+			case strings.HasPrefix(word, term):
+				best = max(best, 1)
+			case strings.Contains(word, term):
+				best = max(best, 0)
+			}
+		}
+		if best < 0 {
+			return 0, false
+		}
+		rank += best
+	}
+	return rank, true
+}
+
+// matches проверяет все условия (объединяются через AND)
+func (r *MemoryRepository[T, ID]) matches(entity *T, conds []db.Condition) (bool, error) {
+	for _, cond := range conds {
+		column, err := r.cols.Resolve(cond.Field)
+		if err != nil {
+			return false, err
+		}
+		op, err := db.NormalizeOperator(cond.Operator)
+		if err != nil {
+			return false, err
+		}
+		ok, err := evalCondition(r.field(entity, column), op, cond.Value)
+		if err != nil {
+			return false, fmt.Errorf("memory: %s: condition on %s: %w", r.tableName, cond.Field, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// field возвращает значение проверенной колонки; nil-указатель означает NULL
+func (r *MemoryRepository[T, ID]) field(entity *T, column string) interface{} {
+	v := reflect.ValueOf(entity).Elem().Field(r.columns[column])
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func evalCondition(field interface{}, op string, value interface{}) (bool, error) {
+	switch op {
+	case "IS NULL":
+		return field == nil, nil
+	case "IS NOT NULL":
+		return field != nil, nil
+	}
+
+	// Как в SQL: сравнение с NULL никогда не истинно
+	if field == nil || isNil(value) {
+		return false, nil
+	}
+
+	switch op {
+	case "LIKE", "ILIKE", "NOT LIKE", "NOT ILIKE":
+		pattern, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("%s expects a string pattern, got %T", op, value)
+		}
+		re, err := likeRegexp(pattern, strings.Contains(op, "ILIKE"))
+		if err != nil {
+			return false, err
+		}
+		matched := re.MatchString(toString(field))
+		if strings.HasPrefix(op, "NOT ") {
+			return !matched, nil
+		}
+		return matched, nil
+	}
+
+	cmp, err := compare(field, value)
+	if err != nil {
+		return false, err
+	}
+	switch op {
+	case "=":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unsupported operator %q", op)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// compare сравнивает значение поля со значением условия, приводя значение условия к типу поля
+// (строки из query-параметров сравниваются с числами, датами и bool, как это делает PostgreSQL)
+func compare(field, value interface{}) (int, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		value = rv.Elem().Interface()
+	}
+
+	switch f := field.(type) {
+	case time.Time:
+		t, err := toTime(value)
+		if err != nil {
+			return 0, err
+		}
+		return f.Compare(t), nil
+	case string:
+		return strings.Compare(f, toString(value)), nil
+	case []byte:
+		return strings.Compare(string(f), toString(value)), nil
+	case bool:
+		b, err := toBool(value)
+		if err != nil {
+			return 0, err
+		}
+		return compareBool(f, b), nil
+	}
+
+	fn, ok := toFloat(field)
+	if !ok {
+		return 0, fmt.Errorf("cannot compare values of type %T", field)
+	}
+	vn, ok := toFloat(value)
+	if !ok {
+		s, isString := value.(string)
+		if !isString {
+			return 0, fmt.Errorf("cannot compare %T with %T", field, value)
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		vn = parsed
+	}
+	switch {
+	case fn < vn:
+		return -1, nil
+	case fn > vn:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case time.Time:
+		return s.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(b))
+		if err != nil {
+			return false, fmt.Errorf("invalid boolean %q", b)
+		}
+		return parsed, nil
+	}
+	return false, fmt.Errorf("cannot compare bool with %T", v)
+}
+
+// timeLayouts форматы, в которых менеджеры и query-параметры передают даты и время
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02", "15:04:05", "15:04", "02.01.2006"}
+
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		s := strings.TrimSpace(t)
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, s); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q", t)
+	}
+	return time.Time{}, fmt.Errorf("cannot compare time with %T", v)
+}
+
+// likeRegexp переводит шаблон LIKE (% и _, экранирование через \) в регулярное выражение
+func likeRegexp(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	if caseInsensitive {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("(?s)^")
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '%':
+			sb.WriteString(".*")
+		case c == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// less сравнивает две строки по ключам сортировки
+func (r *MemoryRepository[T, ID]) less(a, b *T, keys []db.SortField) bool {
+	return compareKeys(r.values(a, keys), r.values(b, keys), keys) < 0
+}
+
+// compareKeys сравнивает значения ключей сортировки с учетом направления;
+// NULL, как в PostgreSQL, больше любого значения
+func compareKeys(a, b []interface{}, keys []db.SortField) int {
+	for i, key := range keys {
+		av, bv := a[i], b[i]
+
+		var cmp int
+		switch {
+		case av == nil && bv == nil:
+			cmp = 0
+		case av == nil:
+			cmp = 1
+		case bv == nil:
+			cmp = -1
+		default:
+			cmp, _ = compare(av, bv)
+		}
+		if cmp == 0 {
+			continue
+		}
+		if key.Desc {
+			return -cmp
+		}
+		return cmp
+	}
+	return 0
+}
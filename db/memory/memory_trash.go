@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"GO_Music/db"
+)
+
+// [RU] Restore возвращает мягко удаленную строку; sql.ErrNoRows, если в корзине ее нет,
+// db.ErrDuplicate, если ее ключ (см. Unique) уже занят <--->
+// [ENG] Restore brings back a soft deleted row; sql.ErrNoRows if the row is not in the trash,
+// db.ErrDuplicate if its key (see Unique) is already taken
+func (r *MemoryRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	return r.trashed(ctx, id, func(t *table, current *row) error {
+		restored := r.withDeletedAt(clone(current.value.(*T)), nil)
+		if err := r.checkUnique(t, id, restored); err != nil {
+			return err
+		}
+		r.store.put(r.txID, t, id, &row{seq: current.seq, value: restored})
+		return nil
+	})
+}
+
+// [RU] Purge окончательно удаляет строку из корзины; внешних ключей в памяти нет,
+// поэтому db.ErrReferenced не возвращается <--->
+// [ENG] Purge removes a row from the trash for good; there are no foreign keys in memory,
+// so db.ErrReferenced is never returned
+func (r *MemoryRepository[T, ID]) Purge(ctx context.Context, id ID) error {
+	return r.trashed(ctx, id, func(t *table, _ *row) error {
+		r.store.put(r.txID, t, id, nil)
+		return nil
+	})
+}
+
+// trashed находит строку в корзине и применяет к ней apply под блокировкой хранилища
+func (r *MemoryRepository[T, ID]) trashed(ctx context.Context, id ID, apply func(*table, *row) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := r.cols.Scope(true); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if err := r.check(); err != nil {
+		return err
+	}
+
+	t := r.store.table(r.tableName)
+	current, ok := t.rows[id]
+	if !ok || !r.deleted(current.value.(*T)) {
+		return sql.ErrNoRows
+	}
+	return apply(t, current)
+}
+
+// active возвращает строку, если она есть и не удалена; вызывается под s.mu
+func (r *MemoryRepository[T, ID]) active(t *table, id ID) (*row, bool) {
+	current, ok := t.rows[id]
+	if !ok || r.deleted(current.value.(*T)) {
+		return nil, false
+	}
+	return current, true
+}
+
+// deleted сообщает, что строка мягко удалена
+func (r *MemoryRepository[T, ID]) deleted(entity *T) bool {
+	return r.cols.SoftDelete() && r.field(entity, db.DeletedAtColumn) != nil
+}
+
+// withDeletedAt записывает момент удаления в копию сущности; nil - строка активна
+func (r *MemoryRepository[T, ID]) withDeletedAt(entity *T, at *time.Time) *T {
+	if !r.cols.SoftDelete() {
+		return entity
+	}
+	field := reflect.ValueOf(entity).Elem().Field(r.columns[db.DeletedAtColumn])
+	if at == nil {
+		field.SetZero()
+	} else {
+		field.Set(reflect.ValueOf(at))
+	}
+	return entity
+}
@@ -0,0 +1,130 @@
+// Package memory - хранилище в памяти с реализацией db.Repository для unit-тестов и демо-режима.
+//
+// Store отдает *sql.DB, транзакции которого (BeginTx/Commit/Rollback) управляют журналом отката
+// хранилища: MemoryRepository.WithTx(tx) пишет изменения в журнал, Rollback возвращает строки
+// в состояние до транзакции, если их с тех пор не изменили другие транзакции. Изоляции чтения нет:
+// незафиксированные строки видны всем. Произвольные SQL-запросы не поддерживаются и возвращают ErrRawSQL.
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrRawSQL возвращается на любые SQL-запросы к хранилищу в памяти
+var ErrRawSQL = errors.New("memory: raw SQL queries are not supported")
+
+// lastTxID общий счетчик транзакций, чтобы ID не совпадали между хранилищами
+var lastTxID atomic.Int64
+
+// Store набор таблиц в памяти
+type Store struct {
+	mu     sync.RWMutex
+	tables map[string]*table
+	txs    map[int64]*txLog
+	db     *sql.DB
+}
+
+// row строка таблицы; seq сохраняет порядок вставки для выборок без ORDER BY
+type row struct {
+	seq   int64
+	value interface{}
+}
+
+type table struct {
+	rows    map[interface{}]*row
+	nextID  int64
+	nextSeq int64
+}
+
+// undo запись журнала отката: prev == nil означает, что строки до транзакции не было, next - строка,
+// записанная транзакцией (nil при удалении). Каждая запись создает новый *row, поэтому указатель
+// служит версией строки
+type undo struct {
+	table *table
+	id    interface{}
+	prev  *row
+	next  *row
+}
+
+type txLog struct {
+	undo []undo
+}
+
+// [RU] NewStore создает пустое хранилище <--->
+// [ENG] NewStore creates an empty store
+func NewStore() *Store {
+	s := &Store{
+		tables: map[string]*table{},
+		txs:    map[int64]*txLog{},
+	}
+	s.db = sql.OpenDB(&connector{store: s})
+	return s
+}
+
+// [RU] DB возвращает *sql.DB хранилища для менеджеров и BaseManager.ExecuteInTx <--->
+// [ENG] DB returns the store's *sql.DB for managers and BaseManager.ExecuteInTx
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// table возвращает таблицу, создавая ее при первом обращении; вызывается под s.mu
+func (s *Store) table(name string) *table {
+	t, ok := s.tables[name]
+	if !ok {
+		t = &table{rows: map[interface{}]*row{}}
+		s.tables[name] = t
+	}
+	return t
+}
+
+func (s *Store) beginTx() int64 {
+	id := lastTxID.Add(1)
+	s.mu.Lock()
+	s.txs[id] = &txLog{}
+	s.mu.Unlock()
+	return id
+}
+
+// endTx завершает транзакцию; при откате применяет журнал в обратном порядке, пропуская строки,
+// которые после записи транзакции изменила другая транзакция
+func (s *Store) endTx(id int64, commit bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log, ok := s.txs[id]
+	if !ok {
+		return sql.ErrTxDone
+	}
+	delete(s.txs, id)
+
+	if commit {
+		return nil
+	}
+	for i := len(log.undo) - 1; i >= 0; i-- {
+		u := log.undo[i]
+		if u.table.rows[u.id] != u.next {
+			continue // строку изменили после нас: ее значение не затираем
+		}
+		if u.prev == nil {
+			delete(u.table.rows, u.id)
+		} else {
+			u.table.rows[u.id] = u.prev
+		}
+	}
+	return nil
+}
+
+// put записывает строку и, если идет транзакция, запоминает прежнее состояние; вызывается под s.mu
+func (s *Store) put(txID int64, t *table, id interface{}, r *row) {
+	if txID != 0 {
+		s.txs[txID].undo = append(s.txs[txID].undo, undo{table: t, id: id, prev: t.rows[id], next: r})
+	}
+	if r == nil {
+		delete(t.rows, id)
+		return
+	}
+	t.rows[id] = r
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS subject_distribution;
+DROP TABLE IF EXISTS programm_distribution;
+DROP TABLE IF EXISTS student_attendance;
+DROP TABLE IF EXISTS student_assessment;
+DROP TABLE IF EXISTS schedule;
+DROP TABLE IF EXISTS lesson;
+DROP TABLE IF EXISTS instrument;
+DROP TABLE IF EXISTS student;
+DROP TABLE IF EXISTS study_group;
+DROP TABLE IF EXISTS employee;
+DROP TABLE IF EXISTS audience;
+DROP TABLE IF EXISTS subject;
+DROP TABLE IF EXISTS programm;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,151 @@
+-- Базовая схема GO_Music: таблицы для всех репозиториев db/repositories
+
+CREATE TABLE users (
+    user_id           SERIAL PRIMARY KEY,
+    login             VARCHAR(250) NOT NULL,
+    password          TEXT         NOT NULL,
+    role              VARCHAR(50)  NOT NULL,
+    surname           VARCHAR(100) NOT NULL,
+    name              VARCHAR(100) NOT NULL,
+    registration_date TIMESTAMP    NOT NULL DEFAULT NOW(),
+    email             VARCHAR(254) NOT NULL,
+    image             BYTEA,
+    CONSTRAINT users_login_key UNIQUE (login)
+);
+
+CREATE TABLE programm (
+    musprogramm_id           SERIAL PRIMARY KEY,
+    programm_name            VARCHAR(100) NOT NULL,
+    programm_type            VARCHAR(70)  NOT NULL,
+    duration                 INTEGER      NOT NULL CHECK (duration >= 0),
+    instrument               VARCHAR(100),
+    description              TEXT,
+    study_load               INTEGER      NOT NULL CHECK (study_load >= 0),
+    final_certification_form VARCHAR(100) NOT NULL,
+    CONSTRAINT programm_programm_name_key UNIQUE (programm_name)
+);
+
+CREATE TABLE subject (
+    subject_id   SERIAL PRIMARY KEY,
+    subject_name VARCHAR(60) NOT NULL,
+    subject_type VARCHAR(30) NOT NULL,
+    short_desc   TEXT        NOT NULL,
+    CONSTRAINT subject_subject_name_key UNIQUE (subject_name)
+);
+
+CREATE TABLE audience (
+    audience_id  SERIAL PRIMARY KEY,
+    name         VARCHAR(50) NOT NULL,
+    audin_type   VARCHAR(50) NOT NULL,
+    audin_number VARCHAR(30) NOT NULL,
+    capacity     INTEGER     NOT NULL CHECK (capacity > 0),
+    CONSTRAINT audience_audin_number_key UNIQUE (audin_number)
+);
+
+CREATE TABLE employee (
+    employee_id     SERIAL PRIMARY KEY,
+    user_id         INTEGER REFERENCES users (user_id) ON DELETE SET NULL,
+    surname         VARCHAR(60) NOT NULL,
+    name            VARCHAR(45) NOT NULL,
+    father_name     VARCHAR(55),
+    birthday        DATE        NOT NULL,
+    phone_number    VARCHAR(11) NOT NULL,
+    job             VARCHAR(60) NOT NULL,
+    work_experience INTEGER     NOT NULL CHECK (work_experience >= 0),
+    CONSTRAINT employee_user_id_key UNIQUE (user_id),
+    CONSTRAINT employee_phone_number_key UNIQUE (phone_number)
+);
+
+CREATE TABLE study_group (
+    group_id           SERIAL PRIMARY KEY,
+    mus_programm_id    INTEGER      NOT NULL REFERENCES programm (musprogramm_id),
+    group_name         VARCHAR(100) NOT NULL,
+    study_year         INTEGER      NOT NULL,
+    number_of_students INTEGER      NOT NULL DEFAULT 0 CHECK (number_of_students >= 0),
+    CONSTRAINT study_group_group_name_key UNIQUE (group_name)
+);
+
+CREATE TABLE student (
+    student_id     SERIAL PRIMARY KEY,
+    user_id        INTEGER REFERENCES users (user_id) ON DELETE SET NULL,
+    surname        VARCHAR(60) NOT NULL,
+    name           VARCHAR(45) NOT NULL,
+    father_name    VARCHAR(55),
+    birthday       DATE        NOT NULL,
+    phone_number   VARCHAR(11),
+    group_id       INTEGER     NOT NULL REFERENCES study_group (group_id),
+    musprogramm_id INTEGER     NOT NULL REFERENCES programm (musprogramm_id),
+    CONSTRAINT student_user_id_key UNIQUE (user_id),
+    CONSTRAINT student_phone_number_key UNIQUE (phone_number)
+);
+
+CREATE TABLE instrument (
+    instrument_id SERIAL PRIMARY KEY,
+    audience_id   INTEGER      NOT NULL REFERENCES audience (audience_id),
+    name          VARCHAR(150) NOT NULL,
+    instr_type    VARCHAR(70)  NOT NULL,
+    condition     VARCHAR(70)  NOT NULL,
+    CONSTRAINT instrument_name_key UNIQUE (name)
+);
+
+CREATE TABLE lesson (
+    lesson_id   SERIAL PRIMARY KEY,
+    audience_id INTEGER REFERENCES audience (audience_id) ON DELETE SET NULL,
+    employee_id INTEGER     NOT NULL REFERENCES employee (employee_id),
+    group_id    INTEGER     NOT NULL REFERENCES study_group (group_id),
+    student_id  INTEGER REFERENCES student (student_id),
+    lesson_name VARCHAR(70) NOT NULL,
+    subject_id  INTEGER     NOT NULL REFERENCES subject (subject_id)
+);
+
+CREATE TABLE schedule (
+    schedule_id     SERIAL PRIMARY KEY,
+    lesson_id       INTEGER     NOT NULL REFERENCES lesson (lesson_id) ON DELETE CASCADE,
+    day_week        VARCHAR(20) NOT NULL,
+    time_begin      TIME        NOT NULL,
+    time_end        TIME        NOT NULL,
+    schd_date_start DATE        NOT NULL,
+    schd_date_end   DATE        NOT NULL,
+    CONSTRAINT schedule_time_check CHECK (time_begin < time_end),
+    CONSTRAINT schedule_date_check CHECK (schd_date_start <= schd_date_end)
+);
+
+CREATE TABLE student_assessment (
+    assessment_note_id SERIAL PRIMARY KEY,
+    lesson_id          INTEGER     NOT NULL REFERENCES lesson (lesson_id),
+    student_id         INTEGER     NOT NULL REFERENCES student (student_id),
+    task_type          VARCHAR(70) NOT NULL,
+    grade              INTEGER     NOT NULL,
+    assessment_date    DATE        NOT NULL
+);
+
+CREATE TABLE student_attendance (
+    attendance_note_id SERIAL PRIMARY KEY,
+    student_id         INTEGER NOT NULL REFERENCES student (student_id),
+    lesson_id          INTEGER NOT NULL REFERENCES lesson (lesson_id),
+    presence_mark      BOOLEAN NOT NULL DEFAULT FALSE,
+    attendance_date    DATE    NOT NULL
+);
+
+CREATE TABLE programm_distribution (
+    programm_distr_id SERIAL PRIMARY KEY,
+    musprogramm_id    INTEGER NOT NULL REFERENCES programm (musprogramm_id) ON DELETE CASCADE,
+    subject_id        INTEGER NOT NULL REFERENCES subject (subject_id) ON DELETE CASCADE,
+    CONSTRAINT programm_distribution_key UNIQUE (musprogramm_id, subject_id)
+);
+
+CREATE TABLE subject_distribution (
+    subject_distr_id SERIAL PRIMARY KEY,
+    employee_id      INTEGER NOT NULL REFERENCES employee (employee_id) ON DELETE CASCADE,
+    subject_id       INTEGER NOT NULL REFERENCES subject (subject_id) ON DELETE CASCADE,
+    CONSTRAINT subject_distribution_key UNIQUE (employee_id, subject_id)
+);
+
+CREATE INDEX idx_student_group_id ON student (group_id);
+CREATE INDEX idx_lesson_employee_id ON lesson (employee_id);
+CREATE INDEX idx_lesson_group_id ON lesson (group_id);
+CREATE INDEX idx_schedule_lesson_id ON schedule (lesson_id);
+CREATE INDEX idx_student_assessment_student_id ON student_assessment (student_id);
+CREATE INDEX idx_student_assessment_lesson_id ON student_assessment (lesson_id);
+CREATE INDEX idx_student_attendance_student_id ON student_attendance (student_id);
+CREATE INDEX idx_student_attendance_lesson_id ON student_attendance (lesson_id);
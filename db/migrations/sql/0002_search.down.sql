@@ -0,0 +1,12 @@
+-- Расширение pg_trgm не удаляется: им могут пользоваться другие схемы базы
+
+DROP INDEX IF EXISTS subject_search_trgm_idx;
+DROP INDEX IF EXISTS subject_search_fts_idx;
+DROP INDEX IF EXISTS programm_search_trgm_idx;
+DROP INDEX IF EXISTS programm_search_fts_idx;
+DROP INDEX IF EXISTS users_search_trgm_idx;
+DROP INDEX IF EXISTS users_search_fts_idx;
+DROP INDEX IF EXISTS employee_search_trgm_idx;
+DROP INDEX IF EXISTS employee_search_fts_idx;
+DROP INDEX IF EXISTS student_search_trgm_idx;
+DROP INDEX IF EXISTS student_search_fts_idx;
@@ -0,0 +1,40 @@
+-- Поиск по ?search=: полнотекстовые и триграммные индексы по колонкам из db/repositories (*SearchColumns).
+-- Выражения совпадают с PostgresRepository.searchDocument, иначе планировщик не использует индексы.
+-- Расширение ставится в public, чтобы операторы pg_trgm были видны из любой схемы в search_path.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+CREATE INDEX student_search_fts_idx ON student USING GIN (
+    to_tsvector('russian', coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(father_name, ''))
+);
+CREATE INDEX student_search_trgm_idx ON student USING GIN (
+    (coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(father_name, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX employee_search_fts_idx ON employee USING GIN (
+    to_tsvector('russian', coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(father_name, '') || ' ' || coalesce(job, ''))
+);
+CREATE INDEX employee_search_trgm_idx ON employee USING GIN (
+    (coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(father_name, '') || ' ' || coalesce(job, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX users_search_fts_idx ON users USING GIN (
+    to_tsvector('russian', coalesce(login, '') || ' ' || coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(email, ''))
+);
+CREATE INDEX users_search_trgm_idx ON users USING GIN (
+    (coalesce(login, '') || ' ' || coalesce(surname, '') || ' ' || coalesce(name, '') || ' ' || coalesce(email, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX programm_search_fts_idx ON programm USING GIN (
+    to_tsvector('russian', coalesce(programm_name, '') || ' ' || coalesce(programm_type, '') || ' ' || coalesce(instrument, '') || ' ' || coalesce(description, ''))
+);
+CREATE INDEX programm_search_trgm_idx ON programm USING GIN (
+    (coalesce(programm_name, '') || ' ' || coalesce(programm_type, '') || ' ' || coalesce(instrument, '') || ' ' || coalesce(description, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX subject_search_fts_idx ON subject USING GIN (
+    to_tsvector('russian', coalesce(subject_name, '') || ' ' || coalesce(subject_type, '') || ' ' || coalesce(short_desc, ''))
+);
+CREATE INDEX subject_search_trgm_idx ON subject USING GIN (
+    (coalesce(subject_name, '') || ' ' || coalesce(subject_type, '') || ' ' || coalesce(short_desc, '')) public.gin_trgm_ops
+);
@@ -0,0 +1,14 @@
+ALTER TABLE subject_distribution DROP COLUMN IF EXISTS version;
+ALTER TABLE programm_distribution DROP COLUMN IF EXISTS version;
+ALTER TABLE student_attendance DROP COLUMN IF EXISTS version;
+ALTER TABLE student_assessment DROP COLUMN IF EXISTS version;
+ALTER TABLE schedule DROP COLUMN IF EXISTS version;
+ALTER TABLE lesson DROP COLUMN IF EXISTS version;
+ALTER TABLE instrument DROP COLUMN IF EXISTS version;
+ALTER TABLE student DROP COLUMN IF EXISTS version;
+ALTER TABLE study_group DROP COLUMN IF EXISTS version;
+ALTER TABLE employee DROP COLUMN IF EXISTS version;
+ALTER TABLE audience DROP COLUMN IF EXISTS version;
+ALTER TABLE subject DROP COLUMN IF EXISTS version;
+ALTER TABLE programm DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
@@ -0,0 +1,17 @@
+-- Версия строки для оптимистичной блокировки: PostgresRepository.Update проверяет ее в WHERE
+-- и увеличивает на единицу; API отдает ее в ETag и сверяет с If-Match.
+
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE programm ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE subject ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE audience ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE employee ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE study_group ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE student ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE instrument ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE lesson ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE schedule ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE student_assessment ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE student_attendance ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE programm_distribution ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE subject_distribution ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+ALTER TABLE subject_distribution DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE programm_distribution DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE student_attendance DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE student_assessment DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE schedule DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE lesson DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE instrument DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE student DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE study_group DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE employee DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE audience DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE subject DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE programm DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
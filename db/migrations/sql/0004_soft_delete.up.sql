@@ -0,0 +1,18 @@
+-- Мягкое удаление: Delete проставляет deleted_at, List/Get/Count/Exists пропускают такие строки,
+-- /restore возвращает строку, /purge удаляет ее окончательно. Ограничения UNIQUE по-прежнему
+-- учитывают удаленные строки, поэтому восстановление никогда не нарушает уникальность.
+
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE programm ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE subject ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE audience ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE employee ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE study_group ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE student ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE instrument ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE lesson ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE schedule ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE student_assessment ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE student_attendance ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE programm_distribution ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE subject_distribution ADD COLUMN deleted_at TIMESTAMP NULL;
@@ -0,0 +1,16 @@
+-- Журнал изменений: BaseManager пишет сюда create/update/delete/restore/purge в той же транзакции,
+-- что и само изменение. user_id без внешнего ключа, чтобы история переживала удаление пользователя.
+
+CREATE TABLE audit_log (
+    audit_id   SERIAL      PRIMARY KEY,
+    entity     VARCHAR(50) NOT NULL,
+    entity_id  VARCHAR(50) NOT NULL,
+    action     VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')),
+    user_id    INTEGER     NULL,
+    changes    JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity, entity_id, created_at);
+CREATE INDEX audit_log_user_idx ON audit_log (user_id, created_at);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
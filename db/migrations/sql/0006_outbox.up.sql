@@ -0,0 +1,17 @@
+-- Outbox: сообщения о побочных эффектах (уведомления) пишутся в той же транзакции, что и изменение,
+-- и доставляются фоновым диспетчером после фиксации. Откат транзакции откатывает и сообщение.
+-- Диспетчер забирает строки через FOR UPDATE SKIP LOCKED, поэтому экземпляров может быть несколько.
+
+CREATE TABLE outbox (
+    outbox_id       SERIAL       PRIMARY KEY,
+    topic           VARCHAR(100) NOT NULL,
+    payload         JSONB        NOT NULL DEFAULT '{}',
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    last_error      TEXT         NULL,
+    delivered_at    TIMESTAMP    NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- Очередь недоставленных сообщений в порядке готовности
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, outbox_id) WHERE delivered_at IS NULL;
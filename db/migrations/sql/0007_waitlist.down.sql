@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS group_waitlist;
+ALTER TABLE study_group DROP COLUMN IF EXISTS max_students;
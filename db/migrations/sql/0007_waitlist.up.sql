@@ -0,0 +1,26 @@
+-- Вместимость групп и лист ожидания. max_students NULL - группа без ограничения.
+-- Записи листа ожидания не удаляются: завершенные (enrolled, withdrawn) остаются как история.
+
+ALTER TABLE study_group ADD COLUMN max_students INTEGER NULL CHECK (max_students > 0);
+
+CREATE TABLE group_waitlist (
+    waitlist_id SERIAL      PRIMARY KEY,
+    group_id    INTEGER     NOT NULL REFERENCES study_group (group_id),
+    student_id  INTEGER     NOT NULL REFERENCES student (student_id),
+    status      VARCHAR(20) NOT NULL DEFAULT 'waiting'
+                CHECK (status IN ('waiting', 'offered', 'enrolled', 'withdrawn')),
+    enlisted_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    offered_at  TIMESTAMP   NULL,
+    resolved_at TIMESTAMP   NULL,
+    version     INTEGER     NOT NULL DEFAULT 1
+);
+
+-- Студент стоит в очереди группы не более одного раза
+CREATE UNIQUE INDEX group_waitlist_active_key ON group_waitlist (group_id, student_id)
+    WHERE status IN ('waiting', 'offered');
+
+-- Очередь группы в порядке постановки
+CREATE INDEX group_waitlist_queue_idx ON group_waitlist (group_id, enlisted_at, waitlist_id)
+    WHERE status = 'waiting';
+
+CREATE INDEX group_waitlist_student_idx ON group_waitlist (student_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS schedule_exception;
+ALTER TABLE schedule ALTER COLUMN day_week TYPE VARCHAR(20) USING LEFT(day_week, 20);
+ALTER TABLE schedule DROP COLUMN IF EXISTS exdate;
+ALTER TABLE schedule DROP COLUMN IF EXISTS rrule;
@@ -0,0 +1,26 @@
+-- Расписание хранится правилами повторения (подмножество RRULE из RFC 5545), занятия разворачиваются при чтении.
+-- Строки без rrule остаются еженедельными сериями по day_week в пределах schd_date_start..schd_date_end,
+-- поэтому ранее сгенерированные построчно записи читаются как серии из одного занятия.
+
+ALTER TABLE schedule ADD COLUMN rrule TEXT NULL;
+ALTER TABLE schedule ADD COLUMN exdate TEXT NULL;
+-- day_week правила на несколько дней: "Понедельник,Четверг"
+ALTER TABLE schedule ALTER COLUMN day_week TYPE VARCHAR(100);
+
+-- Исключения для отдельных занятий серии: отмена или перенос. Адресуются датой занятия по правилу.
+CREATE TABLE schedule_exception (
+    exception_id    SERIAL       PRIMARY KEY,
+    schedule_id     INTEGER      NOT NULL REFERENCES schedule (schedule_id) ON DELETE CASCADE,
+    occurrence_date DATE         NOT NULL,
+    cancelled       BOOLEAN      NOT NULL DEFAULT FALSE,
+    new_date        DATE         NULL,
+    time_begin      TIME         NULL,
+    time_end        TIME         NULL,
+    reason          VARCHAR(255) NULL,
+    version         INTEGER      NOT NULL DEFAULT 1,
+    CONSTRAINT schedule_exception_occurrence_key UNIQUE (schedule_id, occurrence_date),
+    CONSTRAINT schedule_exception_time_check CHECK (time_begin IS NULL OR time_end IS NULL OR time_begin < time_end)
+);
+
+-- Перенесенные занятия ищутся по новой дате
+CREATE INDEX schedule_exception_new_date_idx ON schedule_exception (new_date) WHERE new_date IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Учебный календарь: четверти, праздники и переносы рабочих дней. Занятия серий расписания
+-- в закрытые дни отменяются при развертывании, занятия перенесенной даты идут в день переноса.
+-- Если задана хотя бы одна четверть, дни вне четвертей считаются каникулами.
+
+CREATE TABLE academic_calendar (
+    calendar_id SERIAL       PRIMARY KEY,
+    kind        VARCHAR(10)  NOT NULL CHECK (kind IN ('term', 'holiday', 'swap')),
+    name        VARCHAR(255) NOT NULL,
+    date_start  DATE         NOT NULL,
+    date_end    DATE         NOT NULL,
+    worked_as   DATE         NULL,
+    source_uid  VARCHAR(255) NULL UNIQUE,
+    version     INTEGER      NOT NULL DEFAULT 1,
+    CONSTRAINT academic_calendar_period_check CHECK (date_start <= date_end),
+    CONSTRAINT academic_calendar_swap_check CHECK (
+        (kind = 'swap' AND worked_as IS NOT NULL AND date_start = date_end)
+        OR (kind <> 'swap' AND worked_as IS NULL)
+    )
+);
+
+CREATE INDEX academic_calendar_period_idx ON academic_calendar (date_start, date_end);
+
+-- Дата переносится не более одного раза
+CREATE UNIQUE INDEX academic_calendar_worked_as_key ON academic_calendar (worked_as) WHERE worked_as IS NOT NULL;
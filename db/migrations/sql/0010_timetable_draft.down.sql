@@ -0,0 +1 @@
+DROP TABLE IF EXISTS timetable_draft;
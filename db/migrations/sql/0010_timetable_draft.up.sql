@@ -0,0 +1,16 @@
+-- Черновики недельного расписания, предложенные решателем: часы занятий (slots) и ограничения,
+-- которые не удалось выполнить (unsatisfied). Применение черновика создает записи schedule
+-- на период date_start..date_end и переводит черновик в applied.
+
+CREATE TABLE timetable_draft (
+    draft_id    SERIAL      PRIMARY KEY,
+    date_start  DATE        NOT NULL,
+    date_end    DATE        NOT NULL,
+    status      VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'applied')),
+    slots       JSONB       NOT NULL DEFAULT '[]',
+    unsatisfied JSONB       NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    applied_at  TIMESTAMP   NULL,
+    version     INTEGER     NOT NULL DEFAULT 1,
+    CONSTRAINT timetable_draft_period_check CHECK (date_start <= date_end)
+);
@@ -0,0 +1,28 @@
+-- Доступность преподавателей: еженедельные окна, когда преподаватель может вести занятия (weekly),
+-- и отсутствия на даты - больничные, отпуска (absence). Если у преподавателя есть окна, занятия
+-- ставятся только внутри них; отсутствие без времени закрывает весь день.
+
+CREATE TABLE employee_availability (
+    availability_id SERIAL       PRIMARY KEY,
+    employee_id     INTEGER      NOT NULL REFERENCES employee (employee_id) ON DELETE CASCADE,
+    kind            VARCHAR(10)  NOT NULL CHECK (kind IN ('weekly', 'absence')),
+    day_week        VARCHAR(20)  NULL,
+    time_begin      TIME         NULL,
+    time_end        TIME         NULL,
+    date_start      DATE         NULL,
+    date_end        DATE         NULL,
+    reason          VARCHAR(255) NULL,
+    version         INTEGER      NOT NULL DEFAULT 1,
+    CONSTRAINT employee_availability_time_check CHECK (
+        (time_begin IS NULL AND time_end IS NULL) OR time_begin < time_end
+    ),
+    CONSTRAINT employee_availability_period_check CHECK (
+        date_start IS NULL OR date_end IS NULL OR date_start <= date_end
+    ),
+    CONSTRAINT employee_availability_kind_check CHECK (
+        (kind = 'weekly' AND day_week IS NOT NULL AND time_begin IS NOT NULL)
+        OR (kind = 'absence' AND day_week IS NULL AND date_start IS NOT NULL AND date_end IS NOT NULL)
+    )
+);
+
+CREATE INDEX employee_availability_employee_idx ON employee_availability (employee_id, kind);
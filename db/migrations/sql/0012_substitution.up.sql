@@ -0,0 +1,26 @@
+-- Замены преподавателей на отдельных занятиях серий расписания. Занятие (lesson) не меняется:
+-- замена адресуется серией и датой занятия по правилу, дата и время проведения запоминаются для отчетов.
+-- open - замена нужна, assigned - заменяющий преподаватель назначен.
+
+CREATE TABLE substitution (
+    substitution_id SERIAL       PRIMARY KEY,
+    schedule_id     INTEGER      NOT NULL REFERENCES schedule (schedule_id) ON DELETE CASCADE,
+    lesson_id       INTEGER      NOT NULL REFERENCES lesson (lesson_id) ON DELETE CASCADE,
+    occurrence_date DATE         NOT NULL,
+    date            DATE         NOT NULL,
+    time_begin      TIME         NOT NULL,
+    time_end        TIME         NOT NULL,
+    employee_id     INTEGER      NOT NULL REFERENCES employee (employee_id),
+    substitute_id   INTEGER      NULL REFERENCES employee (employee_id),
+    status          VARCHAR(10)  NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'assigned')),
+    reason          VARCHAR(255) NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    assigned_at     TIMESTAMP    NULL,
+    version         INTEGER      NOT NULL DEFAULT 1,
+    CONSTRAINT substitution_occurrence_key UNIQUE (schedule_id, occurrence_date),
+    CONSTRAINT substitution_status_check CHECK ((status = 'assigned') = (substitute_id IS NOT NULL)),
+    CONSTRAINT substitution_substitute_check CHECK (substitute_id <> employee_id)
+);
+
+CREATE INDEX substitution_employee_idx ON substitution (employee_id, date);
+CREATE INDEX substitution_substitute_idx ON substitution (substitute_id, date) WHERE substitute_id IS NOT NULL;
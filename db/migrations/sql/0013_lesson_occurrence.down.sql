@@ -0,0 +1,3 @@
+ALTER TABLE student_assessment DROP COLUMN IF EXISTS occurrence_id;
+ALTER TABLE student_attendance DROP COLUMN IF EXISTS occurrence_id;
+DROP TABLE IF EXISTS lesson_occurrence;
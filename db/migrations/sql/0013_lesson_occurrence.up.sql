@@ -0,0 +1,32 @@
+-- Проведенные занятия серий расписания: создаются из серии по дате занятия по правилу при первом обращении.
+-- Дата, время, состояние и преподаватель следуют за расписанием, пока занятие не проведено (held).
+-- Посещаемость и оценки ссылаются на занятие, чтобы журнал одного урока читался и сохранялся целиком.
+
+CREATE TABLE lesson_occurrence (
+    occurrence_id   SERIAL        PRIMARY KEY,
+    schedule_id     INTEGER       NOT NULL REFERENCES schedule (schedule_id) ON DELETE CASCADE,
+    lesson_id       INTEGER       NOT NULL REFERENCES lesson (lesson_id) ON DELETE CASCADE,
+    occurrence_date DATE          NOT NULL,
+    date            DATE          NOT NULL,
+    time_begin      TIME          NOT NULL,
+    time_end        TIME          NOT NULL,
+    status          VARCHAR(10)   NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'held', 'cancelled', 'moved')),
+    employee_id     INTEGER       NOT NULL REFERENCES employee (employee_id),
+    audience_id     INTEGER       NULL REFERENCES audience (audience_id) ON DELETE SET NULL,
+    topic           VARCHAR(255)  NULL,
+    notes           TEXT          NULL,
+    version         INTEGER       NOT NULL DEFAULT 1,
+    CONSTRAINT lesson_occurrence_key UNIQUE (schedule_id, occurrence_date),
+    CONSTRAINT lesson_occurrence_time_check CHECK (time_begin < time_end)
+);
+
+CREATE INDEX lesson_occurrence_lesson_idx ON lesson_occurrence (lesson_id, date);
+CREATE INDEX lesson_occurrence_employee_idx ON lesson_occurrence (employee_id, date);
+
+ALTER TABLE student_attendance
+    ADD COLUMN occurrence_id INTEGER NULL REFERENCES lesson_occurrence (occurrence_id) ON DELETE SET NULL;
+ALTER TABLE student_assessment
+    ADD COLUMN occurrence_id INTEGER NULL REFERENCES lesson_occurrence (occurrence_id) ON DELETE SET NULL;
+
+CREATE INDEX student_attendance_occurrence_idx ON student_attendance (occurrence_id) WHERE occurrence_id IS NOT NULL;
+CREATE INDEX student_assessment_occurrence_idx ON student_assessment (occurrence_id) WHERE occurrence_id IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS student_attendance_occurrence_key;
@@ -0,0 +1,17 @@
+-- Одна отметка присутствия ученика на проведенное занятие: одновременные сохранения журнала
+-- не создают двух записей. Повторы, записанные до индекса, переносятся в корзину; остается первая отметка.
+
+UPDATE student_attendance AS a
+SET deleted_at = NOW()
+WHERE a.occurrence_id IS NOT NULL
+  AND a.deleted_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM student_attendance AS b
+      WHERE b.occurrence_id = a.occurrence_id
+        AND b.student_id = a.student_id
+        AND b.deleted_at IS NULL
+        AND b.attendance_note_id < a.attendance_note_id
+  );
+
+CREATE UNIQUE INDEX student_attendance_occurrence_key ON student_attendance (occurrence_id, student_id)
+    WHERE occurrence_id IS NOT NULL AND deleted_at IS NULL;
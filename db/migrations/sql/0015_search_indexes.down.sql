@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS student_assessment_search_trgm_idx;
+DROP INDEX IF EXISTS student_assessment_search_fts_idx;
+DROP INDEX IF EXISTS schedule_search_trgm_idx;
+DROP INDEX IF EXISTS schedule_search_fts_idx;
+DROP INDEX IF EXISTS lesson_search_trgm_idx;
+DROP INDEX IF EXISTS lesson_search_fts_idx;
+DROP INDEX IF EXISTS instrument_search_trgm_idx;
+DROP INDEX IF EXISTS instrument_search_fts_idx;
+DROP INDEX IF EXISTS study_group_search_trgm_idx;
+DROP INDEX IF EXISTS study_group_search_fts_idx;
+DROP INDEX IF EXISTS audience_search_trgm_idx;
+DROP INDEX IF EXISTS audience_search_fts_idx;
@@ -0,0 +1,44 @@
+-- Индексы поиска по ?search= для таблиц с *SearchColumns, не вошедших в 0002_search.
+-- Выражения совпадают с PostgresRepository.searchDocument, иначе планировщик не использует индексы.
+
+CREATE INDEX audience_search_fts_idx ON audience USING GIN (
+    to_tsvector('russian', coalesce(name, '') || ' ' || coalesce(audin_type, '') || ' ' || coalesce(audin_number, ''))
+);
+CREATE INDEX audience_search_trgm_idx ON audience USING GIN (
+    (coalesce(name, '') || ' ' || coalesce(audin_type, '') || ' ' || coalesce(audin_number, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX study_group_search_fts_idx ON study_group USING GIN (
+    to_tsvector('russian', coalesce(group_name, ''))
+);
+CREATE INDEX study_group_search_trgm_idx ON study_group USING GIN (
+    (coalesce(group_name, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX instrument_search_fts_idx ON instrument USING GIN (
+    to_tsvector('russian', coalesce(name, '') || ' ' || coalesce(instr_type, '') || ' ' || coalesce(condition, ''))
+);
+CREATE INDEX instrument_search_trgm_idx ON instrument USING GIN (
+    (coalesce(name, '') || ' ' || coalesce(instr_type, '') || ' ' || coalesce(condition, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX lesson_search_fts_idx ON lesson USING GIN (
+    to_tsvector('russian', coalesce(lesson_name, ''))
+);
+CREATE INDEX lesson_search_trgm_idx ON lesson USING GIN (
+    (coalesce(lesson_name, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX schedule_search_fts_idx ON schedule USING GIN (
+    to_tsvector('russian', coalesce(day_week, ''))
+);
+CREATE INDEX schedule_search_trgm_idx ON schedule USING GIN (
+    (coalesce(day_week, '')) public.gin_trgm_ops
+);
+
+CREATE INDEX student_assessment_search_fts_idx ON student_assessment USING GIN (
+    to_tsvector('russian', coalesce(task_type, ''))
+);
+CREATE INDEX student_assessment_search_trgm_idx ON student_assessment USING GIN (
+    (coalesce(task_type, '')) public.gin_trgm_ops
+);
@@ -3,6 +3,7 @@ package postgreSQL
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,26 +11,34 @@ import (
 )
 
 type PostgresRepository[T any, ID comparable] struct {
-	db        *sql.DB
-	tx        *sql.Tx
-	tableName string
-	idColumn  string
+	db            *sql.DB
+	tx            *sql.Tx
+	tableName     string
+	idColumn      string
+	columns       db.Columns        // белый список колонок для List/Count
+	scanner       *db.RowScanner[T] // чтение строк прямо в поля T
+	searchColumns []string          // колонки для Filter.Search (см. Searchable)
 }
 
-func NewPostgresRepository[T any, ID comparable](db *sql.DB, tableName string, idColumn string) *PostgresRepository[T, ID] {
+func NewPostgresRepository[T any, ID comparable](sqlDB *sql.DB, tableName string, idColumn string) *PostgresRepository[T, ID] {
 	return &PostgresRepository[T, ID]{
-		db:        db,
+		db:        sqlDB,
 		tableName: tableName,
 		idColumn:  idColumn,
+		columns:   db.ColumnsOf[T](),
+		scanner:   db.ScannerOf[T](),
 	}
 }
 
 func (r *PostgresRepository[T, ID]) WithTx(tx *sql.Tx) db.Repository[T, ID] {
 	return &PostgresRepository[T, ID]{
-		db:        r.db,
-		tx:        tx,
-		tableName: r.tableName,
-		idColumn:  r.idColumn,
+		db:            r.db,
+		tx:            tx,
+		tableName:     r.tableName,
+		idColumn:      r.idColumn,
+		columns:       r.columns,
+		scanner:       r.scanner,
+		searchColumns: r.searchColumns,
 	}
 }
 
@@ -54,15 +63,22 @@ func (r *PostgresRepository[T, ID]) QueryContext(ctx context.Context, query stri
 	return r.db.QueryContext(ctx, query, args...)
 }
 
-// Create конвертирует struct в map через reflect и вставляет запись
+// Create конвертирует struct в map через reflect и вставляет запись; нарушение уникальности - db.ErrDuplicate
 func (r *PostgresRepository[T, ID]) Create(ctx context.Context, entity *T) error {
 	m, err := db.StructToMap(entity)
 	if err != nil {
 		return err
 	}
 
-	// Исключаем ID-колонку из вставки
+	// Исключаем ID-колонку из вставки; новая строка всегда активна
 	delete(m, r.idColumn)
+	delete(m, db.DeletedAtColumn)
+
+	// Новая строка начинается с версии 1
+	versioned, isVersioned := any(entity).(db.Versioned)
+	if isVersioned {
+		m[db.VersionColumn] = 1
+	}
 
 	columns := make([]string, 0, len(m))
 	placeholders := make([]string, 0, len(m))
@@ -88,18 +104,21 @@ func (r *PostgresRepository[T, ID]) Create(ctx context.Context, entity *T) error
 	var id ID
 	err = r.QueryRowContext(ctx, query, values...).Scan(&id)
 	if err != nil {
-		return err
+		return r.duplicate(err)
 	}
 
 	// Устанавливаем полученный ID обратно в структуру
 	if setter, ok := any(entity).(interface{ SetID(ID) }); ok {
 		setter.SetID(id)
 	}
+	if isVersioned {
+		versioned.SetVersion(1)
+	}
 
 	return nil
 }
 
-// Update обновляет запись по id
+// Update обновляет запись по id; для db.Versioned проверяет версию строки и увеличивает ее
 func (r *PostgresRepository[T, ID]) Update(ctx context.Context, entity *T) error {
 	m, err := db.StructToMap(entity)
 	if err != nil {
@@ -111,9 +130,16 @@ func (r *PostgresRepository[T, ID]) Update(ctx context.Context, entity *T) error
 		return fmt.Errorf("entity must have field %s", r.idColumn)
 	}
 	delete(m, r.idColumn)
+	// Удаление и восстановление меняют deleted_at только через Delete/Restore
+	delete(m, db.DeletedAtColumn)
+
+	versioned, isVersioned := any(entity).(db.Versioned)
+	if isVersioned {
+		delete(m, db.VersionColumn)
+	}
 
-	setParts := make([]string, 0, len(m))
-	values := make([]interface{}, 0, len(m)+1)
+	setParts := make([]string, 0, len(m)+1)
+	values := make([]interface{}, 0, len(m)+2)
 
 	i := 1
 	for col, val := range m {
@@ -123,82 +149,70 @@ func (r *PostgresRepository[T, ID]) Update(ctx context.Context, entity *T) error
 	}
 	values = append(values, idVal)
 
+	if !isVersioned {
+		query := fmt.Sprintf(
+			"UPDATE %s SET %s WHERE %s = $%d%s",
+			r.tableName,
+			strings.Join(setParts, ", "),
+			r.idColumn,
+			i,
+			r.alive(),
+		)
+		_, err = r.ExecContext(ctx, query, values...)
+		return r.duplicate(err)
+	}
+
+	// Версии строк начинаются с 1: сущность с нулевой версией не читалась из БД и всегда устарела
+	setParts = append(setParts, fmt.Sprintf("%s = %s + 1", db.VersionColumn, db.VersionColumn))
+	expected := versioned.GetVersion()
+	values = append(values, expected)
 	query := fmt.Sprintf(
-		"UPDATE %s SET %s WHERE %s = $%d",
+		"UPDATE %s SET %s WHERE %s = $%d%s AND %s = $%d RETURNING %s",
 		r.tableName,
 		strings.Join(setParts, ", "),
 		r.idColumn,
 		i,
+		r.alive(),
+		db.VersionColumn,
+		i+1,
+		db.VersionColumn,
 	)
-	_, err = r.ExecContext(ctx, query, values...)
-	return err
+
+	var version int
+	err = r.QueryRowContext(ctx, query, values...).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.conflict(ctx, idVal, expected)
+	}
+	if err != nil {
+		return r.duplicate(err)
+	}
+	versioned.SetVersion(version)
+	return nil
 }
 
+// conflict объясняет, почему UPDATE не затронул строку: ее удалили (sql.ErrNoRows) или изменили (ConflictError)
+func (r *PostgresRepository[T, ID]) conflict(ctx context.Context, id interface{}, expected int) error {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1%s", db.VersionColumn, r.tableName, r.idColumn, r.alive())
+	var actual int
+	if err := r.QueryRowContext(ctx, query, id).Scan(&actual); err != nil {
+		return err
+	}
+	return &db.ConflictError{Table: r.tableName, ID: id, Expected: expected, Actual: actual}
+}
+
+// Delete удаляет запись; для сущностей с deleted_at только помечает ее удаленной (см. Restore, Purge)
 func (r *PostgresRepository[T, ID]) Delete(ctx context.Context, id ID) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", r.tableName, r.idColumn)
+	if r.columns.SoftDelete() {
+		query = fmt.Sprintf("UPDATE %s SET %s = NOW() WHERE %s = $1%s", r.tableName, db.DeletedAtColumn, r.idColumn, r.alive())
+	}
 	_, err := r.ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *PostgresRepository[T, ID]) GetByID(ctx context.Context, id ID) (*T, error) {
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = $1", r.tableName, r.idColumn)
-	row := r.QueryRowContext(ctx, query, id)
-
-	cols, err := r.getColumns(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	// Создаем слайс для сканирования, учитывая nullable поля
-	values := make([]interface{}, len(cols))
-	valuePtrs := make([]interface{}, len(cols))
-
-	// Для nullable строк используем sql.NullString
-	nullStringMap := map[string]*sql.NullString{}
-	for i, col := range cols {
-		if strings.HasSuffix(col, "_string") { // или другой способ идентификации
-			ns := new(sql.NullString)
-			values[i] = ns
-			nullStringMap[col] = ns
-			valuePtrs[i] = ns
-		} else {
-			var v interface{}
-			values[i] = &v
-			valuePtrs[i] = &v
-		}
-	}
-
-	err = row.Scan(valuePtrs...)
-	if err != nil {
-		return nil, err
-	}
-
-	m := make(map[string]interface{})
-	for i, col := range cols {
-		if ns, ok := nullStringMap[col]; ok {
-			if ns.Valid {
-				// Создаем копию строки, чтобы избежать проблем с указателями
-				str := ns.String
-				m[col] = &str
-			} else {
-				m[col] = nil
-			}
-		} else {
-			valPtr := values[i].(*interface{})
-			val := *valPtr
-			if b, ok := val.([]byte); ok {
-				m[col] = string(b)
-			} else {
-				m[col] = val
-			}
-		}
-	}
-
-	var entity T
-	if err := db.MapToStruct(m, &entity); err != nil {
-		return nil, err
-	}
-	return &entity, nil
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1%s", r.scanner.SelectList(), r.tableName, r.idColumn, r.alive())
+	return r.scanner.ScanRow(r.QueryRowContext(ctx, query, id))
 }
 
 func (r *PostgresRepository[T, ID]) GetByIDs(ctx context.Context, ids []ID) ([]*T, error) {
@@ -213,46 +227,12 @@ func (r *PostgresRepository[T, ID]) GetByIDs(ctx context.Context, ids []ID) ([]*
 		args[i] = id
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s)", r.tableName, r.idColumn, strings.Join(params, ", "))
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)%s", r.scanner.SelectList(), r.tableName, r.idColumn, strings.Join(params, ", "), r.alive())
 	rows, err := r.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	cols, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
-
-	var results []*T
-	for rows.Next() {
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range cols {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
-		}
-
-		m := make(map[string]interface{})
-		for i, col := range cols {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				m[col] = string(b)
-			} else {
-				m[col] = val
-			}
-		}
-
-		var entity T
-		if err := db.MapToStruct(m, &entity); err != nil {
-			return nil, err
-		}
-		results = append(results, &entity)
-	}
-
-	return results, nil
+	return r.scanner.ScanRows(rows)
 }
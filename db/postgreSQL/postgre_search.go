@@ -0,0 +1,83 @@
+package postgreSQL
+
+import (
+	"fmt"
+	"strings"
+
+	"GO_Music/db"
+)
+
+// searchConfig конфигурация полнотекстового поиска; индексы из миграций 0002_search и 0015_search_indexes используют ту же
+const searchConfig = "russian"
+
+// [RU] Searchable задает колонки для Filter.Search; panic, если колонки нет в сущности <--->
+// [ENG] Searchable sets the columns used by Filter.Search; panics if a column is not part of the entity
+func (r *PostgresRepository[T, ID]) Searchable(columns ...string) *PostgresRepository[T, ID] {
+	resolved := make([]string, len(columns))
+	for i, name := range columns {
+		column, err := r.columns.Resolve(name)
+		if err != nil {
+			panic(fmt.Sprintf("postgreSQL: %s: searchable column: %v", r.tableName, err))
+		}
+		resolved[i] = column
+	}
+	r.searchColumns = resolved
+	return r
+}
+
+// searchDocument склеивает искомые колонки в один текст; выражение совпадает с индексами миграции
+func (r *PostgresRepository[T, ID]) searchDocument() string {
+	parts := make([]string, len(r.searchColumns))
+	for i, column := range r.searchColumns {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", column)
+	}
+	return strings.Join(parts, " || ' ' || ")
+}
+
+// where собирает WHERE из условий фильтра, Search и видимости удаленных строк; rank - выражение релевантности для ORDER BY
+func (r *PostgresRepository[T, ID]) where(filter db.Filter) (where string, rank string, args []interface{}, err error) {
+	where, args, err = r.columns.Where(filter.Conditions)
+	if err != nil {
+		return "", "", nil, err
+	}
+	scope, err := r.columns.Scope(filter.Trash)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if scope != "" {
+		if where == "" {
+			where = " WHERE " + scope
+		} else {
+			where += " AND " + scope
+		}
+	}
+
+	search := strings.TrimSpace(filter.Search)
+	if search == "" {
+		return where, "", args, nil
+	}
+	if len(r.searchColumns) == 0 {
+		return "", "", nil, fmt.Errorf("%w: search is not supported for %s", db.ErrInvalidFilter, r.tableName)
+	}
+
+	// Совпадение по словам (tsvector), по подстроке (ILIKE) или похожее слово с опечаткой (pg_trgm)
+	args = append(args, search, "%"+escapeLike(search)+"%")
+	text, pattern := len(args)-1, len(args)
+	doc := r.searchDocument()
+	tsv := fmt.Sprintf("to_tsvector('%s', %s)", searchConfig, doc)
+	query := fmt.Sprintf("plainto_tsquery('%s', $%d)", searchConfig, text)
+
+	cond := fmt.Sprintf("(%s @@ %s OR %s ILIKE $%d OR $%d <%% (%s))", tsv, query, doc, pattern, text, doc)
+	if where == "" {
+		where = " WHERE " + cond
+	} else {
+		where += " AND " + cond
+	}
+	rank = fmt.Sprintf("ts_rank(%s, %s) DESC, word_similarity($%d, %s) DESC", tsv, query, text, doc)
+	return where, rank, args, nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы строка поиска сравнивалась буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
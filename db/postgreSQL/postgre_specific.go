@@ -3,6 +3,7 @@ package postgreSQL
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,135 +11,101 @@ import (
 )
 
 func (r *PostgresRepository[T, ID]) List(ctx context.Context, filter db.Filter) ([]*T, error) {
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(fmt.Sprintf("SELECT * FROM %s", r.tableName))
-
-	args := []interface{}{}
-	argPos := 1
-
-	if len(filter.Conditions) > 0 {
-		conds := make([]string, 0, len(filter.Conditions))
-		for _, cond := range filter.Conditions {
-			op := strings.ToUpper(cond.Operator)
-			if op == "IS NULL" || op == "IS NOT NULL" {
-				conds = append(conds, fmt.Sprintf("%s %s", cond.Field, cond.Operator))
-			} else {
-				conds = append(conds, fmt.Sprintf("%s %s $%d", cond.Field, cond.Operator, argPos))
-				args = append(args, cond.Value)
-				argPos++
-			}
-		}
-		queryBuilder.WriteString(" WHERE " + strings.Join(conds, " AND "))
+	where, rank, args, err := r.where(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	// ORDER BY
-	if filter.OrderBy != "" {
-		queryBuilder.WriteString(" ORDER BY " + filter.OrderBy)
+	var orderBy string
+	if filter.Cursor != nil {
+		where, orderBy, args, err = r.keyset(filter, where, args)
+	} else {
+		orderBy, err = r.columns.OrderBy(filter.OrderBy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Без явной сортировки результаты поиска упорядочиваются по релевантности
+	if orderBy == "" && rank != "" {
+		orderBy = " ORDER BY " + rank
 	}
 
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString(fmt.Sprintf("SELECT %s FROM %s", r.scanner.SelectList(), r.tableName))
+	queryBuilder.WriteString(where)
+	queryBuilder.WriteString(orderBy)
+
 	// LIMIT OFFSET
 	if filter.Limit > 0 {
 		queryBuilder.WriteString(" LIMIT " + strconv.Itoa(filter.Limit))
 	}
-	if filter.Offset > 0 {
+	if filter.Offset > 0 && filter.Cursor == nil {
 		queryBuilder.WriteString(" OFFSET " + strconv.Itoa(filter.Offset))
 	}
+	if filter.SkipLocked {
+		queryBuilder.WriteString(" FOR UPDATE SKIP LOCKED")
+	}
 
-	query := queryBuilder.String()
-
-	fmt.Println("SQL Query:", query)
-	fmt.Println("Args:", args)
-
-	rows, err := r.QueryContext(ctx, query, args...)
+	rows, err := r.QueryContext(ctx, queryBuilder.String(), args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	cols, err := rows.Columns()
+	results, err := r.scanner.ScanRows(rows)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []*T
-	for rows.Next() {
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range cols {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
-		}
-
-		m := make(map[string]interface{})
-		for i, col := range cols {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				m[col] = string(b)
-			} else {
-				m[col] = val
-			}
-		}
-
-		var entity T
-		if err := db.MapToStruct(m, &entity); err != nil {
-			return nil, err
-		}
-		results = append(results, &entity)
+	// Страница перед курсором выбиралась в обратном порядке
+	if filter.Cursor != nil && filter.Cursor.Backward {
+		slices.Reverse(results)
 	}
-
 	return results, nil
 }
 
-func (r *PostgresRepository[T, ID]) Count(ctx context.Context, filter db.Filter) (int, error) {
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(fmt.Sprintf("SELECT COUNT(*) FROM %s", r.tableName))
-
-	args := []interface{}{}
-	argPos := 1
+// keyset дополняет WHERE условием "после курсора" и возвращает ORDER BY по ключам курсорного режима
+func (r *PostgresRepository[T, ID]) keyset(filter db.Filter, where string, args []interface{}) (string, string, []interface{}, error) {
+	keys, err := r.columns.KeysetSort(filter.OrderBy, r.idColumn)
+	if err != nil {
+		return "", "", nil, err
+	}
+	values, err := db.KeysetValues(keys, filter.Cursor)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if filter.Cursor.Backward {
+		keys = db.ReverseSort(keys)
+	}
+	orderBy := " ORDER BY " + db.FormatSort(keys)
+	if values == nil {
+		return where, orderBy, args, nil
+	}
 
-	if len(filter.Conditions) > 0 {
-		conds := make([]string, 0, len(filter.Conditions))
-		for _, cond := range filter.Conditions {
-			conds = append(conds, fmt.Sprintf("%s %s $%d", cond.Field, cond.Operator, argPos))
-			args = append(args, cond.Value)
-			argPos++
-		}
-		queryBuilder.WriteString(" WHERE " + strings.Join(conds, " AND "))
+	cond, condArgs := db.KeysetWhere(keys, values, len(args)+1)
+	if where == "" {
+		where = " WHERE " + cond
+	} else {
+		where += " AND " + cond
 	}
+	return where, orderBy, append(args, condArgs...), nil
+}
 
-	query := queryBuilder.String()
+func (r *PostgresRepository[T, ID]) Count(ctx context.Context, filter db.Filter) (int, error) {
+	where, _, args, err := r.where(filter)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", r.tableName, where)
 
 	var count int
-	err := r.QueryRowContext(ctx, query, args...).Scan(&count)
+	err = r.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
 func (r *PostgresRepository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
-	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE %s = $1)", r.tableName, r.idColumn)
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE %s = $1%s)", r.tableName, r.idColumn, r.alive())
 	var exists bool
 	err := r.QueryRowContext(ctx, query, id).Scan(&exists)
 	return exists, err
 }
-
-// getColumns возвращает список колонок таблицы
-func (r *PostgresRepository[T, ID]) getColumns(ctx context.Context) ([]string, error) {
-	query := fmt.Sprintf("SELECT column_name FROM information_schema.columns WHERE table_name = '%s' ORDER BY ordinal_position", r.tableName)
-	rows, err := r.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var columns []string
-	for rows.Next() {
-		var col string
-		if err := rows.Scan(&col); err != nil {
-			return nil, err
-		}
-		columns = append(columns, col)
-	}
-	return columns, nil
-}
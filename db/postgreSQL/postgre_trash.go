@@ -0,0 +1,81 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"GO_Music/db"
+
+	"github.com/lib/pq"
+)
+
+// foreignKeyViolation код ошибки PostgreSQL: на строку ссылаются другие таблицы
+const foreignKeyViolation = "23503"
+
+// uniqueViolation код ошибки PostgreSQL: нарушено ограничение уникальности
+const uniqueViolation = "23505"
+
+// duplicate переводит нарушение уникальности в db.ErrDuplicate; остальные ошибки возвращаются как есть
+func (r *PostgresRepository[T, ID]) duplicate(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s: %s", db.ErrDuplicate, r.tableName, pqErr.Detail)
+	}
+	return err
+}
+
+// alive дополняет WHERE по id условием "строка не удалена"; пусто для сущностей без deleted_at
+func (r *PostgresRepository[T, ID]) alive() string {
+	if !r.columns.SoftDelete() {
+		return ""
+	}
+	return " AND " + db.DeletedAtColumn + " IS NULL"
+}
+
+// [RU] Restore возвращает мягко удаленную запись; sql.ErrNoRows, если в корзине такой записи нет <--->
+// [ENG] Restore brings back a soft deleted row; sql.ErrNoRows if the row is not in the trash
+func (r *PostgresRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	scope, err := r.columns.Scope(true)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s = $1 AND %s", r.tableName, db.DeletedAtColumn, r.idColumn, scope)
+	return r.execOne(ctx, query, id)
+}
+
+// [RU] Purge окончательно удаляет запись из корзины. Если на нее ссылаются другие строки,
+// возвращается ошибка db.ErrReferenced <--->
+// [ENG] Purge removes a row from the trash for good. If other rows reference it,
+// a db.ErrReferenced error is returned
+func (r *PostgresRepository[T, ID]) Purge(ctx context.Context, id ID) error {
+	scope, err := r.columns.Scope(true)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND %s", r.tableName, r.idColumn, scope)
+	err = r.execOne(ctx, query, id)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w: %s %v: %s", db.ErrReferenced, r.tableName, id, pqErr.Detail)
+	}
+	return err
+}
+
+// execOne выполняет запрос, который должен затронуть ровно одну строку; иначе sql.ErrNoRows
+func (r *PostgresRepository[T, ID]) execOne(ctx context.Context, query string, id ID) error {
+	res, err := r.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
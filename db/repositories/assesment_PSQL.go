@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type StudentAssessmentRepository struct {
-	*postgreSQL.PostgresRepository[domain.StudentAssessment, int]
+	db.SQLRepository[domain.StudentAssessment, int]
 }
 
+// assessmentSearchColumns колонки для поиска по ?search=
+var assessmentSearchColumns = []string{"task_type"}
+
 func NewStudentAssessmentRepository(db *sql.DB) *StudentAssessmentRepository {
 	return &StudentAssessmentRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.StudentAssessment, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.StudentAssessment, int](
 			db,
 			"student_assessment", // имя таблицы
 			"assessment_note_id", // имя поля с ID
-		),
+		).Searchable(assessmentSearchColumns...),
 	}
 }
@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type AudienceRepository struct {
-	*postgreSQL.PostgresRepository[domain.Audience, int]
+	db.SQLRepository[domain.Audience, int]
 }
 
+// audienceSearchColumns колонки для поиска по ?search=
+var audienceSearchColumns = []string{"name", "audin_type", "audin_number"}
+
 func NewAudienceRepository(db *sql.DB) *AudienceRepository {
 	return &AudienceRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.Audience, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Audience, int](
 			db,
 			"audience",    // имя таблицы
 			"audience_id", // имя поля с ID
-		),
+		).Searchable(audienceSearchColumns...),
 	}
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type AuditRepository struct {
+	db.SQLRepository[domain.AuditEntry, int]
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.AuditEntry, int](
+			db,
+			"audit_log", // имя таблицы
+			"audit_id",  // имя поля с ID
+		),
+	}
+}
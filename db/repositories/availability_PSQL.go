@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type AvailabilityRepository struct {
+	db.SQLRepository[domain.EmployeeAvailability, int]
+}
+
+func NewAvailabilityRepository(db *sql.DB) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.EmployeeAvailability, int](
+			db,
+			"employee_availability", // имя таблицы
+			"availability_id",       // имя поля с ID
+		),
+	}
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type CalendarRepository struct {
+	db.SQLRepository[domain.CalendarEntry, int]
+}
+
+func NewCalendarRepository(db *sql.DB) *CalendarRepository {
+	return &CalendarRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.CalendarEntry, int](
+			db,
+			"academic_calendar", // имя таблицы
+			"calendar_id",       // имя поля с ID
+		),
+	}
+}
@@ -3,17 +3,18 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type ProgrammDistributionRepository struct {
-	*postgreSQL.PostgresRepository[domain.ProgrammDistribution, int]
+	db.SQLRepository[domain.ProgrammDistribution, int]
 }
 
 func NewProgrammDistributionRepository(db *sql.DB) *ProgrammDistributionRepository {
 	return &ProgrammDistributionRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.ProgrammDistribution, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.ProgrammDistribution, int](
 			db,
 			"programm_distribution", // имя таблицы
 			"programm_distr_id",     // имя поля с ID
@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type EmployeeRepository struct {
-	*postgreSQL.PostgresRepository[domain.Employee, int]
+	db.SQLRepository[domain.Employee, int]
 }
 
+// employeeSearchColumns колонки для поиска по ?search=
+var employeeSearchColumns = []string{"surname", "name", "father_name", "job"}
+
 func NewEmployeeRepository(db *sql.DB) *EmployeeRepository {
 	return &EmployeeRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.Employee, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Employee, int](
 			db,
 			"employee",    // имя таблицы
 			"employee_id", // имя поля с ID
-		),
+		).Searchable(employeeSearchColumns...),
 	}
 }
@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type StudyGroupRepository struct {
-	*postgreSQL.PostgresRepository[domain.StudyGroup, int]
+	db.SQLRepository[domain.StudyGroup, int]
 }
 
+// studyGroupSearchColumns колонки для поиска по ?search=
+var studyGroupSearchColumns = []string{"group_name"}
+
 func NewStudyGroupRepository(db *sql.DB) *StudyGroupRepository {
 	return &StudyGroupRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.StudyGroup, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.StudyGroup, int](
 			db,
 			"study_group", // имя таблицы
 			"group_id",    // имя поля с ID
-		),
+		).Searchable(studyGroupSearchColumns...),
 	}
 }
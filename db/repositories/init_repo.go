@@ -2,6 +2,10 @@ package repositories
 
 import (
 	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/memory"
+	"GO_Music/domain"
 )
 
 // Repositories содержит все репозитории приложения
@@ -12,6 +16,7 @@ type Repositories struct {
 	Employee      *EmployeeRepository
 	StudyGroup    *StudyGroupRepository
 	Schedule      *ScheduleRepository
+	ScheduleExc   *ScheduleExceptionRepository
 	Instrument    *InstrumentRepository
 	ProgrammDistr *ProgrammDistributionRepository
 	SubjectDistr  *SubjectDistributionRepository
@@ -20,6 +25,14 @@ type Repositories struct {
 	Student       *StudentRepository
 	Subject       *SubjectRepository
 	User          *UserRepository
+	Audit         *AuditRepository
+	Outbox        *OutboxRepository
+	Waitlist      *WaitlistRepository
+	Calendar      *CalendarRepository
+	Timetable     *TimetableDraftRepository
+	Availability  *AvailabilityRepository
+	Substitution  *SubstitutionRepository
+	Occurrence    *LessonOccurrenceRepository
 }
 
 // NewRepositories создает все репозитории
@@ -31,6 +44,7 @@ func NewRepositories(db *sql.DB) *Repositories {
 		Employee:      NewEmployeeRepository(db),
 		StudyGroup:    NewStudyGroupRepository(db),
 		Schedule:      NewScheduleRepository(db),
+		ScheduleExc:   NewScheduleExceptionRepository(db),
 		Instrument:    NewInstrumentRepository(db),
 		ProgrammDistr: NewProgrammDistributionRepository(db),
 		SubjectDistr:  NewSubjectDistributionRepository(db),
@@ -39,5 +53,57 @@ func NewRepositories(db *sql.DB) *Repositories {
 		Student:       NewStudentRepository(db),
 		Subject:       NewSubjectRepository(db),
 		User:          NewUserRepository(db),
+		Audit:         NewAuditRepository(db),
+		Outbox:        NewOutboxRepository(db),
+		Waitlist:      NewWaitlistRepository(db),
+		Calendar:      NewCalendarRepository(db),
+		Timetable:     NewTimetableDraftRepository(db),
+		Availability:  NewAvailabilityRepository(db),
+		Substitution:  NewSubstitutionRepository(db),
+		Occurrence:    NewLessonOccurrenceRepository(db),
 	}
 }
+
+// [RU] NewMemoryRepositories создает все репозитории поверх хранилища в памяти (unit-тесты, демо-режим).
+// Специфичные методы с SQL-запросами (статистика, проверки занятости) в этом режиме возвращают memory.ErrRawSQL <--->
+// [ENG] NewMemoryRepositories creates all repositories on top of an in-memory store (unit tests, demo mode).
+// Entity-specific methods backed by SQL (statistics, availability checks) return memory.ErrRawSQL in this mode
+func NewMemoryRepositories(store *memory.Store) *Repositories {
+	return &Repositories{
+		Audience:   &AudienceRepository{SQLRepository: memoryRepo[domain.Audience](store, "audience", "audience_id", audienceSearchColumns...)},
+		Assessment: &StudentAssessmentRepository{SQLRepository: memoryRepo[domain.StudentAssessment](store, "student_assessment", "assessment_note_id", assessmentSearchColumns...)},
+		Attendance: &StudentAttendanceRepository{
+			// Ключ student_attendance_occurrence_key миграции 0014_attendance_occurrence_key
+			SQLRepository: memory.NewMemoryRepository[domain.StudentAttendance, int](store, "student_attendance", "attendance_note_id").
+				Unique("occurrence_id", "student_id"),
+		},
+		Employee:      &EmployeeRepository{SQLRepository: memoryRepo[domain.Employee](store, "employee", "employee_id", employeeSearchColumns...)},
+		StudyGroup:    &StudyGroupRepository{SQLRepository: memoryRepo[domain.StudyGroup](store, "study_group", "group_id", studyGroupSearchColumns...)},
+		Schedule:      &ScheduleRepository{SQLRepository: memoryRepo[domain.Schedule](store, "schedule", "schedule_id", scheduleSearchColumns...)},
+		ScheduleExc:   &ScheduleExceptionRepository{SQLRepository: memoryRepo[domain.ScheduleException](store, "schedule_exception", "exception_id")},
+		Instrument:    &InstrumentRepository{SQLRepository: memoryRepo[domain.Instrument](store, "instrument", "instrument_id", instrumentSearchColumns...)},
+		ProgrammDistr: &ProgrammDistributionRepository{SQLRepository: memoryRepo[domain.ProgrammDistribution](store, "programm_distribution", "programm_distr_id")},
+		SubjectDistr:  &SubjectDistributionRepository{SQLRepository: memoryRepo[domain.SubjectDistribution](store, "subject_distribution", "subject_distr_id")},
+		Lesson:        &LessonRepository{SQLRepository: memoryRepo[domain.Lesson](store, "lesson", "lesson_id", lessonSearchColumns...)},
+		Programm:      &ProgrammRepository{SQLRepository: memoryRepo[domain.Programm](store, "programm", "musprogramm_id", programmSearchColumns...)},
+		Student:       &StudentRepository{SQLRepository: memoryRepo[domain.Student](store, "student", "student_id", studentSearchColumns...)},
+		Subject:       &SubjectRepository{SQLRepository: memoryRepo[domain.Subject](store, "subject", "subject_id", subjectSearchColumns...)},
+		User:          &UserRepository{SQLRepository: memoryRepo[domain.User](store, "users", "user_id", userSearchColumns...)},
+		Audit:         &AuditRepository{SQLRepository: memoryRepo[domain.AuditEntry](store, "audit_log", "audit_id")},
+		Outbox:        &OutboxRepository{SQLRepository: memoryRepo[domain.OutboxMessage](store, "outbox", "outbox_id")},
+		Waitlist:      &WaitlistRepository{SQLRepository: memoryRepo[domain.WaitlistEntry](store, "group_waitlist", "waitlist_id")},
+		Calendar:      &CalendarRepository{SQLRepository: memoryRepo[domain.CalendarEntry](store, "academic_calendar", "calendar_id")},
+		Timetable:     &TimetableDraftRepository{SQLRepository: memoryRepo[domain.TimetableDraft](store, "timetable_draft", "draft_id")},
+		Availability:  &AvailabilityRepository{SQLRepository: memoryRepo[domain.EmployeeAvailability](store, "employee_availability", "availability_id")},
+		Substitution:  &SubstitutionRepository{SQLRepository: memoryRepo[domain.Substitution](store, "substitution", "substitution_id")},
+		Occurrence: &LessonOccurrenceRepository{
+			// Ключ lesson_occurrence_key миграции 0013_lesson_occurrence
+			SQLRepository: memory.NewMemoryRepository[domain.LessonOccurrence, int](store, "lesson_occurrence", "occurrence_id").
+				Unique("schedule_id", "occurrence_date"),
+		},
+	}
+}
+
+func memoryRepo[T any](store *memory.Store, tableName, idColumn string, searchColumns ...string) db.SQLRepository[T, int] {
+	return memory.NewMemoryRepository[T, int](store, tableName, idColumn).Searchable(searchColumns...)
+}
@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type InstrumentRepository struct {
-	*postgreSQL.PostgresRepository[domain.Instrument, int]
+	db.SQLRepository[domain.Instrument, int]
 }
 
+// instrumentSearchColumns колонки для поиска по ?search=
+var instrumentSearchColumns = []string{"name", "instr_type", "condition"}
+
 func NewInstrumentRepository(db *sql.DB) *InstrumentRepository {
 	return &InstrumentRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.Instrument, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Instrument, int](
 			db,
 			"instrument",    // имя таблицы
 			"instrument_id", // имя поля с ID
-		),
+		).Searchable(instrumentSearchColumns...),
 	}
 }
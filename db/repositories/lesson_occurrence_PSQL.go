@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type LessonOccurrenceRepository struct {
+	db.SQLRepository[domain.LessonOccurrence, int]
+}
+
+func NewLessonOccurrenceRepository(db *sql.DB) *LessonOccurrenceRepository {
+	return &LessonOccurrenceRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.LessonOccurrence, int](
+			db,
+			"lesson_occurrence", // имя таблицы
+			"occurrence_id",     // имя поля с ID
+		),
+	}
+}
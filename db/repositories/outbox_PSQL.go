@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type OutboxRepository struct {
+	db.SQLRepository[domain.OutboxMessage, int]
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.OutboxMessage, int](
+			db,
+			"outbox",    // имя таблицы
+			"outbox_id", // имя поля с ID
+		),
+	}
+}
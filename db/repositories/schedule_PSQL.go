@@ -3,20 +3,24 @@ package repositories
 import (
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type ScheduleRepository struct {
-	*postgreSQL.PostgresRepository[domain.Schedule, int]
+	db.SQLRepository[domain.Schedule, int]
 }
 
+// scheduleSearchColumns колонки для поиска по ?search=
+var scheduleSearchColumns = []string{"day_week"}
+
 func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
 	return &ScheduleRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.Schedule, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Schedule, int](
 			db,
 			"schedule",    // имя таблицы
 			"schedule_id", // имя поля с ID
-		),
+		).Searchable(scheduleSearchColumns...),
 	}
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type ScheduleExceptionRepository struct {
+	db.SQLRepository[domain.ScheduleException, int]
+}
+
+func NewScheduleExceptionRepository(db *sql.DB) *ScheduleExceptionRepository {
+	return &ScheduleExceptionRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.ScheduleException, int](
+			db,
+			"schedule_exception", // имя таблицы
+			"exception_id",       // имя поля с ID
+		),
+	}
+}
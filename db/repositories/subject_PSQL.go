@@ -4,29 +4,34 @@ import (
 	"context"
 	"database/sql"
 
+	"GO_Music/db"
 	"GO_Music/db/postgreSQL"
 	"GO_Music/domain"
 )
 
 type SubjectRepository struct {
-	*postgreSQL.PostgresRepository[domain.Subject, int]
+	db.SQLRepository[domain.Subject, int]
 }
 
+// subjectSearchColumns колонки для поиска по ?search=
+var subjectSearchColumns = []string{"subject_name", "subject_type", "short_desc"}
+
 func NewSubjectRepository(db *sql.DB) *SubjectRepository {
 	return &SubjectRepository{
-		PostgresRepository: postgreSQL.NewPostgresRepository[domain.Subject, int](
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Subject, int](
 			db,
 			"subject",    // имя таблицы
 			"subject_id", // имя поля с ID
-		),
+		).Searchable(subjectSearchColumns...),
 	}
 }
 
-// Кастомные SQL-запросы для предметов
+// Кастомные SQL-запросы для предметов; удаленные предметы и распределения не учитываются
 const (
 	getPopularSubjectsQuery = `
 		SELECT s.* FROM subject s
 		JOIN programm_distribution pd ON s.subject_id = pd.subject_id
+		WHERE s.deleted_at IS NULL AND pd.deleted_at IS NULL
 		GROUP BY s.subject_id
 		ORDER BY COUNT(pd.musprogramm_id) DESC
 		LIMIT $1`
@@ -34,7 +39,7 @@ const (
 	getSubjectsWithProgramsQuery = `
 		SELECT s.* FROM subject s
 		JOIN programm_distribution pd ON s.subject_id = pd.subject_id
-		WHERE pd.musprogramm_id = $1
+		WHERE pd.musprogramm_id = $1 AND s.deleted_at IS NULL AND pd.deleted_at IS NULL
 		ORDER BY s.subject_name`
 )
 
@@ -58,20 +63,7 @@ func (r *SubjectRepository) GetSubjectsWithPrograms(ctx context.Context, program
 	return r.scanSubjectRows(rows)
 }
 
+// scanSubjectRows читает s.* по именам колонок, поэтому новые колонки таблицы не ломают запросы
 func (r *SubjectRepository) scanSubjectRows(rows *sql.Rows) ([]*domain.Subject, error) {
-	var subjects []*domain.Subject
-	for rows.Next() {
-		var s domain.Subject
-		err := rows.Scan(
-			&s.SubjectID,
-			&s.SubjectName,
-			&s.SubjectType,
-			&s.ShortDesc,
-		)
-		if err != nil {
-			return nil, err
-		}
-		subjects = append(subjects, &s)
-	}
-	return subjects, nil
+	return db.ScannerOf[domain.Subject]().ScanRows(rows)
 }
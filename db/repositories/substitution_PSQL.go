@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type SubstitutionRepository struct {
+	db.SQLRepository[domain.Substitution, int]
+}
+
+func NewSubstitutionRepository(db *sql.DB) *SubstitutionRepository {
+	return &SubstitutionRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.Substitution, int](
+			db,
+			"substitution",    // имя таблицы
+			"substitution_id", // имя поля с ID
+		),
+	}
+}
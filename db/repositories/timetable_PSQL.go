@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type TimetableDraftRepository struct {
+	db.SQLRepository[domain.TimetableDraft, int]
+}
+
+func NewTimetableDraftRepository(db *sql.DB) *TimetableDraftRepository {
+	return &TimetableDraftRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.TimetableDraft, int](
+			db,
+			"timetable_draft", // имя таблицы
+			"draft_id",        // имя поля с ID
+		),
+	}
+}
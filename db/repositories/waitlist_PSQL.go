@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"GO_Music/db"
+	"GO_Music/db/postgreSQL"
+	"GO_Music/domain"
+)
+
+type WaitlistRepository struct {
+	db.SQLRepository[domain.WaitlistEntry, int]
+}
+
+func NewWaitlistRepository(db *sql.DB) *WaitlistRepository {
+	return &WaitlistRepository{
+		SQLRepository: postgreSQL.NewPostgresRepository[domain.WaitlistEntry, int](
+			db,
+			"group_waitlist", // имя таблицы
+			"waitlist_id",    // имя поля с ID
+		),
+	}
+}
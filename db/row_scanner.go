@@ -0,0 +1,262 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scanField колонка сущности и функция записи значения драйвера в поле
+type scanField struct {
+	column string
+	index  int
+	set    func(fv reflect.Value, src interface{}) error
+}
+
+// [RU] RowScanner сканирует строки результата прямо в поля T; метаданные полей строятся один раз на тип <--->
+// [ENG] RowScanner scans result rows straight into the fields of T; field metadata is built once per type
+type RowScanner[T any] struct {
+	fields     []scanField
+	byColumn   map[string]int // колонка -> индекс в fields
+	selectList string
+}
+
+var scannersCache sync.Map // reflect.Type -> *RowScanner[T]
+
+// [RU] ScannerOf возвращает RowScanner сущности T; колонки те же, что у ColumnsOf <--->
+// [ENG] ScannerOf returns the RowScanner of entity T; the columns are the same as in ColumnsOf
+func ScannerOf[T any]() *RowScanner[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := scannersCache.Load(t); ok {
+		return cached.(*RowScanner[T])
+	}
+
+	s := &RowScanner[T]{byColumn: make(map[string]int)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := ColumnName(field)
+		if field.PkgPath != "" || column == "-" {
+			continue
+		}
+		s.byColumn[column] = len(s.fields)
+		s.fields = append(s.fields, scanField{column: column, index: i, set: setterFor(field.Type)})
+	}
+
+	columns := make([]string, len(s.fields))
+	for i, f := range s.fields {
+		columns[i] = f.column
+	}
+	s.selectList = strings.Join(columns, ", ")
+
+	actual, _ := scannersCache.LoadOrStore(t, s)
+	return actual.(*RowScanner[T])
+}
+
+// [RU] SelectList возвращает список колонок для SELECT в порядке, который ожидает ScanRow <--->
+// [ENG] SelectList returns the SELECT column list in the order ScanRow expects
+func (s *RowScanner[T]) SelectList() string {
+	return s.selectList
+}
+
+// [RU] ScanRow читает одну строку, выбранную через SelectList; sql.ErrNoRows возвращается как есть <--->
+// [ENG] ScanRow reads a single row selected with SelectList; sql.ErrNoRows is returned as is
+func (s *RowScanner[T]) ScanRow(row *sql.Row) (*T, error) {
+	var entity T
+	v := reflect.ValueOf(&entity).Elem()
+
+	dest := make([]interface{}, len(s.fields))
+	targets := make([]fieldTarget, len(s.fields))
+	for i := range s.fields {
+		targets[i] = fieldTarget{field: &s.fields[i], value: v.Field(s.fields[i].index)}
+		dest[i] = &targets[i]
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// [RU] ScanRows читает все строки; колонки сопоставляются по имени, лишние колонки результата пропускаются <--->
+// [ENG] ScanRows reads all rows; columns are matched by name, extra result columns are skipped
+func (s *RowScanner[T]) ScanRows(rows *sql.Rows) ([]*T, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	// Назначения сканирования создаются один раз на запрос и перенацеливаются на каждую новую сущность
+	dest := make([]interface{}, len(cols))
+	targets := make([]fieldTarget, len(cols))
+	for i, col := range cols {
+		if idx, ok := s.byColumn[col]; ok {
+			targets[i].field = &s.fields[idx]
+			dest[i] = &targets[i]
+		} else {
+			dest[i] = new(interface{})
+		}
+	}
+
+	var results []*T
+	for rows.Next() {
+		entity := new(T)
+		v := reflect.ValueOf(entity).Elem()
+		for i := range targets {
+			if targets[i].field != nil {
+				targets[i].value = v.Field(targets[i].field.index)
+			}
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		results = append(results, entity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// fieldTarget sql.Scanner, который пишет значение колонки в поле текущей сущности
+type fieldTarget struct {
+	field *scanField
+	value reflect.Value
+}
+
+func (t *fieldTarget) Scan(src interface{}) error {
+	if err := t.field.set(t.value, src); err != nil {
+		return fmt.Errorf("column %s: %w", t.field.column, err)
+	}
+	return nil
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// [RU] setterFor выбирает функцию записи по типу поля. Указатели (*string, *int, *time.Time) получают nil для NULL;
+// обычные поля при NULL остаются нулевыми, как в MapToStruct <--->
+// [ENG] setterFor picks the setter by field type. Pointers (*string, *int, *time.Time) get nil for NULL;
+// plain fields stay zero on NULL, as in MapToStruct
+func setterFor(t reflect.Type) func(reflect.Value, interface{}) error {
+	if t.Kind() == reflect.Pointer && !reflect.PointerTo(t).Implements(scannerType) {
+		elem := setterFor(t.Elem())
+		return func(fv reflect.Value, src interface{}) error {
+			if src == nil {
+				fv.SetZero()
+				return nil
+			}
+			ptr := reflect.New(t.Elem())
+			if err := elem(ptr.Elem(), src); err != nil {
+				return err
+			}
+			fv.Set(ptr)
+			return nil
+		}
+	}
+
+	set := valueSetter(t)
+	return func(fv reflect.Value, src interface{}) error {
+		if src == nil {
+			fv.SetZero()
+			return nil
+		}
+		return set(fv, src)
+	}
+}
+
+// valueSetter запись значения, отличного от NULL; типы значений - те, что возвращает lib/pq
+func valueSetter(t reflect.Type) func(reflect.Value, interface{}) error {
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return func(fv reflect.Value, src interface{}) error {
+			return fv.Addr().Interface().(sql.Scanner).Scan(src)
+		}
+	}
+
+	switch {
+	case t == timeType:
+		return func(fv reflect.Value, src interface{}) error {
+			if v, ok := src.(time.Time); ok {
+				fv.Set(reflect.ValueOf(v))
+				return nil
+			}
+			return setFieldValue(fv, src)
+		}
+	case t == bytesType:
+		return func(fv reflect.Value, src interface{}) error {
+			switch v := src.(type) {
+			case []byte:
+				// Буфер драйвера переиспользуется при следующем Next, поэтому копируем
+				fv.SetBytes(append([]byte(nil), v...))
+			case string:
+				fv.SetBytes([]byte(v))
+			default:
+				return fmt.Errorf("cannot convert %T to []byte", src)
+			}
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(fv reflect.Value, src interface{}) error {
+			switch v := src.(type) {
+			case string:
+				fv.SetString(v)
+			case []byte:
+				fv.SetString(string(v))
+			default:
+				return setFieldValue(fv, src)
+			}
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(fv reflect.Value, src interface{}) error {
+			switch v := src.(type) {
+			case int64:
+				fv.SetInt(v)
+			case []byte:
+				n, err := strconv.ParseInt(string(v), 10, 64)
+				if err != nil {
+					return fmt.Errorf("cannot convert %q to int", v)
+				}
+				fv.SetInt(n)
+			default:
+				return setFieldValue(fv, src)
+			}
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(fv reflect.Value, src interface{}) error {
+			switch v := src.(type) {
+			case float64:
+				fv.SetFloat(v)
+			case []byte:
+				f, err := strconv.ParseFloat(string(v), 64)
+				if err != nil {
+					return fmt.Errorf("cannot convert %q to float", v)
+				}
+				fv.SetFloat(f)
+			default:
+				return setFieldValue(fv, src)
+			}
+			return nil
+		}
+	case reflect.Bool:
+		return func(fv reflect.Value, src interface{}) error {
+			if v, ok := src.(bool); ok {
+				fv.SetBool(v)
+				return nil
+			}
+			return setFieldValue(fv, src)
+		}
+	}
+	return setFieldValue
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DeletedAtColumn колонка мягкого удаления (миграция 0004_soft_delete)
+const DeletedAtColumn = "deleted_at"
+
+// ErrReferenced возвращается, если строку нельзя удалить окончательно: на нее ссылаются другие строки
+var ErrReferenced = errors.New("row is referenced by other rows")
+
+// [RU] ErrDuplicate возвращается, если запись нарушает ограничение уникальности. Удаленные строки
+// в корзине продолжают занимать свои ключи, пока их не удалят окончательно (Purge) <--->
+// [ENG] ErrDuplicate is returned when a row violates a unique constraint. Deleted rows in the trash
+// keep reserving their keys until they are purged
+var ErrDuplicate = errors.New("duplicate key")
+
+// [RU] SoftDelete сообщает, что у сущности есть колонка deleted_at: Delete только помечает строку,
+// а выборки ее пропускают <--->
+// [ENG] SoftDelete reports that the entity has the deleted_at column: Delete only marks the row
+// and reads skip it
+func (c Columns) SoftDelete() bool {
+	return slices.Contains(c.names, DeletedAtColumn)
+}
+
+// [RU] Scope возвращает условие видимости строк: активные или, для trash, только удаленные.
+// Пустая строка - сущность без мягкого удаления <--->
+// [ENG] Scope returns the row visibility condition: active rows or, for trash, deleted rows only.
+// An empty string means the entity has no soft delete
+func (c Columns) Scope(trash bool) (string, error) {
+	switch {
+	case !c.SoftDelete() && trash:
+		return "", fmt.Errorf("%w: entity has no %s column", ErrInvalidFilter, DeletedAtColumn)
+	case !c.SoftDelete():
+		return "", nil
+	case trash:
+		return DeletedAtColumn + " IS NOT NULL", nil
+	default:
+		return DeletedAtColumn + " IS NULL", nil
+	}
+}
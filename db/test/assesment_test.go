@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,22 +14,10 @@ import (
 )
 
 func TestStudentAssessmentRepository_AllMethods(t *testing.T) {
-	// Загрузка конфигурации
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -38,8 +26,8 @@ func TestStudentAssessmentRepository_AllMethods(t *testing.T) {
 
 	// Тестовые данные
 	testAssessment := &domain.StudentAssessment{
-		StudentID:      2,
-		LessonID:       2,
+		StudentID:      fx.Students[1].StudentID,
+		LessonID:       fx.Lessons[1].LessonID,
 		Grade:          5,
 		TaskType:       "test",
 		AssessmentDate: time.Now(),
@@ -193,8 +181,8 @@ func TestStudentAssessmentRepository_AllMethods(t *testing.T) {
 
 	t.Run("GetByIDs", func(t *testing.T) {
 		secondAssessment := &domain.StudentAssessment{
-			StudentID:      3,
-			LessonID:       3,
+			StudentID:      fx.Students[2].StudentID,
+			LessonID:       fx.Lessons[2].LessonID,
 			Grade:          4,
 			TaskType:       "test_2",
 			AssessmentDate: time.Now(),
@@ -2,11 +2,12 @@ package db_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +15,9 @@ import (
 )
 
 func TestAudienceRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
+	t.Parallel()
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB := dbtest.New(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -261,4 +250,20 @@ func TestAudienceRepository_AllMethods(t *testing.T) {
 			t.Error("Expected audience to be deleted")
 		}
 	})
+
+	t.Run("Deleted row keeps its number", func(t *testing.T) {
+		duplicate := &domain.Audience{
+			Name:        "Duplicate Audience",
+			AudinType:   "Lecture Hall",
+			AudinNumber: testAudience.AudinNumber,
+			Capacity:    20,
+		}
+		if err := repo.Create(ctx, duplicate); !errors.Is(err, db.ErrDuplicate) {
+			t.Errorf("Expected ErrDuplicate for number of a deleted audience, got %v", err)
+		}
+
+		if err := repo.Restore(ctx, testAudience.AudienceID); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"GO_Music/db"
+	"GO_Music/domain"
 )
 
 type TestUser struct {
@@ -108,6 +109,30 @@ func TestStructToMap(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Relation fields are skipped",
+			input: domain.Lesson{
+				LessonID:   5,
+				EmployeeID: 1,
+				GroupID:    2,
+				LessonName: "Сольфеджио",
+				SubjectID:  3,
+				Version:    2,
+				Employee:   &domain.Employee{EmployeeID: 1},
+			},
+			expected: map[string]interface{}{
+				"lesson_id":   5,
+				"audience_id": nil,
+				"employee_id": 1,
+				"group_id":    2,
+				"student_id":  nil,
+				"lesson_name": "Сольфеджио",
+				"subject_id":  3,
+				"version":     2,
+				"deleted_at":  nil,
+			},
+			wantErr: false,
+		},
 		{
 			name:     "Non-struct input",
 			input:    "not a struct",
@@ -1,34 +1,19 @@
 package db_test
 
 import (
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"testing"
 )
 
 func TestInitPostgresDB(t *testing.T) {
-	// [RU] Укажите путь к вашему реальному конфиг-файлу;
-	// [ENG] Write path for your database config file
-	cfgPath := "../../config/DB_config.yml"
+	t.Parallel()
 
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("Failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer sqlDB.Close()
-
-	err = sqlDB.Ping()
-	if err != nil {
-		t.Fatalf("Failed to ping database: %v", err)
-	}
+	// [RU] Подключение к тестовой схеме из GO_MUSIC_TEST_DSN или локального postgres;
+	// [ENG] Connects to a throwaway schema from GO_MUSIC_TEST_DSN or a local postgres
+	sqlDB := dbtest.New(t)
 
 	var result int
-	err = sqlDB.QueryRow("SELECT 1").Scan(&result)
+	err := sqlDB.QueryRow("SELECT 1").Scan(&result)
 	if err != nil {
 		t.Fatalf("Failed to execute test query: %v", err)
 	}
@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestProgrammDistributionRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -36,8 +24,8 @@ func TestProgrammDistributionRepository_AllMethods(t *testing.T) {
 	repo := repositories.NewProgrammDistributionRepository(sqlDB)
 
 	testPD := &domain.ProgrammDistribution{
-		MusprogrammID: 1,
-		SubjectID:     2,
+		MusprogrammID: fx.Programms[0].MusprogrammID,
+		SubjectID:     fx.Subjects[1].SubjectID,
 	}
 
 	t.Run("Create", func(t *testing.T) {
@@ -91,8 +79,8 @@ func TestProgrammDistributionRepository_AllMethods(t *testing.T) {
 		repoWithTx := repo.WithTx(tx)
 
 		updatedPD := *testPD
-		updatedPD.MusprogrammID = 3
-		updatedPD.SubjectID = 4
+		updatedPD.MusprogrammID = fx.Programms[1].MusprogrammID
+		updatedPD.SubjectID = fx.Subjects[2].SubjectID
 
 		err = repoWithTx.Update(ctx, &updatedPD)
 		if err != nil {
@@ -195,8 +183,8 @@ func TestProgrammDistributionRepository_AllMethods(t *testing.T) {
 
 	t.Run("GetByIDs", func(t *testing.T) {
 		secondPD := &domain.ProgrammDistribution{
-			MusprogrammID: 5,
-			SubjectID:     5,
+			MusprogrammID: fx.Programms[2].MusprogrammID,
+			SubjectID:     fx.Subjects[0].SubjectID,
 		}
 
 		tx, err := sqlDB.BeginTx(ctx, nil)
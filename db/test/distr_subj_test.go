@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestSubjectDistributionRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -36,8 +24,8 @@ func TestSubjectDistributionRepository_AllMethods(t *testing.T) {
 	repo := repositories.NewSubjectDistributionRepository(sqlDB)
 
 	testSD := &domain.SubjectDistribution{
-		EmployeeID: 6, // укажите валидные ID существующих записей
-		SubjectID:  2,
+		EmployeeID: fx.Employees[0].EmployeeID,
+		SubjectID:  fx.Subjects[1].SubjectID,
 	}
 
 	t.Run("Create", func(t *testing.T) {
@@ -91,8 +79,8 @@ func TestSubjectDistributionRepository_AllMethods(t *testing.T) {
 		repoWithTx := repo.WithTx(tx)
 
 		updatedSD := *testSD
-		updatedSD.EmployeeID = 7
-		updatedSD.SubjectID = 4
+		updatedSD.EmployeeID = fx.Employees[1].EmployeeID
+		updatedSD.SubjectID = fx.Subjects[2].SubjectID
 
 		err = repoWithTx.Update(ctx, &updatedSD)
 		if err != nil {
@@ -195,8 +183,8 @@ func TestSubjectDistributionRepository_AllMethods(t *testing.T) {
 
 	t.Run("GetByIDs", func(t *testing.T) {
 		secondSD := &domain.SubjectDistribution{
-			EmployeeID: 8,
-			SubjectID:  2,
+			EmployeeID: fx.Employees[2].EmployeeID,
+			SubjectID:  fx.Subjects[0].SubjectID,
 		}
 
 		tx, err := sqlDB.BeginTx(ctx, nil)
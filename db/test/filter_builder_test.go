@@ -0,0 +1,139 @@
+package db_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+)
+
+func TestColumnsOf(t *testing.T) {
+	cols := db.ColumnsOf[TestUser]()
+
+	expected := []string{"user_id", "user_name", "email_address", "created_at", "is_active"}
+	if !reflect.DeepEqual(cols.Names(), expected) {
+		t.Errorf("Names() = %v, want %v", cols.Names(), expected)
+	}
+
+	// json-имя поля StudyGroup отличается от колонки
+	groupCols := db.ColumnsOf[domain.StudyGroup]()
+	for _, name := range []string{"mus_programm_id", "musprogramm_id"} {
+		column, err := groupCols.Resolve(name)
+		if err != nil {
+			t.Fatalf("Resolve(%q) failed: %v", name, err)
+		}
+		if column != "mus_programm_id" {
+			t.Errorf("Resolve(%q) = %q, want mus_programm_id", name, column)
+		}
+	}
+
+	_, err := cols.Resolve("secret")
+	if !errors.Is(err, db.ErrInvalidFilter) {
+		t.Fatalf("Expected ErrInvalidFilter for unexported field, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"secret"`) || !strings.Contains(err.Error(), "user_name") {
+		t.Errorf("Error should name the field and list allowed ones: %v", err)
+	}
+}
+
+func TestColumns_Sort(t *testing.T) {
+	cols := db.ColumnsOf[domain.Student]()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"Prefix syntax", "-birthday,surname", "birthday DESC, surname", false},
+		{"SQL syntax", "group_id DESC, surname ASC", "group_id DESC, surname", false},
+		{"Json alias", "-student_id", "student_id DESC", false},
+		{"Unknown column", "password", "", true},
+		{"Injection in column", "surname; DROP TABLE student", "", true},
+		{"Injection in direction", "surname DESC NULLS FIRST", "", true},
+		{"Bad direction", "surname SIDEWAYS", "", true},
+		{"Conflicting direction", "-surname ASC", "", true},
+		{"Empty item", "surname,,name", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := cols.Sort(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, db.ErrInvalidFilter) {
+					t.Errorf("Expected ErrInvalidFilter, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Sort failed: %v", err)
+			}
+			if got := db.FormatSort(fields); got != tt.expected {
+				t.Errorf("FormatSort = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColumns_Where(t *testing.T) {
+	cols := db.ColumnsOf[domain.Student]()
+
+	where, args, err := cols.Where([]db.Condition{
+		{Field: "group_id", Operator: "=", Value: 1},
+		{Field: "phone_number", Operator: "is  not null"},
+		{Field: "surname", Operator: "ilike", Value: "%ов"},
+		{Field: "birthday", Operator: "<=", Value: "2010-01-01"},
+	})
+	if err != nil {
+		t.Fatalf("Where failed: %v", err)
+	}
+
+	expected := " WHERE group_id = $1 AND phone_number IS NOT NULL AND surname ILIKE $2 AND birthday <= $3"
+	if where != expected {
+		t.Errorf("Where = %q, want %q", where, expected)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, "%ов", "2010-01-01"}) {
+		t.Errorf("Unexpected args %v", args)
+	}
+
+	if where, args, err := cols.Where(nil); where != "" || args != nil || err != nil {
+		t.Errorf("Expected empty clause for no conditions, got %q %v %v", where, args, err)
+	}
+
+	invalid := []db.Condition{
+		{Field: "1=1 OR surname", Operator: "=", Value: 1},
+		{Field: "surname", Operator: "= 'x' OR 1=1 --", Value: 1},
+		{Field: "surname", Operator: "IN", Value: 1},
+	}
+	for _, cond := range invalid {
+		if _, _, err := cols.Where([]db.Condition{cond}); !errors.Is(err, db.ErrInvalidFilter) {
+			t.Errorf("Expected ErrInvalidFilter for %+v, got %v", cond, err)
+		}
+	}
+}
+
+func TestColumns_Normalize(t *testing.T) {
+	cols := db.ColumnsOf[domain.StudyGroup]()
+
+	filter, err := cols.Normalize(db.Filter{
+		Limit:      10,
+		OrderBy:    "-study_year,group_name",
+		Conditions: []db.Condition{{Field: "musprogramm_id", Operator: "=", Value: "3"}},
+	})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if filter.OrderBy != "study_year DESC, group_name" {
+		t.Errorf("Unexpected OrderBy %q", filter.OrderBy)
+	}
+	if filter.Conditions[0].Field != "mus_programm_id" || filter.Limit != 10 {
+		t.Errorf("Unexpected filter %+v", filter)
+	}
+
+	if _, err := cols.Normalize(db.Filter{Conditions: []db.Condition{{Field: "unknown", Operator: "="}}}); !errors.Is(err, db.ErrInvalidFilter) {
+		t.Errorf("Expected ErrInvalidFilter, got %v", err)
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestStudyGroupRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -36,7 +24,7 @@ func TestStudyGroupRepository_AllMethods(t *testing.T) {
 	repo := repositories.NewStudyGroupRepository(sqlDB)
 
 	testGroup := &domain.StudyGroup{
-		MusProgrammID:    1, // валидный ID музыкальной программы
+		MusProgrammID:    fx.Programms[0].MusprogrammID,
 		GroupName:        "Группа A",
 		StudyYear:        2023,
 		NumberOfStudents: 25,
@@ -207,7 +195,7 @@ func TestStudyGroupRepository_AllMethods(t *testing.T) {
 
 	t.Run("GetByIDs", func(t *testing.T) {
 		secondGroup := &domain.StudyGroup{
-			MusProgrammID:    1,
+			MusProgrammID:    fx.Programms[0].MusprogrammID,
 			GroupName:        "Группа В",
 			StudyYear:        2023,
 			NumberOfStudents: 20,
@@ -0,0 +1,136 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+)
+
+func TestKeysetWhere(t *testing.T) {
+	cols := db.ColumnsOf[domain.Student]()
+
+	keys, err := cols.KeysetSort("-birthday,phone_number", "student_id")
+	if err != nil {
+		t.Fatalf("KeysetSort failed: %v", err)
+	}
+	if got := db.FormatSort(keys); got != "birthday DESC, phone_number, student_id" {
+		t.Fatalf("Unexpected keyset sort %q", got)
+	}
+
+	values, err := db.KeysetValues(keys, &db.Cursor{Values: []interface{}{"2010-01-01", nil}, ID: 5})
+	if err != nil {
+		t.Fatalf("KeysetValues failed: %v", err)
+	}
+	where, args := db.KeysetWhere(keys, values, 3)
+
+	expected := "(birthday < $3 OR birthday = $4 AND phone_number IS NULL AND (student_id > $5 OR student_id IS NULL))"
+	if where != expected {
+		t.Errorf("KeysetWhere = %q, want %q", where, expected)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"2010-01-01", "2010-01-01", 5}) {
+		t.Errorf("Unexpected args %v", args)
+	}
+
+	// Назад от той же границы: после NULL по DESC идут все непустые значения
+	where, _ = db.KeysetWhere(db.ReverseSort(keys), values, 1)
+	expected = "((birthday > $1 OR birthday IS NULL) OR birthday = $2 AND phone_number IS NOT NULL OR birthday = $2 AND phone_number IS NULL AND student_id < $3)"
+	if where != expected {
+		t.Errorf("Backward KeysetWhere = %q, want %q", where, expected)
+	}
+
+	if _, err := db.KeysetValues(keys, &db.Cursor{Values: []interface{}{"2010-01-01"}, ID: 5}); !errors.Is(err, db.ErrInvalidFilter) {
+		t.Errorf("Expected ErrInvalidFilter for cursor of another sort, got %v", err)
+	}
+	if values, err := db.KeysetValues(keys, &db.Cursor{}); values != nil || err != nil {
+		t.Errorf("Expected no boundary for the first page, got %v %v", values, err)
+	}
+}
+
+// cursorAt строит курсор так же, как API: значения после JSON приходят строками
+func cursorAt(s *domain.Student, backward bool) *db.Cursor {
+	var phone interface{}
+	if s.PhoneNumber != nil {
+		phone = *s.PhoneNumber
+	}
+	return &db.Cursor{
+		Values:   []interface{}{strconv.Itoa(s.GroupID), phone},
+		ID:       strconv.Itoa(s.StudentID),
+		Backward: backward,
+	}
+}
+
+func TestMemoryRepository_Cursor(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+
+	phone := "79990000002"
+	for _, s := range []*domain.Student{
+		{Surname: "Орлов", Name: "Олег", GroupID: 1, MusprogrammID: 1, PhoneNumber: &phone},
+		{Surname: "Зайцев", Name: "Захар", GroupID: 2, MusprogrammID: 1},
+		{Surname: "Козлов", Name: "Кирилл", GroupID: 1, MusprogrammID: 2},
+	} {
+		if err := repo.Create(ctx, s); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	const sort = "group_id,-phone_number"
+	all, err := repo.List(ctx, db.Filter{OrderBy: sort + ",student_id"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	// Вперед по страницам из двух строк: каждая строка ровно один раз и в порядке полной выборки
+	var pages [][]*domain.Student
+	cursor := &db.Cursor{}
+	for {
+		page, err := repo.List(ctx, db.Filter{Limit: 2, OrderBy: sort, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		cursor = cursorAt(page[len(page)-1], false)
+	}
+
+	var walked []*domain.Student
+	for _, page := range pages {
+		walked = append(walked, page...)
+	}
+	if got, want := surnames(walked), surnames(all); !equalStrings(got, want) {
+		t.Fatalf("Forward walk = %v, want %v", got, want)
+	}
+
+	// Назад от первой строки последней страницы - предпоследняя страница в прямом порядке
+	last := pages[len(pages)-1]
+	prev, err := repo.List(ctx, db.Filter{Limit: 2, OrderBy: sort, Cursor: cursorAt(last[0], true)})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got, want := surnames(prev), surnames(pages[len(pages)-2]); !equalStrings(got, want) {
+		t.Errorf("Backward page = %v, want %v", got, want)
+	}
+
+	// Курсор сочетается с условиями, Offset в курсорном режиме не применяется
+	filtered, err := repo.List(ctx, db.Filter{
+		Limit: 10, Offset: 5, OrderBy: sort, Cursor: &db.Cursor{},
+		Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: 2}},
+	})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got := surnames(filtered); !equalStrings(got, []string{"Петров", "Алексеев", "Зайцев"}) {
+		t.Errorf("Filtered cursor page = %v", got)
+	}
+
+	if _, err := repo.List(ctx, db.Filter{OrderBy: sort, Cursor: &db.Cursor{ID: "1"}}); !errors.Is(err, db.ErrInvalidFilter) {
+		t.Errorf("Expected ErrInvalidFilter for cursor without sort values, got %v", err)
+	}
+}
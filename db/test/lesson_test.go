@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestLessonRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
+	t.Parallel()
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -36,10 +24,10 @@ func TestLessonRepository_AllMethods(t *testing.T) {
 	repo := repositories.NewLessonRepository(sqlDB)
 
 	// укажите валидные IDs для FK
-	audienceID := 1
-	employeeID := 6
-	groupID := 1
-	subjectID := 1
+	audienceID := fx.Audiences[0].AudienceID
+	employeeID := fx.Employees[0].EmployeeID
+	groupID := fx.Groups[0].GroupID
+	subjectID := fx.Subjects[0].SubjectID
 
 	testLesson := &domain.Lesson{
 		AudienceID: &audienceID,
@@ -245,32 +233,4 @@ func TestLessonRepository_AllMethods(t *testing.T) {
 			t.Errorf("Expected 2 Lessons, got %d", len(lessons))
 		}
 	})
-
-	t.Run("CheckEmployeeAvailability", func(t *testing.T) {
-		// Для корректного теста нужно, чтобы в таблицах lessons и schedules были данные.
-		startTime := time.Now().Add(1 * time.Hour)
-		endTime := startTime.Add(1 * time.Hour)
-
-		available, err := repo.CheckEmployeeAvailability(ctx, employeeID, startTime, endTime, 0)
-		if err != nil {
-			t.Fatalf("CheckEmployeeAvailability failed: %v", err)
-		}
-
-		t.Logf("Employee availability: %v", available)
-	})
-
-	t.Run("CheckAudienceAvailability", func(t *testing.T) {
-		if testLesson.AudienceID == nil {
-			t.Skip("AudienceID is nil, skipping CheckAudienceAvailability test")
-		}
-		startTime := time.Now().Add(1 * time.Hour)
-		endTime := startTime.Add(1 * time.Hour)
-
-		available, err := repo.CheckAudienceAvailability(ctx, *testLesson.AudienceID, startTime, endTime, 0)
-		if err != nil {
-			t.Fatalf("CheckAudienceAvailability failed: %v", err)
-		}
-
-		t.Logf("Audience availability: %v", available)
-	})
 }
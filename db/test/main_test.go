@@ -0,0 +1,12 @@
+package db_test
+
+import (
+	"os"
+	"testing"
+
+	"GO_Music/db/dbtest"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
@@ -0,0 +1,488 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/db/memory"
+	"GO_Music/domain"
+)
+
+func newMemoryStudents(t *testing.T) (*memory.Store, *memory.MemoryRepository[domain.Student, int]) {
+	t.Helper()
+
+	store := memory.NewStore()
+	repo := memory.NewMemoryRepository[domain.Student, int](store, "student", "student_id")
+
+	phone := "79990000001"
+	userID := 7
+	students := []*domain.Student{
+		{Surname: "Иванов", Name: "Иван", Birthday: domain.ParseDMY("01.02.2010"), GroupID: 1, MusprogrammID: 1, PhoneNumber: &phone, UserID: &userID},
+		{Surname: "Петров", Name: "Пётр", Birthday: domain.ParseDMY("15.06.2011"), GroupID: 2, MusprogrammID: 1},
+		{Surname: "Сидоров", Name: "Сидор", Birthday: domain.ParseDMY("20.11.2009"), GroupID: 1, MusprogrammID: 2},
+		{Surname: "Алексеев", Name: "Алексей", Birthday: domain.ParseDMY("03.03.2012"), GroupID: 2, MusprogrammID: 2},
+	}
+	for _, s := range students {
+		if err := repo.Create(context.Background(), s); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	return store, repo
+}
+
+func surnames(students []*domain.Student) []string {
+	result := make([]string, len(students))
+	for i, s := range students {
+		result[i] = s.Surname
+	}
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemoryRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+
+	stud, err := repo.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stud.Surname != "Иванов" || stud.PhoneNumber == nil || *stud.PhoneNumber != "79990000001" {
+		t.Errorf("Unexpected student %+v", stud)
+	}
+
+	// Возвращается копия: изменения не попадают в хранилище без Update
+	*stud.PhoneNumber = "70000000000"
+	stud.Name = "Изменено"
+	again, _ := repo.GetByID(ctx, 1)
+	if again.Name != "Иван" || *again.PhoneNumber != "79990000001" {
+		t.Errorf("Store was modified through returned entity: %+v", again)
+	}
+
+	stud.Surname = "Обновлён"
+	if err := repo.Update(ctx, stud); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	updated, _ := repo.GetByID(ctx, 1)
+	if updated.Surname != "Обновлён" {
+		t.Errorf("Expected updated surname, got %q", updated.Surname)
+	}
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if exists, _ := repo.Exists(ctx, 1); exists {
+		t.Error("Expected deleted student to not exist")
+	}
+
+	created := &domain.Student{Surname: "Новый", Name: "Студент", GroupID: 1, MusprogrammID: 1}
+	if err := repo.Create(ctx, created); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.StudentID != 5 {
+		t.Errorf("Expected generated ID 5, got %d", created.StudentID)
+	}
+
+	list, err := repo.GetByIDs(ctx, []int{2, 5, 100})
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
+	}
+	if got := surnames(list); !equalStrings(got, []string{"Петров", "Новый"}) {
+		t.Errorf("GetByIDs returned %v", got)
+	}
+}
+
+func TestMemoryRepository_List(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+
+	tests := []struct {
+		name   string
+		filter db.Filter
+		want   []string
+	}{
+		{"No filter keeps insertion order", db.Filter{}, []string{"Иванов", "Петров", "Сидоров", "Алексеев"}},
+		{"Equal", db.Filter{Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: 1}}}, []string{"Иванов", "Сидоров"}},
+		{"Equal with string value", db.Filter{Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: "2"}}}, []string{"Петров", "Алексеев"}},
+		{"Not equal", db.Filter{Conditions: []db.Condition{{Field: "musprogramm_id", Operator: "!=", Value: 1}}}, []string{"Сидоров", "Алексеев"}},
+		{"Date range", db.Filter{Conditions: []db.Condition{
+			{Field: "birthday", Operator: ">=", Value: domain.ParseDMY("01.01.2010")},
+			{Field: "birthday", Operator: "<", Value: "2012-01-01"},
+		}}, []string{"Иванов", "Петров"}},
+		{"Greater and less or equal", db.Filter{Conditions: []db.Condition{
+			{Field: "student_id", Operator: ">", Value: 1},
+			{Field: "student_id", Operator: "<=", Value: 3},
+		}}, []string{"Петров", "Сидоров"}},
+		{"LIKE is case sensitive", db.Filter{Conditions: []db.Condition{{Field: "surname", Operator: "LIKE", Value: "%ов"}}}, []string{"Иванов", "Петров", "Сидоров"}},
+		{"LIKE with underscore", db.Filter{Conditions: []db.Condition{{Field: "name", Operator: "LIKE", Value: "П_тр"}}}, []string{"Петров"}},
+		{"ILIKE", db.Filter{Conditions: []db.Condition{{Field: "surname", Operator: "ILIKE", Value: "%ИД%"}}}, []string{"Сидоров"}},
+		{"IS NULL", db.Filter{Conditions: []db.Condition{{Field: "user_id", Operator: "IS NULL"}}}, []string{"Петров", "Сидоров", "Алексеев"}},
+		{"IS NOT NULL", db.Filter{Conditions: []db.Condition{{Field: "phone_number", Operator: "IS NOT NULL"}}}, []string{"Иванов"}},
+		{"Comparison with NULL is false", db.Filter{Conditions: []db.Condition{{Field: "user_id", Operator: "!=", Value: 1}}}, []string{"Иванов"}},
+		{"Order by several columns", db.Filter{OrderBy: "group_id DESC, surname"}, []string{"Алексеев", "Петров", "Иванов", "Сидоров"}},
+		{"Limit and offset", db.Filter{OrderBy: "birthday", Limit: 2, Offset: 1}, []string{"Иванов", "Петров"}},
+		{"Offset past the end", db.Filter{Offset: 10}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if got := surnames(list); !equalStrings(got, tt.want) {
+				t.Errorf("List = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	count, err := repo.Count(ctx, db.Filter{Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: 2}}, Limit: 1})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected count 2, got %d", count)
+	}
+
+	if _, err := repo.List(ctx, db.Filter{Conditions: []db.Condition{{Field: "unknown", Operator: "=", Value: 1}}}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+	if _, err := repo.List(ctx, db.Filter{OrderBy: "surname; DROP TABLE student"}); err == nil {
+		t.Error("Expected error for invalid ORDER BY")
+	}
+	if _, err := repo.List(ctx, db.Filter{Conditions: []db.Condition{{Field: "group_id", Operator: "~", Value: 1}}}); err == nil {
+		t.Error("Expected error for unsupported operator")
+	}
+}
+
+func TestMemoryRepository_WithTx(t *testing.T) {
+	ctx := context.Background()
+	store, repo := newMemoryStudents(t)
+
+	t.Run("Rollback restores snapshot", func(t *testing.T) {
+		tx, err := store.DB().BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		txRepo := repo.WithTx(tx)
+
+		if err := txRepo.Create(ctx, &domain.Student{Surname: "Временный", GroupID: 1, MusprogrammID: 1}); err != nil {
+			t.Fatalf("Create in tx failed: %v", err)
+		}
+		stud, _ := txRepo.GetByID(ctx, 2)
+		stud.Surname = "Переименован"
+		if err := txRepo.Update(ctx, stud); err != nil {
+			t.Fatalf("Update in tx failed: %v", err)
+		}
+		if err := txRepo.Delete(ctx, 3); err != nil {
+			t.Fatalf("Delete in tx failed: %v", err)
+		}
+
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback failed: %v", err)
+		}
+
+		list, _ := repo.List(ctx, db.Filter{})
+		if got := surnames(list); !equalStrings(got, []string{"Иванов", "Петров", "Сидоров", "Алексеев"}) {
+			t.Errorf("After rollback List = %v", got)
+		}
+
+		if err := txRepo.Create(ctx, &domain.Student{Surname: "Поздно"}); !errors.Is(err, sql.ErrTxDone) {
+			t.Errorf("Expected sql.ErrTxDone after rollback, got %v", err)
+		}
+	})
+
+	t.Run("Commit keeps changes", func(t *testing.T) {
+		tx, err := store.DB().BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := repo.WithTx(tx).Delete(ctx, 4); err != nil {
+			t.Fatalf("Delete in tx failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		if exists, _ := repo.Exists(ctx, 4); exists {
+			t.Error("Expected committed delete to persist")
+		}
+	})
+
+	t.Run("Rollback keeps rows changed by another transaction", func(t *testing.T) {
+		first, err := store.DB().BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		second, err := store.DB().BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		rename := func(tx *sql.Tx, id int, surname string) {
+			t.Helper()
+			txRepo := repo.WithTx(tx)
+			stud, err := txRepo.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetByID in tx failed: %v", err)
+			}
+			stud.Surname = surname
+			if err := txRepo.Update(ctx, stud); err != nil {
+				t.Fatalf("Update in tx failed: %v", err)
+			}
+		}
+
+		// Обе транзакции меняют первую строку, вторую - только откатываемая
+		rename(first, 1, "Откатится")
+		rename(first, 2, "Откатится")
+		rename(second, 1, "Зафиксирован")
+		if err := second.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		if err := first.Rollback(); err != nil {
+			t.Fatalf("Rollback failed: %v", err)
+		}
+
+		list, _ := repo.List(ctx, db.Filter{})
+		if got := surnames(list); !equalStrings(got, []string{"Зафиксирован", "Петров", "Сидоров"}) {
+			t.Errorf("After concurrent commit and rollback List = %v", got)
+		}
+	})
+
+	t.Run("Raw SQL is rejected", func(t *testing.T) {
+		_, err := repo.QueryContext(ctx, "SELECT 1")
+		if !errors.Is(err, memory.ErrRawSQL) {
+			t.Errorf("Expected memory.ErrRawSQL, got %v", err)
+		}
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		cctx, cancel := context.WithTimeout(ctx, time.Nanosecond)
+		defer cancel()
+		<-cctx.Done()
+		if _, err := repo.List(cctx, db.Filter{}); err == nil {
+			t.Error("Expected error for cancelled context")
+		}
+	})
+}
+
+func TestMemoryRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+	repo.Searchable("surname", "name", "father_name")
+	if err := repo.Create(ctx, &domain.Student{Surname: "Петр", Name: "Анна", GroupID: 3, MusprogrammID: 1}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter db.Filter
+		want   []string
+	}{
+		{"Whole word ranks before prefix", db.Filter{Search: "петр"}, []string{"Петр", "Петров"}},
+		{"Case insensitive prefix", db.Filter{Search: "СИД"}, []string{"Сидоров"}},
+		{"Several words", db.Filter{Search: "Сидоров Сидор"}, []string{"Сидоров"}},
+		{"Substring in any column", db.Filter{Search: "ов"}, []string{"Иванов", "Петров", "Сидоров"}},
+		{"Combined with conditions", db.Filter{Search: "ов", Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: 1}}}, []string{"Иванов", "Сидоров"}},
+		{"Explicit sort wins", db.Filter{Search: "алекс", OrderBy: "-surname"}, []string{"Алексеев"}},
+		{"No match", db.Filter{Search: "Шостакович"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if got := surnames(list); !equalStrings(got, tt.want) {
+				t.Errorf("List = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	count, err := repo.Count(ctx, db.Filter{Search: "ов"})
+	if err != nil || count != 3 {
+		t.Errorf("Count = %d, %v; want 3", count, err)
+	}
+
+	plain := memory.NewMemoryRepository[domain.Student, int](memory.NewStore(), "student", "student_id")
+	if _, err := plain.List(ctx, db.Filter{Search: "петр"}); !errors.Is(err, db.ErrInvalidFilter) {
+		t.Errorf("Expected ErrInvalidFilter for repository without searchable columns, got %v", err)
+	}
+}
+
+func TestMemoryRepository_Version(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+
+	first, err := repo.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("Expected new row version 1, got %d", first.Version)
+	}
+	second, _ := repo.GetByID(ctx, 1)
+
+	first.Name = "Иоанн"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Expected version 2 after update, got %d", first.Version)
+	}
+
+	// Второй читатель пишет поверх устаревшей версии
+	second.Name = "Ванька"
+	err = repo.Update(ctx, second)
+	var conflict *db.ConflictError
+	if !errors.Is(err, db.ErrVersionConflict) || !errors.As(err, &conflict) {
+		t.Fatalf("Expected ConflictError, got %v", err)
+	}
+	if conflict.Expected != 1 || conflict.Actual != 2 {
+		t.Errorf("Unexpected conflict versions %+v", conflict)
+	}
+
+	stored, _ := repo.GetByID(ctx, 1)
+	if stored.Name != "Иоанн" || stored.Version != 2 {
+		t.Errorf("Stale update must not be applied: %+v", stored)
+	}
+
+	// Версия 0 не пропускает проверку: такую сущность не читали из хранилища
+	stored.Name = "Без версии"
+	stored.Version = 0
+	if err := repo.Update(ctx, stored); !errors.As(err, &conflict) || conflict.Expected != 0 || conflict.Actual != 2 {
+		t.Errorf("Expected ConflictError for version 0, got %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, 1); stored.Name != "Иоанн" || stored.Version != 2 {
+		t.Errorf("Update with version 0 must not be applied: %+v", stored)
+	}
+
+	if err := repo.Update(ctx, &domain.Student{StudentID: 100, Version: 1}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for missing row, got %v", err)
+	}
+}
+
+func TestMemoryRepository_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newMemoryStudents(t)
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	// Удаленная строка не видна обычным выборкам
+	if _, err := repo.GetByID(ctx, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for deleted row, got %v", err)
+	}
+	if exists, _ := repo.Exists(ctx, 1); exists {
+		t.Error("Deleted row must not exist")
+	}
+	if found, _ := repo.GetByIDs(ctx, []int{1, 2}); !equalStrings(surnames(found), []string{"Петров"}) {
+		t.Errorf("GetByIDs = %v", surnames(found))
+	}
+	if count, _ := repo.Count(ctx, db.Filter{}); count != 3 {
+		t.Errorf("Expected 3 active rows, got %d", count)
+	}
+	if err := repo.Update(ctx, &domain.Student{StudentID: 1, Surname: "Иванов", Name: "Иван"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for update of deleted row, got %v", err)
+	}
+
+	trash, err := repo.List(ctx, db.Filter{Trash: true})
+	if err != nil {
+		t.Fatalf("List trash failed: %v", err)
+	}
+	if !equalStrings(surnames(trash), []string{"Иванов"}) || trash[0].DeletedAt == nil {
+		t.Fatalf("Unexpected trash %+v", trash)
+	}
+	if count, _ := repo.Count(ctx, db.Filter{Trash: true}); count != 1 {
+		t.Errorf("Expected 1 row in trash, got %d", count)
+	}
+
+	// Из корзины восстанавливаются и удаляются только удаленные строки
+	if err := repo.Purge(ctx, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for purge of active row, got %v", err)
+	}
+	if err := repo.Restore(ctx, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for restore of active row, got %v", err)
+	}
+
+	if err := repo.Restore(ctx, 1); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := repo.GetByID(ctx, 1)
+	if err != nil || restored.DeletedAt != nil || restored.Surname != "Иванов" {
+		t.Fatalf("Unexpected restored row %+v, %v", restored, err)
+	}
+
+	if err := repo.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Purge(ctx, 2); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if err := repo.Restore(ctx, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for restore of purged row, got %v", err)
+	}
+	if trash, _ := repo.List(ctx, db.Filter{Trash: true}); len(trash) != 0 {
+		t.Errorf("Expected empty trash, got %v", surnames(trash))
+	}
+}
+
+func TestMemoryRepository_Unique(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	repo := memory.NewMemoryRepository[domain.Student, int](store, "student", "student_id").Unique("phone_number")
+
+	phone, other := "79990000001", "79990000002"
+	first := &domain.Student{Surname: "Иванов", Name: "Иван", Birthday: domain.ParseDMY("01.02.2010"), GroupID: 1, MusprogrammID: 1, PhoneNumber: &phone}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Строки с NULL в ключе не конфликтуют
+	for range 2 {
+		if err := repo.Create(ctx, &domain.Student{Surname: "Петров", Name: "Пётр", Birthday: domain.ParseDMY("15.06.2011"), GroupID: 1, MusprogrammID: 1}); err != nil {
+			t.Fatalf("Create without key failed: %v", err)
+		}
+	}
+
+	second := &domain.Student{Surname: "Сидоров", Name: "Сидор", Birthday: domain.ParseDMY("20.11.2009"), GroupID: 1, MusprogrammID: 1, PhoneNumber: &phone}
+	if err := repo.Create(ctx, second); !errors.Is(err, db.ErrDuplicate) {
+		t.Fatalf("Expected db.ErrDuplicate on create, got %v", err)
+	}
+	second.PhoneNumber = &other
+	if err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second.PhoneNumber = &phone
+	if err := repo.Update(ctx, second); !errors.Is(err, db.ErrDuplicate) {
+		t.Errorf("Expected db.ErrDuplicate on update, got %v", err)
+	}
+
+	// Удаленная строка освобождает ключ, но не может вернуться на занятый
+	if err := repo.Delete(ctx, first.StudentID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Update(ctx, second); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.Restore(ctx, first.StudentID); !errors.Is(err, db.ErrDuplicate) {
+		t.Errorf("Expected db.ErrDuplicate on restore, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"GO_Music/db/migrations"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := migrations.NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("NewMigrator failed: %v", err)
+	}
+
+	list := migrator.Migrations()
+	if len(list) == 0 {
+		t.Fatal("Expected at least one embedded migration")
+	}
+	if list[0].Version != 1 {
+		t.Errorf("Expected first migration version 1, got %d", list[0].Version)
+	}
+
+	tables := []string{
+		"users", "programm", "subject", "audience", "employee", "study_group", "student",
+		"instrument", "lesson", "schedule", "student_assessment", "student_attendance",
+		"programm_distribution", "subject_distribution",
+	}
+	for _, table := range tables {
+		if !strings.Contains(list[0].Up, "CREATE TABLE "+table+" (") {
+			t.Errorf("Expected init migration to create table %s", table)
+		}
+		if !strings.Contains(list[0].Down, "DROP TABLE IF EXISTS "+table+";") {
+			t.Errorf("Expected init migration to drop table %s", table)
+		}
+	}
+
+	// Индексы поиска нужны каждой таблице с Searchable в db/repositories: первые - в 0002, остальные - в 0015
+	var searchUp, searchDown string
+	for _, m := range list {
+		if m.Version == 2 || m.Version == 15 {
+			searchUp, searchDown = searchUp+m.Up, searchDown+m.Down
+		}
+	}
+	searchable := []string{
+		"users", "programm", "subject", "audience", "employee", "study_group", "student",
+		"instrument", "lesson", "schedule", "student_assessment",
+	}
+	for _, table := range searchable {
+		for _, index := range []string{table + "_search_fts_idx", table + "_search_trgm_idx"} {
+			if strings.Count(searchUp, "CREATE INDEX "+index+" ON "+table+" ") != 1 {
+				t.Errorf("Expected search migrations to create index %s once", index)
+			}
+			if !strings.Contains(searchDown, "DROP INDEX IF EXISTS "+index+";") {
+				t.Errorf("Expected search migrations to drop index %s", index)
+			}
+		}
+	}
+
+	for i := 1; i < len(list); i++ {
+		if list[i].Version <= list[i-1].Version {
+			t.Errorf("Migrations are not sorted: %d after %d", list[i].Version, list[i-1].Version)
+		}
+	}
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fs      fstest.MapFS
+		wantErr string
+	}{
+		{
+			name: "Missing down file",
+			fs: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "has no down file",
+		},
+		{
+			name: "Invalid file name",
+			fs: fstest.MapFS{
+				"init.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "Conflicting names",
+			fs: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("SELECT 1")},
+				"0001_other.down.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrations.Load(tt.fs)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+)
+
+// fakeConnector отдает заранее заданный результат на любой запрос; значения имеют те же типы, что возвращает lib/pq
+type fakeConnector struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{c}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ result *fakeConnector }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{result: c.result}, nil
+}
+
+type fakeRows struct {
+	result *fakeConnector
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFake(columns []string, rows ...[]driver.Value) *sql.DB {
+	return sql.OpenDB(&fakeConnector{columns: columns, rows: rows})
+}
+
+var studentColumns = []string{"student_id", "user_id", "surname", "name", "father_name", "birthday", "phone_number", "group_id", "musprogramm_id", "version", "deleted_at"}
+
+func studentRow(id int64) []driver.Value {
+	return []driver.Value{id, int64(7), "Иванов", []byte("Иван"), "Иванович", time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC), "79990000001", int64(1), int64(2), int64(1), nil}
+}
+
+func TestRowScanner_ScanRows(t *testing.T) {
+	scanner := db.ScannerOf[domain.Student]()
+	if scanner.SelectList() != strings.Join(db.ColumnsOf[domain.Student]().Names(), ", ") {
+		t.Errorf("Unexpected select list %q", scanner.SelectList())
+	}
+	if db.ScannerOf[domain.Student]() != scanner {
+		t.Error("Expected cached scanner for the same type")
+	}
+
+	// Колонки в другом порядке, NULL в nullable полях и лишняя колонка результата
+	columns := []string{"surname", "extra", "student_id", "user_id", "father_name", "birthday", "phone_number", "group_id", "musprogramm_id", "name"}
+	sqlDB := openFake(columns,
+		[]driver.Value{"Петров", "x", int64(1), nil, nil, time.Date(2011, 6, 15, 0, 0, 0, 0, time.UTC), nil, int64(2), int64(1), "Пётр"},
+		[]driver.Value{[]byte("Сидоров"), nil, int64(2), int64(9), "Сидорович", time.Date(2009, 11, 20, 0, 0, 0, 0, time.UTC), "79990000002", int64(1), int64(2), nil},
+	)
+	defer sqlDB.Close()
+
+	rows, err := sqlDB.QueryContext(context.Background(), "SELECT")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	defer rows.Close()
+
+	students, err := scanner.ScanRows(rows)
+	if err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(students) != 2 {
+		t.Fatalf("Expected 2 students, got %d", len(students))
+	}
+
+	first, second := students[0], students[1]
+	if first.StudentID != 1 || first.Surname != "Петров" || first.Name != "Пётр" || first.GroupID != 2 {
+		t.Errorf("Unexpected first student %+v", first)
+	}
+	if first.UserID != nil || first.FatherName != nil || first.PhoneNumber != nil {
+		t.Errorf("Expected NULL columns to stay nil: %+v", first)
+	}
+	if !first.Birthday.Equal(time.Date(2011, 6, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected birthday %v", first.Birthday)
+	}
+
+	if second.Surname != "Сидоров" || second.Name != "" {
+		t.Errorf("Expected []byte to be scanned into string and NULL into zero value: %+v", second)
+	}
+	if second.UserID == nil || *second.UserID != 9 {
+		t.Errorf("Expected *int 9, got %v", second.UserID)
+	}
+	if second.FatherName == nil || *second.FatherName != "Сидорович" || second.PhoneNumber == nil || *second.PhoneNumber != "79990000002" {
+		t.Errorf("Unexpected *string fields %v %v", second.FatherName, second.PhoneNumber)
+	}
+}
+
+func TestRowScanner_ScanRow(t *testing.T) {
+	scanner := db.ScannerOf[domain.Student]()
+
+	sqlDB := openFake(studentColumns, studentRow(5))
+	defer sqlDB.Close()
+
+	student, err := scanner.ScanRow(sqlDB.QueryRowContext(context.Background(), "SELECT"))
+	if err != nil {
+		t.Fatalf("ScanRow failed: %v", err)
+	}
+	if student.StudentID != 5 || student.Name != "Иван" || student.UserID == nil || *student.UserID != 7 {
+		t.Errorf("Unexpected student %+v", student)
+	}
+
+	empty := openFake(studentColumns)
+	defer empty.Close()
+	if _, err := scanner.ScanRow(empty.QueryRowContext(context.Background(), "SELECT")); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	bad := openFake(studentColumns, []driver.Value{"abc", nil, "Иванов", "Иван", nil, time.Now(), nil, int64(1), int64(1), int64(1), nil})
+	defer bad.Close()
+	if _, err := scanner.ScanRow(bad.QueryRowContext(context.Background(), "SELECT")); err == nil || !strings.Contains(err.Error(), "student_id") {
+		t.Errorf("Expected error naming the column, got %v", err)
+	}
+}
+
+// benchmarkRows результат из 100 строк учеников для сравнения способов чтения
+func benchmarkRows() *sql.DB {
+	rows := make([][]driver.Value, 100)
+	for i := range rows {
+		rows[i] = studentRow(int64(i + 1))
+	}
+	return openFake(studentColumns, rows...)
+}
+
+// scanViaMap прежний способ чтения: []interface{} -> map -> MapToStruct
+func scanViaMap(rows *sql.Rows) ([]*domain.Student, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*domain.Student
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		valuePtrs := make([]interface{}, len(cols))
+		for i := range cols {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]interface{})
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				m[col] = string(b)
+			} else {
+				m[col] = values[i]
+			}
+		}
+
+		var entity domain.Student
+		if err := db.MapToStruct(m, &entity); err != nil {
+			return nil, err
+		}
+		results = append(results, &entity)
+	}
+	return results, rows.Err()
+}
+
+func BenchmarkScan_MapToStruct(b *testing.B) {
+	sqlDB := benchmarkRows()
+	defer sqlDB.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := sqlDB.Query("SELECT")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := scanViaMap(rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkScan_RowScanner(b *testing.B) {
+	sqlDB := benchmarkRows()
+	defer sqlDB.Close()
+	scanner := db.ScannerOf[domain.Student]()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := sqlDB.Query("SELECT")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := scanner.ScanRows(rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestScheduleRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -40,7 +28,7 @@ func TestScheduleRepository_AllMethods(t *testing.T) {
 	endDate := startDate.AddDate(0, 1, 0) // +1 месяц
 
 	testSchedule := &domain.Schedule{
-		LessonID:      3,
+		LessonID:      fx.Lessons[2].LessonID,
 		DayWeek:       "Monday",
 		TimeBegin:     domain.ParseTimeHM("09:00"),
 		TimeEnd:       domain.ParseTimeHM("10:30"),
@@ -227,7 +215,7 @@ func TestScheduleRepository_AllMethods(t *testing.T) {
 
 	t.Run("GetByIDs", func(t *testing.T) {
 		secondSchedule := &domain.Schedule{
-			LessonID:      2, // валидный LessonID
+			LessonID:      fx.Lessons[1].LessonID,
 			DayWeek:       "Wednesday",
 			TimeBegin:     domain.ParseTimeHM("11:00"),
 			TimeEnd:       domain.ParseTimeHM("12:00"),
@@ -2,11 +2,13 @@ package db_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,28 +16,16 @@ import (
 )
 
 func TestStudentRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
+	t.Parallel()
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	repo := repositories.NewStudentRepository(sqlDB)
 
-	userID := 3
+	userID := fx.Users[0].UserID
 	fatherName := "Алексеевич"
 	phoneNumber := "79109876654"
 
@@ -46,8 +36,8 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 		FatherName:    &fatherName,
 		Birthday:      domain.ParseDMY("19.02.2000"),
 		PhoneNumber:   &phoneNumber,
-		GroupID:       1, // валидный GroupID
-		MusprogrammID: 1, // валидный MusprogrammID
+		GroupID:       fx.Groups[0].GroupID,
+		MusprogrammID: fx.Programms[0].MusprogrammID,
 	}
 
 	t.Run("Create", func(t *testing.T) {
@@ -125,11 +115,11 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 		updatedStudent.FatherName = &newFatherName
 		newPhone := "10987654321"
 		updatedStudent.PhoneNumber = &newPhone
-		newUserID := 4
+		newUserID := fx.Users[1].UserID
 		updatedStudent.UserID = &newUserID
 		updatedStudent.Birthday = updatedStudent.Birthday.AddDate(-1, 0, 0) // на год старше
-		updatedStudent.GroupID = 2
-		updatedStudent.MusprogrammID = 2
+		updatedStudent.GroupID = fx.Groups[1].GroupID
+		updatedStudent.MusprogrammID = fx.Programms[1].MusprogrammID
 
 		err = repoWithTx.Update(ctx, &updatedStudent)
 		if err != nil {
@@ -172,6 +162,27 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 		*testStudent = updatedStudent
 	})
 
+	t.Run("Version conflict", func(t *testing.T) {
+		stale := *testStudent
+		stale.Version--
+
+		err := repo.Update(ctx, &stale)
+		var conflict *db.ConflictError
+		if !errors.As(err, &conflict) || !errors.Is(err, db.ErrVersionConflict) {
+			t.Fatalf("Expected ConflictError for stale version, got %v", err)
+		}
+		if conflict.Actual != testStudent.Version {
+			t.Errorf("Expected actual version %d, got %d", testStudent.Version, conflict.Actual)
+		}
+
+		// Версия 0 проверяется так же, как любая другая
+		unread := *testStudent
+		unread.Version = 0
+		if err := repo.Update(ctx, &unread); !errors.Is(err, db.ErrVersionConflict) {
+			t.Errorf("Expected ConflictError for version 0, got %v", err)
+		}
+	})
+
 	t.Run("List", func(t *testing.T) {
 		filter := db.Filter{
 			Conditions: []db.Condition{
@@ -256,8 +267,8 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 			FatherName:    nil,
 			Birthday:      domain.ParseDMY("05.05.2005"),
 			PhoneNumber:   nil,
-			GroupID:       1,
-			MusprogrammID: 1,
+			GroupID:       fx.Groups[0].GroupID,
+			MusprogrammID: fx.Programms[0].MusprogrammID,
 		}
 
 		tx, err := sqlDB.BeginTx(ctx, nil)
@@ -288,16 +299,16 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 		}
 	})
 
-	t.Run("SearchByName", func(t *testing.T) {
-		results, err := repo.SearchByName(ctx, "Петр")
+	t.Run("Search", func(t *testing.T) {
+		results, err := repo.List(ctx, db.Filter{Search: "Петр"})
 		if err != nil {
-			t.Fatalf("SearchByName failed: %v", err)
+			t.Fatalf("Search failed: %v", err)
 		}
 
 		if len(results) == 0 {
-			t.Error("Expected at least one Student in SearchByName results")
+			t.Error("Expected at least one Student in Search results")
 		} else {
-			t.Logf("SearchByName returned %d items", len(results))
+			t.Logf("Search returned %d items", len(results))
 		}
 
 		found := false
@@ -308,7 +319,38 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 			}
 		}
 		if !found {
-			t.Error("Created Student not found in SearchByName results")
+			t.Error("Created Student not found in Search results")
+		}
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		all, err := repo.List(ctx, db.Filter{OrderBy: "-birthday,student_id"})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+
+		var walked []*domain.Student
+		cursor := &db.Cursor{}
+		for {
+			page, err := repo.List(ctx, db.Filter{Limit: 2, OrderBy: "-birthday", Cursor: cursor})
+			if err != nil {
+				t.Fatalf("Cursor List failed: %v", err)
+			}
+			if len(page) == 0 {
+				break
+			}
+			walked = append(walked, page...)
+			last := page[len(page)-1]
+			cursor = &db.Cursor{Values: []interface{}{last.Birthday}, ID: last.StudentID}
+		}
+
+		if len(walked) != len(all) {
+			t.Fatalf("Cursor walk returned %d rows, want %d", len(walked), len(all))
+		}
+		for i := range all {
+			if walked[i].StudentID != all[i].StudentID {
+				t.Fatalf("Cursor walk differs from full list at %d", i)
+			}
 		}
 	})
 
@@ -338,4 +380,38 @@ func TestStudentRepository_AllMethods(t *testing.T) {
 			t.Error("Expected Student to be deleted")
 		}
 	})
+
+	t.Run("Restore and purge", func(t *testing.T) {
+		trash, err := repo.List(ctx, db.Filter{
+			Trash:      true,
+			Conditions: []db.Condition{{Field: "student_id", Operator: "=", Value: testStudent.StudentID}},
+		})
+		if err != nil {
+			t.Fatalf("List trash failed: %v", err)
+		}
+		if len(trash) != 1 || trash[0].DeletedAt == nil {
+			t.Fatalf("Expected deleted Student in trash, got %+v", trash)
+		}
+
+		if err := repo.Restore(ctx, testStudent.StudentID); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if _, err := repo.GetByID(ctx, testStudent.StudentID); err != nil {
+			t.Fatalf("GetByID after restore failed: %v", err)
+		}
+
+		// Окончательно удаляется только строка из корзины
+		if err := repo.Purge(ctx, testStudent.StudentID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows for purge of active row, got %v", err)
+		}
+		if err := repo.Delete(ctx, testStudent.StudentID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := repo.Purge(ctx, testStudent.StudentID); err != nil {
+			t.Fatalf("Purge failed: %v", err)
+		}
+		if err := repo.Restore(ctx, testStudent.StudentID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after purge, got %v", err)
+		}
+	})
 }
@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -14,21 +14,9 @@ import (
 )
 
 func TestSubjectRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -237,7 +225,6 @@ func TestSubjectRepository_AllMethods(t *testing.T) {
 	})
 
 	t.Run("GetPopularSubjects", func(t *testing.T) {
-		// Для корректного теста нужна реальная связанная таблица programm_distributions с данными
 		limit := 5
 		popularSubjects, err := repo.GetPopularSubjects(ctx, limit)
 		if err != nil {
@@ -252,13 +239,23 @@ func TestSubjectRepository_AllMethods(t *testing.T) {
 	})
 
 	t.Run("GetSubjectsWithPrograms", func(t *testing.T) {
-		// Для корректного теста нужен валидный musprogramm_id в programm_distributions
-		programID := 1
+		programID := fx.Programms[0].MusprogrammID
 		subjectsWithProgram, err := repo.GetSubjectsWithPrograms(ctx, programID)
 		if err != nil {
 			t.Fatalf("GetSubjectsWithPrograms failed: %v", err)
 		}
 
+		found := false
+		for _, s := range subjectsWithProgram {
+			if s.SubjectID == fx.Subjects[0].SubjectID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Error("Fixture subject not found in GetSubjectsWithPrograms results")
+		}
+
 		t.Logf("GetSubjectsWithPrograms returned %d subjects for programID %d", len(subjectsWithProgram), programID)
 	})
 
@@ -6,8 +6,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 
@@ -15,21 +15,9 @@ import (
 )
 
 func TestUserRepository_AllMethods(t *testing.T) {
-	cfgPath := "../../config/DB_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
+	t.Parallel()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	sqlDB := dbtest.New(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -152,15 +140,15 @@ func TestUserRepository_AllMethods(t *testing.T) {
 		*testUser = updatedUser
 	})
 
-	t.Run("SearchByName", func(t *testing.T) {
+	t.Run("Search", func(t *testing.T) {
 		query := "Петро"
-		users, err := repo.SearchByName(ctx, query)
+		users, err := repo.List(ctx, db.Filter{Search: query})
 		if err != nil {
-			t.Fatalf("SearchByName failed: %v", err)
+			t.Fatalf("Search failed: %v", err)
 		}
 
 		if len(users) == 0 {
-			t.Error("Expected at least one user found by SearchByName")
+			t.Error("Expected at least one user found by Search")
 		}
 
 		found := false
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VersionColumn колонка версии строки (миграция 0003_versions)
+const VersionColumn = "version"
+
+// ErrVersionConflict возвращается, если строку изменили после того, как ее прочитали
+var ErrVersionConflict = errors.New("version conflict")
+
+// [RU] Versioned сущность с версией строки: Update проверяет версию и увеличивает ее на единицу;
+// версия 0 означает запись без проверки <--->
+// [ENG] Versioned is an entity with a row version: Update checks the version and increments it;
+// version 0 means a write without the check
+type Versioned interface {
+	GetVersion() int
+	SetVersion(version int)
+}
+
+// [RU] ConflictError ошибка оптимистичной блокировки; errors.Is(err, ErrVersionConflict) == true <--->
+// [ENG] ConflictError is an optimistic locking error; errors.Is(err, ErrVersionConflict) == true
+type ConflictError struct {
+	Table    string
+	ID       interface{}
+	Expected int // версия, с которой клиент начинал изменение
+	Actual   int // текущая версия строки
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %v: version %d is stale, current version is %d", e.Table, e.ID, e.Expected, e.Actual)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
@@ -8,12 +8,15 @@ import (
 
 // StudentAssessment представляет запись оценки студента
 type StudentAssessment struct {
-	AssessmentNoteID int       `json:"assessment_note_id"`
-	LessonID         int       `json:"lesson_id" validate:"required"`
-	StudentID        int       `json:"student_id" validate:"required"`
-	TaskType         string    `json:"task_type" validate:"required,min=1,max=70"`
-	Grade            int       `json:"grade" validate:"required"`
-	AssessmentDate   time.Time `json:"assessment_date" validate:"required"`
+	AssessmentNoteID int        `json:"assessment_note_id"`
+	LessonID         int        `json:"lesson_id" validate:"required"`
+	StudentID        int        `json:"student_id" validate:"required"`
+	TaskType         string     `json:"task_type" validate:"required,min=1,max=70"`
+	Grade            int        `json:"grade" validate:"required"`
+	AssessmentDate   time.Time  `json:"assessment_date" validate:"required"`
+	OccurrenceID     *int       `json:"occurrence_id,omitempty"` // занятие журнала; nil - запись только по занятию и дате
+	Version          int        `json:"version"`                 // версия строки для оптимистичной блокировки
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`    // момент мягкого удаления; nil - строка активна
 }
 
 func (sa *StudentAssessment) GetID() int {
@@ -24,6 +27,14 @@ func (sa *StudentAssessment) SetID(id int) {
 	sa.AssessmentNoteID = id
 }
 
+func (sa *StudentAssessment) GetVersion() int {
+	return sa.Version
+}
+
+func (sa *StudentAssessment) SetVersion(version int) {
+	sa.Version = version
+}
+
 func (sa *StudentAssessment) Validate() error {
 	return validate.ValidateStruct(sa)
 }
@@ -1,16 +1,21 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // StudentAttendance представляет запись посещаемости студента
 type StudentAttendance struct {
-	AttendanceNoteID int    `json:"attendance_note_id"`
-	StudentID        int    `json:"student_id" validate:"required"`
-	LessonID         int    `json:"lesson_id" validate:"required"`
-	PresenceMark     bool   `json:"presence_mark"`
-	AttendanceDate   string `json:"attendance_date" validate:"required,datetime=2006-01-02"`
+	AttendanceNoteID int        `json:"attendance_note_id"`
+	StudentID        int        `json:"student_id" validate:"required"`
+	LessonID         int        `json:"lesson_id" validate:"required"`
+	PresenceMark     bool       `json:"presence_mark"`
+	AttendanceDate   string     `json:"attendance_date" validate:"required,datetime=2006-01-02"`
+	OccurrenceID     *int       `json:"occurrence_id,omitempty"` // занятие журнала; nil - запись только по занятию и дате
+	Version          int        `json:"version"`                 // версия строки для оптимистичной блокировки
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`    // момент мягкого удаления; nil - строка активна
 }
 
 func (sa *StudentAttendance) GetID() int {
@@ -21,6 +26,14 @@ func (sa *StudentAttendance) SetID(id int) {
 	sa.AttendanceNoteID = id
 }
 
+func (sa *StudentAttendance) GetVersion() int {
+	return sa.Version
+}
+
+func (sa *StudentAttendance) SetVersion(version int) {
+	sa.Version = version
+}
+
 func (sa *StudentAttendance) Validate() error {
 	return validate.ValidateStruct(sa)
 }
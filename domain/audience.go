@@ -1,16 +1,20 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // Audience представляет запись аудитории
 type Audience struct {
-	AudienceID  int    `json:"audience_id"`
-	Name        string `json:"name" validate:"required,min=1,max=50"`
-	AudinType   string `json:"audin_type" validate:"required,min=1,max=50"`
-	AudinNumber string `json:"audin_number" validate:"required,min=1,max=30"`
-	Capacity    int    `json:"capacity" validate:"required,min=1"`
+	AudienceID  int        `json:"audience_id"`
+	Name        string     `json:"name" validate:"required,min=1,max=50"`
+	AudinType   string     `json:"audin_type" validate:"required,min=1,max=50"`
+	AudinNumber string     `json:"audin_number" validate:"required,min=1,max=30"`
+	Capacity    int        `json:"capacity" validate:"required,min=1"`
+	Version     int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (a *Audience) GetID() int {
@@ -21,6 +25,14 @@ func (a *Audience) SetID(id int) {
 	a.AudienceID = id
 }
 
+func (a *Audience) GetVersion() int {
+	return a.Version
+}
+
+func (a *Audience) SetVersion(version int) {
+	a.Version = version
+}
+
 func (a *Audience) Validate() error {
 	return validate.ValidateStruct(a)
 }
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Действия, которые попадают в журнал изменений
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// AuditEntry запись журнала изменений: кто, когда и что изменил в сущности
+type AuditEntry struct {
+	AuditID   int       `json:"audit_id"`
+	Entity    string    `json:"entity" validate:"required,max=50"`    // таблица сущности
+	EntityID  string    `json:"entity_id" validate:"required,max=50"` // ID сущности в текстовом виде
+	Action    string    `json:"action" validate:"required,oneof=create update delete restore purge"`
+	UserID    *int      `json:"user_id,omitempty"` // nil - изменение без аутентифицированного пользователя
+	Changes   string    `json:"changes"`           // JSON {"before": {...}, "after": {...}} только с изменившимися полями
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (a *AuditEntry) GetID() int {
+	return a.AuditID
+}
+
+func (a *AuditEntry) SetID(id int) {
+	a.AuditID = id
+}
+
+func (a *AuditEntry) Validate() error {
+	return validate.ValidateStruct(a)
+}
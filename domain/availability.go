@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Виды записей доступности преподавателя
+const (
+	AvailabilityWeekly  = "weekly"  // еженедельное окно: в день DayWeek с TimeBegin до TimeEnd
+	AvailabilityAbsence = "absence" // отсутствие с DateStart по DateEnd: больничный, отпуск; без времени - весь день
+)
+
+var (
+	// ErrInvalidAvailability возвращается для несогласованных записей доступности
+	ErrInvalidAvailability = errors.New("invalid availability entry")
+	// ErrUnavailable возвращается, если занятие выходит за доступность преподавателя (см. AvailabilityError)
+	ErrUnavailable = errors.New("employee is unavailable")
+)
+
+// [RU] EmployeeAvailability запись доступности преподавателя: еженедельное окно, когда он может вести занятия,
+// или отсутствие на даты. У окна DateStart и DateEnd ограничивают срок его действия <--->
+// [ENG] EmployeeAvailability is a teacher availability entry: a weekly window when they can teach,
+// or an absence for dates. For a window DateStart and DateEnd limit the period it applies to
+type EmployeeAvailability struct {
+	AvailabilityID int        `json:"availability_id"`
+	EmployeeID     int        `json:"employee_id" validate:"required"`
+	Kind           string     `json:"kind" validate:"required,oneof=weekly absence"`
+	DayWeek        *string    `json:"day_week,omitempty" validate:"omitempty,max=20"` // окно: день недели
+	TimeBegin      *time.Time `json:"time_begin,omitempty"`
+	TimeEnd        *time.Time `json:"time_end,omitempty"`
+	DateStart      *time.Time `json:"date_start,omitempty"` // nil у окна - действует с любой даты
+	DateEnd        *time.Time `json:"date_end,omitempty"`   // nil у окна - действует бессрочно
+	Reason         *string    `json:"reason,omitempty" validate:"omitempty,max=255"`
+	Version        int        `json:"version"` // версия строки для оптимистичной блокировки
+}
+
+func (a *EmployeeAvailability) GetID() int {
+	return a.AvailabilityID
+}
+
+func (a *EmployeeAvailability) SetID(id int) {
+	a.AvailabilityID = id
+}
+
+func (a *EmployeeAvailability) GetVersion() int {
+	return a.Version
+}
+
+func (a *EmployeeAvailability) SetVersion(version int) {
+	a.Version = version
+}
+
+// Validate проверяет поля и согласованность: у окна день и время, у отсутствия - период
+func (a *EmployeeAvailability) Validate() error {
+	if err := validate.ValidateStruct(a); err != nil {
+		return err
+	}
+	if (a.TimeBegin == nil) != (a.TimeEnd == nil) {
+		return fmt.Errorf("%w: time_begin and time_end go together", ErrInvalidAvailability)
+	}
+	if a.TimeBegin != nil && clockMinutes(*a.TimeBegin) >= clockMinutes(*a.TimeEnd) {
+		return fmt.Errorf("%w: time_end must be after time_begin", ErrInvalidAvailability)
+	}
+	if a.DateStart != nil && a.DateEnd != nil && a.DateEnd.Before(*a.DateStart) {
+		return fmt.Errorf("%w: date_end is before date_start", ErrInvalidAvailability)
+	}
+
+	switch a.Kind {
+	case AvailabilityWeekly:
+		if a.DayWeek == nil {
+			return fmt.Errorf("%w: weekly window requires day_week", ErrInvalidAvailability)
+		}
+		if _, ok := ParseWeekday(*a.DayWeek); !ok {
+			return fmt.Errorf("%w: unknown day_week %q", ErrInvalidAvailability, *a.DayWeek)
+		}
+		if a.TimeBegin == nil {
+			return fmt.Errorf("%w: weekly window requires time_begin and time_end", ErrInvalidAvailability)
+		}
+	case AvailabilityAbsence:
+		if a.DayWeek != nil {
+			return fmt.Errorf("%w: day_week is only allowed for weekly windows", ErrInvalidAvailability)
+		}
+		if a.DateStart == nil || a.DateEnd == nil {
+			return fmt.Errorf("%w: absence requires date_start and date_end", ErrInvalidAvailability)
+		}
+	}
+	return nil
+}
+
+// Covers сообщает, действует ли запись в дату date
+func (a *EmployeeAvailability) Covers(date time.Time) bool {
+	date = DateOnly(date)
+	if a.DateStart != nil && date.Before(DateOnly(*a.DateStart)) {
+		return false
+	}
+	return a.DateEnd == nil || !date.After(DateOnly(*a.DateEnd))
+}
+
+// [RU] AvailabilityError занятие преподавателя вне его доступности; errors.Is(err, ErrUnavailable) <--->
+// [ENG] AvailabilityError is a teacher's lesson outside their availability; errors.Is(err, ErrUnavailable)
+type AvailabilityError struct {
+	EmployeeID int
+	Date       time.Time
+	TimeBegin  time.Time
+	TimeEnd    time.Time
+	Reason     string // отсутствие или отсутствие подходящего окна
+}
+
+func (e *AvailabilityError) Error() string {
+	return fmt.Sprintf("%s: employee %d on %s %s-%s: %s", ErrUnavailable, e.EmployeeID,
+		ToDMY(e.Date), ToTimeHM(e.TimeBegin), ToTimeHM(e.TimeEnd), e.Reason)
+}
+
+func (e *AvailabilityError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// [RU] Availability записи доступности одного преподавателя. Без еженедельных окон преподаватель доступен
+// в любое время, кроме отсутствий; с окнами - только внутри окна своего дня недели <--->
+// [ENG] Availability holds one teacher's availability entries. Without weekly windows the teacher is available
+// at any time except absences; with windows - only inside a window of the day of the week
+type Availability []*EmployeeAvailability
+
+// [RU] Check проверяет занятие в дату date с begin до end; нарушение возвращается как *AvailabilityError <--->
+// [ENG] Check checks a lesson on date from begin to end; a violation is returned as *AvailabilityError
+func (a Availability) Check(employeeID int, date, begin, end time.Time) error {
+	unavailable := func(reason string) error {
+		return &AvailabilityError{EmployeeID: employeeID, Date: DateOnly(date), TimeBegin: begin, TimeEnd: end, Reason: reason}
+	}
+
+	hasWindows, inWindow := false, false
+	for _, entry := range a {
+		switch entry.Kind {
+		case AvailabilityAbsence:
+			if !entry.Covers(date) {
+				continue
+			}
+			if entry.TimeBegin == nil || TimesOverlap(begin, end, *entry.TimeBegin, *entry.TimeEnd) {
+				reason := "absent"
+				if entry.Reason != nil {
+					reason += " (" + *entry.Reason + ")"
+				}
+				return unavailable(reason)
+			}
+		case AvailabilityWeekly:
+			hasWindows = true
+			if inWindow || entry.DayWeek == nil || !entry.Covers(date) {
+				continue
+			}
+			day, _ := ParseWeekday(*entry.DayWeek)
+			inWindow = day == date.Weekday() &&
+				clockMinutes(*entry.TimeBegin) <= clockMinutes(begin) && clockMinutes(end) <= clockMinutes(*entry.TimeEnd)
+		}
+	}
+	if hasWindows && !inWindow {
+		return unavailable(fmt.Sprintf("outside availability windows on %s", WeekdayName(date.Weekday())))
+	}
+	return nil
+}
+
+// [RU] CheckSchedule проверяет все занятия серии расписания; возвращает первое нарушение <--->
+// [ENG] CheckSchedule checks every occurrence of the schedule series; returns the first violation
+func (a Availability) CheckSchedule(employeeID int, schedule *Schedule) error {
+	if len(a) == 0 {
+		return nil
+	}
+	dates, err := schedule.Occurrences(schedule.SchdDateStart, schedule.SchdDateEnd)
+	if err != nil {
+		return err
+	}
+	for _, date := range dates {
+		if err := a.Check(employeeID, date, schedule.TimeBegin, schedule.TimeEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
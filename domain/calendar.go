@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Виды записей учебного календаря
+const (
+	CalendarTerm    = "term"    // учебная четверть или семестр: вне четвертей школа закрыта
+	CalendarHoliday = "holiday" // праздник, каникулы или иной закрытый день
+	CalendarSwap    = "swap"    // перенос рабочего дня: в DateStart идут занятия даты WorkedAs
+)
+
+// ErrInvalidCalendar возвращается для несогласованных записей календаря и ошибок импорта
+var ErrInvalidCalendar = errors.New("invalid academic calendar entry")
+
+// termBreakReason причина закрытия дня вне учебных четвертей
+const termBreakReason = "Каникулы"
+
+// [RU] CalendarEntry запись учебного календаря: четверть, праздник или перенос рабочего дня.
+// Период DateStart..DateEnd включает обе даты; у переноса это один день <--->
+// [ENG] CalendarEntry is an academic calendar entry: a term, a holiday or a working day swap.
+// The DateStart..DateEnd period includes both dates; a swap covers a single day
+type CalendarEntry struct {
+	CalendarID int        `json:"calendar_id"`
+	Kind       string     `json:"kind" validate:"required,oneof=term holiday swap"`
+	Name       string     `json:"name" validate:"required,min=1,max=255"`
+	DateStart  time.Time  `json:"date_start" validate:"required"`
+	DateEnd    time.Time  `json:"date_end" validate:"required"`
+	WorkedAs   *time.Time `json:"worked_as,omitempty"`                                               // перенос: дата, чьи занятия идут в DateStart
+	SourceUID  *string    `json:"source_uid,omitempty" db:"source_uid" validate:"omitempty,max=255"` // UID записи импорта; повторный импорт обновляет запись
+	Version    int        `json:"version"`                                                           // версия строки для оптимистичной блокировки
+}
+
+func (c *CalendarEntry) GetID() int {
+	return c.CalendarID
+}
+
+func (c *CalendarEntry) SetID(id int) {
+	c.CalendarID = id
+}
+
+func (c *CalendarEntry) GetVersion() int {
+	return c.Version
+}
+
+func (c *CalendarEntry) SetVersion(version int) {
+	c.Version = version
+}
+
+// Validate проверяет поля и согласованность периода: у переноса один день и заполнен WorkedAs
+func (c *CalendarEntry) Validate() error {
+	if err := validate.ValidateStruct(c); err != nil {
+		return err
+	}
+	if c.DateEnd.Before(c.DateStart) {
+		return fmt.Errorf("%w: date_end is before date_start", ErrInvalidCalendar)
+	}
+	if c.Kind == CalendarSwap {
+		if c.WorkedAs == nil {
+			return fmt.Errorf("%w: swap requires worked_as", ErrInvalidCalendar)
+		}
+		if !DateOnly(c.DateStart).Equal(DateOnly(c.DateEnd)) {
+			return fmt.Errorf("%w: swap covers a single day", ErrInvalidCalendar)
+		}
+	} else if c.WorkedAs != nil {
+		return fmt.Errorf("%w: worked_as is only allowed for swaps", ErrInvalidCalendar)
+	}
+	return nil
+}
+
+// Covers сообщает, попадает ли дата в период записи
+func (c *CalendarEntry) Covers(date time.Time) bool {
+	date = DateOnly(date)
+	return !date.Before(DateOnly(c.DateStart)) && !date.After(DateOnly(c.DateEnd))
+}
+
+// [RU] Calendar учебный календарь за период: закрытые дни и переносы. Нулевой *Calendar (nil) - все дни открыты <--->
+// [ENG] Calendar is the academic calendar for a period: closed days and swaps. A nil *Calendar keeps every day open
+type Calendar struct {
+	terms    []*CalendarEntry
+	holidays []*CalendarEntry
+	swaps    map[time.Time]*CalendarEntry // дата WorkedAs -> перенос
+	working  map[time.Time]bool           // дни переноса всегда рабочие
+	termsSet bool                         // четверти заданы: дни вне них закрыты
+}
+
+// [RU] NewCalendar строит календарь из записей; termsDefined - в календаре вообще есть четверти
+// (записи entries могут содержать только четверти, пересекающие период) <--->
+// [ENG] NewCalendar builds a calendar from entries; termsDefined means the calendar has terms at all
+// (entries may hold only the terms overlapping the period)
+func NewCalendar(entries []*CalendarEntry, termsDefined bool) *Calendar {
+	c := &Calendar{
+		swaps:    map[time.Time]*CalendarEntry{},
+		working:  map[time.Time]bool{},
+		termsSet: termsDefined,
+	}
+	for _, entry := range entries {
+		switch entry.Kind {
+		case CalendarTerm:
+			c.terms = append(c.terms, entry)
+		case CalendarHoliday:
+			c.holidays = append(c.holidays, entry)
+		case CalendarSwap:
+			if entry.WorkedAs != nil {
+				c.swaps[DateOnly(*entry.WorkedAs)] = entry
+				c.working[DateOnly(entry.DateStart)] = true
+			}
+		}
+	}
+	return c
+}
+
+// [RU] Closed сообщает, закрыта ли школа в дату date, и причину: название праздника или каникулы вне четвертей <--->
+// [ENG] Closed reports whether the school is closed on date and why: the holiday name or a break outside the terms
+func (c *Calendar) Closed(date time.Time) (reason string, closed bool) {
+	if c == nil {
+		return "", false
+	}
+	date = DateOnly(date)
+	if c.working[date] {
+		return "", false
+	}
+	for _, holiday := range c.holidays {
+		if holiday.Covers(date) {
+			return holiday.Name, true
+		}
+	}
+	if !c.termsSet {
+		return "", false
+	}
+	for _, term := range c.terms {
+		if term.Covers(date) {
+			return "", false
+		}
+	}
+	return termBreakReason, true
+}
+
+// [RU] SwappedTo возвращает день, в который переносятся занятия даты date <--->
+// [ENG] SwappedTo returns the day the occurrences of date are moved to
+func (c *Calendar) SwappedTo(date time.Time) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	swap, ok := c.swaps[DateOnly(date)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return DateOnly(swap.DateStart), true
+}
+
+// [RU] SwapSources возвращает даты, занятия которых переносятся в дни периода [from, to] <--->
+// [ENG] SwapSources returns the dates whose occurrences are moved to days within [from, to]
+func (c *Calendar) SwapSources(from, to time.Time) []time.Time {
+	if c == nil {
+		return nil
+	}
+	from, to = DateOnly(from), DateOnly(to)
+	var sources []time.Time
+	for source, swap := range c.swaps {
+		day := DateOnly(swap.DateStart)
+		if !day.Before(from) && !day.After(to) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// [RU] Apply применяет календарь к занятию: занятие даты с переносом идет в день переноса, занятие
+// в закрытый день отменяется с причиной из календаря. Отмены и переносы исключениями не меняются <--->
+// [ENG] Apply applies the calendar to an occurrence: an occurrence on a swapped date moves to the swap day,
+// an occurrence on a closed day is cancelled with the calendar's reason. Exception cancellations and moves are kept
+func (c *Calendar) Apply(occurrence *Occurrence) {
+	if c == nil || occurrence.Cancelled || !occurrence.Date.Equal(occurrence.OriginalDate) {
+		return
+	}
+	if day, ok := c.SwappedTo(occurrence.OriginalDate); ok {
+		occurrence.Date = day
+		occurrence.Swapped = true
+		return
+	}
+	if reason, closed := c.Closed(occurrence.Date); closed {
+		occurrence.Cancelled = true
+		occurrence.Closed = true
+		if occurrence.Reason == nil {
+			occurrence.Reason = &reason
+		}
+	}
+}
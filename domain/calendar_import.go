@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// [RU] ParseICalendar разбирает события VEVENT файла iCalendar (RFC 5545) в записи календаря.
+// Вид записи задает CATEGORIES (TERM, HOLIDAY, SWAP; по умолчанию праздник), дата переноса -
+// свойство X-WORKED-AS. DTEND дневного события не входит в период, как в RFC 5545.
+// Повторяющиеся события (RRULE) не поддерживаются <--->
+// [ENG] ParseICalendar parses the VEVENT events of an iCalendar file (RFC 5545) into calendar entries.
+// CATEGORIES sets the entry kind (TERM, HOLIDAY, SWAP; a holiday by default), the swapped date is
+// the X-WORKED-AS property. DTEND of an all-day event is excluded from the period, as in RFC 5545.
+// Recurring events (RRULE) are not supported
+func ParseICalendar(r io.Reader) ([]*CalendarEntry, error) {
+	lines, err := unfoldICalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries []*CalendarEntry
+		event   map[string]icalProperty
+		start   int
+	)
+	for i, line := range lines {
+		name, prop, ok := parseICalLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event, start = map[string]icalProperty{}, i+1
+		case name == "END" && strings.EqualFold(prop.value, "VEVENT") && event != nil:
+			entry, err := icalEntry(event)
+			if err != nil {
+				return nil, fmt.Errorf("%w: event at line %d: %v", ErrInvalidCalendar, start, err)
+			}
+			entries = append(entries, entry)
+			event = nil
+		case event != nil:
+			event[name] = prop
+		}
+	}
+	return entries, nil
+}
+
+// icalProperty значение свойства iCalendar с параметрами (VALUE=DATE и т.п.)
+type icalProperty struct {
+	params string
+	value  string
+}
+
+// unfoldICalendar читает строки, склеивая перенесенные (начинаются с пробела или табуляции)
+func unfoldICalendar(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseICalLine разбирает строку "NAME;PARAMS:VALUE"
+func parseICalLine(line string) (string, icalProperty, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", icalProperty{}, false
+	}
+	name, params, _ := strings.Cut(head, ";")
+	return strings.ToUpper(strings.TrimSpace(name)), icalProperty{params: strings.ToUpper(params), value: value}, true
+}
+
+// icalEntry строит запись календаря из свойств одного VEVENT
+func icalEntry(event map[string]icalProperty) (*CalendarEntry, error) {
+	if _, ok := event["RRULE"]; ok {
+		return nil, fmt.Errorf("recurring events are not supported")
+	}
+	dtstart, ok := event["DTSTART"]
+	if !ok {
+		return nil, fmt.Errorf("DTSTART is required")
+	}
+	start, _, err := parseICalDate(dtstart)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %v", err)
+	}
+
+	end := start
+	if dtend, ok := event["DTEND"]; ok {
+		date, allDay, err := parseICalDate(dtend)
+		if err != nil {
+			return nil, fmt.Errorf("DTEND: %v", err)
+		}
+		end = date
+		if allDay && date.After(start) {
+			end = date.AddDate(0, 0, -1)
+		}
+	}
+
+	entry := &CalendarEntry{
+		Kind:      CalendarHoliday,
+		Name:      unescapeICalText(event["SUMMARY"].value),
+		DateStart: start,
+		DateEnd:   end,
+	}
+	for _, category := range strings.Split(event["CATEGORIES"].value, ",") {
+		switch strings.ToUpper(strings.TrimSpace(category)) {
+		case "TERM":
+			entry.Kind = CalendarTerm
+		case "SWAP":
+			entry.Kind = CalendarSwap
+		}
+	}
+	if workedAs, ok := event["X-WORKED-AS"]; ok {
+		date, _, err := parseICalDate(workedAs)
+		if err != nil {
+			return nil, fmt.Errorf("X-WORKED-AS: %v", err)
+		}
+		entry.Kind = CalendarSwap
+		entry.WorkedAs = &date
+	}
+	if uid := strings.TrimSpace(event["UID"].value); uid != "" {
+		entry.SourceUID = &uid
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// parseICalDate разбирает DATE или DATE-TIME; allDay - значение без времени
+func parseICalDate(prop icalProperty) (date time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.value)
+	date, err = parseRuleDate(value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return date, len(value) == 8 || strings.Contains(prop.params, "VALUE=DATE"), nil
+}
+
+// unescapeICalText снимает экранирование TEXT: \, \; \n \\
+func unescapeICalText(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, "\n", `\N`, "\n", `\\`, `\`).Replace(strings.TrimSpace(value))
+}
+
+// [RU] ParseCalendarCSV разбирает CSV с заголовком: kind, name, date_start, date_end, worked_as, uid
+// (date_end, worked_as и uid необязательны). Даты - DD.MM.YYYY, разделитель - запятая или точка с запятой <--->
+// [ENG] ParseCalendarCSV parses CSV with a header: kind, name, date_start, date_end, worked_as, uid
+// (date_end, worked_as and uid are optional). Dates are DD.MM.YYYY, the separator is a comma or a semicolon
+func ParseCalendarCSV(r io.Reader) ([]*CalendarEntry, error) {
+	reader := bufio.NewReader(r)
+	header, err := reader.Peek(reader.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	firstLine, _, _ := strings.Cut(string(header), "\n")
+
+	records := csv.NewReader(reader)
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		records.Comma = ';'
+	}
+	records.TrimLeadingSpace = true
+	records.FieldsPerRecord = -1
+
+	rows, err := records.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"kind", "name", "date_start"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: column %s is required", ErrInvalidCalendar, required)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	date := func(value string) (time.Time, error) {
+		return time.Parse("02.01.2006", value)
+	}
+
+	entries := make([]*CalendarEntry, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		line := n + 2
+		entry := &CalendarEntry{Kind: strings.ToLower(field(row, "kind")), Name: field(row, "name")}
+		if entry.DateStart, err = date(field(row, "date_start")); err != nil {
+			return nil, fmt.Errorf("%w: line %d: date_start must be DD.MM.YYYY", ErrInvalidCalendar, line)
+		}
+		entry.DateEnd = entry.DateStart
+		if value := field(row, "date_end"); value != "" {
+			if entry.DateEnd, err = date(value); err != nil {
+				return nil, fmt.Errorf("%w: line %d: date_end must be DD.MM.YYYY", ErrInvalidCalendar, line)
+			}
+		}
+		if value := field(row, "worked_as"); value != "" {
+			workedAs, err := date(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: worked_as must be DD.MM.YYYY", ErrInvalidCalendar, line)
+			}
+			entry.WorkedAs = &workedAs
+		}
+		if uid := field(row, "uid"); uid != "" {
+			entry.SourceUID = &uid
+		}
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
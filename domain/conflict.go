@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ресурсы занятия, которые не могут быть заняты дважды в одно время
+const (
+	ResourceEmployee = "employee" // преподаватель занятия
+	ResourceAudience = "audience" // аудитория занятия
+	ResourceGroup    = "group"    // группа группового занятия
+	ResourceStudent  = "student"  // студент индивидуального занятия или группы группового
+)
+
+// ErrScheduleConflict возвращается, если запись расписания занимает уже занятый ресурс (см. ConflictError)
+var ErrScheduleConflict = errors.New("schedule conflict")
+
+// [RU] Resource ресурс, общий для двух занятий <--->
+// [ENG] Resource is a resource shared by two lessons
+type Resource struct {
+	Kind string `json:"resource"`
+	ID   int    `json:"resource_id"`
+}
+
+// [RU] Conflict пересечение записи расписания с занятием другой записи по ресурсу: первая общая дата
+// и время занятия, с которым конфликт <--->
+// [ENG] Conflict is an overlap of a schedule entry with another entry's lesson on a resource: the first common date
+// and the time of the conflicting lesson
+type Conflict struct {
+	Resource
+	LessonID   int       `json:"lesson_id"`   // занятие, с которым конфликт
+	LessonName string    `json:"lesson_name"` // его название
+	ScheduleID int       `json:"schedule_id"` // запись расписания этого занятия
+	Date       time.Time `json:"date"`        // первая дата, в которую идут оба занятия
+	TimeBegin  time.Time `json:"time_begin"`
+	TimeEnd    time.Time `json:"time_end"`
+}
+
+// String описывает конфликт для сообщений об ошибке
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %d is busy with lesson %d on %s %s-%s",
+		c.Kind, c.ID, c.LessonID, ToDMY(c.Date), ToTimeHM(c.TimeBegin), ToTimeHM(c.TimeEnd))
+}
+
+// [RU] ConflictError ошибка записи расписания с найденными конфликтами; errors.Is(err, ErrScheduleConflict) <--->
+// [ENG] ConflictError is a schedule entry error carrying the conflicts found; errors.Is(err, ErrScheduleConflict)
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Conflicts) == 0 {
+		return ErrScheduleConflict.Error()
+	}
+	msg := fmt.Sprintf("%s: %s", ErrScheduleConflict, e.Conflicts[0])
+	if len(e.Conflicts) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Conflicts)-1)
+	}
+	return msg
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrScheduleConflict
+}
+
+// [RU] SharedResources возвращает ресурсы, которые занимают оба занятия. groupOf возвращает группу студента
+// индивидуального занятия: студент занят и на групповых занятиях своей группы <--->
+// [ENG] SharedResources returns the resources both lessons occupy. groupOf returns the group of an individual
+// lesson's student: the student is also busy during the group lessons of their group
+func SharedResources(a, b *Lesson, groupOf func(studentID int) (int, bool)) []Resource {
+	var shared []Resource
+	if a.EmployeeID == b.EmployeeID {
+		shared = append(shared, Resource{ResourceEmployee, a.EmployeeID})
+	}
+	if a.AudienceID != nil && b.AudienceID != nil && *a.AudienceID == *b.AudienceID {
+		shared = append(shared, Resource{ResourceAudience, *a.AudienceID})
+	}
+
+	switch {
+	case a.StudentID == nil && b.StudentID == nil:
+		if a.GroupID == b.GroupID {
+			shared = append(shared, Resource{ResourceGroup, a.GroupID})
+		}
+	case a.StudentID != nil && b.StudentID != nil:
+		if *a.StudentID == *b.StudentID {
+			shared = append(shared, Resource{ResourceStudent, *a.StudentID})
+		}
+	default:
+		individual, group := a, b
+		if individual.StudentID == nil {
+			individual, group = b, a
+		}
+		studentGroup, ok := groupOf(*individual.StudentID)
+		if !ok {
+			studentGroup = individual.GroupID
+		}
+		if studentGroup == group.GroupID {
+			shared = append(shared, Resource{ResourceStudent, *individual.StudentID})
+		}
+	}
+	return shared
+}
+
+// [RU] TimesOverlap сообщает, пересекаются ли интервалы времени суток [aBegin, aEnd) и [bBegin, bEnd) <--->
+// [ENG] TimesOverlap reports whether the time-of-day intervals [aBegin, aEnd) and [bBegin, bEnd) overlap
+func TimesOverlap(aBegin, aEnd, bBegin, bEnd time.Time) bool {
+	return clockMinutes(aBegin) < clockMinutes(bEnd) && clockMinutes(bBegin) < clockMinutes(aEnd)
+}
+
+// clockMinutes минуты от начала суток
+func clockMinutes(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
@@ -1,14 +1,18 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // ProgrammDistribution представляет распределение предметов по программам
 type ProgrammDistribution struct {
-	ProgrammDistrID int `json:"programm_distr_id"`
-	MusprogrammID   int `json:"musprogramm_id" validate:"required"`
-	SubjectID       int `json:"subject_id" validate:"required"`
+	ProgrammDistrID int        `json:"programm_distr_id"`
+	MusprogrammID   int        `json:"musprogramm_id" validate:"required"`
+	SubjectID       int        `json:"subject_id" validate:"required"`
+	Version         int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (pd *ProgrammDistribution) GetID() int {
@@ -19,15 +23,25 @@ func (pd *ProgrammDistribution) SetID(id int) {
 	pd.ProgrammDistrID = id
 }
 
+func (pd *ProgrammDistribution) GetVersion() int {
+	return pd.Version
+}
+
+func (pd *ProgrammDistribution) SetVersion(version int) {
+	pd.Version = version
+}
+
 func (pd *ProgrammDistribution) Validate() error {
 	return validate.ValidateStruct(pd)
 }
 
 // SubjectDistribution представляет распределение предметов по сотрудникам
 type SubjectDistribution struct {
-	SubjectDistrID int `json:"subject_distr_id"`
-	EmployeeID     int `json:"employee_id" validate:"required"`
-	SubjectID      int `json:"subject_id" validate:"required"`
+	SubjectDistrID int        `json:"subject_distr_id"`
+	EmployeeID     int        `json:"employee_id" validate:"required"`
+	SubjectID      int        `json:"subject_id" validate:"required"`
+	Version        int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (sd *SubjectDistribution) GetID() int {
@@ -38,6 +52,14 @@ func (sd *SubjectDistribution) SetID(id int) {
 	sd.SubjectDistrID = id
 }
 
+func (sd *SubjectDistribution) GetVersion() int {
+	return sd.Version
+}
+
+func (sd *SubjectDistribution) SetVersion(version int) {
+	sd.Version = version
+}
+
 func (sd *SubjectDistribution) Validate() error {
 	return validate.ValidateStruct(sd)
 }
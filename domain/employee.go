@@ -8,15 +8,17 @@ import (
 
 // Employee представляет запись сотрудника
 type Employee struct {
-	EmployeeID     int       `json:"employee_id"`
-	UserID         *int      `json:"user_id,omitempty"`
-	Surname        string    `json:"surname" validate:"required,min=1,max=60"`
-	Name           string    `json:"name" validate:"required,min=1,max=45"`
-	FatherName     *string   `json:"father_name,omitempty" validate:"omitempty,max=55"`
-	Birthday       time.Time `json:"birthday" validate:"required,birthday_past"`
-	PhoneNumber    string    `json:"phone_number" validate:"required,len=11"`
-	Job            string    `json:"job" validate:"required,min=1,max=60"`
-	WorkExperience int       `json:"work_experience" validate:"required,gte=0"`
+	EmployeeID     int        `json:"employee_id"`
+	UserID         *int       `json:"user_id,omitempty"`
+	Surname        string     `json:"surname" validate:"required,min=1,max=60"`
+	Name           string     `json:"name" validate:"required,min=1,max=45"`
+	FatherName     *string    `json:"father_name,omitempty" validate:"omitempty,max=55"`
+	Birthday       time.Time  `json:"birthday" validate:"required,birthday_past"`
+	PhoneNumber    string     `json:"phone_number" validate:"required,len=11"`
+	Job            string     `json:"job" validate:"required,min=1,max=60"`
+	WorkExperience int        `json:"work_experience" validate:"required,gte=0"`
+	Version        int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (e *Employee) GetID() int {
@@ -27,6 +29,14 @@ func (e *Employee) SetID(id int) {
 	e.EmployeeID = id
 }
 
+func (e *Employee) GetVersion() int {
+	return e.Version
+}
+
+func (e *Employee) SetVersion(version int) {
+	e.Version = version
+}
+
 func (e *Employee) Validate() error {
 	return validate.ValidateStruct(e)
 }
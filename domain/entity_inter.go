@@ -35,3 +35,12 @@ func ToTimeHM(t time.Time) string {
 func ToDateTime(t time.Time) string {
 	return t.Format("02.01.2006 15:04:05")
 }
+
+// ToDateTimePtr преобразует *time.Time в "DD.MM.YYYY HH:MM:SS"; nil остается nil
+func ToDateTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := ToDateTime(*t)
+	return &s
+}
@@ -1,16 +1,25 @@
 package domain
 
 import (
+	"errors"
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
+// ErrGroupFull возвращается, если в группе не осталось свободных мест (см. StudyGroup.MaxStudents)
+var ErrGroupFull = errors.New("study group is full")
+
 // StudyGroup представляет запись группы
 type StudyGroup struct {
-	GroupID          int    `json:"group_id"`
-	MusProgrammID    int    `json:"musprogramm_id" validate:"required"`
-	GroupName        string `json:"group_name" validate:"required,min=1,max=100"`
-	StudyYear        int    `json:"study_year" validate:"required"`
-	NumberOfStudents int    `json:"number_of_students" validate:"required"`
+	GroupID          int        `json:"group_id"`
+	MusProgrammID    int        `json:"musprogramm_id" validate:"required"`
+	GroupName        string     `json:"group_name" validate:"required,min=1,max=100"`
+	StudyYear        int        `json:"study_year" validate:"required"`
+	NumberOfStudents int        `json:"number_of_students" validate:"min=0"`               // число активных студентов; ведется автоматически
+	MaxStudents      *int       `json:"max_students,omitempty" validate:"omitempty,min=1"` // вместимость группы; nil - без ограничения
+	Version          int        `json:"version"`                                           // версия строки для оптимистичной блокировки
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`                              // момент мягкого удаления; nil - строка активна
 }
 
 func (g *StudyGroup) GetID() int {
@@ -21,6 +30,25 @@ func (g *StudyGroup) SetID(id int) {
 	g.GroupID = id
 }
 
+func (g *StudyGroup) GetVersion() int {
+	return g.Version
+}
+
+func (g *StudyGroup) SetVersion(version int) {
+	g.Version = version
+}
+
+// [RU] FreePlaces возвращает число свободных мест с учетом мест, предложенных из листа ожидания;
+// ok == false - вместимость не ограничена <--->
+// [ENG] FreePlaces returns the number of free places, counting places offered from the waitlist as taken;
+// ok == false means the capacity is unlimited
+func (g *StudyGroup) FreePlaces(offered int) (free int, ok bool) {
+	if g.MaxStudents == nil {
+		return 0, false
+	}
+	return max(*g.MaxStudents-g.NumberOfStudents-offered, 0), true
+}
+
 func (g *StudyGroup) Validate() error {
 	return validate.ValidateStruct(g)
 }
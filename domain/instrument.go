@@ -1,16 +1,20 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // Instrument представляет запись инструмента
 type Instrument struct {
-	InstrumentID int    `json:"instrument_id"`
-	AudienceID   int    `json:"audience_id" validate:"required"`
-	Name         string `json:"name" validate:"required,min=1,max=150"`
-	InstrType    string `json:"instr_type" validate:"required,min=1,max=70"`
-	Condition    string `json:"condition" validate:"required,min=1,max=70"`
+	InstrumentID int        `json:"instrument_id"`
+	AudienceID   int        `json:"audience_id" validate:"required"`
+	Name         string     `json:"name" validate:"required,min=1,max=150"`
+	InstrType    string     `json:"instr_type" validate:"required,min=1,max=70"`
+	Condition    string     `json:"condition" validate:"required,min=1,max=70"`
+	Version      int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (i *Instrument) GetID() int {
@@ -21,6 +25,14 @@ func (i *Instrument) SetID(id int) {
 	i.InstrumentID = id
 }
 
+func (i *Instrument) GetVersion() int {
+	return i.Version
+}
+
+func (i *Instrument) SetVersion(version int) {
+	i.Version = version
+}
+
 func (i *Instrument) Validate() error {
 	return validate.ValidateStruct(i)
 }
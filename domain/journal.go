@@ -0,0 +1,58 @@
+package domain
+
+import "errors"
+
+// ErrInvalidJournal возвращается для журнала с учеником не из состава занятия, чужой оценкой или отмененным занятием
+var ErrInvalidJournal = errors.New("invalid journal")
+
+// JournalEntry строка журнала: ученик, отметка присутствия и оценки за занятие
+type JournalEntry struct {
+	Student    *Student             `json:"student"`
+	Attendance *StudentAttendance   `json:"attendance,omitempty"` // nil - присутствие не отмечено
+	Grades     []*StudentAssessment `json:"grades"`
+}
+
+// [RU] Journal журнал одного занятия: состав занятия (группа или индивидуальный ученик) с посещаемостью
+// и оценками <--->
+// [ENG] Journal is the journal of a single class session: the lesson's roster (the group or the individual student)
+// with attendance and grades
+type Journal struct {
+	Lesson     *Lesson           `json:"lesson"`
+	Occurrence *LessonOccurrence `json:"occurrence"`
+	Entries    []*JournalEntry   `json:"entries"`
+}
+
+// [RU] NewJournal раскладывает посещаемость и оценки занятия по ученикам состава. Из нескольких записей
+// посещаемости ученика берется первая; записи учеников вне состава в журнал не попадают <--->
+// [ENG] NewJournal sorts the session's attendance and grades by the students of the roster. Of several attendance
+// records of a student the first one is taken; records of students outside the roster are left out
+func NewJournal(lesson *Lesson, session *LessonSession, roster []*Student) *Journal {
+	journal := &Journal{Lesson: lesson, Occurrence: session.Occurrence, Entries: make([]*JournalEntry, 0, len(roster))}
+	byStudent := make(map[int]*JournalEntry, len(roster))
+	for _, student := range roster {
+		entry := &JournalEntry{Student: student, Grades: []*StudentAssessment{}}
+		journal.Entries = append(journal.Entries, entry)
+		byStudent[student.StudentID] = entry
+	}
+	for _, attendance := range session.Attendance {
+		if entry, ok := byStudent[attendance.StudentID]; ok && entry.Attendance == nil {
+			entry.Attendance = attendance
+		}
+	}
+	for _, assessment := range session.Assessments {
+		if entry, ok := byStudent[assessment.StudentID]; ok {
+			entry.Grades = append(entry.Grades, assessment)
+		}
+	}
+	return journal
+}
+
+// Entry возвращает строку журнала ученика; nil - ученик не входит в состав занятия
+func (j *Journal) Entry(studentID int) *JournalEntry {
+	for _, entry := range j.Entries {
+		if entry.Student.StudentID == studentID {
+			return entry
+		}
+	}
+	return nil
+}
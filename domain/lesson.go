@@ -1,18 +1,28 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // Lesson представляет запись занятия
 type Lesson struct {
-	LessonID   int    `json:"lesson_id"`
-	AudienceID *int   `json:"audience_id,omitempty"`
-	EmployeeID int    `json:"employee_id" validate:"required"`
-	GroupID    int    `json:"group_id" validate:"required"`
-	StudentID  *int   `json:"student_id,omitempty"`
-	LessonName string `json:"lesson_name" validate:"required,min=1,max=70"`
-	SubjectID  int    `json:"subject_id" validate:"required"`
+	LessonID   int        `json:"lesson_id"`
+	AudienceID *int       `json:"audience_id,omitempty"`
+	EmployeeID int        `json:"employee_id" validate:"required"`
+	GroupID    int        `json:"group_id" validate:"required"`
+	StudentID  *int       `json:"student_id,omitempty"`
+	LessonName string     `json:"lesson_name" validate:"required,min=1,max=70"`
+	SubjectID  int        `json:"subject_id" validate:"required"`
+	Version    int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
+
+	// Связанные сущности: заполняются только по Filter.Preloads / ?include=
+	Employee *Employee   `json:"employee,omitempty" db:"-" validate:"-"`
+	Group    *StudyGroup `json:"group,omitempty" db:"-" validate:"-"`
+	Subject  *Subject    `json:"subject,omitempty" db:"-" validate:"-"`
+	Audience *Audience   `json:"audience,omitempty" db:"-" validate:"-"`
 }
 
 func (l *Lesson) GetID() int {
@@ -23,6 +33,14 @@ func (l *Lesson) SetID(id int) {
 	l.LessonID = id
 }
 
+func (l *Lesson) GetVersion() int {
+	return l.Version
+}
+
+func (l *Lesson) SetVersion(version int) {
+	l.Version = version
+}
+
 func (l *Lesson) Validate() error {
 	return validate.ValidateStruct(l)
 }
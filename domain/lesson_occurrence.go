@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Состояния проведенного занятия
+const (
+	OccurrencePlanned   = "planned"   // занятие идет по расписанию
+	OccurrenceHeld      = "held"      // занятие проведено; расписание его больше не меняет
+	OccurrenceCancelled = "cancelled" // отменено исключением или учебным календарем
+	OccurrenceMoved     = "moved"     // перенесено на другую дату или время
+)
+
+// ErrInvalidOccurrence возвращается, если посещаемость или оценка не соответствует занятию, к которому привязана
+var ErrInvalidOccurrence = errors.New("record does not match the lesson occurrence")
+
+// [RU] LessonOccurrence проведенное (или запланированное) занятие серии расписания: "занятие 14 октября".
+// Создается из серии по дате OccurrenceDate при первом обращении; дата, время и состояние обновляются по расписанию,
+// пока занятие не проведено. EmployeeID и AudienceID - фактические преподаватель (с учетом замены) и аудитория,
+// их можно уточнить при отметке проведения <--->
+// [ENG] LessonOccurrence is a held (or planned) occurrence of a schedule series: "the lesson on 14 Oct".
+// It is created from the series on the OccurrenceDate on first access; the date, time and status follow the schedule
+// until the occurrence is held. EmployeeID and AudienceID are the actual teacher (substitution applied) and room,
+// they can be corrected when the occurrence is marked held
+type LessonOccurrence struct {
+	OccurrenceID   int       `json:"occurrence_id"`
+	ScheduleID     int       `json:"schedule_id" validate:"required"`
+	LessonID       int       `json:"lesson_id" validate:"required"`
+	OccurrenceDate time.Time `json:"occurrence_date" validate:"required"` // дата занятия по правилу
+	Date           time.Time `json:"date" validate:"required"`            // дата проведения
+	TimeBegin      time.Time `json:"time_begin"`
+	TimeEnd        time.Time `json:"time_end"`
+	Status         string    `json:"status" validate:"required,oneof=planned held cancelled moved"`
+	EmployeeID     int       `json:"employee_id" validate:"required"`
+	AudienceID     *int      `json:"audience_id,omitempty"`
+	Topic          *string   `json:"topic,omitempty" validate:"omitempty,max=255"`
+	Notes          *string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	Version        int       `json:"version"` // версия строки для оптимистичной блокировки
+}
+
+func (o *LessonOccurrence) GetID() int {
+	return o.OccurrenceID
+}
+
+func (o *LessonOccurrence) SetID(id int) {
+	o.OccurrenceID = id
+}
+
+func (o *LessonOccurrence) GetVersion() int {
+	return o.Version
+}
+
+func (o *LessonOccurrence) SetVersion(version int) {
+	o.Version = version
+}
+
+func (o *LessonOccurrence) Validate() error {
+	if err := validate.ValidateStruct(o); err != nil {
+		return err
+	}
+	if clockMinutes(o.TimeBegin) >= clockMinutes(o.TimeEnd) {
+		return fmt.Errorf("%w: occurrence must end after it begins", ErrInvalidRule)
+	}
+	return nil
+}
+
+// [RU] Follow переносит в занятие дату, время, состояние и преподавателя развернутого занятия серии: преподаватель -
+// заменяющий (substituteID != nil) или преподаватель занятия. Аудитория берется из занятия только при создании.
+// Проведенное занятие не меняется. Возвращает true, если занятие изменилось <--->
+// [ENG] Follow copies the date, time, status and teacher of the expanded series occurrence: the teacher is
+// the substitute (substituteID != nil) or the lesson's teacher. The room is taken from the lesson only on creation.
+// A held occurrence is left as is. Returns true if the occurrence has changed
+func (o *LessonOccurrence) Follow(occurrence Occurrence, lesson *Lesson, substituteID *int) bool {
+	if o.Status == OccurrenceHeld {
+		return false
+	}
+	before := *o
+	o.EmployeeID = lesson.EmployeeID
+	if substituteID != nil {
+		o.EmployeeID = *substituteID
+	}
+	if o.OccurrenceID == 0 {
+		o.AudienceID = lesson.AudienceID
+	}
+
+	o.Date, o.TimeBegin, o.TimeEnd = occurrence.Date, occurrence.TimeBegin, occurrence.TimeEnd
+	switch {
+	case occurrence.Cancelled:
+		o.Status = OccurrenceCancelled
+	case occurrence.ExceptionID != nil || occurrence.Swapped:
+		o.Status = OccurrenceMoved
+	default:
+		o.Status = OccurrencePlanned
+	}
+	return o.EmployeeID != before.EmployeeID || o.Status != before.Status || !o.Date.Equal(before.Date) ||
+		ToTimeHM(o.TimeBegin) != ToTimeHM(before.TimeBegin) || ToTimeHM(o.TimeEnd) != ToTimeHM(before.TimeEnd)
+}
+
+// [RU] LessonSession журнал одного занятия: само занятие, посещаемость и оценки, привязанные к нему <--->
+// [ENG] LessonSession is the journal of a single class session: the occurrence, its attendance and grades
+type LessonSession struct {
+	Occurrence  *LessonOccurrence    `json:"occurrence"`
+	Attendance  []*StudentAttendance `json:"attendance"`
+	Assessments []*StudentAssessment `json:"assessments"`
+}
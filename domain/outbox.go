@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// OutboxMessage сообщение outbox: побочный эффект изменения, который доставляется после фиксации транзакции
+type OutboxMessage struct {
+	OutboxID      int        `json:"outbox_id"`
+	Topic         string     `json:"topic" validate:"required,max=100"` // по топику диспетчер выбирает получателей
+	Payload       string     `json:"payload"`                           // JSON сообщения
+	Attempts      int        `json:"attempts"`                          // число неудачных попыток доставки
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"` // nil - сообщение еще не доставлено
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (o *OutboxMessage) GetID() int {
+	return o.OutboxID
+}
+
+func (o *OutboxMessage) SetID(id int) {
+	o.OutboxID = id
+}
+
+func (o *OutboxMessage) Validate() error {
+	return validate.ValidateStruct(o)
+}
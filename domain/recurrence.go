@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule возвращается для правила повторения вне поддерживаемого подмножества RRULE
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// weekdayCodes коды дней недели RRULE (RFC 5545, BYDAY)
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// weekdayNames названия дней недели, в которых хранится day_week
+var weekdayNames = []string{"Воскресенье", "Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота"}
+
+// [RU] ParseWeekday разбирает название дня недели: русское (как в day_week) или английское <--->
+// [ENG] ParseWeekday parses a weekday name: Russian (as stored in day_week) or English
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.TrimSpace(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, weekdayNames[day]) || strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// WeekdayName возвращает русское название дня недели для day_week
+func WeekdayName(day time.Weekday) string {
+	return weekdayNames[day]
+}
+
+// DateOnly отбрасывает время суток: даты занятий сравниваются как даты в UTC
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// [RU] Recurrence правило повторения - подмножество RRULE из RFC 5545: FREQ=WEEKLY с INTERVAL
+// (2 - раз в две недели), BYDAY, UNTIL или COUNT. Недели начинаются с понедельника (WKST=MO) <--->
+// [ENG] Recurrence is a recurrence rule - an RFC 5545 RRULE subset: FREQ=WEEKLY with INTERVAL
+// (2 - every other week), BYDAY, UNTIL or COUNT. Weeks start on Monday (WKST=MO)
+type Recurrence struct {
+	Interval int            // повтор каждые Interval недель
+	ByDay    []time.Weekday // дни недели по порядку с понедельника; пусто - день первого занятия
+	Until    *time.Time     // последняя возможная дата, включительно
+	Count    int            // число занятий серии; 0 - без ограничения по числу
+}
+
+// [RU] ParseRRule разбирает значение RRULE, например "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20250531".
+// Префикс "RRULE:" допускается <--->
+// [ENG] ParseRRule parses an RRULE value, e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20250531".
+// The "RRULE:" prefix is allowed
+func ParseRRule(value string) (Recurrence, error) {
+	rule := Recurrence{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return rule, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if !ok || val == "" {
+			return rule, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return rule, fmt.Errorf("%w: %s is repeated", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			if val != "WEEKLY" {
+				return rule, fmt.Errorf("%w: only FREQ=WEEKLY is supported, got %s", ErrInvalidRule, val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("%w: INTERVAL must be a positive number", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day := slices.Index(weekdayCodes, code)
+				if day < 0 {
+					return rule, fmt.Errorf("%w: unsupported BYDAY value %q", ErrInvalidRule, code)
+				}
+				if !slices.Contains(rule.ByDay, time.Weekday(day)) {
+					rule.ByDay = append(rule.ByDay, time.Weekday(day))
+				}
+			}
+			slices.SortFunc(rule.ByDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+		case "UNTIL":
+			until, err := parseRuleDate(val)
+			if err != nil {
+				return rule, fmt.Errorf("%w: UNTIL: %v", ErrInvalidRule, err)
+			}
+			rule.Until = &until
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("%w: COUNT must be a positive number", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "WKST":
+			if val != "MO" {
+				return rule, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return rule, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if !seen["FREQ"] {
+		return rule, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Until != nil && rule.Count > 0 {
+		return rule, fmt.Errorf("%w: UNTIL and COUNT cannot be combined", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+// String возвращает правило в каноническом виде RRULE (без префикса "RRULE:")
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=WEEKLY"}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+FormatRuleDate(*r.Until))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// DayNames возвращает дни правила для day_week, например "Понедельник,Четверг"
+func (r Recurrence) DayNames() string {
+	names := make([]string, len(r.ByDay))
+	for i, day := range r.ByDay {
+		names[i] = WeekdayName(day)
+	}
+	return strings.Join(names, ",")
+}
+
+// [RU] Each перебирает даты серии, начинающейся в start, по возрастанию до даты limit включительно,
+// пока fn возвращает true. COUNT отсчитывается от start, поэтому перебор всегда идет с начала серии <--->
+// [ENG] Each walks the dates of the series starting at start in ascending order up to limit inclusive,
+// while fn returns true. COUNT is counted from start, so the walk always begins at the series start
+func (r Recurrence) Each(start, limit time.Time, fn func(date time.Time) bool) {
+	start, limit = DateOnly(start), DateOnly(limit)
+	if r.Until != nil && r.Until.Before(limit) {
+		limit = DateOnly(*r.Until)
+	}
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	interval := max(r.Interval, 1)
+
+	weekStart := start.AddDate(0, 0, -mondayIndex(start.Weekday()))
+	for n := 0; !weekStart.After(limit); weekStart = weekStart.AddDate(0, 0, 7*interval) {
+		for _, day := range days {
+			date := weekStart.AddDate(0, 0, mondayIndex(day))
+			if date.Before(start) {
+				continue
+			}
+			if date.After(limit) {
+				return
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return
+			}
+			if !fn(date) {
+				return
+			}
+		}
+	}
+}
+
+// [RU] Last возвращает дату последнего занятия серии; ok == false, если серия пуста или не ограничена <--->
+// [ENG] Last returns the date of the series' last occurrence; ok == false if the series is empty or unbounded
+func (r Recurrence) Last(start time.Time) (last time.Time, ok bool) {
+	if r.Until == nil && r.Count == 0 {
+		return time.Time{}, false
+	}
+	limit := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	r.Each(start, limit, func(date time.Time) bool {
+		last, ok = date, true
+		return true
+	})
+	return last, ok
+}
+
+// mondayIndex номер дня в неделе, начинающейся с понедельника (0 - понедельник)
+func mondayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// parseRuleDate разбирает дату RFC 5545: YYYYMMDD или YYYYMMDDTHHMMSS[Z]; время отбрасывается
+func parseRuleDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("date %q is not YYYYMMDD", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date %q is not YYYYMMDD", value)
+	}
+	return date, nil
+}
+
+// FormatRuleDate форматирует дату для RRULE и EXDATE: YYYYMMDD
+func FormatRuleDate(date time.Time) string {
+	return date.Format("20060102")
+}
+
+// [RU] ParseExDates разбирает значение EXDATE: даты YYYYMMDD через запятую <--->
+// [ENG] ParseExDates parses an EXDATE value: comma-separated YYYYMMDD dates
+func ParseExDates(value string) ([]time.Time, error) {
+	var dates []time.Time
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(value), "EXDATE:"), ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		date, err := parseRuleDate(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: EXDATE: %v", ErrInvalidRule, err)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// FormatExDates форматирует даты для EXDATE: YYYYMMDD через запятую
+func FormatExDates(dates []time.Time) string {
+	parts := make([]string, len(dates))
+	for i, date := range dates {
+		parts[i] = FormatRuleDate(date)
+	}
+	return strings.Join(parts, ",")
+}
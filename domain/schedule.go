@@ -1,20 +1,30 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/SerMoskvin/validate"
 )
 
-// Schedule представляет запись расписания
+// [RU] Schedule правило расписания занятия: серия повторений с SchdDateStart по SchdDateEnd.
+// Без RRule занятие идет каждую неделю в день DayWeek; с RRule DayWeek и SchdDateEnd выводятся из правила <--->
+// [ENG] Schedule is a lesson's schedule rule: a series of occurrences from SchdDateStart to SchdDateEnd.
+// Without RRule the lesson runs weekly on DayWeek; with RRule, DayWeek and SchdDateEnd are derived from the rule
 type Schedule struct {
-	ScheduleID    int       `json:"schedule_id"`
-	LessonID      int       `json:"lesson_id" validate:"required"`
-	DayWeek       string    `json:"day_week" validate:"required,min=1,max=20"`
-	TimeBegin     time.Time `json:"time_begin" validate:"required"`
-	TimeEnd       time.Time `json:"time_end" validate:"required"`
-	SchdDateStart time.Time `json:"schd_date_start" validate:"required"`
-	SchdDateEnd   time.Time `json:"schd_date_end" validate:"required"`
+	ScheduleID    int        `json:"schedule_id"`
+	LessonID      int        `json:"lesson_id" validate:"required"`
+	DayWeek       string     `json:"day_week" validate:"required,min=1,max=100"`
+	TimeBegin     time.Time  `json:"time_begin" validate:"required"`
+	TimeEnd       time.Time  `json:"time_end" validate:"required"`
+	SchdDateStart time.Time  `json:"schd_date_start" validate:"required"` // DTSTART: дата первого занятия
+	SchdDateEnd   time.Time  `json:"schd_date_end" validate:"required"`   // дата последнего занятия; без UNTIL и COUNT - граница серии
+	RRule         *string    `json:"rrule,omitempty" db:"rrule"`          // правило RRULE, см. Recurrence
+	ExDate        *string    `json:"exdate,omitempty" db:"exdate"`        // исключенные даты EXDATE: YYYYMMDD через запятую
+	Version       int        `json:"version"`                             // версия строки для оптимистичной блокировки
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`                // момент мягкого удаления; nil - строка активна
 }
 
 func (s *Schedule) GetID() int {
@@ -25,6 +35,160 @@ func (s *Schedule) SetID(id int) {
 	s.ScheduleID = id
 }
 
+func (s *Schedule) GetVersion() int {
+	return s.Version
+}
+
+func (s *Schedule) SetVersion(version int) {
+	s.Version = version
+}
+
+// Validate проверяет поля и правило повторения: серия должна разбираться и содержать хотя бы одно занятие
 func (s *Schedule) Validate() error {
-	return validate.ValidateStruct(s)
+	if err := validate.ValidateStruct(s); err != nil {
+		return err
+	}
+	rule, err := s.Recurrence()
+	if err != nil {
+		return err
+	}
+	if _, err := s.ExDates(); err != nil {
+		return err
+	}
+	if _, ok := rule.Last(s.SchdDateStart); !ok {
+		return fmt.Errorf("%w: the series has no occurrences", ErrInvalidRule)
+	}
+	return nil
+}
+
+// [RU] Normalize приводит RRule и ExDate к каноническому виду и выводит из правила DayWeek и SchdDateEnd -
+// дату последнего занятия серии. Пустое RRule удаляет правило: запись снова еженедельная по DayWeek <--->
+// [ENG] Normalize brings RRule and ExDate to the canonical form and derives DayWeek and SchdDateEnd -
+// the date of the series' last occurrence - from the rule. An empty RRule removes the rule: the entry is weekly on DayWeek again
+func (s *Schedule) Normalize() error {
+	if s.RRule != nil && strings.TrimSpace(*s.RRule) == "" {
+		s.RRule = nil
+	}
+	if s.RRule != nil {
+		// Каноническая строка хранит только то, что задано в правиле: граница по SchdDateEnd в нее не попадает
+		rule, err := ParseRRule(*s.RRule)
+		if err != nil {
+			return err
+		}
+		if len(rule.ByDay) == 0 {
+			rule.ByDay = []time.Weekday{s.SchdDateStart.Weekday()}
+		}
+		canonical := rule.String()
+		s.RRule = &canonical
+		s.DayWeek = rule.DayNames()
+	}
+
+	exdates, err := s.ExDates()
+	if err != nil {
+		return err
+	}
+	if len(exdates) == 0 {
+		s.ExDate = nil
+	} else {
+		slices.SortFunc(exdates, func(a, b time.Time) int { return a.Compare(b) })
+		formatted := FormatExDates(slices.CompactFunc(exdates, func(a, b time.Time) bool { return a.Equal(b) }))
+		s.ExDate = &formatted
+	}
+
+	rule, err := s.Recurrence()
+	if err != nil {
+		return err
+	}
+	last, ok := rule.Last(s.SchdDateStart)
+	if !ok {
+		return fmt.Errorf("%w: the series has no occurrences", ErrInvalidRule)
+	}
+	s.SchdDateEnd = last
+	return nil
+}
+
+// [RU] Recurrence возвращает правило повторения записи. Без RRule - еженедельно в дни DayWeek (через запятую);
+// без UNTIL и COUNT серия ограничена SchdDateEnd <--->
+// [ENG] Recurrence returns the entry's recurrence rule. Without RRule - weekly on the DayWeek days (comma-separated);
+// without UNTIL and COUNT the series is bounded by SchdDateEnd
+func (s *Schedule) Recurrence() (Recurrence, error) {
+	var rule Recurrence
+	if s.RRule != nil && *s.RRule != "" {
+		parsed, err := ParseRRule(*s.RRule)
+		if err != nil {
+			return rule, err
+		}
+		rule = parsed
+	} else {
+		rule = Recurrence{Interval: 1}
+		for _, name := range strings.Split(s.DayWeek, ",") {
+			day, ok := ParseWeekday(name)
+			if !ok {
+				return rule, fmt.Errorf("%w: unknown day_week %q", ErrInvalidRule, s.DayWeek)
+			}
+			if !slices.Contains(rule.ByDay, day) {
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		}
+		slices.SortFunc(rule.ByDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+	}
+	if rule.Until == nil && rule.Count == 0 {
+		until := DateOnly(s.SchdDateEnd)
+		rule.Until = &until
+	}
+	return rule, nil
+}
+
+// ExDates возвращает даты, исключенные из серии через EXDATE
+func (s *Schedule) ExDates() ([]time.Time, error) {
+	if s.ExDate == nil {
+		return nil, nil
+	}
+	return ParseExDates(*s.ExDate)
+}
+
+// [RU] Occurrences возвращает даты занятий серии в диапазоне [from, to] без дат EXDATE.
+// Исключения отдельных занятий (ScheduleException) здесь не учитываются <--->
+// [ENG] Occurrences returns the series' occurrence dates within [from, to] without the EXDATE dates.
+// Single-occurrence exceptions (ScheduleException) are not applied here
+func (s *Schedule) Occurrences(from, to time.Time) ([]time.Time, error) {
+	rule, err := s.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+	exdates, err := s.ExDates()
+	if err != nil {
+		return nil, err
+	}
+
+	from = DateOnly(from)
+	var dates []time.Time
+	rule.Each(s.SchdDateStart, to, func(date time.Time) bool {
+		if !date.Before(from) && !containsDate(exdates, date) {
+			dates = append(dates, date)
+		}
+		return true
+	})
+	return dates, nil
+}
+
+// [RU] HasOccurrence сообщает, есть ли в серии занятие в дату date (с учетом EXDATE) <--->
+// [ENG] HasOccurrence reports whether the series has an occurrence on date (EXDATE applied)
+func (s *Schedule) HasOccurrence(date time.Time) (bool, error) {
+	dates, err := s.Occurrences(date, date)
+	if err != nil {
+		return false, err
+	}
+	return len(dates) > 0, nil
+}
+
+// containsDate ищет дату в списке без учета времени суток
+func containsDate(dates []time.Time, date time.Time) bool {
+	date = DateOnly(date)
+	for _, d := range dates {
+		if DateOnly(d).Equal(date) {
+			return true
+		}
+	}
+	return false
 }
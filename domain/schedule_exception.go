@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// ErrNoOccurrence возвращается, если в серии расписания нет занятия в указанную дату
+var ErrNoOccurrence = errors.New("no occurrence of the schedule on this date")
+
+// [RU] ScheduleException исключение из серии расписания для одного занятия: отмена или перенос
+// на другую дату и время. OccurrenceDate - дата занятия по правилу <--->
+// [ENG] ScheduleException is an exception to a schedule series for a single occurrence: a cancellation
+// or a move to another date and time. OccurrenceDate is the occurrence date according to the rule
+type ScheduleException struct {
+	ExceptionID    int        `json:"exception_id"`
+	ScheduleID     int        `json:"schedule_id" validate:"required"`
+	OccurrenceDate time.Time  `json:"occurrence_date" validate:"required"`
+	Cancelled      bool       `json:"cancelled"`
+	NewDate        *time.Time `json:"new_date,omitempty"`   // nil - дата не меняется
+	TimeBegin      *time.Time `json:"time_begin,omitempty"` // nil - время из правила
+	TimeEnd        *time.Time `json:"time_end,omitempty"`
+	Reason         *string    `json:"reason,omitempty" validate:"omitempty,max=255"`
+	Version        int        `json:"version"` // версия строки для оптимистичной блокировки
+}
+
+func (e *ScheduleException) GetID() int {
+	return e.ExceptionID
+}
+
+func (e *ScheduleException) SetID(id int) {
+	e.ExceptionID = id
+}
+
+func (e *ScheduleException) GetVersion() int {
+	return e.Version
+}
+
+func (e *ScheduleException) SetVersion(version int) {
+	e.Version = version
+}
+
+func (e *ScheduleException) Validate() error {
+	return validate.ValidateStruct(e)
+}
+
+// [RU] Occurrence одно занятие серии расписания, развернутое при чтении, с примененным исключением <--->
+// [ENG] Occurrence is a single occurrence of a schedule series, expanded on read, with its exception applied
+type Occurrence struct {
+	ScheduleID   int       `json:"schedule_id"`
+	LessonID     int       `json:"lesson_id"`
+	Date         time.Time `json:"date"`          // дата проведения (после переноса)
+	OriginalDate time.Time `json:"original_date"` // дата по правилу; по ней адресуется исключение
+	TimeBegin    time.Time `json:"time_begin"`
+	TimeEnd      time.Time `json:"time_end"`
+	Cancelled    bool      `json:"cancelled"`
+	Closed       bool      `json:"closed"`                 // отменено учебным календарем: праздник или каникулы
+	Swapped      bool      `json:"swapped"`                // перенесено календарем на день переноса
+	ExceptionID  *int      `json:"exception_id,omitempty"` // nil - занятие идет по правилу
+	Reason       *string   `json:"reason,omitempty"`
+}
+
+// Start возвращает дату и время начала занятия
+func (o *Occurrence) Start() time.Time {
+	return atClock(o.Date, o.TimeBegin)
+}
+
+// End возвращает дату и время окончания занятия
+func (o *Occurrence) End() time.Time {
+	return atClock(o.Date, o.TimeEnd)
+}
+
+// atClock соединяет дату date и время суток clock
+func atClock(date, clock time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+}
+
+// [RU] NewOccurrence строит занятие серии schedule в дату date и применяет исключение exception (может быть nil) <--->
+// [ENG] NewOccurrence builds the occurrence of schedule on date and applies exception (may be nil)
+func NewOccurrence(schedule *Schedule, date time.Time, exception *ScheduleException) Occurrence {
+	occurrence := Occurrence{
+		ScheduleID:   schedule.ScheduleID,
+		LessonID:     schedule.LessonID,
+		Date:         DateOnly(date),
+		OriginalDate: DateOnly(date),
+		TimeBegin:    schedule.TimeBegin,
+		TimeEnd:      schedule.TimeEnd,
+	}
+	if exception == nil {
+		return occurrence
+	}
+
+	id := exception.ExceptionID
+	occurrence.ExceptionID = &id
+	occurrence.Cancelled = exception.Cancelled
+	occurrence.Reason = exception.Reason
+	if exception.NewDate != nil {
+		occurrence.Date = DateOnly(*exception.NewDate)
+	}
+	if exception.TimeBegin != nil {
+		occurrence.TimeBegin = *exception.TimeBegin
+	}
+	if exception.TimeEnd != nil {
+		occurrence.TimeEnd = *exception.TimeEnd
+	}
+	return occurrence
+}
@@ -7,15 +7,21 @@ import (
 )
 
 type Student struct {
-	StudentID     int       `json:"student_id"`
-	UserID        *int      `json:"user_id,omitempty"`
-	Surname       string    `json:"surname" validate:"required,min=1,max=60"`
-	Name          string    `json:"name" validate:"required,min=1,max=45"`
-	FatherName    *string   `json:"father_name,omitempty" validate:"omitempty,max=55"`
-	Birthday      time.Time `json:"birthday" validate:"required,birthday_past"`
-	PhoneNumber   *string   `json:"phone_number,omitempty" validate:"omitempty,len=11"`
-	GroupID       int       `json:"group_id" validate:"required"`
-	MusprogrammID int       `json:"musprogramm_id" validate:"required"`
+	StudentID     int        `json:"student_id"`
+	UserID        *int       `json:"user_id,omitempty"`
+	Surname       string     `json:"surname" validate:"required,min=1,max=60"`
+	Name          string     `json:"name" validate:"required,min=1,max=45"`
+	FatherName    *string    `json:"father_name,omitempty" validate:"omitempty,max=55"`
+	Birthday      time.Time  `json:"birthday" validate:"required,birthday_past"`
+	PhoneNumber   *string    `json:"phone_number,omitempty" validate:"omitempty,len=11"`
+	GroupID       int        `json:"group_id" validate:"required"`
+	MusprogrammID int        `json:"musprogramm_id" validate:"required"`
+	Version       int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
+
+	// Связанные сущности: заполняются только по Filter.Preloads / ?include=
+	Group    *StudyGroup `json:"group,omitempty" db:"-" validate:"-"`
+	Programm *Programm   `json:"programm,omitempty" db:"-" validate:"-"`
 }
 
 func (s *Student) GetID() int {
@@ -26,6 +32,14 @@ func (s *Student) SetID(id int) {
 	s.StudentID = id
 }
 
+func (s *Student) GetVersion() int {
+	return s.Version
+}
+
+func (s *Student) SetVersion(version int) {
+	s.Version = version
+}
+
 func (s *Student) Validate() error {
 	return validate.ValidateStruct(s)
 }
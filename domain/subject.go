@@ -1,15 +1,19 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/SerMoskvin/validate"
 )
 
 // Subject представляет запись предмета
 type Subject struct {
-	SubjectID   int    `json:"subject_id"`
-	SubjectName string `json:"subject_name" validate:"required,min=1,max=60"`
-	SubjectType string `json:"subject_type" validate:"required,min=1,max=30"`
-	ShortDesc   string `json:"short_desc" validate:"required"`
+	SubjectID   int        `json:"subject_id"`
+	SubjectName string     `json:"subject_name" validate:"required,min=1,max=60"`
+	SubjectType string     `json:"subject_type" validate:"required,min=1,max=30"`
+	ShortDesc   string     `json:"short_desc" validate:"required"`
+	Version     int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (s *Subject) GetID() int {
@@ -20,6 +24,14 @@ func (s *Subject) SetID(id int) {
 	s.SubjectID = id
 }
 
+func (s *Subject) GetVersion() int {
+	return s.Version
+}
+
+func (s *Subject) SetVersion(version int) {
+	s.Version = version
+}
+
 func (s *Subject) Validate() error {
 	return validate.ValidateStruct(s)
 }
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Состояния замены
+const (
+	SubstitutionOpen     = "open"     // занятию нужна замена
+	SubstitutionAssigned = "assigned" // заменяющий преподаватель назначен
+)
+
+// ErrInvalidSubstitution возвращается для несогласованной замены: заменяющий не ведет предмет или совпадает с преподавателем занятия
+var ErrInvalidSubstitution = errors.New("invalid substitution")
+
+// [RU] Substitution замена преподавателя на одном занятии серии расписания. Занятие (Lesson) не меняется:
+// замена действует только на дату OccurrenceDate серии ScheduleID. Дата и время проведения запоминаются
+// при создании замены для отчетов <--->
+// [ENG] Substitution is a teacher substitution for a single occurrence of a schedule series. The lesson
+// is left intact: the substitution only applies to the OccurrenceDate of the ScheduleID series. The date and time
+// the occurrence is held are recorded when the substitution is created, for reports
+type Substitution struct {
+	SubstitutionID int        `json:"substitution_id"`
+	ScheduleID     int        `json:"schedule_id" validate:"required"`
+	LessonID       int        `json:"lesson_id" validate:"required"`
+	OccurrenceDate time.Time  `json:"occurrence_date" validate:"required"` // дата занятия по правилу
+	Date           time.Time  `json:"date" validate:"required"`            // дата проведения
+	TimeBegin      time.Time  `json:"time_begin"`
+	TimeEnd        time.Time  `json:"time_end"`
+	EmployeeID     int        `json:"employee_id" validate:"required"` // преподаватель занятия
+	SubstituteID   *int       `json:"substitute_id,omitempty"`         // nil - замена еще не найдена
+	Status         string     `json:"status" validate:"required,oneof=open assigned"`
+	Reason         *string    `json:"reason,omitempty" validate:"omitempty,max=255"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AssignedAt     *time.Time `json:"assigned_at,omitempty"`
+	Version        int        `json:"version"` // версия строки для оптимистичной блокировки
+}
+
+func (s *Substitution) GetID() int {
+	return s.SubstitutionID
+}
+
+func (s *Substitution) SetID(id int) {
+	s.SubstitutionID = id
+}
+
+func (s *Substitution) GetVersion() int {
+	return s.Version
+}
+
+func (s *Substitution) SetVersion(version int) {
+	s.Version = version
+}
+
+// Validate проверяет поля и согласованность состояния с заменяющим преподавателем
+func (s *Substitution) Validate() error {
+	if err := validate.ValidateStruct(s); err != nil {
+		return err
+	}
+	if (s.Status == SubstitutionAssigned) != (s.SubstituteID != nil) {
+		return fmt.Errorf("%w: substitute_id is set only for assigned substitutions", ErrInvalidSubstitution)
+	}
+	if s.SubstituteID != nil && *s.SubstituteID == s.EmployeeID {
+		return fmt.Errorf("%w: employee %d cannot substitute themselves", ErrInvalidSubstitution, s.EmployeeID)
+	}
+	return nil
+}
+
+// Minutes возвращает длительность занятия в минутах
+func (s *Substitution) Minutes() int {
+	return clockMinutes(s.TimeEnd) - clockMinutes(s.TimeBegin)
+}
+
+// [RU] SubstitutionReport замены преподавателя за период: занятия, которые он провел вместо других (Covered),
+// и его занятия, на которые понадобилась замена (Absent), с суммарной длительностью в минутах <--->
+// [ENG] SubstitutionReport is an employee's substitutions for a period: the occurrences they taught instead of others
+// (Covered) and their own occurrences that needed cover (Absent), with the total duration in minutes
+type SubstitutionReport struct {
+	EmployeeID     int             `json:"employee_id"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Covered        []*Substitution `json:"covered"`
+	CoveredMinutes int             `json:"covered_minutes"`
+	Absent         []*Substitution `json:"absent"`
+	AbsentMinutes  int             `json:"absent_minutes"`
+}
+
+// [RU] NewSubstitutionReport раскладывает замены периода по ролям преподавателя; открытые замены
+// в Covered не попадают <--->
+// [ENG] NewSubstitutionReport sorts the period's substitutions by the employee's role; open substitutions
+// are not Covered by anyone
+func NewSubstitutionReport(employeeID int, from, to time.Time, substitutions []*Substitution) *SubstitutionReport {
+	report := &SubstitutionReport{
+		EmployeeID: employeeID,
+		From:       DateOnly(from),
+		To:         DateOnly(to),
+		Covered:    []*Substitution{},
+		Absent:     []*Substitution{},
+	}
+	for _, substitution := range substitutions {
+		switch {
+		case substitution.SubstituteID != nil && *substitution.SubstituteID == employeeID:
+			report.Covered = append(report.Covered, substitution)
+			report.CoveredMinutes += substitution.Minutes()
+		case substitution.EmployeeID == employeeID:
+			report.Absent = append(report.Absent, substitution)
+			report.AbsentMinutes += substitution.Minutes()
+		}
+	}
+	return report
+}
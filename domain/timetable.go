@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Состояния черновика расписания
+const (
+	DraftProposed = "draft"   // предложение решателя, расписание не изменено
+	DraftApplied  = "applied" // записи расписания созданы
+)
+
+// Ограничения, которые решатель не смог выполнить для занятия
+const (
+	ConstraintQualification = "qualification" // преподаватель не ведет предмет (SubjectDistribution)
+	ConstraintProgramm      = "programm"      // предмета нет в программе (ProgrammDistribution)
+	ConstraintCapacity      = "capacity"      // ни одна аудитория не вмещает группу
+	ConstraintInstrument    = "instrument"    // ни в одной аудитории нет инструмента программы
+	ConstraintNoSlot        = "no_slot"       // не хватило свободного времени на все часы
+	ConstraintScheduled     = "scheduled"     // у занятия уже есть расписание в периоде
+)
+
+var (
+	// ErrInvalidTimetable возвращается для несогласованных параметров решателя
+	ErrInvalidTimetable = errors.New("invalid timetable parameters")
+	// ErrDraftApplied возвращается при повторном применении черновика
+	ErrDraftApplied = errors.New("timetable draft is already applied")
+)
+
+// [RU] TimetableSlot час занятия в недельном расписании, предложенном решателем <--->
+// [ENG] TimetableSlot is a lesson hour in the weekly timetable proposed by the solver
+type TimetableSlot struct {
+	LessonID   int       `json:"lesson_id"`
+	LessonName string    `json:"lesson_name"`
+	EmployeeID int       `json:"employee_id"`
+	AudienceID int       `json:"audience_id"` // аудитория занятия или выбранная решателем
+	GroupID    int       `json:"group_id"`
+	StudentID  *int      `json:"student_id,omitempty"`
+	DayWeek    string    `json:"day_week"`
+	TimeBegin  time.Time `json:"time_begin"`
+	TimeEnd    time.Time `json:"time_end"`
+}
+
+// [RU] UnsatisfiedConstraint ограничение, из-за которого часы занятия не попали в расписание <--->
+// [ENG] UnsatisfiedConstraint is a constraint that kept lesson hours out of the timetable
+type UnsatisfiedConstraint struct {
+	LessonID   int    `json:"lesson_id"`
+	LessonName string `json:"lesson_name"`
+	Constraint string `json:"constraint"`
+	Reason     string `json:"reason"`
+	Hours      int    `json:"hours"` // неразмещенных часов в неделю
+}
+
+// [RU] TimetableDraft черновик недельного расписания на период DateStart..DateEnd: предложение решателя
+// и невыполненные ограничения. Применение создает по записи расписания на каждый час <--->
+// [ENG] TimetableDraft is a weekly timetable draft for the DateStart..DateEnd period: the solver's proposal
+// and the unsatisfied constraints. Applying it creates a schedule entry per hour
+type TimetableDraft struct {
+	DraftID     int        `json:"draft_id"`
+	DateStart   time.Time  `json:"date_start" validate:"required"`
+	DateEnd     time.Time  `json:"date_end" validate:"required"`
+	Status      string     `json:"status" validate:"required,oneof=draft applied"`
+	Slots       string     `json:"slots"`       // JSON []TimetableSlot
+	Unsatisfied string     `json:"unsatisfied"` // JSON []UnsatisfiedConstraint
+	CreatedAt   time.Time  `json:"created_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+	Version     int        `json:"version"` // версия строки для оптимистичной блокировки
+}
+
+func (d *TimetableDraft) GetID() int {
+	return d.DraftID
+}
+
+func (d *TimetableDraft) SetID(id int) {
+	d.DraftID = id
+}
+
+func (d *TimetableDraft) GetVersion() int {
+	return d.Version
+}
+
+func (d *TimetableDraft) SetVersion(version int) {
+	d.Version = version
+}
+
+func (d *TimetableDraft) Validate() error {
+	if err := validate.ValidateStruct(d); err != nil {
+		return err
+	}
+	if d.DateEnd.Before(d.DateStart) {
+		return fmt.Errorf("%w: date_end is before date_start", ErrInvalidTimetable)
+	}
+	return nil
+}
+
+// SetProposal сохраняет в черновике предложение решателя
+func (d *TimetableDraft) SetProposal(proposal TimetableProposal) error {
+	slots, err := json.Marshal(nonNil(proposal.Slots))
+	if err != nil {
+		return err
+	}
+	unsatisfied, err := json.Marshal(nonNil(proposal.Unsatisfied))
+	if err != nil {
+		return err
+	}
+	d.Slots, d.Unsatisfied = string(slots), string(unsatisfied)
+	return nil
+}
+
+// Proposal возвращает сохраненное в черновике предложение решателя
+func (d *TimetableDraft) Proposal() (TimetableProposal, error) {
+	var proposal TimetableProposal
+	if d.Slots != "" {
+		if err := json.Unmarshal([]byte(d.Slots), &proposal.Slots); err != nil {
+			return proposal, fmt.Errorf("draft %d slots: %w", d.DraftID, err)
+		}
+	}
+	if d.Unsatisfied != "" {
+		if err := json.Unmarshal([]byte(d.Unsatisfied), &proposal.Unsatisfied); err != nil {
+			return proposal, fmt.Errorf("draft %d constraints: %w", d.DraftID, err)
+		}
+	}
+	return proposal, nil
+}
+
+// nonNil заменяет nil пустым списком, чтобы в JSON был [] вместо null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
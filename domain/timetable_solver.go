@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// resourceGroupMember занятость студентов группы на индивидуальных занятиях: мешает групповым занятиям группы
+const resourceGroupMember = "group_member"
+
+// [RU] TimetableProblem задача решателя: занятия с числом часов в неделю, аудитории, учебная сетка
+// и занятия, которые уже стоят в расписании периода <--->
+// [ENG] TimetableProblem is the solver's input: lessons with their weekly hours, rooms, the school day grid
+// and the lessons already scheduled in the period
+type TimetableProblem struct {
+	Days         []time.Weekday // учебные дни недели
+	DayBegin     time.Time      // начало учебного дня
+	DayEnd       time.Time      // конец учебного дня: последний час заканчивается не позже
+	SlotMinutes  int            // длительность академического часа
+	BreakMinutes int            // перемена между часами
+	Demands      []TimetableDemand
+	Rooms        []TimetableRoom
+	Busy         []TimetableBusy
+
+	// Available сообщает, может ли преподаватель вести занятие в это время; nil - весь учебный день
+	Available func(employeeID int, day time.Weekday, begin, end time.Time) bool
+}
+
+// [RU] TimetableDemand занятие, которое нужно разместить <--->
+// [ENG] TimetableDemand is a lesson to be placed
+type TimetableDemand struct {
+	Lesson       *Lesson
+	Hours        int    // академических часов в неделю
+	Size         int    // число студентов: размер группы или 1 для индивидуального занятия
+	StudentGroup int    // группа студента индивидуального занятия
+	Instrument   string // инструмент программы индивидуального занятия; "" - не нужен
+}
+
+// [RU] TimetableRoom аудитория с вместимостью и инструментами <--->
+// [ENG] TimetableRoom is a room with its capacity and instruments
+type TimetableRoom struct {
+	AudienceID  int
+	Capacity    int
+	Instruments []string // названия и типы инструментов аудитории
+}
+
+// Has сообщает, есть ли в аудитории инструмент: название или тип содержит его без учета регистра
+func (r TimetableRoom) Has(instrument string) bool {
+	instrument = strings.ToLower(strings.TrimSpace(instrument))
+	for _, name := range r.Instruments {
+		if strings.Contains(strings.ToLower(name), instrument) {
+			return true
+		}
+	}
+	return false
+}
+
+// [RU] TimetableBusy занятие, которое уже стоит в расписании: его ресурсы заняты <--->
+// [ENG] TimetableBusy is an already scheduled lesson: its resources are taken
+type TimetableBusy struct {
+	Lesson       *Lesson
+	StudentGroup int // группа студента индивидуального занятия
+	Day          time.Weekday
+	TimeBegin    time.Time
+	TimeEnd      time.Time
+}
+
+// [RU] TimetableProposal предложенное недельное расписание и ограничения, которые не удалось выполнить <--->
+// [ENG] TimetableProposal is the proposed weekly timetable and the constraints that could not be met
+type TimetableProposal struct {
+	Slots       []TimetableSlot         `json:"slots"`
+	Unsatisfied []UnsatisfiedConstraint `json:"unsatisfied"`
+}
+
+// [RU] SolveTimetable размещает часы занятий в недельной сетке без конфликтов по преподавателю, аудитории,
+// группе и студенту. Сначала размещаются занятия с наименьшим выбором аудиторий; каждый час ставится
+// в свободное время с наименьшей ценой: часы одного занятия расходятся по разным дням, нагрузка группы
+// выравнивается по неделе, при прочих равных - раньше. Занятие без аудитории получает одну аудиторию на все часы <--->
+// [ENG] SolveTimetable places lesson hours into the weekly grid without teacher, room, group or student clashes.
+// Lessons with the fewest room options go first; each hour takes the cheapest free time: hours of one lesson
+// spread over different days, the group's load is balanced across the week, earlier wins ties.
+// A lesson without a room gets a single room for all of its hours
+func SolveTimetable(p TimetableProblem) TimetableProposal {
+	var proposal TimetableProposal
+	grid := timetableGrid{}
+	for _, busy := range p.Busy {
+		room := 0
+		if busy.Lesson.AudienceID != nil {
+			room = *busy.Lesson.AudienceID
+		}
+		holds, _ := lessonResources(busy.Lesson, room, busy.StudentGroup)
+		grid.take(holds, busy.Day, clockMinutes(busy.TimeBegin), clockMinutes(busy.TimeEnd))
+	}
+
+	type placement struct {
+		TimetableDemand
+		rooms []TimetableRoom
+	}
+	var queue []placement
+	for _, demand := range p.Demands {
+		if demand.Hours <= 0 {
+			continue
+		}
+		rooms, constraint, reason := demandRooms(demand, p.Rooms)
+		if len(rooms) == 0 {
+			proposal.Unsatisfied = append(proposal.Unsatisfied, unsatisfied(demand.Lesson, constraint, reason, demand.Hours))
+			continue
+		}
+		queue = append(queue, placement{demand, rooms})
+	}
+	slices.SortStableFunc(queue, func(a, b placement) int {
+		if len(a.rooms) != len(b.rooms) {
+			return len(a.rooms) - len(b.rooms)
+		}
+		if a.Hours != b.Hours {
+			return b.Hours - a.Hours
+		}
+		return a.Lesson.LessonID - b.Lesson.LessonID
+	})
+
+	var starts []int
+	begin, end := clockMinutes(p.DayBegin), clockMinutes(p.DayEnd)
+	for start := begin; start+p.SlotMinutes <= end; start += p.SlotMinutes + p.BreakMinutes {
+		starts = append(starts, start)
+	}
+	groupLoad := map[int]map[time.Weekday]int{} // часов группы по дням
+
+	for _, item := range queue {
+		lesson := item.Lesson
+		group := lesson.GroupID
+		if lesson.StudentID != nil {
+			group = item.StudentGroup
+		}
+		if groupLoad[group] == nil {
+			groupLoad[group] = map[time.Weekday]int{}
+		}
+		lessonDays := map[time.Weekday]int{}
+
+		for hour := 0; hour < item.Hours; hour++ {
+			found := false
+			var bestDay time.Weekday
+			var bestStart, bestScore int
+			var bestRoom TimetableRoom
+			for _, day := range p.Days {
+				for i, start := range starts {
+					if p.Available != nil && !p.Available(lesson.EmployeeID, day, clockTime(start), clockTime(start+p.SlotMinutes)) {
+						continue
+					}
+					score := lessonDays[day]*1000 + groupLoad[group][day]*10 + i
+					if found && score >= bestScore {
+						continue
+					}
+					for _, room := range item.rooms {
+						_, blockers := lessonResources(lesson, room.AudienceID, item.StudentGroup)
+						if grid.free(blockers, day, start, start+p.SlotMinutes) {
+							found, bestDay, bestStart, bestScore, bestRoom = true, day, start, score, room
+							break
+						}
+					}
+				}
+			}
+			if !found {
+				proposal.Unsatisfied = append(proposal.Unsatisfied, unsatisfied(lesson, ConstraintNoSlot,
+					fmt.Sprintf("no free time for %d of %d hours a week", item.Hours-hour, item.Hours), item.Hours-hour))
+				break
+			}
+
+			holds, _ := lessonResources(lesson, bestRoom.AudienceID, item.StudentGroup)
+			grid.take(holds, bestDay, bestStart, bestStart+p.SlotMinutes)
+			lessonDays[bestDay]++
+			groupLoad[group][bestDay]++
+			item.rooms = []TimetableRoom{bestRoom}
+
+			proposal.Slots = append(proposal.Slots, TimetableSlot{
+				LessonID:   lesson.LessonID,
+				LessonName: lesson.LessonName,
+				EmployeeID: lesson.EmployeeID,
+				AudienceID: bestRoom.AudienceID,
+				GroupID:    lesson.GroupID,
+				StudentID:  lesson.StudentID,
+				DayWeek:    WeekdayName(bestDay),
+				TimeBegin:  clockTime(bestStart),
+				TimeEnd:    clockTime(bestStart + p.SlotMinutes),
+			})
+		}
+	}
+
+	slices.SortFunc(proposal.Slots, func(a, b TimetableSlot) int {
+		dayA, _ := ParseWeekday(a.DayWeek)
+		dayB, _ := ParseWeekday(b.DayWeek)
+		if dayA != dayB {
+			return mondayIndex(dayA) - mondayIndex(dayB)
+		}
+		if c := a.TimeBegin.Compare(b.TimeBegin); c != 0 {
+			return c
+		}
+		return a.LessonID - b.LessonID
+	})
+	slices.SortStableFunc(proposal.Unsatisfied, func(a, b UnsatisfiedConstraint) int { return a.LessonID - b.LessonID })
+	return proposal
+}
+
+// demandRooms подбирает аудитории, которые вмещают занятие и в которых есть его инструмент.
+// У занятия с аудиторией выбор только из нее
+func demandRooms(demand TimetableDemand, rooms []TimetableRoom) ([]TimetableRoom, string, string) {
+	if demand.Lesson.AudienceID != nil {
+		rooms = slices.DeleteFunc(slices.Clone(rooms), func(r TimetableRoom) bool { return r.AudienceID != *demand.Lesson.AudienceID })
+	}
+
+	var fitting, equipped []TimetableRoom
+	for _, room := range rooms {
+		if room.Capacity < demand.Size {
+			continue
+		}
+		fitting = append(fitting, room)
+		if demand.Instrument == "" || room.Has(demand.Instrument) {
+			equipped = append(equipped, room)
+		}
+	}
+	switch {
+	case len(fitting) == 0:
+		return nil, ConstraintCapacity, fmt.Sprintf("no audience holds %d students", demand.Size)
+	case len(equipped) == 0:
+		return nil, ConstraintInstrument, fmt.Sprintf("no audience has instrument %q", demand.Instrument)
+	}
+	return equipped, "", ""
+}
+
+// [RU] lessonResources возвращает ресурсы, которые занятие занимает (holds), и ресурсы, занятость которых
+// ему мешает (blockers). Индивидуальное занятие занимает студента и мешает групповым занятиям его группы <--->
+// [ENG] lessonResources returns the resources the lesson takes (holds) and the resources whose use blocks it
+// (blockers). An individual lesson takes the student and blocks the group lessons of their group
+func lessonResources(lesson *Lesson, audienceID, studentGroup int) (holds, blockers []Resource) {
+	holds = []Resource{{ResourceEmployee, lesson.EmployeeID}}
+	if audienceID != 0 {
+		holds = append(holds, Resource{ResourceAudience, audienceID})
+	}
+	blockers = slices.Clone(holds)
+	if lesson.StudentID == nil {
+		holds = append(holds, Resource{ResourceGroup, lesson.GroupID})
+		blockers = append(blockers, Resource{ResourceGroup, lesson.GroupID}, Resource{resourceGroupMember, lesson.GroupID})
+		return holds, blockers
+	}
+	if studentGroup == 0 {
+		studentGroup = lesson.GroupID
+	}
+	holds = append(holds, Resource{ResourceStudent, *lesson.StudentID}, Resource{resourceGroupMember, studentGroup})
+	blockers = append(blockers, Resource{ResourceStudent, *lesson.StudentID}, Resource{ResourceGroup, studentGroup})
+	return holds, blockers
+}
+
+// timetableGrid занятые интервалы ресурсов по дням недели, в минутах от начала суток
+type timetableGrid map[Resource]map[time.Weekday][][2]int
+
+func (g timetableGrid) free(resources []Resource, day time.Weekday, begin, end int) bool {
+	for _, resource := range resources {
+		for _, taken := range g[resource][day] {
+			if begin < taken[1] && taken[0] < end {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (g timetableGrid) take(resources []Resource, day time.Weekday, begin, end int) {
+	for _, resource := range resources {
+		if g[resource] == nil {
+			g[resource] = map[time.Weekday][][2]int{}
+		}
+		g[resource][day] = append(g[resource][day], [2]int{begin, end})
+	}
+}
+
+// clockTime время суток по минутам от его начала, в виде ParseTimeHM
+func clockTime(minutes int) time.Time {
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC)
+}
+
+func unsatisfied(lesson *Lesson, constraint, reason string, hours int) UnsatisfiedConstraint {
+	return UnsatisfiedConstraint{
+		LessonID:   lesson.LessonID,
+		LessonName: lesson.LessonName,
+		Constraint: constraint,
+		Reason:     reason,
+		Hours:      hours,
+	}
+}
@@ -8,15 +8,17 @@ import (
 
 // User представляет запись пользователя
 type User struct {
-	UserID           int       `json:"user_id"`
-	Login            string    `json:"login" validate:"required,min=1,max=250"`
-	Password         string    `json:"password" validate:"required"`
-	Role             string    `json:"role" validate:"required,min=1,max=50"`
-	Surname          string    `json:"surname" validate:"required,min=1,max=100"`
-	Name             string    `json:"name" validate:"required,min=1,max=100"`
-	RegistrationDate time.Time `json:"registration_date" validate:"required"`
-	Email            string    `json:"email" validate:"required,email"`
-	Image            []byte    `json:"image,omitempty"`
+	UserID           int        `json:"user_id"`
+	Login            string     `json:"login" validate:"required,min=1,max=250"`
+	Password         string     `json:"password" validate:"required"`
+	Role             string     `json:"role" validate:"required,min=1,max=50"`
+	Surname          string     `json:"surname" validate:"required,min=1,max=100"`
+	Name             string     `json:"name" validate:"required,min=1,max=100"`
+	RegistrationDate time.Time  `json:"registration_date" validate:"required"`
+	Email            string     `json:"email" validate:"required,email"`
+	Image            []byte     `json:"image,omitempty"`
+	Version          int        `json:"version"`              // версия строки для оптимистичной блокировки
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"` // момент мягкого удаления; nil - строка активна
 }
 
 func (u *User) GetID() int {
@@ -27,6 +29,14 @@ func (u *User) SetID(id int) {
 	u.UserID = id
 }
 
+func (u *User) GetVersion() int {
+	return u.Version
+}
+
+func (u *User) SetVersion(version int) {
+	u.Version = version
+}
+
 func (u *User) Validate() error {
 	return validate.ValidateStruct(u)
 }
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SerMoskvin/validate"
+)
+
+// Статусы записи листа ожидания
+const (
+	WaitlistWaiting   = "waiting"   // ждет свободного места
+	WaitlistOffered   = "offered"   // место предложено и удерживается до ответа студента
+	WaitlistEnrolled  = "enrolled"  // студент зачислен в группу
+	WaitlistWithdrawn = "withdrawn" // запись отозвана (студентом, администратором или при удалении студента)
+)
+
+// ErrWaitlistConflict возвращается, если действие с листом ожидания невозможно в текущем состоянии записи
+var ErrWaitlistConflict = errors.New("waitlist conflict")
+
+// WaitlistEntry запись листа ожидания группы; завершенные записи остаются как история
+type WaitlistEntry struct {
+	WaitlistID int        `json:"waitlist_id"`
+	GroupID    int        `json:"group_id" validate:"required"`
+	StudentID  int        `json:"student_id" validate:"required"`
+	Status     string     `json:"status" validate:"required,oneof=waiting offered enrolled withdrawn"`
+	EnlistedAt time.Time  `json:"enlisted_at"` // порядок очереди: enlisted_at, затем waitlist_id
+	OfferedAt  *time.Time `json:"offered_at,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"` // момент зачисления или отзыва
+	Version    int        `json:"version"`               // версия строки для оптимистичной блокировки
+
+	// Место в очереди группы (с 1); вычисляется для ожидающих записей, в БД не хранится
+	Position int `json:"position,omitempty" db:"-" validate:"-"`
+}
+
+// Active сообщает, что запись еще в очереди: ждет места или место ей предложено
+func (w *WaitlistEntry) Active() bool {
+	return w.Status == WaitlistWaiting || w.Status == WaitlistOffered
+}
+
+func (w *WaitlistEntry) GetID() int {
+	return w.WaitlistID
+}
+
+func (w *WaitlistEntry) SetID(id int) {
+	w.WaitlistID = id
+}
+
+func (w *WaitlistEntry) GetVersion() int {
+	return w.Version
+}
+
+func (w *WaitlistEntry) SetVersion(version int) {
+	w.Version = version
+}
+
+func (w *WaitlistEntry) Validate() error {
+	return validate.ValidateStruct(w)
+}
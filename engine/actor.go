@@ -0,0 +1,22 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/SerMoskvin/access"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// [RU] ActorID возвращает ID пользователя из JWT claims контекста; false - запрос без аутентификации <--->
+// [ENG] ActorID returns the user ID from the JWT claims in the context; false means an unauthenticated request
+func ActorID(ctx context.Context) (int, bool) {
+	claims, ok := ctx.Value(access.UserClaimsKey).(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(userID), true
+}
@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+)
+
+// auditSkipped служебные колонки, изменения которых не попадают в журнал
+var auditSkipped = map[string]bool{db.VersionColumn: true, db.DeletedAtColumn: true}
+
+// auditMasked колонки, значения которых в журнал не пишутся: видно только факт изменения
+var auditMasked = map[string]bool{"password": true}
+
+// [RU] AuditTrail журнал изменений сущностей (таблица audit_log, миграция 0005_audit) <--->
+// [ENG] AuditTrail is the entity change log (audit_log table, migration 0005_audit)
+type AuditTrail struct {
+	repo db.Repository[domain.AuditEntry, int]
+	db   TxProvider // транзакции для изменений, сделанных вне ExecuteInTx
+}
+
+// [RU] NewAuditTrail создает журнал изменений поверх репозитория audit_log <--->
+// [ENG] NewAuditTrail creates the change log on top of the audit_log repository
+func NewAuditTrail(repo db.Repository[domain.AuditEntry, int], txProvider TxProvider) *AuditTrail {
+	return &AuditTrail{repo: repo, db: txProvider}
+}
+
+// [RU] EnableAudit включает журнал изменений: Create/Update/Delete/Restore/Purge через Repo менеджера,
+// в том числе внутри ExecuteInTx, пишут запись журнала в той же транзакции. entity - имя сущности в журнале <--->
+// [ENG] EnableAudit turns on the change log: Create/Update/Delete/Restore/Purge through the manager's Repo,
+// including inside ExecuteInTx, write a log entry in the same transaction. entity is the entity name in the log
+func (m *BaseManager[ID, T, PT]) EnableAudit(trail *AuditTrail, entity string) {
+	m.Repo = &auditedRepository[T, ID]{Repository: m.Repo, trail: trail, entity: entity}
+}
+
+// auditedRepository декоратор репозитория, который записывает изменения в журнал
+type auditedRepository[T any, ID comparable] struct {
+	db.Repository[T, ID]
+	trail  *AuditTrail
+	entity string
+	log    db.Repository[domain.AuditEntry, int] // журнал в транзакции из WithTx; nil - вне транзакции
+}
+
+func (r *auditedRepository[T, ID]) WithTx(tx *sql.Tx) db.Repository[T, ID] {
+	return &auditedRepository[T, ID]{
+		Repository: r.Repository.WithTx(tx),
+		trail:      r.trail,
+		entity:     r.entity,
+		log:        r.trail.repo.WithTx(tx),
+	}
+}
+
+// write выполняет изменение и запись журнала в одной транзакции; вне транзакции открывает собственную
+func (r *auditedRepository[T, ID]) write(ctx context.Context, op func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error) error {
+	if r.log != nil {
+		return op(r.Repository, r.log)
+	}
+
+	return RunInTx(ctx, r.trail.db, func(tx *sql.Tx) error {
+		return op(r.Repository.WithTx(tx), r.trail.repo.WithTx(tx))
+	})
+}
+
+func (r *auditedRepository[T, ID]) Create(ctx context.Context, entity *T) error {
+	return r.write(ctx, func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error {
+		if err := repo.Create(ctx, entity); err != nil {
+			return err
+		}
+		return r.record(ctx, log, idOf[T, ID](entity), domain.AuditCreate, nil, entity)
+	})
+}
+
+// Update сохраняет в журнал состояние строки до изменения; отсутствующая строка в журнал не попадает
+func (r *auditedRepository[T, ID]) Update(ctx context.Context, entity *T) error {
+	return r.write(ctx, func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error {
+		id := idOf[T, ID](entity)
+		before, err := repo.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.Update(ctx, entity)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := repo.Update(ctx, entity); err != nil {
+			return err
+		}
+		return r.record(ctx, log, id, domain.AuditUpdate, before, entity)
+	})
+}
+
+func (r *auditedRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	return r.write(ctx, func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error {
+		before, err := repo.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.Delete(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.record(ctx, log, id, domain.AuditDelete, before, nil)
+	})
+}
+
+func (r *auditedRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	return r.write(ctx, func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error {
+		if err := repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		after, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.record(ctx, log, id, domain.AuditRestore, nil, after)
+	})
+}
+
+func (r *auditedRepository[T, ID]) Purge(ctx context.Context, id ID) error {
+	return r.write(ctx, func(repo db.Repository[T, ID], log db.Repository[domain.AuditEntry, int]) error {
+		if err := repo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return r.record(ctx, log, id, domain.AuditPurge, nil, nil)
+	})
+}
+
+// record пишет запись журнала от имени пользователя из JWT claims контекста
+func (r *auditedRepository[T, ID]) record(ctx context.Context, log db.Repository[domain.AuditEntry, int], id ID, action string, before, after *T) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("audit diff failed: %w", err)
+	}
+
+	entry := &domain.AuditEntry{
+		Entity:    r.entity,
+		EntityID:  fmt.Sprint(id),
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+	if userID, ok := ActorID(ctx); ok {
+		entry.UserID = &userID
+	}
+	if err := log.Create(ctx, entry); err != nil {
+		return fmt.Errorf("audit record failed: %w", err)
+	}
+	return nil
+}
+
+// [RU] auditDiff строит JSON {"before": {...}, "after": {...}}: при изменении - только изменившиеся колонки,
+// при создании и удалении - вся строка с одной стороны <--->
+// [ENG] auditDiff builds JSON {"before": {...}, "after": {...}}: on update only the changed columns,
+// on create and delete the whole row on one side
+func auditDiff[T any](before, after *T) (string, error) {
+	rows := make([]map[string]interface{}, 2)
+	for i, entity := range []*T{before, after} {
+		if entity == nil {
+			continue
+		}
+		row, err := db.StructToMap(entity)
+		if err != nil {
+			return "", err
+		}
+		rows[i] = row
+	}
+
+	diff := map[string]map[string]interface{}{}
+	for i, side := range []string{"before", "after"} {
+		if rows[i] != nil {
+			diff[side] = map[string]interface{}{}
+		}
+	}
+
+	columns := map[string]bool{}
+	for _, row := range rows {
+		for column := range row {
+			columns[column] = true
+		}
+	}
+	for column := range columns {
+		if auditSkipped[column] {
+			continue
+		}
+		if rows[0] != nil && rows[1] != nil {
+			changed, err := auditChanged(rows[0][column], rows[1][column])
+			if err != nil {
+				return "", err
+			}
+			if !changed {
+				continue
+			}
+		}
+		for i, side := range []string{"before", "after"} {
+			if rows[i] != nil {
+				diff[side][column] = auditValue(column, rows[i][column])
+			}
+		}
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// auditChanged сравнивает значения колонки через их JSON-представление
+func auditChanged(before, after interface{}) (bool, error) {
+	a, err := json.Marshal(before)
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(after)
+	if err != nil {
+		return false, err
+	}
+	return string(a) != string(b), nil
+}
+
+// auditValue значение колонки для журнала: секреты маскируются, двоичные данные заменяются размером
+func auditValue(column string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if auditMasked[column] {
+		return "***"
+	}
+	if b, ok := value.([]byte); ok {
+		return fmt.Sprintf("<%d bytes>", len(b))
+	}
+	return value
+}
@@ -30,6 +30,8 @@ type BaseManager[ID comparable, T any, PT interface {
 	Repo      db.Repository[T, ID]
 	Logger    *logger.LevelLogger
 	txTimeout time.Duration
+	relations map[string]Relation[T] // связи для Filter.Preloads (см. RegisterRelation)
+	events    *EventBus[T]           // события жизненного цикла (см. EnableEvents)
 }
 
 // Конструктор менеджера
@@ -94,6 +96,36 @@ func (m *BaseManager[ID, T, PT]) Delete(ctx context.Context, id ID) error {
 	return nil
 }
 
+// [RU] Restore возвращает сущность из корзины (см. Delete для сущностей с deleted_at) <--->
+// [ENG] Restore brings an entity back from the trash (see Delete for entities with deleted_at)
+func (m *BaseManager[ID, T, PT]) Restore(ctx context.Context, id ID) (PT, error) {
+	var zeroID ID
+	if id == zeroID {
+		return nil, errors.New("ID is required")
+	}
+
+	if err := m.Repo.Restore(ctx, id); err != nil {
+		m.Logger.Error("Restore failed", logger.Error(err), logger.Any("id", id))
+		return nil, fmt.Errorf("restore failed: %w", err)
+	}
+	return m.GetByID(ctx, id)
+}
+
+// [RU] Purge окончательно удаляет сущность из корзины <--->
+// [ENG] Purge removes an entity from the trash for good
+func (m *BaseManager[ID, T, PT]) Purge(ctx context.Context, id ID) error {
+	var zeroID ID
+	if id == zeroID {
+		return errors.New("ID is required")
+	}
+
+	if err := m.Repo.Purge(ctx, id); err != nil {
+		m.Logger.Error("Purge failed", logger.Error(err), logger.Any("id", id))
+		return fmt.Errorf("purge failed: %w", err)
+	}
+	return nil
+}
+
 func (m *BaseManager[ID, T, PT]) GetByID(ctx context.Context, id ID) (PT, error) {
 	var zeroID ID
 	if id == zeroID {
@@ -125,11 +157,19 @@ func (m *BaseManager[ID, T, PT]) GetByIDs(ctx context.Context, ids []ID) ([]*T,
 }
 
 func (m *BaseManager[ID, T, PT]) List(ctx context.Context, filter db.Filter) ([]*T, error) {
+	if err := m.checkPreloads(filter.Preloads); err != nil {
+		return nil, err
+	}
+
 	entities, err := m.Repo.List(ctx, filter)
 	if err != nil {
 		m.Logger.Error("List failed", logger.Field{Key: "error", Value: err})
 		return nil, fmt.Errorf("list failed: %w", err)
 	}
+
+	if err := m.Preload(ctx, entities, filter.Preloads); err != nil {
+		return nil, err
+	}
 	return entities, nil
 }
 
@@ -167,17 +207,17 @@ func (m *BaseManager[ID, T, PT]) ExecuteInTx(
 
 	defer func() {
 		if p := recover(); p != nil {
-			_ = tx.Rollback()
+			_ = RollbackTx(tx)
 			panic(p)
 		}
 	}()
 
 	if err := ops(m.Repo.WithTx(tx)); err != nil {
-		_ = tx.Rollback()
+		_ = RollbackTx(tx)
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := CommitTx(tx); err != nil {
 		m.Logger.Error("Commit failed", logger.Error(err))
 		return fmt.Errorf("commit failed: %w", err)
 	}
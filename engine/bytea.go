@@ -22,7 +22,7 @@ func DecodeByteaToImage(data []byte, outputPath string) error {
 }
 
 var DefaultImage []byte
-var defaultImagePath = "C:/Users/Joker/Desktop/GO_Music/GO_Music/static/WhereMyFoto.jpg"
+var defaultImagePath = "static/WhereMyFoto.jpg"
 
 func InitDefaultImage() {
 	var err error
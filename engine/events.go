@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"GO_Music/db"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// [RU] EventType тип события жизненного цикла сущности <--->
+// [ENG] EventType is the type of an entity lifecycle event
+type EventType string
+
+const (
+	BeforeCreate EventType = "before_create"
+	AfterCreate  EventType = "after_create"
+	BeforeUpdate EventType = "before_update"
+	AfterUpdate  EventType = "after_update"
+	BeforeDelete EventType = "before_delete"
+	AfterDelete  EventType = "after_delete"
+	AfterRestore EventType = "after_restore" // строка возвращена из корзины (см. Repository.Restore)
+)
+
+// ErrVetoed возвращается из Create/Update/Delete, если синхронный обработчик отклонил операцию
+var ErrVetoed = errors.New("operation vetoed")
+
+// [RU] Event событие жизненного цикла: Old - строка до изменения (nil для Create и Restore),
+// New - после (nil для Delete). Tx - транзакция операции, доступна только синхронным обработчикам <--->
+// [ENG] Event is a lifecycle event: Old is the row before the change (nil for Create and Restore),
+// New is the row after it (nil for Delete). Tx is the operation's transaction, given to synchronous handlers only
+type Event[T any] struct {
+	Type EventType
+	Old  *T
+	New  *T
+	Tx   *sql.Tx
+}
+
+// [RU] Handler синхронный обработчик: выполняется в транзакции операции, ошибка отменяет операцию <--->
+// [ENG] Handler is a synchronous handler: it runs inside the operation's transaction, an error vetoes the operation
+type Handler[T any] func(ctx context.Context, event Event[T]) error
+
+// [RU] AsyncHandler асинхронный обработчик: выполняется в отдельной горутине после фиксации транзакции <--->
+// [ENG] AsyncHandler is an asynchronous handler: it runs in its own goroutine after the transaction commits
+type AsyncHandler[T any] func(ctx context.Context, event Event[T])
+
+// [RU] EventBus шина событий жизненного цикла одной сущности (см. BaseManager.EnableEvents) <--->
+// [ENG] EventBus is the lifecycle event bus of one entity (see BaseManager.EnableEvents)
+type EventBus[T any] struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]Handler[T]
+	async    map[EventType][]AsyncHandler[T]
+	db       TxProvider // транзакции для изменений, сделанных вне ExecuteInTx
+	logger   *logger.LevelLogger
+	wg       sync.WaitGroup
+}
+
+// [RU] NewEventBus создает шину событий; txProvider открывает транзакцию для изменений вне ExecuteInTx <--->
+// [ENG] NewEventBus creates an event bus; txProvider opens a transaction for changes made outside ExecuteInTx
+func NewEventBus[T any](txProvider TxProvider, logger *logger.LevelLogger) *EventBus[T] {
+	if logger == nil {
+		panic("logger is required")
+	}
+	return &EventBus[T]{
+		handlers: map[EventType][]Handler[T]{},
+		async:    map[EventType][]AsyncHandler[T]{},
+		db:       txProvider,
+		logger:   logger,
+	}
+}
+
+// [RU] Subscribe подписывает синхронный обработчик; обработчики вызываются в порядке подписки <--->
+// [ENG] Subscribe registers a synchronous handler; handlers are called in subscription order
+func (b *EventBus[T]) Subscribe(eventType EventType, handler Handler[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// [RU] SubscribeAsync подписывает асинхронный обработчик; при откате транзакции он не вызывается <--->
+// [ENG] SubscribeAsync registers an asynchronous handler; it is not called if the transaction rolls back
+func (b *EventBus[T]) SubscribeAsync(eventType EventType, handler AsyncHandler[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.async[eventType] = append(b.async[eventType], handler)
+}
+
+// [RU] Wait ждет завершения запущенных асинхронных обработчиков (остановка сервера, тесты) <--->
+// [ENG] Wait blocks until the running asynchronous handlers finish (server shutdown, tests)
+func (b *EventBus[T]) Wait() {
+	b.wg.Wait()
+}
+
+// subscribed сообщает, есть ли обработчики хотя бы одного из событий
+func (b *EventBus[T]) subscribed(types ...EventType) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, t := range types {
+		if len(b.handlers[t]) > 0 || len(b.async[t]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// publish вызывает синхронные обработчики и откладывает асинхронные до фиксации tx
+func (b *EventBus[T]) publish(ctx context.Context, tx *sql.Tx, event Event[T]) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	async := b.async[event.Type]
+	b.mu.RUnlock()
+
+	event.Tx = tx
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrVetoed, event.Type, err)
+		}
+	}
+
+	if len(async) == 0 {
+		return nil
+	}
+	// Асинхронные обработчики получают копии: вызывающий код может изменить сущность после операции
+	event.Tx, event.Old, event.New = nil, snapshot(event.Old), snapshot(event.New)
+	// Запрос может завершиться раньше обработчиков: отмена его контекста на них не распространяется
+	detached := context.WithoutCancel(ctx)
+	AfterCommit(tx, func() {
+		for _, handler := range async {
+			b.wg.Add(1)
+			go b.dispatch(detached, handler, event)
+		}
+	})
+	return nil
+}
+
+// snapshot возвращает копию сущности; nil остается nil
+func snapshot[T any](entity *T) *T {
+	if entity == nil {
+		return nil
+	}
+	copied := *entity
+	return &copied
+}
+
+// dispatch выполняет асинхронный обработчик; паника обработчика только логируется
+func (b *EventBus[T]) dispatch(ctx context.Context, handler AsyncHandler[T], event Event[T]) {
+	defer b.wg.Done()
+	defer func() {
+		if p := recover(); p != nil {
+			b.logger.Error("Async event handler panicked",
+				logger.Field{Key: "event", Value: event.Type},
+				logger.Field{Key: "panic", Value: p},
+			)
+		}
+	}()
+	handler(ctx, event)
+}
+
+// [RU] EnableEvents подключает шину событий: Create/Update/Delete/Restore через Repo менеджера, в том числе
+// внутри ExecuteInTx, публикуют Before*/After* события в транзакции операции <--->
+// [ENG] EnableEvents attaches the event bus: Create/Update/Delete/Restore through the manager's Repo, including
+// inside ExecuteInTx, publish Before*/After* events within the operation's transaction
+func (m *BaseManager[ID, T, PT]) EnableEvents(bus *EventBus[T]) {
+	m.events = bus
+	m.Repo = &eventedRepository[T, ID]{Repository: m.Repo, bus: bus}
+}
+
+// [RU] Events возвращает шину событий менеджера; nil, если EnableEvents не вызывался <--->
+// [ENG] Events returns the manager's event bus; nil if EnableEvents was not called
+func (m *BaseManager[ID, T, PT]) Events() *EventBus[T] {
+	return m.events
+}
+
+// eventedRepository декоратор репозитория, который публикует события жизненного цикла
+type eventedRepository[T any, ID comparable] struct {
+	db.Repository[T, ID]
+	bus *EventBus[T]
+	tx  *sql.Tx // транзакция из WithTx; nil - вне транзакции
+}
+
+func (r *eventedRepository[T, ID]) WithTx(tx *sql.Tx) db.Repository[T, ID] {
+	return &eventedRepository[T, ID]{Repository: r.Repository.WithTx(tx), bus: r.bus, tx: tx}
+}
+
+// write выполняет изменение вместе с обработчиками событий в одной транзакции; вне транзакции открывает собственную
+func (r *eventedRepository[T, ID]) write(ctx context.Context, op func(repo db.Repository[T, ID], tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return op(r.Repository, r.tx)
+	}
+	return RunInTx(ctx, r.bus.db, func(tx *sql.Tx) error {
+		return op(r.Repository.WithTx(tx), tx)
+	})
+}
+
+func (r *eventedRepository[T, ID]) Create(ctx context.Context, entity *T) error {
+	if !r.bus.subscribed(BeforeCreate, AfterCreate) {
+		return r.Repository.Create(ctx, entity)
+	}
+	return r.write(ctx, func(repo db.Repository[T, ID], tx *sql.Tx) error {
+		if err := r.bus.publish(ctx, tx, Event[T]{Type: BeforeCreate, New: entity}); err != nil {
+			return err
+		}
+		if err := repo.Create(ctx, entity); err != nil {
+			return err
+		}
+		return r.bus.publish(ctx, tx, Event[T]{Type: AfterCreate, New: entity})
+	})
+}
+
+// Update передает обработчикам строку до изменения; для отсутствующей строки события не публикуются
+func (r *eventedRepository[T, ID]) Update(ctx context.Context, entity *T) error {
+	if !r.bus.subscribed(BeforeUpdate, AfterUpdate) {
+		return r.Repository.Update(ctx, entity)
+	}
+	return r.write(ctx, func(repo db.Repository[T, ID], tx *sql.Tx) error {
+		old, err := repo.GetByID(ctx, idOf[T, ID](entity))
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.Update(ctx, entity)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := r.bus.publish(ctx, tx, Event[T]{Type: BeforeUpdate, Old: old, New: entity}); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, entity); err != nil {
+			return err
+		}
+		return r.bus.publish(ctx, tx, Event[T]{Type: AfterUpdate, Old: old, New: entity})
+	})
+}
+
+func (r *eventedRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	if !r.bus.subscribed(BeforeDelete, AfterDelete) {
+		return r.Repository.Delete(ctx, id)
+	}
+	return r.write(ctx, func(repo db.Repository[T, ID], tx *sql.Tx) error {
+		old, err := repo.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repo.Delete(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := r.bus.publish(ctx, tx, Event[T]{Type: BeforeDelete, Old: old}); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.bus.publish(ctx, tx, Event[T]{Type: AfterDelete, Old: old})
+	})
+}
+
+func (r *eventedRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	if !r.bus.subscribed(AfterRestore) {
+		return r.Repository.Restore(ctx, id)
+	}
+	return r.write(ctx, func(repo db.Repository[T, ID], tx *sql.Tx) error {
+		if err := repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		restored, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.bus.publish(ctx, tx, Event[T]{Type: AfterRestore, New: restored})
+	})
+}
+
+// idOf возвращает ID сущности через domain.Entity
+func idOf[T any, ID comparable](entity *T) ID {
+	return any(entity).(interface{ GetID() ID }).GetID()
+}
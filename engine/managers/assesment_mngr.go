@@ -131,42 +131,27 @@ func (m *StudentAssessmentManager) GetGradesByDateRange(ctx context.Context, sta
 // [RU] BulkUpsert массовое обновление/добавление оценок в транзакции <--->
 // [ENG] BulkUpsert - massive updating/adding grades into transaction
 func (m *StudentAssessmentManager) BulkUpsert(ctx context.Context, assessments []*domain.StudentAssessment) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for _, assessment := range assessments {
-		if err := assessment.Validate(); err != nil {
-			return fmt.Errorf("validation failed: %w", err)
-		}
-
-		exists, err := txRepo.Exists(ctx, assessment.GetID())
-		if err != nil {
-			return fmt.Errorf("exists check failed: %w", err)
-		}
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.StudentAssessment, int]) error {
+		for _, assessment := range assessments {
+			if err := assessment.Validate(); err != nil {
+				return fmt.Errorf("validation failed: %w", err)
+			}
 
-		if exists {
-			if err := txRepo.Update(ctx, assessment); err != nil {
-				return fmt.Errorf("update failed: %w", err)
+			exists, err := txRepo.Exists(ctx, assessment.GetID())
+			if err != nil {
+				return fmt.Errorf("exists check failed: %w", err)
 			}
-		} else {
-			if err := txRepo.Create(ctx, assessment); err != nil {
-				return fmt.Errorf("create failed: %w", err)
+
+			if exists {
+				if err := txRepo.Update(ctx, assessment); err != nil {
+					return fmt.Errorf("update failed: %w", err)
+				}
+			} else {
+				if err := txRepo.Create(ctx, assessment); err != nil {
+					return fmt.Errorf("create failed: %w", err)
+				}
 			}
 		}
-	}
-
-	return tx.Commit()
+		return nil
+	})
 }
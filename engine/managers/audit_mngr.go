@@ -0,0 +1,52 @@
+package managers
+
+import (
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// AuditManager чтение журнала изменений; записи создает только engine.AuditTrail
+type AuditManager struct {
+	*engine.BaseManager[int, domain.AuditEntry, *domain.AuditEntry]
+}
+
+// NewAuditManager создает новый экземпляр AuditManager
+func NewAuditManager(
+	repo db.Repository[domain.AuditEntry, int],
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *AuditManager {
+	return &AuditManager{
+		BaseManager: engine.NewBaseManager[int, domain.AuditEntry, *domain.AuditEntry](repo, logger, txTimeout),
+	}
+}
+
+// [RU] enableAudit подключает журнал изменений ко всем менеджерам сущностей; имя сущности - таблица БД <--->
+// [ENG] enableAudit attaches the change log to every entity manager; the entity name is the DB table
+func (m *Managers) enableAudit(trail *engine.AuditTrail) {
+	m.Assessment.EnableAudit(trail, "student_assessment")
+	m.Attendance.EnableAudit(trail, "student_attendance")
+	m.Audience.EnableAudit(trail, "audience")
+	m.Employee.EnableAudit(trail, "employee")
+	m.StudyGroup.EnableAudit(trail, "study_group")
+	m.Schedule.EnableAudit(trail, "schedule")
+	m.ScheduleExc.EnableAudit(trail, "schedule_exception")
+	m.Instrument.EnableAudit(trail, "instrument")
+	m.ProgrammDistr.EnableAudit(trail, "programm_distribution")
+	m.SubjectDistr.EnableAudit(trail, "subject_distribution")
+	m.Lesson.EnableAudit(trail, "lesson")
+	m.Programm.EnableAudit(trail, "programm")
+	m.Student.EnableAudit(trail, "student")
+	m.Subject.EnableAudit(trail, "subject")
+	m.User.EnableAudit(trail, "users")
+	m.Waitlist.EnableAudit(trail, "group_waitlist")
+	m.Calendar.EnableAudit(trail, "academic_calendar")
+	m.Availability.EnableAudit(trail, "employee_availability")
+	m.Substitution.EnableAudit(trail, "substitution")
+	m.Occurrence.EnableAudit(trail, "lesson_occurrence")
+}
@@ -0,0 +1,101 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// AvailabilityManager доступность преподавателей: еженедельные окна и отсутствия
+type AvailabilityManager struct {
+	*engine.BaseManager[int, domain.EmployeeAvailability, *domain.EmployeeAvailability]
+}
+
+// NewAvailabilityManager создает новый экземпляр AvailabilityManager
+func NewAvailabilityManager(
+	repo db.Repository[domain.EmployeeAvailability, int],
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *AvailabilityManager {
+	return &AvailabilityManager{
+		BaseManager: engine.NewBaseManager[int, domain.EmployeeAvailability, *domain.EmployeeAvailability](repo, logger, txTimeout),
+	}
+}
+
+// [RU] GetByEmployee возвращает окна и отсутствия преподавателя <--->
+// [ENG] GetByEmployee returns the teacher's windows and absences
+func (m *AvailabilityManager) GetByEmployee(ctx context.Context, employeeID int) (domain.Availability, error) {
+	return m.load(ctx, nil, employeeID)
+}
+
+func (m *AvailabilityManager) load(ctx context.Context, tx *sql.Tx, employeeID int) (domain.Availability, error) {
+	repo := m.Repo
+	if tx != nil {
+		repo = repo.WithTx(tx)
+	}
+	entries, err := repo.List(ctx, db.Filter{
+		Conditions: []db.Condition{{Field: "employee_id", Operator: "=", Value: employeeID}},
+		OrderBy:    "kind, availability_id",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load availability of employee %d: %w", employeeID, err)
+	}
+	return entries, nil
+}
+
+// [RU] checkAvailability отклоняет занятия вне доступности преподавателя: запись расписания проверяется
+// при создании, изменении и восстановлении, занятие - при смене преподавателя, перенесенное занятие серии -
+// в EditOccurrence. Новое отсутствие уже стоящие занятия не отклоняет: их нужно заменить или перенести <--->
+// [ENG] checkAvailability vetoes lessons outside the teacher's availability: a schedule entry is checked
+// on create, update and restore, a lesson when its teacher changes, a moved occurrence of a series
+// in EditOccurrence. A new absence does not reject lessons already scheduled: they have to be covered or moved
+func (m *Managers) checkAvailability() {
+	schedules := m.Schedule.Events()
+	checkSchedule := func(ctx context.Context, e engine.Event[domain.Schedule]) error {
+		lesson, err := m.Lesson.Repo.WithTx(e.Tx).GetByID(ctx, e.New.LessonID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil // ссылку на занятие проверяет внешний ключ
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load lesson %d: %w", e.New.LessonID, err)
+		}
+		availability, err := m.Availability.load(ctx, e.Tx, lesson.EmployeeID)
+		if err != nil {
+			return err
+		}
+		return availability.CheckSchedule(lesson.EmployeeID, e.New)
+	}
+	schedules.Subscribe(engine.BeforeCreate, checkSchedule)
+	schedules.Subscribe(engine.BeforeUpdate, checkSchedule)
+	schedules.Subscribe(engine.AfterRestore, checkSchedule)
+
+	m.Lesson.Events().Subscribe(engine.BeforeUpdate, func(ctx context.Context, e engine.Event[domain.Lesson]) error {
+		if e.Old.EmployeeID == e.New.EmployeeID {
+			return nil
+		}
+		availability, err := m.Availability.load(ctx, e.Tx, e.New.EmployeeID)
+		if err != nil || len(availability) == 0 {
+			return err
+		}
+		own, err := m.Schedule.Repo.WithTx(e.Tx).List(ctx, db.Filter{Conditions: []db.Condition{
+			{Field: "lesson_id", Operator: "=", Value: e.New.LessonID},
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to list schedule of lesson %d: %w", e.New.LessonID, err)
+		}
+		for _, schedule := range own {
+			if err := availability.CheckSchedule(e.New.EmployeeID, schedule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
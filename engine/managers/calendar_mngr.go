@@ -0,0 +1,186 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// CalendarManager учебный календарь: четверти, праздники и переносы рабочих дней
+type CalendarManager struct {
+	*engine.BaseManager[int, domain.CalendarEntry, *domain.CalendarEntry]
+	db *sql.DB
+}
+
+// NewCalendarManager создает новый экземпляр CalendarManager
+func NewCalendarManager(
+	repo db.Repository[domain.CalendarEntry, int],
+	db *sql.DB,
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *CalendarManager {
+	return &CalendarManager{
+		BaseManager: engine.NewBaseManager[int, domain.CalendarEntry, *domain.CalendarEntry](repo, logger, txTimeout),
+		db:          db,
+	}
+}
+
+// [RU] Load загружает календарь периода [from, to]: записи, пересекающие период, и переносы с дат периода <--->
+// [ENG] Load loads the calendar of the [from, to] period: the entries overlapping it and the swaps of its dates
+func (m *CalendarManager) Load(ctx context.Context, from, to time.Time) (*domain.Calendar, error) {
+	return m.load(ctx, nil, from, to)
+}
+
+func (m *CalendarManager) load(ctx context.Context, tx *sql.Tx, from, to time.Time) (*domain.Calendar, error) {
+	repo := m.Repo
+	if tx != nil {
+		repo = repo.WithTx(tx)
+	}
+	entries, err := repo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "date_start", Operator: "<=", Value: domain.DateOnly(to)},
+		{Field: "date_end", Operator: ">=", Value: domain.DateOnly(from)},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load academic calendar: %w", err)
+	}
+	swaps, err := repo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "kind", Operator: "=", Value: domain.CalendarSwap},
+		{Field: "worked_as", Operator: ">=", Value: domain.DateOnly(from)},
+		{Field: "worked_as", Operator: "<=", Value: domain.DateOnly(to)},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load academic calendar: %w", err)
+	}
+	for _, swap := range swaps {
+		if !slices.ContainsFunc(entries, func(e *domain.CalendarEntry) bool { return e.CalendarID == swap.CalendarID }) {
+			entries = append(entries, swap)
+		}
+	}
+
+	terms, err := repo.Count(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "kind", Operator: "=", Value: domain.CalendarTerm},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count academic terms: %w", err)
+	}
+	return domain.NewCalendar(entries, terms > 0), nil
+}
+
+// [RU] AddCalendarEntry создает запись календаря и возвращает занятия, которые она отменяет или переносит:
+// праздник, добавленный задним числом, сразу показывает затронутые занятия <--->
+// [ENG] AddCalendarEntry creates a calendar entry and returns the occurrences it cancels or moves:
+// a holiday added after the fact shows the affected occurrences right away
+func (m *Managers) AddCalendarEntry(ctx context.Context, entry *domain.CalendarEntry) ([]domain.Occurrence, error) {
+	if err := m.Calendar.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return m.CalendarImpact(ctx, entry)
+}
+
+// [RU] CalendarImpact возвращает занятия, которые запись календаря отменяет (праздник) или переносит (перенос).
+// Четверти открывают дни, а не закрывают, поэтому для них список пуст <--->
+// [ENG] CalendarImpact returns the occurrences the calendar entry cancels (holiday) or moves (swap).
+// Terms open days rather than close them, so their list is empty
+func (m *Managers) CalendarImpact(ctx context.Context, entry *domain.CalendarEntry) ([]domain.Occurrence, error) {
+	var affected []domain.Occurrence
+	switch entry.Kind {
+	case domain.CalendarHoliday:
+		occurrences, err := m.ScheduleOccurrences(ctx, entry.DateStart, entry.DateEnd, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences {
+			if occurrence.Closed {
+				affected = append(affected, occurrence)
+			}
+		}
+	case domain.CalendarSwap:
+		occurrences, err := m.ScheduleOccurrences(ctx, entry.DateStart, entry.DateEnd, 0, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences {
+			if occurrence.Swapped && entry.WorkedAs != nil && occurrence.OriginalDate.Equal(domain.DateOnly(*entry.WorkedAs)) {
+				affected = append(affected, occurrence)
+			}
+		}
+	}
+	return affected, nil
+}
+
+// [RU] CalendarImport итог импорта календаря: созданные и обновленные записи и затронутые ими занятия <--->
+// [ENG] CalendarImport is the outcome of a calendar import: created and updated entries and the occurrences they affect
+type CalendarImport struct {
+	Created  []*domain.CalendarEntry
+	Updated  []*domain.CalendarEntry
+	Affected []domain.Occurrence
+}
+
+// [RU] ImportCalendar сохраняет записи календаря в одной транзакции. Запись с SourceUID обновляет ранее
+// импортированную с тем же UID, поэтому повторный импорт файла не создает дублей <--->
+// [ENG] ImportCalendar saves calendar entries in a single transaction. An entry with SourceUID updates the one
+// imported earlier with the same UID, so importing a file again creates no duplicates
+func (m *Managers) ImportCalendar(ctx context.Context, entries []*domain.CalendarEntry) (*CalendarImport, error) {
+	result := &CalendarImport{}
+	err := engine.RunInTx(ctx, m.Calendar.db, func(tx *sql.Tx) error {
+		repo := m.Calendar.Repo.WithTx(tx)
+		for _, entry := range entries {
+			if err := entry.Validate(); err != nil {
+				return fmt.Errorf("%s %q: validation failed: %w", entry.Kind, entry.Name, err)
+			}
+
+			if entry.SourceUID != nil {
+				existing, err := repo.List(ctx, db.Filter{
+					Conditions: []db.Condition{{Field: "source_uid", Operator: "=", Value: *entry.SourceUID}},
+					Limit:      1,
+				})
+				if err != nil {
+					return fmt.Errorf("calendar entry %s: %w", *entry.SourceUID, err)
+				}
+				if len(existing) > 0 {
+					entry.CalendarID, entry.Version = existing[0].CalendarID, existing[0].Version
+					if err := repo.Update(ctx, entry); err != nil {
+						return fmt.Errorf("calendar entry %s: update failed: %w", *entry.SourceUID, err)
+					}
+					result.Updated = append(result.Updated, entry)
+					continue
+				}
+			}
+
+			if err := repo.Create(ctx, entry); err != nil {
+				return fmt.Errorf("%s %q: create failed: %w", entry.Kind, entry.Name, err)
+			}
+			result.Created = append(result.Created, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Одно занятие может затронуть несколько записей импорта
+	seen := map[occurrenceKey]bool{}
+	for _, entry := range entries {
+		affected, err := m.CalendarImpact(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range affected {
+			key := occurrenceKey{occurrence.ScheduleID, occurrence.OriginalDate}
+			if !seen[key] {
+				seen[key] = true
+				result.Affected = append(result.Affected, occurrence)
+			}
+		}
+	}
+	slices.SortFunc(result.Affected, func(a, b domain.Occurrence) int { return a.Start().Compare(b.Start()) })
+	return result, nil
+}
@@ -0,0 +1,307 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+)
+
+// [RU] ScheduleConflicts ищет занятия, которые делят с записью расписания преподавателя, аудиторию, группу
+// или студента и идут в одну с ней дату в пересекающееся время. Запись с ScheduleID не сравнивается сама с собой <--->
+// [ENG] ScheduleConflicts finds the lessons that share the schedule entry's teacher, room, group or student
+// and run on a common date at an overlapping time. An entry with ScheduleID is not compared with itself
+func (m *Managers) ScheduleConflicts(ctx context.Context, schedule *domain.Schedule) ([]domain.Conflict, error) {
+	lesson, err := m.Lesson.GetByID(ctx, schedule.LessonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // ссылку на занятие проверяет внешний ключ
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lesson %d: %w", schedule.LessonID, err)
+	}
+	return m.conflicts(ctx, nil, lesson, schedule)
+}
+
+// [RU] ResourceAvailable сообщает, свободен ли преподаватель или аудитория resource в дату startTime
+// с startTime до endTime. Занятия ищутся так же, как в ScheduleConflicts; занятие excludeLessonID не учитывается <--->
+// [ENG] ResourceAvailable reports whether the teacher or room resource is free on startTime's date
+// from startTime to endTime. Lessons are found the same way as in ScheduleConflicts; lesson excludeLessonID is ignored
+func (m *Managers) ResourceAvailable(
+	ctx context.Context,
+	resource domain.Resource,
+	startTime, endTime time.Time,
+	excludeLessonID int,
+) (bool, error) {
+	// Занятие-образец занимает только проверяемый ресурс: ID групп и преподавателей начинаются с 1
+	probe := &domain.Lesson{LessonID: excludeLessonID}
+	switch resource.Kind {
+	case domain.ResourceEmployee:
+		probe.EmployeeID = resource.ID
+	case domain.ResourceAudience:
+		probe.AudienceID = &resource.ID
+	default:
+		return false, fmt.Errorf("resource %q cannot be checked", resource.Kind)
+	}
+	date := domain.DateOnly(startTime)
+	slot := &domain.Schedule{
+		LessonID:      excludeLessonID,
+		DayWeek:       domain.WeekdayName(startTime.Weekday()),
+		TimeBegin:     domain.ParseTimeHM(startTime.Format("15:04")),
+		TimeEnd:       domain.ParseTimeHM(endTime.Format("15:04")),
+		SchdDateStart: date,
+		SchdDateEnd:   date,
+	}
+
+	conflicts, err := m.conflicts(ctx, nil, probe, slot)
+	if err != nil {
+		return false, err
+	}
+	for _, conflict := range conflicts {
+		if conflict.LessonID != excludeLessonID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// [RU] checkConflicts отклоняет изменения, после которых ресурс занят дважды: запись расписания проверяется
+// при создании, изменении и восстановлении, занятие - при смене преподавателя, аудитории, группы или студента <--->
+// [ENG] checkConflicts vetoes changes that leave a resource double-booked: a schedule entry is checked
+// on create, update and restore, a lesson when its teacher, room, group or student changes
+func (m *Managers) checkConflicts() {
+	schedules := m.Schedule.Events()
+	checkSchedule := func(ctx context.Context, e engine.Event[domain.Schedule]) error {
+		lesson, err := m.Lesson.Repo.WithTx(e.Tx).GetByID(ctx, e.New.LessonID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load lesson %d: %w", e.New.LessonID, err)
+		}
+		return m.vetoConflicts(ctx, e.Tx, lesson, e.New)
+	}
+	schedules.Subscribe(engine.BeforeCreate, checkSchedule)
+	schedules.Subscribe(engine.BeforeUpdate, checkSchedule)
+	schedules.Subscribe(engine.AfterRestore, checkSchedule) // за время в корзине ресурсы могли занять
+
+	m.Lesson.Events().Subscribe(engine.BeforeUpdate, func(ctx context.Context, e engine.Event[domain.Lesson]) error {
+		if sameResources(e.Old, e.New) {
+			return nil
+		}
+		own, err := m.Schedule.Repo.WithTx(e.Tx).List(ctx, db.Filter{Conditions: []db.Condition{
+			{Field: "lesson_id", Operator: "=", Value: e.New.LessonID},
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to list schedule of lesson %d: %w", e.New.LessonID, err)
+		}
+		for _, schedule := range own {
+			if err := m.vetoConflicts(ctx, e.Tx, e.New, schedule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// vetoConflicts возвращает *domain.ConflictError, если у записи есть конфликты
+func (m *Managers) vetoConflicts(ctx context.Context, tx *sql.Tx, lesson *domain.Lesson, schedule *domain.Schedule) error {
+	conflicts, err := m.conflicts(ctx, tx, lesson, schedule)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &domain.ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// vetoOccurrenceConflicts проверяет одно занятие серии в его дату проведения; исключение занятия уже записано в tx
+func (m *Managers) vetoOccurrenceConflicts(ctx context.Context, tx *sql.Tx, lesson *domain.Lesson, occurrence domain.Occurrence) error {
+	occurrences, err := m.expandSchedules(ctx, tx, occurrence.Date, occurrence.Date, 0, false, nil)
+	if err != nil {
+		return err
+	}
+	own := slices.DeleteFunc(slices.Clone(occurrences), func(o domain.Occurrence) bool {
+		return o.ScheduleID != occurrence.ScheduleID || !o.OriginalDate.Equal(occurrence.OriginalDate)
+	})
+	if len(own) == 0 {
+		return nil // дата закрыта календарем
+	}
+	conflicts, err := m.occurrenceConflicts(ctx, tx, lesson, own, occurrences)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &domain.ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// [RU] conflicts сравнивает занятия записи расписания занятия lesson с занятиями других записей в те же даты.
+// Серии разворачиваются с исключениями и учебным календарем: отмененные занятия слот не занимают,
+// перенесенные сравниваются в новые дату и время, занятие с заменой занимает заменяющего преподавателя <--->
+// [ENG] conflicts compares the occurrences of the schedule entry of lesson with the occurrences of other entries
+// on the same dates. Series are expanded with their exceptions and the academic calendar: cancelled occurrences
+// do not hold their slot, moved ones are compared at their new date and time, a covered occurrence occupies
+// the substitute teacher
+func (m *Managers) conflicts(ctx context.Context, tx *sql.Tx, lesson *domain.Lesson, schedule *domain.Schedule) ([]domain.Conflict, error) {
+	// Перенесенные занятия серии могут выйти за ее даты
+	from, to := domain.DateOnly(schedule.SchdDateStart), domain.DateOnly(schedule.SchdDateEnd)
+	if schedule.ScheduleID != 0 {
+		exceptions := m.ScheduleExc.Repo
+		if tx != nil {
+			exceptions = exceptions.WithTx(tx)
+		}
+		moved, err := exceptions.List(ctx, db.Filter{Conditions: []db.Condition{
+			{Field: "schedule_id", Operator: "=", Value: schedule.ScheduleID},
+			{Field: "new_date", Operator: "IS NOT NULL"},
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("schedule %d: failed to load exceptions: %w", schedule.ScheduleID, err)
+		}
+		for _, exception := range moved {
+			from, to = minDate(from, *exception.NewDate), maxDate(to, *exception.NewDate)
+		}
+	}
+
+	occurrences, err := m.expandSchedules(ctx, tx, from, to, 0, false, schedule)
+	if err != nil {
+		return nil, err
+	}
+	var own []domain.Occurrence
+	for _, occurrence := range occurrences {
+		if occurrence.ScheduleID == schedule.ScheduleID {
+			own = append(own, occurrence)
+		}
+	}
+	return m.occurrenceConflicts(ctx, tx, lesson, own, occurrences)
+}
+
+// [RU] occurrenceConflicts сравнивает занятия own занятия lesson с остальными занятиями из occurrences в те же даты.
+// О каждом занятом ресурсе записи расписания сообщается один раз, на первую общую дату <--->
+// [ENG] occurrenceConflicts compares the occurrences own of lesson with the other occurrences on the same dates.
+// Each busy resource of a schedule entry is reported once, on the first common date
+func (m *Managers) occurrenceConflicts(
+	ctx context.Context,
+	tx *sql.Tx,
+	lesson *domain.Lesson,
+	own, occurrences []domain.Occurrence,
+) ([]domain.Conflict, error) {
+	lessons, students := m.Lesson.Repo, m.Student.Repo
+	if tx != nil {
+		lessons, students = lessons.WithTx(tx), students.WithTx(tx)
+	}
+
+	isOwn := make(map[occurrenceKey]bool, len(own))
+	for _, occurrence := range own {
+		isOwn[occurrenceKey{occurrence.ScheduleID, occurrence.OriginalDate}] = true
+	}
+	byDate := map[time.Time][]domain.Occurrence{}
+	var lessonIDs []int
+	for _, occurrence := range occurrences {
+		if isOwn[occurrenceKey{occurrence.ScheduleID, occurrence.OriginalDate}] {
+			continue
+		}
+		byDate[occurrence.Date] = append(byDate[occurrence.Date], occurrence)
+		if !slices.Contains(lessonIDs, occurrence.LessonID) {
+			lessonIDs = append(lessonIDs, occurrence.LessonID)
+		}
+	}
+	if len(lessonIDs) == 0 {
+		return nil, nil
+	}
+
+	loaded, err := lessons.GetByIDs(ctx, lessonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lessons: %w", err)
+	}
+	byID := make(map[int]*domain.Lesson, len(loaded)+1)
+	for _, l := range loaded {
+		byID[l.LessonID] = l
+	}
+	byID[lesson.LessonID] = lesson // занятие могло измениться в этой же операции
+
+	// Группы студентов индивидуальных занятий
+	var studentIDs []int
+	for _, l := range byID {
+		if l.StudentID != nil && !slices.Contains(studentIDs, *l.StudentID) {
+			studentIDs = append(studentIDs, *l.StudentID)
+		}
+	}
+	groups := make(map[int]int, len(studentIDs))
+	if len(studentIDs) > 0 {
+		loadedStudents, err := students.GetByIDs(ctx, studentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load students: %w", err)
+		}
+		for _, student := range loadedStudents {
+			groups[student.StudentID] = student.GroupID
+		}
+	}
+	groupOf := func(studentID int) (int, bool) {
+		group, ok := groups[studentID]
+		return group, ok
+	}
+
+	// Занятие с назначенной заменой занимает заменяющего преподавателя, а не своего
+	substitutes, err := m.substitutes(ctx, tx, occurrences)
+	if err != nil {
+		return nil, err
+	}
+	taught := func(l *domain.Lesson, occurrence domain.Occurrence) *domain.Lesson {
+		substitute, ok := substitutes[occurrenceKey{occurrence.ScheduleID, occurrence.OriginalDate}]
+		if !ok {
+			return l
+		}
+		covered := *l
+		covered.EmployeeID = substitute
+		return &covered
+	}
+
+	type busy struct {
+		scheduleID int
+		resource   domain.Resource
+	}
+	reported := map[busy]bool{}
+	var conflicts []domain.Conflict
+	for _, occurrence := range own {
+		ownLesson := taught(lesson, occurrence)
+		for _, other := range byDate[occurrence.Date] {
+			otherLesson, ok := byID[other.LessonID]
+			if !ok {
+				continue // занятие в корзине
+			}
+			if !domain.TimesOverlap(occurrence.TimeBegin, occurrence.TimeEnd, other.TimeBegin, other.TimeEnd) {
+				continue
+			}
+			for _, resource := range domain.SharedResources(ownLesson, taught(otherLesson, other), groupOf) {
+				if reported[busy{other.ScheduleID, resource}] {
+					continue
+				}
+				reported[busy{other.ScheduleID, resource}] = true
+				conflicts = append(conflicts, domain.Conflict{
+					Resource:   resource,
+					LessonID:   otherLesson.LessonID,
+					LessonName: otherLesson.LessonName,
+					ScheduleID: other.ScheduleID,
+					Date:       other.Date,
+					TimeBegin:  other.TimeBegin,
+					TimeEnd:    other.TimeEnd,
+				})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// sameResources сообщает, что занятие осталось с тем же преподавателем, аудиторией, группой и студентом
+func sameResources(a, b *domain.Lesson) bool {
+	return a.EmployeeID == b.EmployeeID && a.GroupID == b.GroupID &&
+		equalIntPtr(a.AudienceID, b.AudienceID) && equalIntPtr(a.StudentID, b.StudentID)
+}
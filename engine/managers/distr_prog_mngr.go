@@ -165,36 +165,17 @@ func (m *ProgrammDistributionManager) BulkCreate(
 	ctx context.Context,
 	distributions []*domain.ProgrammDistribution,
 ) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for _, distr := range distributions {
-		if err := distr.Validate(); err != nil {
-			return fmt.Errorf("validation failed for distribution %v: %w", distr, err)
-		}
-
-		ptrToDistr := distr
-		if err := txRepo.Create(ctx, ptrToDistr); err != nil {
-			return fmt.Errorf("create failed for distribution %v: %w", distr, err)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.ProgrammDistribution, int]) error {
+		for _, distr := range distributions {
+			if err := distr.Validate(); err != nil {
+				return fmt.Errorf("validation failed for distribution %v: %w", distr, err)
+			}
+
+			ptrToDistr := distr
+			if err := txRepo.Create(ctx, ptrToDistr); err != nil {
+				return fmt.Errorf("create failed for distribution %v: %w", distr, err)
+			}
 		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -182,43 +182,24 @@ func (m *EmployeeManager) Update(ctx context.Context, employee *domain.Employee)
 // [RU] BulkCreate массово создает сотрудников в транзакции <--->
 // [ENG] BulkCreate creates multiple employees in a transaction
 func (m *EmployeeManager) BulkCreate(ctx context.Context, employees []*domain.Employee) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, emp := range employees {
-		if err := emp.Validate(); err != nil {
-			return fmt.Errorf("validation failed for employee %v: %w", emp, err)
-		}
-
-		isUnique, err := m.CheckPhoneUnique(ctx, emp.PhoneNumber, 0)
-		if err != nil {
-			return fmt.Errorf("phone uniqueness check failed: %w", err)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Employee, int]) error {
+		for _, emp := range employees {
+			if err := emp.Validate(); err != nil {
+				return fmt.Errorf("validation failed for employee %v: %w", emp, err)
+			}
+
+			isUnique, err := m.CheckPhoneUnique(ctx, emp.PhoneNumber, 0)
+			if err != nil {
+				return fmt.Errorf("phone uniqueness check failed: %w", err)
+			}
+			if !isUnique {
+				return fmt.Errorf("phone number %s already exists", emp.PhoneNumber)
+			}
+
+			if err := txRepo.Create(ctx, emp); err != nil {
+				return fmt.Errorf("create failed for employee %v: %w", emp, err)
+			}
 		}
-		if !isUnique {
-			return fmt.Errorf("phone number %s already exists", emp.PhoneNumber)
-		}
-
-		if err := txRepo.Create(ctx, emp); err != nil {
-			return fmt.Errorf("create failed for employee %v: %w", emp, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,77 @@
+package managers
+
+import (
+	"database/sql"
+
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// Топики outbox, на которые менеджеры пишут изменения (см. enableOutbox)
+const (
+	TopicAssessment = "assessment"
+	TopicSchedule   = "schedule" // серии расписания и исключения отдельных занятий
+	TopicWaitlist   = "waitlist" // постановка в очередь, предложение места, зачисление, отзыв
+)
+
+// [RU] enableEvents подключает шины событий ко всем менеджерам сущностей; подписка - через Events() менеджера <--->
+// [ENG] enableEvents attaches event buses to every entity manager; subscribe through the manager's Events()
+func (m *Managers) enableEvents(db *sql.DB, logger *logger.LevelLogger) {
+	m.Assessment.EnableEvents(engine.NewEventBus[domain.StudentAssessment](db, logger))
+	m.Attendance.EnableEvents(engine.NewEventBus[domain.StudentAttendance](db, logger))
+	m.Audience.EnableEvents(engine.NewEventBus[domain.Audience](db, logger))
+	m.Employee.EnableEvents(engine.NewEventBus[domain.Employee](db, logger))
+	m.StudyGroup.EnableEvents(engine.NewEventBus[domain.StudyGroup](db, logger))
+	m.Schedule.EnableEvents(engine.NewEventBus[domain.Schedule](db, logger))
+	m.ScheduleExc.EnableEvents(engine.NewEventBus[domain.ScheduleException](db, logger))
+	m.Instrument.EnableEvents(engine.NewEventBus[domain.Instrument](db, logger))
+	m.ProgrammDistr.EnableEvents(engine.NewEventBus[domain.ProgrammDistribution](db, logger))
+	m.SubjectDistr.EnableEvents(engine.NewEventBus[domain.SubjectDistribution](db, logger))
+	m.Lesson.EnableEvents(engine.NewEventBus[domain.Lesson](db, logger))
+	m.Programm.EnableEvents(engine.NewEventBus[domain.Programm](db, logger))
+	m.Student.EnableEvents(engine.NewEventBus[domain.Student](db, logger))
+	m.Subject.EnableEvents(engine.NewEventBus[domain.Subject](db, logger))
+	m.User.EnableEvents(engine.NewEventBus[domain.User](db, logger))
+	m.Waitlist.EnableEvents(engine.NewEventBus[domain.WaitlistEntry](db, logger))
+	m.Calendar.EnableEvents(engine.NewEventBus[domain.CalendarEntry](db, logger))
+	m.Availability.EnableEvents(engine.NewEventBus[domain.EmployeeAvailability](db, logger))
+	m.Substitution.EnableEvents(engine.NewEventBus[domain.Substitution](db, logger))
+	m.Occurrence.EnableEvents(engine.NewEventBus[domain.LessonOccurrence](db, logger))
+}
+
+// [RU] Wait ждет завершения асинхронных обработчиков событий всех менеджеров (вызывается при остановке сервера) <--->
+// [ENG] Wait blocks until the asynchronous event handlers of all managers finish (called on server shutdown)
+func (m *Managers) Wait() {
+	m.Assessment.Events().Wait()
+	m.Attendance.Events().Wait()
+	m.Audience.Events().Wait()
+	m.Employee.Events().Wait()
+	m.StudyGroup.Events().Wait()
+	m.Schedule.Events().Wait()
+	m.ScheduleExc.Events().Wait()
+	m.Instrument.Events().Wait()
+	m.ProgrammDistr.Events().Wait()
+	m.SubjectDistr.Events().Wait()
+	m.Lesson.Events().Wait()
+	m.Programm.Events().Wait()
+	m.Student.Events().Wait()
+	m.Subject.Events().Wait()
+	m.User.Events().Wait()
+	m.Waitlist.Events().Wait()
+	m.Calendar.Events().Wait()
+	m.Availability.Events().Wait()
+	m.Substitution.Events().Wait()
+	m.Occurrence.Events().Wait()
+}
+
+// [RU] enableOutbox пишет в outbox изменения оценок, расписания и листа ожидания для уведомлений <--->
+// [ENG] enableOutbox writes grade, schedule and waitlist changes to the outbox for notifications
+func (m *Managers) enableOutbox(outbox *engine.Outbox) {
+	m.Outbox = outbox
+	m.Assessment.EnableOutbox(outbox, TopicAssessment)
+	m.Schedule.EnableOutbox(outbox, TopicSchedule)
+	m.ScheduleExc.EnableOutbox(outbox, TopicSchedule) // переносы и отмены занятий
+	m.Waitlist.EnableOutbox(outbox, TopicWaitlist)
+}
@@ -111,40 +111,10 @@ func (m *StudyGroupManager) CheckNameUnique(ctx context.Context, name string, ex
 	return len(groups) == 0, nil
 }
 
-// [RU] UpdateStudentCount обновляет количество студентов в группе <--->
-// [ENG] UpdateStudentCount updates the number of students in the group
-func (m *StudyGroupManager) UpdateStudentCount(ctx context.Context, groupID int, newCount int) error {
-	groupPtr, err := m.GetByID(ctx, groupID)
-	if err != nil {
-		m.Logger.Error("UpdateStudentCount failed to get group",
-			logger.Field{Key: "error", Value: err},
-			logger.Field{Key: "group_id", Value: groupID},
-		)
-		return fmt.Errorf("failed to get group: %w", err)
-	}
-	if groupPtr == nil {
-		m.Logger.Error("Group not found",
-			logger.Field{Key: "group_id", Value: groupID},
-		)
-		return fmt.Errorf("group not found")
-	}
-
-	group := *groupPtr
-	group.NumberOfStudents = newCount
-
-	if err := m.BaseManager.Update(ctx, &group); err != nil {
-		m.Logger.Error("UpdateStudentCount failed to update",
-			logger.Field{Key: "error", Value: err},
-			logger.Field{Key: "group", Value: group},
-		)
-		return fmt.Errorf("update failed: %w", err)
-	}
-	return nil
-}
-
-// [RU] Create создает новую учебную группу <--->
-// [ENG] Create creates a new study group
+// [RU] Create создает новую учебную группу; в новой группе студентов нет <--->
+// [ENG] Create creates a new study group; a new group has no students
 func (m *StudyGroupManager) Create(ctx context.Context, group *domain.StudyGroup) error {
+	group.NumberOfStudents = 0
 	if err := group.Validate(); err != nil {
 		m.Logger.Error("Validation failed",
 			logger.Field{Key: "error", Value: err},
@@ -189,43 +159,25 @@ func (m *StudyGroupManager) Update(ctx context.Context, group *domain.StudyGroup
 // [RU] BulkCreate массово создает учебные группы в транзакции <--->
 // [ENG] BulkCreate creates multiple study groups in a transaction
 func (m *StudyGroupManager) BulkCreate(ctx context.Context, groups []*domain.StudyGroup) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, gr := range groups {
-		if err := gr.Validate(); err != nil {
-			return fmt.Errorf("validation failed for group %v: %w", gr, err)
-		}
-
-		isUnique, err := m.CheckNameUnique(ctx, gr.GroupName, 0)
-		if err != nil {
-			return fmt.Errorf("name uniqueness check failed: %w", err)
-		}
-		if !isUnique {
-			return fmt.Errorf("group name %s already exists", gr.GroupName)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.StudyGroup, int]) error {
+		for _, gr := range groups {
+			gr.NumberOfStudents = 0
+			if err := gr.Validate(); err != nil {
+				return fmt.Errorf("validation failed for group %v: %w", gr, err)
+			}
+
+			isUnique, err := m.CheckNameUnique(ctx, gr.GroupName, 0)
+			if err != nil {
+				return fmt.Errorf("name uniqueness check failed: %w", err)
+			}
+			if !isUnique {
+				return fmt.Errorf("group name %s already exists", gr.GroupName)
+			}
+
+			if err := txRepo.Create(ctx, gr); err != nil {
+				return fmt.Errorf("create failed for group %v: %w", gr, err)
+			}
 		}
-
-		if err := txRepo.Create(ctx, gr); err != nil {
-			return fmt.Errorf("create failed for group %v: %w", gr, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
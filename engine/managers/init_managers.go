@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"GO_Music/db/repositories"
+	"GO_Music/engine"
 
 	"github.com/SerMoskvin/access"
 	"github.com/SerMoskvin/logger"
@@ -18,6 +19,7 @@ type Managers struct {
 	Employee      *EmployeeManager
 	StudyGroup    *StudyGroupManager
 	Schedule      *ScheduleManager
+	ScheduleExc   *ScheduleExceptionManager
 	Instrument    *InstrumentManager
 	ProgrammDistr *ProgrammDistributionManager
 	SubjectDistr  *SubjectDistributionManager
@@ -26,19 +28,28 @@ type Managers struct {
 	Student       *StudentManager
 	Subject       *SubjectManager
 	User          *UserManager
+	Audit         *AuditManager
+	Waitlist      *WaitlistManager
+	Calendar      *CalendarManager
+	Timetable     *TimetableManager
+	Availability  *AvailabilityManager
+	Substitution  *SubstitutionManager
+	Occurrence    *LessonOccurrenceManager
+	Outbox        *engine.Outbox
 }
 
 // NewManagers создает все менеджеры
 func NewManagers(db *sql.DB, repos *repositories.Repositories, logger *logger.LevelLogger, auth *access.Authenticator) *Managers {
 	txTimeout := 10 * time.Second // Общий таймаут для всех менеджеров
 
-	return &Managers{
+	m := &Managers{
 		Assessment:    NewStudentAssessmentManager(repos.Assessment, db, logger, txTimeout),
 		Attendance:    NewStudentAttendanceManager(repos.Attendance, db, logger, txTimeout),
 		Audience:      NewAudienceManager(repos.Audience, logger, txTimeout),
 		Employee:      NewEmployeeManager(repos.Employee, db, logger, txTimeout),
 		StudyGroup:    NewStudyGroupManager(repos.StudyGroup, db, logger, txTimeout),
 		Schedule:      NewScheduleManager(repos.Schedule, db, logger, txTimeout),
+		ScheduleExc:   NewScheduleExceptionManager(repos.ScheduleExc, logger, txTimeout),
 		Instrument:    NewInstrumentManager(repos.Instrument, db, logger, txTimeout),
 		ProgrammDistr: NewProgrammDistributionManager(repos.ProgrammDistr, db, logger, txTimeout),
 		SubjectDistr:  NewSubjectDistributionManager(repos.SubjectDistr, db, logger, txTimeout),
@@ -47,5 +58,21 @@ func NewManagers(db *sql.DB, repos *repositories.Repositories, logger *logger.Le
 		Student:       NewStudentManager(repos.Student, db, logger, txTimeout),
 		Subject:       NewSubjectManager(repos.Subject, db, logger, txTimeout),
 		User:          NewUserManager(repos.User, db, logger, txTimeout, auth),
+		Audit:         NewAuditManager(repos.Audit, logger, txTimeout),
+		Waitlist:      NewWaitlistManager(repos.Waitlist, db, logger, txTimeout),
+		Calendar:      NewCalendarManager(repos.Calendar, db, logger, txTimeout),
+		Timetable:     NewTimetableManager(repos.Timetable, db, logger, txTimeout),
+		Availability:  NewAvailabilityManager(repos.Availability, logger, txTimeout),
+		Substitution:  NewSubstitutionManager(repos.Substitution, db, logger, txTimeout),
+		Occurrence:    NewLessonOccurrenceManager(repos.Occurrence, db, logger, txTimeout),
 	}
+	m.registerRelations()
+	m.enableAudit(engine.NewAuditTrail(repos.Audit, db))
+	m.enableEvents(db, logger)
+	m.enableOutbox(engine.NewOutbox(repos.Outbox))
+	m.maintainStudentCounts()
+	m.checkAvailability()
+	m.checkConflicts()
+	m.checkOccurrenceLinks()
+	return m
 }
@@ -181,43 +181,24 @@ func (m *InstrumentManager) UpdateCondition(ctx context.Context, instrumentID in
 // [RU] BulkCreate массово создает инструменты в транзакции <--->
 // [ENG] BulkCreate creates multiple instruments in a transaction
 func (m *InstrumentManager) BulkCreate(ctx context.Context, instruments []*domain.Instrument) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, instr := range instruments {
-		if err := instr.Validate(); err != nil {
-			return fmt.Errorf("validation failed for instrument %v: %w", instr, err)
-		}
-
-		isUnique, err := m.CheckNameUnique(ctx, instr.Name, 0)
-		if err != nil {
-			return fmt.Errorf("name uniqueness check failed: %w", err)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Instrument, int]) error {
+		for _, instr := range instruments {
+			if err := instr.Validate(); err != nil {
+				return fmt.Errorf("validation failed for instrument %v: %w", instr, err)
+			}
+
+			isUnique, err := m.CheckNameUnique(ctx, instr.Name, 0)
+			if err != nil {
+				return fmt.Errorf("name uniqueness check failed: %w", err)
+			}
+			if !isUnique {
+				return fmt.Errorf("instrument name %s already exists", instr.Name)
+			}
+
+			if err := txRepo.Create(ctx, instr); err != nil {
+				return fmt.Errorf("create failed for instrument %v: %w", instr, err)
+			}
 		}
-		if !isUnique {
-			return fmt.Errorf("instrument name %s already exists", instr.Name)
-		}
-
-		if err := txRepo.Create(ctx, instr); err != nil {
-			return fmt.Errorf("create failed for instrument %v: %w", instr, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,220 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/domain"
+	"GO_Music/engine"
+)
+
+// JournalGrade оценка ученика в журнале
+type JournalGrade struct {
+	AssessmentNoteID int // 0 - новая оценка
+	TaskType         string
+	Grade            int
+}
+
+// JournalMark строка журнала для сохранения: присутствие и полный список оценок ученика за занятие
+type JournalMark struct {
+	StudentID int
+	Present   bool
+	Grades    []JournalGrade // оценки ученика, которых нет в списке, удаляются
+}
+
+// JournalChanges изменения журнала занятия; ученики без строки в Marks не меняются
+type JournalChanges struct {
+	Topic *string
+	Notes *string
+	Marks []JournalMark
+}
+
+// [RU] LessonJournal возвращает журнал занятия lessonID в дату проведения date: состав занятия с отметками
+// присутствия и оценками <--->
+// [ENG] LessonJournal returns the journal of lessonID on the date it is held: the lesson's roster with presence
+// marks and grades
+func (m *Managers) LessonJournal(ctx context.Context, lessonID int, date time.Time) (*domain.Journal, error) {
+	lesson, occurrence, roster, err := m.journalSession(ctx, lessonID, date)
+	if err != nil {
+		return nil, err
+	}
+	session, err := loadSession(ctx, m.Attendance.Repo, m.Assessment.Repo, occurrence)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewJournal(lesson, session, roster), nil
+}
+
+// [RU] SaveLessonJournal сохраняет журнал занятия в одной транзакции: отметки присутствия и оценки сверяются
+// с сохраненными, меняются только отличающиеся записи, поэтому повторное сохранение ничего не создает.
+// Ученики должны входить в состав занятия; занятие отмечается проведенным. Если журнал одновременно сохраняет
+// другой запрос, возвращается db.ErrDuplicate или db.ErrVersionConflict и ничего не записывается <--->
+// [ENG] SaveLessonJournal saves the journal of the session in a single transaction: presence marks and grades are
+// compared with the stored ones and only the differing records are written, so saving again creates nothing.
+// The students must be on the lesson's roster; the occurrence is marked held. If another request saves the journal
+// concurrently, db.ErrDuplicate or db.ErrVersionConflict is returned and nothing is written
+func (m *Managers) SaveLessonJournal(ctx context.Context, lessonID int, date time.Time, changes JournalChanges) (*domain.Journal, error) {
+	lesson, occurrence, roster, err := m.journalSession(ctx, lessonID, date)
+	if err != nil {
+		return nil, err
+	}
+	if occurrence.Status == domain.OccurrenceCancelled {
+		return nil, fmt.Errorf("%w: lesson %d on %s is cancelled", domain.ErrInvalidJournal, lessonID, domain.ToDMY(occurrence.Date))
+	}
+	var seen []int
+	for _, mark := range changes.Marks {
+		if !slices.ContainsFunc(roster, func(s *domain.Student) bool { return s.StudentID == mark.StudentID }) {
+			return nil, fmt.Errorf("%w: student %d is not on the roster of lesson %d", domain.ErrInvalidJournal, mark.StudentID, lessonID)
+		}
+		if slices.Contains(seen, mark.StudentID) {
+			return nil, fmt.Errorf("%w: student %d is listed twice", domain.ErrInvalidJournal, mark.StudentID)
+		}
+		seen = append(seen, mark.StudentID)
+	}
+
+	err = engine.RunInTx(ctx, m.Occurrence.db, func(tx *sql.Tx) error {
+		attendance, assessments := m.Attendance.Repo.WithTx(tx), m.Assessment.Repo.WithTx(tx)
+		session, err := loadSession(ctx, attendance, assessments, occurrence)
+		if err != nil {
+			return err
+		}
+		journal := domain.NewJournal(lesson, session, roster)
+
+		for _, mark := range changes.Marks {
+			entry := journal.Entry(mark.StudentID)
+			if err := m.saveAttendance(ctx, tx, occurrence, entry, mark.Present); err != nil {
+				return err
+			}
+			if err := m.saveGrades(ctx, tx, occurrence, entry, mark.Grades); err != nil {
+				return err
+			}
+		}
+
+		if occurrence.Status == domain.OccurrenceHeld && changes.Topic == nil && changes.Notes == nil {
+			return nil
+		}
+		occurrence.Status = domain.OccurrenceHeld
+		if changes.Topic != nil {
+			occurrence.Topic = changes.Topic
+		}
+		if changes.Notes != nil {
+			occurrence.Notes = changes.Notes
+		}
+		if err := occurrence.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		return m.Occurrence.Repo.WithTx(tx).Update(ctx, occurrence)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.LessonJournal(ctx, lessonID, date)
+}
+
+// journalSession загружает занятие, его проведение в дату date и состав
+func (m *Managers) journalSession(ctx context.Context, lessonID int, date time.Time) (*domain.Lesson, *domain.LessonOccurrence, []*domain.Student, error) {
+	lesson, err := m.Lesson.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	occurrence, err := m.LessonOccurrenceOn(ctx, lessonID, date)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if lesson.StudentID != nil {
+		student, err := m.Student.GetByID(ctx, *lesson.StudentID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("student %d: %w", *lesson.StudentID, err)
+		}
+		return lesson, occurrence, []*domain.Student{student}, nil
+	}
+	roster, err := m.Student.GetByGroup(ctx, lesson.GroupID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return lesson, occurrence, roster, nil
+}
+
+// saveAttendance создает отметку присутствия или обновляет отличающуюся; запись без привязки привязывается к занятию
+func (m *Managers) saveAttendance(ctx context.Context, tx *sql.Tx, occurrence *domain.LessonOccurrence, entry *domain.JournalEntry, present bool) error {
+	record := entry.Attendance
+	if record != nil && record.PresenceMark == present && record.OccurrenceID != nil {
+		return nil
+	}
+	exists := record != nil
+	if !exists {
+		record = &domain.StudentAttendance{
+			StudentID:      entry.Student.StudentID,
+			LessonID:       occurrence.LessonID,
+			AttendanceDate: occurrence.Date.Format("2006-01-02"),
+		}
+	}
+	record.PresenceMark, record.OccurrenceID = present, &occurrence.OccurrenceID
+	// Дата из БД может прийти в RFC3339
+	record.AttendanceDate = occurrence.Date.Format("2006-01-02")
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	repo := m.Attendance.Repo.WithTx(tx)
+	var err error
+	if exists {
+		err = repo.Update(ctx, record)
+	} else {
+		err = repo.Create(ctx, record)
+	}
+	if err != nil {
+		return fmt.Errorf("student %d: attendance: %w", entry.Student.StudentID, err)
+	}
+	return nil
+}
+
+// saveGrades приводит оценки ученика за занятие к списку grades: новые создаются, измененные обновляются, остальные удаляются
+func (m *Managers) saveGrades(ctx context.Context, tx *sql.Tx, occurrence *domain.LessonOccurrence, entry *domain.JournalEntry, grades []JournalGrade) error {
+	repo := m.Assessment.Repo.WithTx(tx)
+	studentID := entry.Student.StudentID
+	stale := slices.Clone(entry.Grades)
+
+	for _, grade := range grades {
+		assessment := &domain.StudentAssessment{StudentID: studentID, LessonID: occurrence.LessonID}
+		exists := grade.AssessmentNoteID != 0
+		if exists {
+			i := slices.IndexFunc(stale, func(a *domain.StudentAssessment) bool { return a.AssessmentNoteID == grade.AssessmentNoteID })
+			if i < 0 {
+				return fmt.Errorf("%w: assessment %d is not a grade of student %d for this session",
+					domain.ErrInvalidJournal, grade.AssessmentNoteID, studentID)
+			}
+			assessment = stale[i]
+			stale = slices.Delete(stale, i, i+1)
+			if assessment.TaskType == grade.TaskType && assessment.Grade == grade.Grade && assessment.OccurrenceID != nil {
+				continue
+			}
+		}
+		assessment.TaskType, assessment.Grade = grade.TaskType, grade.Grade
+		assessment.AssessmentDate, assessment.OccurrenceID = domain.DateOnly(occurrence.Date), &occurrence.OccurrenceID
+		if err := assessment.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+
+		var err error
+		if exists {
+			err = repo.Update(ctx, assessment)
+		} else {
+			err = repo.Create(ctx, assessment)
+		}
+		if err != nil {
+			return fmt.Errorf("student %d: grade: %w", studentID, err)
+		}
+	}
+
+	for _, assessment := range stale {
+		if err := repo.Delete(ctx, assessment.AssessmentNoteID); err != nil {
+			return fmt.Errorf("student %d: delete grade %d: %w", studentID, assessment.AssessmentNoteID, err)
+		}
+	}
+	return nil
+}
@@ -129,62 +129,19 @@ func (m *LessonManager) GetByAudience(ctx context.Context, audienceID int) ([]*d
 	return lessons, nil
 }
 
-// [RU] CheckEmployeeAvailability проверяет, свободен ли преподаватель в указанное время <--->
-// [ENG] CheckEmployeeAvailability checks if the employee is available during the specified time
-func (m *LessonManager) CheckEmployeeAvailability(
-	ctx context.Context,
-	employeeID int,
-	startTime time.Time,
-	endTime time.Time,
-	excludeLessonID int,
-) (bool, error) {
-	return m.repo.CheckEmployeeAvailability(ctx, employeeID, startTime, endTime, excludeLessonID)
-}
-
-// [RU] CheckAudienceAvailability проверяет, свободна ли аудитория в указанное время <--->
-// [ENG] CheckAudienceAvailability checks if the audience is available during the specified time
-func (m *LessonManager) CheckAudienceAvailability(
-	ctx context.Context,
-	audienceID int,
-	startTime time.Time,
-	endTime time.Time,
-	excludeLessonID int,
-) (bool, error) {
-	return m.repo.CheckAudienceAvailability(ctx, audienceID, startTime, endTime, excludeLessonID)
-}
-
 // [RU] BulkCreate массово создает занятия в транзакции <--->
 // [ENG] BulkCreate creates multiple lessons in a transaction
 func (m *LessonManager) BulkCreate(ctx context.Context, lessons []*domain.Lesson) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Lesson, int]) error {
+		for _, lesson := range lessons {
+			if err := lesson.Validate(); err != nil {
+				return fmt.Errorf("validation failed for lesson %v: %w", lesson, err)
+			}
+
+			if err := txRepo.Create(ctx, lesson); err != nil {
+				return fmt.Errorf("create failed for lesson %v: %w", lesson, err)
+			}
 		}
-	}()
-
-	for _, lesson := range lessons {
-		if err := lesson.Validate(); err != nil {
-			return fmt.Errorf("validation failed for lesson %v: %w", lesson, err)
-		}
-
-		if err := txRepo.Create(ctx, lesson); err != nil {
-			return fmt.Errorf("create failed for lesson %v: %w", lesson, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,302 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// LessonOccurrenceManager проведенные занятия; создаются и обновляются по расписанию через методы Managers
+type LessonOccurrenceManager struct {
+	*engine.BaseManager[int, domain.LessonOccurrence, *domain.LessonOccurrence]
+	db *sql.DB
+}
+
+// NewLessonOccurrenceManager создает новый экземпляр LessonOccurrenceManager
+func NewLessonOccurrenceManager(
+	repo db.Repository[domain.LessonOccurrence, int],
+	db *sql.DB,
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *LessonOccurrenceManager {
+	return &LessonOccurrenceManager{
+		BaseManager: engine.NewBaseManager[int, domain.LessonOccurrence, *domain.LessonOccurrence](repo, logger, txTimeout),
+		db:          db,
+	}
+}
+
+// [RU] OccurrenceAt возвращает занятие серии scheduleID в дату date (дата по правилу), создавая его при первом
+// обращении; дата, время, состояние и преподаватель обновляются по расписанию, исключениям, календарю и заменам <--->
+// [ENG] OccurrenceAt returns the occurrence of the scheduleID series on date (the date according to the rule),
+// creating it on first access; the date, time, status and teacher follow the schedule, exceptions, calendar and substitutions
+func (m *Managers) OccurrenceAt(ctx context.Context, scheduleID int, date time.Time) (*domain.LessonOccurrence, error) {
+	occurrence, err := m.expandOccurrence(ctx, scheduleID, date)
+	if err != nil {
+		return nil, err
+	}
+	return m.followOccurrence(ctx, occurrence)
+}
+
+// [RU] LessonOccurrenceOn возвращает занятие lessonID, проводимое в дату date (с учетом переносов). Из нескольких
+// занятий дня берется первое неотмененное <--->
+// [ENG] LessonOccurrenceOn returns the occurrence of lessonID held on date (moves applied). Of several occurrences
+// of the day the first one not cancelled is taken
+func (m *Managers) LessonOccurrenceOn(ctx context.Context, lessonID int, date time.Time) (*domain.LessonOccurrence, error) {
+	occurrences, err := m.ScheduleOccurrences(ctx, date, date, lessonID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("%w: lesson %d, %s", domain.ErrNoOccurrence, lessonID, domain.ToDMY(date))
+	}
+	chosen := occurrences[0]
+	for _, occurrence := range occurrences {
+		if !occurrence.Cancelled {
+			chosen = occurrence
+			break
+		}
+	}
+	return m.followOccurrence(ctx, chosen)
+}
+
+// [RU] LessonOccurrence возвращает занятие, обновленное по расписанию. Занятие, которого больше нет в серии
+// (правило изменилось), отмечается отмененным <--->
+// [ENG] LessonOccurrence returns the occurrence brought up to date with the schedule. An occurrence that is no longer
+// in the series (the rule has changed) is marked cancelled
+func (m *Managers) LessonOccurrence(ctx context.Context, occurrenceID int) (*domain.LessonOccurrence, error) {
+	stored, err := m.Occurrence.GetByID(ctx, occurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Status == domain.OccurrenceHeld {
+		return stored, nil
+	}
+
+	occurrence, err := m.expandOccurrence(ctx, stored.ScheduleID, stored.OccurrenceDate)
+	if errors.Is(err, domain.ErrNoOccurrence) {
+		if stored.Status != domain.OccurrenceCancelled {
+			stored.Status = domain.OccurrenceCancelled
+			if err := m.Occurrence.Update(ctx, stored); err != nil {
+				return nil, err
+			}
+		}
+		return stored, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.followOccurrence(ctx, occurrence)
+}
+
+// [RU] LessonSession возвращает журнал занятия: занятие, посещаемость и оценки. Записи, созданные без привязки
+// к занятию, подбираются по занятию (Lesson) и дате проведения <--->
+// [ENG] LessonSession returns the journal of an occurrence: the occurrence, its attendance and grades. Records created
+// without a link to the occurrence are matched by the lesson and the date it is held
+func (m *Managers) LessonSession(ctx context.Context, occurrenceID int) (*domain.LessonSession, error) {
+	occurrence, err := m.LessonOccurrence(ctx, occurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	return loadSession(ctx, m.Attendance.Repo, m.Assessment.Repo, occurrence)
+}
+
+// loadSession загружает посещаемость и оценки занятия: привязанные к нему и записи занятия (Lesson) в дату проведения без привязки
+func loadSession(
+	ctx context.Context,
+	attendance db.Repository[domain.StudentAttendance, int],
+	assessments db.Repository[domain.StudentAssessment, int],
+	occurrence *domain.LessonOccurrence,
+) (*domain.LessonSession, error) {
+	session := &domain.LessonSession{Occurrence: occurrence}
+
+	linked := db.Condition{Field: "occurrence_id", Operator: "=", Value: occurrence.OccurrenceID}
+	unlinked := []db.Condition{
+		{Field: "occurrence_id", Operator: "IS NULL"},
+		{Field: "lesson_id", Operator: "=", Value: occurrence.LessonID},
+	}
+
+	for _, conditions := range [][]db.Condition{
+		{linked},
+		append(unlinked, db.Condition{Field: "attendance_date", Operator: "=", Value: occurrence.Date.Format("2006-01-02")}),
+	} {
+		found, err := attendance.List(ctx, db.Filter{Conditions: conditions, OrderBy: "student_id, attendance_note_id"})
+		if err != nil {
+			return nil, fmt.Errorf("occurrence %d: failed to list attendance: %w", occurrence.OccurrenceID, err)
+		}
+		session.Attendance = append(session.Attendance, found...)
+	}
+	for _, conditions := range [][]db.Condition{
+		{linked},
+		append(unlinked, db.Condition{Field: "assessment_date", Operator: "=", Value: domain.DateOnly(occurrence.Date)}),
+	} {
+		found, err := assessments.List(ctx, db.Filter{Conditions: conditions, OrderBy: "student_id, assessment_note_id"})
+		if err != nil {
+			return nil, fmt.Errorf("occurrence %d: failed to list assessments: %w", occurrence.OccurrenceID, err)
+		}
+		session.Assessments = append(session.Assessments, found...)
+	}
+	return session, nil
+}
+
+// [RU] expandOccurrence разворачивает одно занятие серии в дату date (дата по правилу) с исключением и календарем <--->
+// [ENG] expandOccurrence expands a single occurrence of the series on date (the rule's date) with its exception and the calendar
+func (m *Managers) expandOccurrence(ctx context.Context, scheduleID int, date time.Time) (domain.Occurrence, error) {
+	date = domain.DateOnly(date)
+	var schedule *domain.Schedule
+	var exception *domain.ScheduleException
+	err := engine.RunInTx(ctx, m.Schedule.db, func(tx *sql.Tx) error {
+		var err error
+		if schedule, err = m.occurrenceSchedule(ctx, tx, scheduleID, date); err != nil {
+			return err
+		}
+		exception, err = m.findException(ctx, tx, scheduleID, date)
+		return err
+	})
+	if err != nil {
+		return domain.Occurrence{}, err
+	}
+
+	occurrence := domain.NewOccurrence(schedule, date, exception)
+	calendar, err := m.Calendar.Load(ctx, date, date)
+	if err != nil {
+		return domain.Occurrence{}, err
+	}
+	// Перенос календаря меняет дату проведения: праздники проверяются уже в нее
+	probe := occurrence
+	calendar.Apply(&probe)
+	if !probe.Date.Equal(occurrence.Date) {
+		if calendar, err = m.Calendar.Load(ctx, minDate(date, probe.Date), maxDate(date, probe.Date)); err != nil {
+			return domain.Occurrence{}, err
+		}
+	}
+	calendar.Apply(&occurrence)
+	return occurrence, nil
+}
+
+// [RU] followOccurrence создает или обновляет занятие по развернутому занятию серии; проведенное занятие не меняется.
+// Если занятие одновременно создал или изменил другой запрос, оно перечитывается и сверяется еще раз <--->
+// [ENG] followOccurrence creates or updates the occurrence from the expanded occurrence of the series; a held occurrence
+// is left intact. If another request created or changed the occurrence concurrently, it is read and compared again
+func (m *Managers) followOccurrence(ctx context.Context, occurrence domain.Occurrence) (*domain.LessonOccurrence, error) {
+	lessonOccurrence, err := m.syncOccurrence(ctx, occurrence)
+	if errors.Is(err, db.ErrDuplicate) || errors.Is(err, db.ErrVersionConflict) {
+		lessonOccurrence, err = m.syncOccurrence(ctx, occurrence)
+	}
+	return lessonOccurrence, err
+}
+
+// syncOccurrence читает занятие и записывает его, если оно отстало от расписания
+func (m *Managers) syncOccurrence(ctx context.Context, occurrence domain.Occurrence) (*domain.LessonOccurrence, error) {
+	found, err := m.Occurrence.List(ctx, db.Filter{
+		Conditions: []db.Condition{
+			{Field: "schedule_id", Operator: "=", Value: occurrence.ScheduleID},
+			{Field: "occurrence_date", Operator: "=", Value: occurrence.OriginalDate},
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: failed to load occurrence: %w", occurrence.ScheduleID, err)
+	}
+	lessonOccurrence := &domain.LessonOccurrence{
+		ScheduleID:     occurrence.ScheduleID,
+		LessonID:       occurrence.LessonID,
+		OccurrenceDate: occurrence.OriginalDate,
+	}
+	if len(found) > 0 {
+		lessonOccurrence = found[0]
+	}
+	if lessonOccurrence.Status == domain.OccurrenceHeld {
+		return lessonOccurrence, nil
+	}
+
+	lesson, err := m.Lesson.GetByID(ctx, occurrence.LessonID)
+	if err != nil {
+		return nil, fmt.Errorf("lesson %d: %w", occurrence.LessonID, err)
+	}
+	substituteID, err := m.assignedSubstitute(ctx, occurrence.ScheduleID, occurrence.OriginalDate)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := lessonOccurrence.Follow(occurrence, lesson, substituteID)
+	switch {
+	case lessonOccurrence.OccurrenceID == 0:
+		err = m.Occurrence.Create(ctx, lessonOccurrence)
+	case changed:
+		err = m.Occurrence.Update(ctx, lessonOccurrence)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: occurrence for %s: %w", occurrence.ScheduleID, domain.ToDMY(occurrence.OriginalDate), err)
+	}
+	return lessonOccurrence, nil
+}
+
+// assignedSubstitute возвращает назначенного заменяющего на занятии серии; nil - замены нет
+func (m *Managers) assignedSubstitute(ctx context.Context, scheduleID int, date time.Time) (*int, error) {
+	found, err := m.Substitution.List(ctx, db.Filter{
+		Conditions: []db.Condition{
+			{Field: "schedule_id", Operator: "=", Value: scheduleID},
+			{Field: "occurrence_date", Operator: "=", Value: domain.DateOnly(date)},
+			{Field: "status", Operator: "=", Value: domain.SubstitutionAssigned},
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: failed to load substitution: %w", scheduleID, err)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0].SubstituteID, nil
+}
+
+// [RU] checkOccurrenceLinks подписывает проверку ссылок посещаемости и оценок на занятие: запись должна относиться
+// к тому же занятию (Lesson) и дате проведения, что и занятие журнала <--->
+// [ENG] checkOccurrenceLinks subscribes the check of attendance and assessment links to an occurrence: the record must
+// belong to the same lesson and the date the occurrence is held
+func (m *Managers) checkOccurrenceLinks() {
+	check := func(ctx context.Context, tx *sql.Tx, occurrenceID *int, lessonID int, date time.Time) error {
+		if occurrenceID == nil {
+			return nil
+		}
+		occurrence, err := m.Occurrence.Repo.WithTx(tx).GetByID(ctx, *occurrenceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: occurrence %d not found", domain.ErrInvalidOccurrence, *occurrenceID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load occurrence %d: %w", *occurrenceID, err)
+		}
+		if occurrence.LessonID != lessonID || !domain.DateOnly(occurrence.Date).Equal(domain.DateOnly(date)) {
+			return fmt.Errorf("%w: occurrence %d is lesson %d on %s", domain.ErrInvalidOccurrence,
+				occurrence.OccurrenceID, occurrence.LessonID, domain.ToDMY(occurrence.Date))
+		}
+		return nil
+	}
+
+	checkAttendance := func(ctx context.Context, e engine.Event[domain.StudentAttendance]) error {
+		if e.New.OccurrenceID == nil {
+			return nil
+		}
+		date, err := time.Parse("2006-01-02", e.New.AttendanceDate)
+		if err != nil {
+			return fmt.Errorf("%w: attendance_date %q", domain.ErrInvalidOccurrence, e.New.AttendanceDate)
+		}
+		return check(ctx, e.Tx, e.New.OccurrenceID, e.New.LessonID, date)
+	}
+	m.Attendance.Events().Subscribe(engine.BeforeCreate, checkAttendance)
+	m.Attendance.Events().Subscribe(engine.BeforeUpdate, checkAttendance)
+
+	checkAssessment := func(ctx context.Context, e engine.Event[domain.StudentAssessment]) error {
+		return check(ctx, e.Tx, e.New.OccurrenceID, e.New.LessonID, e.New.AssessmentDate)
+	}
+	m.Assessment.Events().Subscribe(engine.BeforeCreate, checkAssessment)
+	m.Assessment.Events().Subscribe(engine.BeforeUpdate, checkAssessment)
+}
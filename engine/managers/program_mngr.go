@@ -155,10 +155,10 @@ func (m *ProgrammManager) CheckNameUnique(ctx context.Context, name string, excl
 	return len(programms) == 0, nil
 }
 
-// [RU] SearchByDescription возвращает программы, содержащие указанный текст в описании <--->
-// [ENG] SearchByDescription returns programs containing the specified text in the description
+// [RU] SearchByDescription полнотекстовый поиск программ по названию, типу, инструменту и описанию <--->
+// [ENG] SearchByDescription full-text search of programs by name, type, instrument and description
 func (m *ProgrammManager) SearchByDescription(ctx context.Context, searchText string) ([]*domain.Programm, error) {
-	programms, err := m.repo.SearchByDescriptionFullText(ctx, searchText)
+	programms, err := m.List(ctx, db.Filter{Search: searchText})
 	if err != nil {
 		m.Logger.Error("SearchByDescription failed",
 			logger.Field{Key: "error", Value: err},
@@ -230,43 +230,24 @@ func (m *ProgrammManager) Update(ctx context.Context, programm *domain.Programm)
 // [RU] BulkCreate массово создает программы в транзакции <--->
 // [ENG] BulkCreate creates multiple programs in a transaction
 func (m *ProgrammManager) BulkCreate(ctx context.Context, programms []*domain.Programm) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, prog := range programms {
-		if err := prog.Validate(); err != nil {
-			return fmt.Errorf("validation failed for programm %v: %w", prog, err)
-		}
-
-		isUnique, err := m.CheckNameUnique(ctx, prog.ProgrammName, 0)
-		if err != nil {
-			return fmt.Errorf("name uniqueness check failed: %w", err)
-		}
-		if !isUnique {
-			return fmt.Errorf("programm name %s already exists", prog.ProgrammName)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Programm, int]) error {
+		for _, prog := range programms {
+			if err := prog.Validate(); err != nil {
+				return fmt.Errorf("validation failed for programm %v: %w", prog, err)
+			}
+
+			isUnique, err := m.CheckNameUnique(ctx, prog.ProgrammName, 0)
+			if err != nil {
+				return fmt.Errorf("name uniqueness check failed: %w", err)
+			}
+			if !isUnique {
+				return fmt.Errorf("programm name %s already exists", prog.ProgrammName)
+			}
+
+			if err := txRepo.Create(ctx, prog); err != nil {
+				return fmt.Errorf("create failed for programm %v: %w", prog, err)
+			}
 		}
-
-		if err := txRepo.Create(ctx, prog); err != nil {
-			return fmt.Errorf("create failed for programm %v: %w", prog, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
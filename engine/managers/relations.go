@@ -0,0 +1,43 @@
+package managers
+
+import (
+	"GO_Music/domain"
+	"GO_Music/engine"
+)
+
+// [RU] registerRelations объявляет связи сущностей для Filter.Preloads и ?include= <--->
+// [ENG] registerRelations declares entity relations for Filter.Preloads and ?include=
+func (m *Managers) registerRelations() {
+	lesson := m.Lesson.BaseManager
+	lesson.RegisterRelation("employee", engine.BelongsTo(m.Employee.BaseManager,
+		func(l *domain.Lesson) (int, bool) { return l.EmployeeID, true },
+		func(l *domain.Lesson, e *domain.Employee) { l.Employee = e },
+	))
+	lesson.RegisterRelation("group", engine.BelongsTo(m.StudyGroup.BaseManager,
+		func(l *domain.Lesson) (int, bool) { return l.GroupID, true },
+		func(l *domain.Lesson, g *domain.StudyGroup) { l.Group = g },
+	))
+	lesson.RegisterRelation("subject", engine.BelongsTo(m.Subject.BaseManager,
+		func(l *domain.Lesson) (int, bool) { return l.SubjectID, true },
+		func(l *domain.Lesson, s *domain.Subject) { l.Subject = s },
+	))
+	lesson.RegisterRelation("audience", engine.BelongsTo(m.Audience.BaseManager,
+		func(l *domain.Lesson) (int, bool) {
+			if l.AudienceID == nil {
+				return 0, false
+			}
+			return *l.AudienceID, true
+		},
+		func(l *domain.Lesson, a *domain.Audience) { l.Audience = a },
+	))
+
+	student := m.Student.BaseManager
+	student.RegisterRelation("group", engine.BelongsTo(m.StudyGroup.BaseManager,
+		func(s *domain.Student) (int, bool) { return s.GroupID, true },
+		func(s *domain.Student, g *domain.StudyGroup) { s.Group = g },
+	))
+	student.RegisterRelation("programm", engine.BelongsTo(m.Programm.BaseManager,
+		func(s *domain.Student) (int, bool) { return s.MusprogrammID, true },
+		func(s *domain.Student, p *domain.Programm) { s.Programm = p },
+	))
+}
@@ -0,0 +1,381 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// ScheduleExceptionManager исключения отдельных занятий серий расписания; изменение - через методы Managers
+type ScheduleExceptionManager struct {
+	*engine.BaseManager[int, domain.ScheduleException, *domain.ScheduleException]
+}
+
+// NewScheduleExceptionManager создает новый экземпляр ScheduleExceptionManager
+func NewScheduleExceptionManager(
+	repo db.Repository[domain.ScheduleException, int],
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *ScheduleExceptionManager {
+	return &ScheduleExceptionManager{
+		BaseManager: engine.NewBaseManager[int, domain.ScheduleException, *domain.ScheduleException](repo, logger, txTimeout),
+	}
+}
+
+// [RU] ScheduleOccurrences разворачивает серии расписания в занятия с датами в диапазоне [from, to] и применяет
+// исключения и учебный календарь: перенесенные из диапазона занятия пропускаются, перенесенные в него - добавляются,
+// занятия в закрытые дни отменяются. lessonID == 0 - все занятия; includeCancelled - вернуть и отмененные занятия.
+// Результат упорядочен по началу <--->
+// [ENG] ScheduleOccurrences expands schedule series into occurrences dated within [from, to] and applies
+// the exceptions and the academic calendar: occurrences moved out of the range are skipped, those moved into it
+// are added, occurrences on closed days are cancelled. lessonID == 0 means every lesson; includeCancelled also
+// returns cancelled occurrences. The result is ordered by start
+func (m *Managers) ScheduleOccurrences(ctx context.Context, from, to time.Time, lessonID int, includeCancelled bool) ([]domain.Occurrence, error) {
+	return m.expandSchedules(ctx, nil, from, to, lessonID, includeCancelled, nil)
+}
+
+// expandSchedules разворачивает расписание как ScheduleOccurrences, читая в транзакции tx (nil - вне транзакции).
+// pending - еще не записанная версия серии: она заменяет сохраненную с тем же ScheduleID
+func (m *Managers) expandSchedules(
+	ctx context.Context,
+	tx *sql.Tx,
+	from, to time.Time,
+	lessonID int,
+	includeCancelled bool,
+	pending *domain.Schedule,
+) ([]domain.Occurrence, error) {
+	from, to = domain.DateOnly(from), domain.DateOnly(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("range end %s is before its start %s", domain.ToDMY(to), domain.ToDMY(from))
+	}
+	scheduleRepo, exceptionRepo := m.Schedule.Repo, m.ScheduleExc.Repo
+	if tx != nil {
+		scheduleRepo, exceptionRepo = scheduleRepo.WithTx(tx), exceptionRepo.WithTx(tx)
+	}
+
+	calendar, err := m.Calendar.load(ctx, tx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// Занятия дат, перенесенных календарем в диапазон, могут лежать за его пределами
+	swapSources := calendar.SwapSources(from, to)
+	lo, hi := from, to
+	for _, source := range swapSources {
+		lo, hi = minDate(lo, source), maxDate(hi, source)
+	}
+
+	conditions := []db.Condition{
+		{Field: "schd_date_start", Operator: "<=", Value: hi},
+		{Field: "schd_date_end", Operator: ">=", Value: lo},
+	}
+	if lessonID != 0 {
+		conditions = append(conditions, db.Condition{Field: "lesson_id", Operator: "=", Value: lessonID})
+	}
+	schedules, err := scheduleRepo.List(ctx, db.Filter{Conditions: conditions, OrderBy: "schedule_id"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	if pending != nil {
+		schedules = slices.DeleteFunc(schedules, func(s *domain.Schedule) bool { return s.ScheduleID == pending.ScheduleID })
+		if lessonID == 0 || pending.LessonID == lessonID {
+			schedules = append(schedules, pending)
+		}
+	}
+
+	// Исключения занятий диапазона и занятий, перенесенных в него
+	exceptions, err := exceptionRepo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "occurrence_date", Operator: ">=", Value: lo},
+		{Field: "occurrence_date", Operator: "<=", Value: hi},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule exceptions: %w", err)
+	}
+	movedIn, err := exceptionRepo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "new_date", Operator: ">=", Value: from},
+		{Field: "new_date", Operator: "<=", Value: to},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule exceptions: %w", err)
+	}
+
+	byOccurrence := make(map[occurrenceKey]*domain.ScheduleException, len(exceptions))
+	for _, exception := range exceptions {
+		byOccurrence[occurrenceKey{exception.ScheduleID, domain.DateOnly(exception.OccurrenceDate)}] = exception
+	}
+
+	var occurrences []domain.Occurrence
+	add := func(occurrence domain.Occurrence) {
+		calendar.Apply(&occurrence)
+		if occurrence.Date.Before(from) || occurrence.Date.After(to) {
+			return // перенесено за пределы диапазона
+		}
+		if occurrence.Cancelled && !includeCancelled {
+			return
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	byID := make(map[int]*domain.Schedule, len(schedules))
+	for _, schedule := range schedules {
+		byID[schedule.ScheduleID] = schedule
+		dates, err := schedule.Occurrences(from, to)
+		if err != nil {
+			// Одна испорченная запись не должна скрывать все расписание
+			m.Schedule.Logger.Warn("Schedule rule skipped", logger.Int("schedule_id", schedule.ScheduleID), logger.Error(err))
+			continue
+		}
+		for _, source := range swapSources {
+			if has, err := schedule.HasOccurrence(source); err == nil && has && !slices.ContainsFunc(dates, source.Equal) {
+				dates = append(dates, source)
+			}
+		}
+		for _, date := range dates {
+			add(domain.NewOccurrence(schedule, date, byOccurrence[occurrenceKey{schedule.ScheduleID, date}]))
+		}
+	}
+
+	// Занятия, перенесенные в диапазон с дат вне его; их серии могли не попасть в выборку
+	expanded := func(date time.Time) bool {
+		date = domain.DateOnly(date)
+		return !date.Before(from) && !date.After(to) || slices.ContainsFunc(swapSources, date.Equal)
+	}
+	var missing []int
+	for _, exception := range movedIn {
+		if expanded(exception.OccurrenceDate) {
+			continue
+		}
+		if _, ok := byID[exception.ScheduleID]; !ok && !slices.Contains(missing, exception.ScheduleID) {
+			missing = append(missing, exception.ScheduleID)
+		}
+	}
+	if len(missing) > 0 {
+		loaded, err := scheduleRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load schedules: %w", err)
+		}
+		for _, schedule := range loaded {
+			byID[schedule.ScheduleID] = schedule
+		}
+	}
+	for _, exception := range movedIn {
+		if expanded(exception.OccurrenceDate) {
+			continue
+		}
+		schedule, ok := byID[exception.ScheduleID]
+		if !ok || (lessonID != 0 && schedule.LessonID != lessonID) {
+			continue // серия удалена или относится к другому занятию
+		}
+		// Исключение, оставшееся от прежнего правила серии, не соответствует ни одному занятию
+		if has, err := schedule.HasOccurrence(exception.OccurrenceDate); err != nil || !has {
+			continue
+		}
+		add(domain.NewOccurrence(schedule, exception.OccurrenceDate, exception))
+	}
+
+	slices.SortFunc(occurrences, func(a, b domain.Occurrence) int {
+		if c := a.Start().Compare(b.Start()); c != 0 {
+			return c
+		}
+		return a.ScheduleID - b.ScheduleID
+	})
+	return occurrences, nil
+}
+
+// minDate возвращает более раннюю из дат
+func minDate(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// maxDate возвращает более позднюю из дат
+func maxDate(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// occurrenceKey адрес занятия серии: запись расписания и дата по правилу
+type occurrenceKey struct {
+	scheduleID int
+	date       time.Time
+}
+
+// [RU] EditOccurrence переносит одно занятие серии с даты date (дата по правилу): patch задает новую дату,
+// время начала и окончания и причину, nil - значение из правила. Отмена занятия при этом снимается.
+// Если в новое время преподаватель недоступен, возвращается *domain.AvailabilityError, если преподаватель,
+// аудитория, группа или студент заняты - *domain.ConflictError <--->
+// [ENG] EditOccurrence moves a single occurrence of the series from date (the date according to the rule): patch sets
+// the new date, start and end time and the reason, nil keeps the rule's value. A cancellation of the occurrence is lifted.
+// If the teacher is unavailable at the new time, a *domain.AvailabilityError is returned, if the teacher, room,
+// group or student is busy, a *domain.ConflictError
+func (m *Managers) EditOccurrence(ctx context.Context, scheduleID int, date time.Time, patch domain.ScheduleException) (*domain.ScheduleException, error) {
+	return m.saveException(ctx, scheduleID, date, func(schedule *domain.Schedule, exception *domain.ScheduleException) error {
+		exception.Cancelled = false
+		exception.NewDate = patch.NewDate
+		exception.TimeBegin = patch.TimeBegin
+		exception.TimeEnd = patch.TimeEnd
+		exception.Reason = patch.Reason
+		if exception.NewDate != nil {
+			newDate := domain.DateOnly(*exception.NewDate)
+			exception.NewDate = &newDate
+		}
+
+		occurrence := domain.NewOccurrence(schedule, date, exception)
+		if domain.ToTimeHM(occurrence.TimeBegin) >= domain.ToTimeHM(occurrence.TimeEnd) {
+			return fmt.Errorf("%w: occurrence must end after it begins", domain.ErrInvalidRule)
+		}
+		return nil
+	})
+}
+
+// [RU] CancelOccurrence отменяет одно занятие серии в дату date (дата по правилу); перенос занятия снимается <--->
+// [ENG] CancelOccurrence cancels a single occurrence of the series on date (the date according to the rule); a move is lifted
+func (m *Managers) CancelOccurrence(ctx context.Context, scheduleID int, date time.Time, reason *string) (*domain.ScheduleException, error) {
+	return m.saveException(ctx, scheduleID, date, func(_ *domain.Schedule, exception *domain.ScheduleException) error {
+		exception.Cancelled = true
+		exception.NewDate = nil
+		exception.TimeBegin = nil
+		exception.TimeEnd = nil
+		exception.Reason = reason
+		return nil
+	})
+}
+
+// [RU] ResetOccurrence удаляет исключение занятия в дату date: занятие снова идет по правилу.
+// Занятие без исключения не считается ошибкой <--->
+// [ENG] ResetOccurrence deletes the exception of the occurrence on date: the occurrence follows the rule again.
+// An occurrence without an exception is not an error
+func (m *Managers) ResetOccurrence(ctx context.Context, scheduleID int, date time.Time) error {
+	return engine.RunInTx(ctx, m.Schedule.db, func(tx *sql.Tx) error {
+		if _, err := m.occurrenceSchedule(ctx, tx, scheduleID, date); err != nil {
+			return err
+		}
+		exception, err := m.findException(ctx, tx, scheduleID, date)
+		if err != nil || exception == nil {
+			return err
+		}
+		if err := m.ScheduleExc.Repo.WithTx(tx).Delete(ctx, exception.ExceptionID); err != nil {
+			return fmt.Errorf("schedule exception %d: delete failed: %w", exception.ExceptionID, err)
+		}
+		return nil
+	})
+}
+
+// saveException создает или обновляет исключение занятия серии scheduleID в дату date; apply заполняет его поля
+func (m *Managers) saveException(
+	ctx context.Context,
+	scheduleID int,
+	date time.Time,
+	apply func(schedule *domain.Schedule, exception *domain.ScheduleException) error,
+) (*domain.ScheduleException, error) {
+	var saved *domain.ScheduleException
+	err := engine.RunInTx(ctx, m.Schedule.db, func(tx *sql.Tx) error {
+		schedule, err := m.occurrenceSchedule(ctx, tx, scheduleID, date)
+		if err != nil {
+			return err
+		}
+		exception, err := m.findException(ctx, tx, scheduleID, date)
+		if err != nil {
+			return err
+		}
+		exists := exception != nil
+		if !exists {
+			exception = &domain.ScheduleException{ScheduleID: scheduleID, OccurrenceDate: domain.DateOnly(date)}
+		}
+
+		if err := apply(schedule, exception); err != nil {
+			return err
+		}
+		if err := exception.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+
+		repo := m.ScheduleExc.Repo.WithTx(tx)
+		if exists {
+			err = repo.Update(ctx, exception)
+		} else {
+			err = repo.Create(ctx, exception)
+		}
+		if err != nil {
+			return fmt.Errorf("schedule %d: exception for %s: %w", scheduleID, domain.ToDMY(date), err)
+		}
+
+		if !exception.Cancelled {
+			if err := m.checkOccurrence(ctx, tx, domain.NewOccurrence(schedule, date, exception)); err != nil {
+				return err
+			}
+		}
+		saved = exception
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// checkOccurrence проверяет перенесенное или возвращенное занятие: преподаватель должен быть доступен,
+// а ресурсы занятия - свободны от других занятий
+func (m *Managers) checkOccurrence(ctx context.Context, tx *sql.Tx, occurrence domain.Occurrence) error {
+	lesson, err := m.Lesson.Repo.WithTx(tx).GetByID(ctx, occurrence.LessonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // ссылку на занятие проверяет внешний ключ
+	}
+	if err != nil {
+		return fmt.Errorf("lesson %d: %w", occurrence.LessonID, err)
+	}
+	availability, err := m.Availability.load(ctx, tx, lesson.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if err := availability.Check(lesson.EmployeeID, occurrence.Date, occurrence.TimeBegin, occurrence.TimeEnd); err != nil {
+		return err
+	}
+	return m.vetoOccurrenceConflicts(ctx, tx, lesson, occurrence)
+}
+
+// occurrenceSchedule загружает серию и проверяет, что в дату date у нее есть занятие
+func (m *Managers) occurrenceSchedule(ctx context.Context, tx *sql.Tx, scheduleID int, date time.Time) (*domain.Schedule, error) {
+	schedule, err := m.Schedule.Repo.WithTx(tx).GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: %w", scheduleID, err)
+	}
+	has, err := schedule.HasOccurrence(date)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: %w", scheduleID, err)
+	}
+	if !has {
+		return nil, fmt.Errorf("%w: schedule %d, %s", domain.ErrNoOccurrence, scheduleID, domain.ToDMY(date))
+	}
+	return schedule, nil
+}
+
+// findException ищет исключение занятия; nil - занятие идет по правилу
+func (m *Managers) findException(ctx context.Context, tx *sql.Tx, scheduleID int, date time.Time) (*domain.ScheduleException, error) {
+	found, err := m.ScheduleExc.Repo.WithTx(tx).List(ctx, db.Filter{
+		Conditions: []db.Condition{
+			{Field: "schedule_id", Operator: "=", Value: scheduleID},
+			{Field: "occurrence_date", Operator: "=", Value: domain.DateOnly(date)},
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schedule %d: failed to load exception: %w", scheduleID, err)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
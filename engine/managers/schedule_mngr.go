@@ -53,12 +53,12 @@ func (m *ScheduleManager) GetByLesson(ctx context.Context, lessonID int) ([]*dom
 	return schedules, nil
 }
 
-// [RU] GetByDay возвращает расписание на конкретный день недели <--->
-// [ENG] GetByDay returns the schedule for a specific day of the week
+// [RU] GetByDay возвращает расписание на конкретный день недели, включая правила на несколько дней <--->
+// [ENG] GetByDay returns the schedule for a specific day of the week, multi-day rules included
 func (m *ScheduleManager) GetByDay(ctx context.Context, dayWeek string) ([]*domain.Schedule, error) {
 	schedules, err := m.List(ctx, db.Filter{
 		Conditions: []db.Condition{
-			{Field: "day_week", Operator: "=", Value: dayWeek},
+			{Field: "day_week", Operator: "LIKE", Value: "%" + dayWeek + "%"},
 		},
 		OrderBy: "time_begin",
 	})
@@ -92,30 +92,6 @@ func (m *ScheduleManager) GetCurrentSchedule(ctx context.Context) ([]*domain.Sch
 	return schedules, nil
 }
 
-// [RU] CheckTimeConflict проверяет наличие конфликтов в расписании <--->
-// [ENG] CheckTimeConflict checks for conflicts in the schedule
-func (m *ScheduleManager) CheckTimeConflict(ctx context.Context, dayWeek, timeBegin, timeEnd string, excludeID int) (bool, error) {
-	conflicts, err := m.List(ctx, db.Filter{
-		Conditions: []db.Condition{
-			{Field: "day_week", Operator: "=", Value: dayWeek},
-			{Field: "time_begin", Operator: "<", Value: timeEnd},
-			{Field: "time_end", Operator: ">", Value: timeBegin},
-			{Field: "schedule_id", Operator: "!=", Value: excludeID},
-		},
-		Limit: 1,
-	})
-	if err != nil {
-		m.Logger.Error("CheckTimeConflict failed",
-			logger.Field{Key: "error", Value: err},
-			logger.Field{Key: "day_week", Value: dayWeek},
-			logger.Field{Key: "time_begin", Value: timeBegin},
-			logger.Field{Key: "time_end", Value: timeEnd},
-		)
-		return false, fmt.Errorf("failed to check schedule conflict: %w", err)
-	}
-	return len(conflicts) > 0, nil
-}
-
 // [RU] GetByDateRange возвращает расписание в указанном временном периоде <--->
 // [ENG] GetByDateRange returns the schedule in the specified date range
 func (m *ScheduleManager) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*domain.Schedule, error) {
@@ -137,139 +113,76 @@ func (m *ScheduleManager) GetByDateRange(ctx context.Context, startDate, endDate
 	return schedules, nil
 }
 
-// [RU] GenerateSchedule генерирует расписание на основе шаблона (повторяющихся событий) <--->
-// [ENG] GenerateSchedule generates a schedule based on a template (recurring events)
+// [RU] GenerateSchedule создает по шаблону еженедельную серию в день template.DayWeek до даты until.
+// Серия хранится одной записью с правилом RRULE, занятия разворачиваются при чтении (см. Managers.ScheduleOccurrences),
+// поэтому праздники и переносы учебного календаря действуют и на серии, созданные до них <--->
+// [ENG] GenerateSchedule creates a weekly series on template.DayWeek until the until date from a template.
+// The series is stored as a single entry with an RRULE, occurrences are expanded on read (see Managers.ScheduleOccurrences),
+// so academic calendar holidays and swaps also apply to series created before them
 func (m *ScheduleManager) GenerateSchedule(ctx context.Context, template *domain.Schedule, until time.Time) error {
-	currentDate := template.SchdDateStart
-	dayMap := map[string]time.Weekday{
-		"Понедельник": time.Monday,
-		"Вторник":     time.Tuesday,
-		"Среда":       time.Wednesday,
-		"Четверг":     time.Thursday,
-		"Пятница":     time.Friday,
-		"Суббота":     time.Saturday,
-		"Воскресенье": time.Sunday,
-	}
-
-	targetWeekday, ok := dayMap[template.DayWeek]
+	day, ok := domain.ParseWeekday(template.DayWeek)
 	if !ok {
 		return fmt.Errorf("invalid day week: %s", template.DayWeek)
 	}
 
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for currentDate.Before(until) {
-		for currentDate.Weekday() != targetWeekday {
-			currentDate = currentDate.Add(24 * time.Hour)
-		}
-
-		if currentDate.After(until) {
-			break
-		}
-
-		// Создаем новую запись расписания
-		newSchedule := &domain.Schedule{
-			LessonID:      template.LessonID,
-			DayWeek:       template.DayWeek,
-			TimeBegin:     template.TimeBegin,
-			TimeEnd:       template.TimeEnd,
-			SchdDateStart: currentDate,
-			SchdDateEnd:   currentDate,
-		}
-
-		// Проверяем конфликты времени
-		hasConflict, err := m.CheckTimeConflict(ctx, template.DayWeek, template.TimeBegin.Format("15:04"), template.TimeEnd.Format("15:04"), 0)
-		if err != nil {
-			return fmt.Errorf("failed to check time conflict: %w", err)
-		}
-		if hasConflict {
-			return fmt.Errorf("time conflict detected for %s at %s-%s",
-				template.DayWeek, template.TimeBegin.Format("15:04"), template.TimeEnd.Format("15:04"))
-		}
-
-		if err := txRepo.Create(ctx, newSchedule); err != nil {
-			return fmt.Errorf("failed to create schedule for date %s: %w",
-				currentDate.Format("2006-01-02"), err)
-		}
-
-		currentDate = currentDate.Add(7 * 24 * time.Hour)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+	until = domain.DateOnly(until)
+	rule := domain.Recurrence{Interval: 1, ByDay: []time.Weekday{day}, Until: &until}
+	rrule := rule.String()
+	template.RRule = &rrule
+	return m.Create(ctx, template)
 }
 
-// [RU] Create создает новую запись расписания <--->
-// [ENG] Create creates a new schedule entry
+// [RU] Create создает новую запись расписания; DayWeek и SchdDateEnd выводятся из правила повторения.
+// Конфликты по преподавателю, аудитории, группе и студенту проверяет Managers (см. checkConflicts) <--->
+// [ENG] Create creates a new schedule entry; DayWeek and SchdDateEnd are derived from the recurrence rule.
+// Managers checks conflicts by teacher, room, group and student (see checkConflicts)
 func (m *ScheduleManager) Create(ctx context.Context, schedule *domain.Schedule) error {
-	if err := schedule.Validate(); err != nil {
-		m.Logger.Error("Validation failed",
+	if err := m.prepare(schedule); err != nil {
+		return err
+	}
+
+	if err := m.Repo.Create(ctx, schedule); err != nil {
+		m.Logger.Error("Create failed",
 			logger.Field{Key: "error", Value: err},
 			logger.Field{Key: "schedule", Value: schedule},
 		)
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("create failed: %w", err)
 	}
+	return nil
+}
 
-	hasConflict, err := m.CheckTimeConflict(ctx, schedule.DayWeek, schedule.TimeBegin.Format("15:04"), schedule.TimeEnd.Format("15:04"), 0)
-	if err != nil {
-		return fmt.Errorf("failed to check time conflict: %w", err)
-	}
-	if hasConflict {
-		return fmt.Errorf("time conflict detected for %s at %s-%s", schedule.DayWeek, schedule.TimeBegin.Format("15:04"), schedule.TimeEnd.Format("15:04"))
+// [RU] Update обновляет запись расписания; DayWeek и SchdDateEnd выводятся из правила повторения <--->
+// [ENG] Update updates the schedule entry; DayWeek and SchdDateEnd are derived from the recurrence rule
+func (m *ScheduleManager) Update(ctx context.Context, schedule *domain.Schedule) error {
+	if err := m.prepare(schedule); err != nil {
+		return err
 	}
 
-	if err := m.Repo.Create(ctx, schedule); err != nil {
-		m.Logger.Error("Create failed",
+	if err := m.Repo.Update(ctx, schedule); err != nil {
+		m.Logger.Error("Update failed",
 			logger.Field{Key: "error", Value: err},
 			logger.Field{Key: "schedule", Value: schedule},
 		)
-		return fmt.Errorf("create failed: %w", err)
+		return fmt.Errorf("update failed: %w", err)
 	}
 	return nil
 }
 
-// [RU] Update обновляет запись расписания <--->
-// [ENG] Update updates the schedule entry
-func (m *ScheduleManager) Update(ctx context.Context, schedule *domain.Schedule) error {
-	if err := schedule.Validate(); err != nil {
+// prepare нормализует и проверяет запись
+func (m *ScheduleManager) prepare(schedule *domain.Schedule) error {
+	if err := schedule.Normalize(); err != nil {
 		m.Logger.Error("Validation failed",
 			logger.Field{Key: "error", Value: err},
 			logger.Field{Key: "schedule", Value: schedule},
 		)
 		return fmt.Errorf("validation failed: %w", err)
 	}
-
-	hasConflict, err := m.CheckTimeConflict(ctx, schedule.DayWeek, schedule.TimeBegin.Format("15:04"), schedule.TimeEnd.Format("15:04"), schedule.ScheduleID)
-	if err != nil {
-		return fmt.Errorf("failed to check time conflict: %w", err)
-	}
-	if hasConflict {
-		return fmt.Errorf("time conflict detected for %s at %s-%s", schedule.DayWeek, schedule.TimeBegin.Format("15:04"), schedule.TimeEnd.Format("15:04"))
-	}
-
-	if err := m.Repo.Update(ctx, schedule); err != nil {
-		m.Logger.Error("Update failed",
+	if err := schedule.Validate(); err != nil {
+		m.Logger.Error("Validation failed",
 			logger.Field{Key: "error", Value: err},
 			logger.Field{Key: "schedule", Value: schedule},
 		)
-		return fmt.Errorf("update failed: %w", err)
+		return fmt.Errorf("validation failed: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,202 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+)
+
+// [RU] StudentCountMismatch расхождение сохраненной численности группы с числом ее активных студентов <--->
+// [ENG] StudentCountMismatch is a difference between a group's stored size and its number of active students
+type StudentCountMismatch struct {
+	GroupID   int
+	GroupName string
+	Stored    int
+	Actual    int
+}
+
+// [RU] maintainStudentCounts поддерживает StudyGroup.NumberOfStudents и вместимость групп: создание, удаление,
+// восстановление и перевод студента меняют численность групп в той же транзакции, переполнение группы
+// отменяет операцию с domain.ErrGroupFull, а освободившиеся места предлагаются листу ожидания <--->
+// [ENG] maintainStudentCounts keeps StudyGroup.NumberOfStudents and group capacity: creating, deleting,
+// restoring and transferring a student change the group sizes within the same transaction, overfilling
+// a group vetoes the operation with domain.ErrGroupFull, and freed places are offered to the waitlist
+func (m *Managers) maintainStudentCounts() {
+	students := m.Student.Events()
+	students.Subscribe(engine.AfterCreate, func(ctx context.Context, e engine.Event[domain.Student]) error {
+		return m.joinGroup(ctx, e.Tx, e.New)
+	})
+	students.Subscribe(engine.AfterRestore, func(ctx context.Context, e engine.Event[domain.Student]) error {
+		return m.joinGroup(ctx, e.Tx, e.New)
+	})
+	students.Subscribe(engine.AfterDelete, func(ctx context.Context, e engine.Event[domain.Student]) error {
+		if err := m.leaveGroup(ctx, e.Tx, e.Old.GroupID); err != nil {
+			return err
+		}
+		// Удаленный студент больше не ждет мест ни в одной группе
+		return m.withdrawWaitlist(ctx, e.Tx, db.Filter{Conditions: []db.Condition{
+			{Field: "student_id", Operator: "=", Value: e.Old.StudentID},
+		}})
+	})
+	students.Subscribe(engine.AfterUpdate, func(ctx context.Context, e engine.Event[domain.Student]) error {
+		if e.Old.GroupID == e.New.GroupID {
+			return nil
+		}
+		if err := m.leaveGroup(ctx, e.Tx, e.Old.GroupID); err != nil {
+			return err
+		}
+		return m.joinGroup(ctx, e.Tx, e.New)
+	})
+
+	groups := m.StudyGroup.Events()
+	groups.Subscribe(engine.AfterUpdate, func(ctx context.Context, e engine.Event[domain.StudyGroup]) error {
+		if equalIntPtr(e.Old.MaxStudents, e.New.MaxStudents) {
+			return nil
+		}
+		return m.offerFreePlaces(ctx, e.Tx, e.New.GroupID)
+	})
+	groups.Subscribe(engine.AfterDelete, func(ctx context.Context, e engine.Event[domain.StudyGroup]) error {
+		return m.withdrawWaitlist(ctx, e.Tx, db.Filter{Conditions: []db.Condition{
+			{Field: "group_id", Operator: "=", Value: e.Old.GroupID},
+		}})
+	})
+	// Пока группа в корзине, ее численность не ведется: после восстановления она пересчитывается
+	groups.Subscribe(engine.AfterRestore, func(ctx context.Context, e engine.Event[domain.StudyGroup]) error {
+		if _, err := m.recountStudents(ctx, e.Tx, e.New, true); err != nil {
+			return err
+		}
+		return m.offerFreePlaces(ctx, e.Tx, e.New.GroupID)
+	})
+}
+
+// joinGroup занимает место студента в его группе: проверяет вместимость, увеличивает численность
+// и закрывает записи студента в листе ожидания этой группы; группы в корзине пропускаются
+func (m *Managers) joinGroup(ctx context.Context, tx *sql.Tx, student *domain.Student) error {
+	repo := m.StudyGroup.Repo.WithTx(tx)
+	group, err := repo.GetByID(ctx, student.GroupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("study group %d: %w", student.GroupID, err)
+	}
+
+	// Место, предложенное самому студенту, для него свободно
+	offered, err := m.offeredPlaces(ctx, tx, group.GroupID, student.StudentID)
+	if err != nil {
+		return err
+	}
+	if free, limited := group.FreePlaces(offered); limited && free == 0 {
+		return fmt.Errorf("%w: group %d has %d of %d places taken, %d offered from the waitlist",
+			domain.ErrGroupFull, group.GroupID, group.NumberOfStudents, *group.MaxStudents, offered)
+	}
+
+	group.NumberOfStudents++
+	if err := repo.Update(ctx, group); err != nil {
+		return fmt.Errorf("study group %d: student count update failed: %w", group.GroupID, err)
+	}
+
+	_, err = m.resolveWaitlist(ctx, tx, db.Filter{Conditions: []db.Condition{
+		{Field: "group_id", Operator: "=", Value: group.GroupID},
+		{Field: "student_id", Operator: "=", Value: student.StudentID},
+	}}, domain.WaitlistEnrolled)
+	return err
+}
+
+// leaveGroup освобождает место в группе и предлагает его листу ожидания; группы в корзине пропускаются
+func (m *Managers) leaveGroup(ctx context.Context, tx *sql.Tx, groupID int) error {
+	repo := m.StudyGroup.Repo.WithTx(tx)
+	group, err := repo.GetByID(ctx, groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("study group %d: %w", groupID, err)
+	}
+
+	group.NumberOfStudents = max(group.NumberOfStudents-1, 0)
+	if err := repo.Update(ctx, group); err != nil {
+		return fmt.Errorf("study group %d: student count update failed: %w", groupID, err)
+	}
+	return m.offerFreePlaces(ctx, tx, groupID)
+}
+
+// equalIntPtr сравнивает необязательные числа: вместимость групп, ссылки занятия; nil равен только nil
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// recountStudents считает активных студентов группы в транзакции tx; fix - сохранить число, если оно расходится
+func (m *Managers) recountStudents(ctx context.Context, tx *sql.Tx, group *domain.StudyGroup, fix bool) (int, error) {
+	actual, err := m.Student.Repo.WithTx(tx).Count(ctx, db.Filter{
+		Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: group.GroupID}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("study group %d: student count failed: %w", group.GroupID, err)
+	}
+	if !fix || actual == group.NumberOfStudents {
+		return actual, nil
+	}
+
+	fixed := *group
+	fixed.NumberOfStudents = actual
+	if err := m.StudyGroup.Repo.WithTx(tx).Update(ctx, &fixed); err != nil {
+		return 0, fmt.Errorf("study group %d: student count update failed: %w", group.GroupID, err)
+	}
+	return actual, nil
+}
+
+// [RU] ReconcileStudentCounts пересчитывает численность всех активных групп по их студентам и возвращает
+// расхождения; fix - исправить их. Каждая группа проверяется в своей транзакции <--->
+// [ENG] ReconcileStudentCounts recounts the students of every active group and returns the mismatches;
+// fix corrects them. Every group is checked in its own transaction
+func (m *Managers) ReconcileStudentCounts(ctx context.Context, fix bool) ([]StudentCountMismatch, error) {
+	groups, err := m.StudyGroup.List(ctx, db.Filter{OrderBy: "group_id"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list study groups: %w", err)
+	}
+
+	var mismatches []StudentCountMismatch
+	for _, listed := range groups {
+		err := engine.RunInTx(ctx, m.StudyGroup.db, func(tx *sql.Tx) error {
+			group, err := m.StudyGroup.Repo.WithTx(tx).GetByID(ctx, listed.GroupID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil // группу удалили во время сверки
+			}
+			if err != nil {
+				return fmt.Errorf("study group %d: %w", listed.GroupID, err)
+			}
+
+			actual, err := m.recountStudents(ctx, tx, group, fix)
+			if err != nil {
+				return err
+			}
+			if actual == group.NumberOfStudents {
+				return nil
+			}
+			mismatches = append(mismatches, StudentCountMismatch{
+				GroupID:   group.GroupID,
+				GroupName: group.GroupName,
+				Stored:    group.NumberOfStudents,
+				Actual:    actual,
+			})
+			if !fix {
+				return nil
+			}
+			// Исправленная численность могла освободить места
+			return m.offerFreePlaces(ctx, tx, group.GroupID)
+		})
+		if err != nil {
+			return mismatches, err
+		}
+	}
+	return mismatches, nil
+}
@@ -71,10 +71,10 @@ func (m *StudentManager) GetByProgram(ctx context.Context, programID int) ([]*do
 	return students, nil
 }
 
-// [RU] SearchByName ищет студентов по ФИО <--->
-// [ENG] SearchByName searches for students by full name
+// [RU] SearchByName ищет студентов по ФИО, сначала самые релевантные <--->
+// [ENG] SearchByName searches for students by full name, most relevant first
 func (m *StudentManager) SearchByName(ctx context.Context, query string) ([]*domain.Student, error) {
-	students, err := m.repo.SearchByName(ctx, query)
+	students, err := m.List(ctx, db.Filter{Search: query})
 	if err != nil {
 		m.Logger.Error("SearchByName failed",
 			logger.Field{Key: "error", Value: err},
@@ -106,8 +106,10 @@ func (m *StudentManager) GetByBirthdayRange(ctx context.Context, from, to time.T
 	return students, nil
 }
 
-// [RU] TransferToGroup переводит студента в другую группу <--->
-// [ENG] TransferToGroup transfers a student to another group
+// [RU] TransferToGroup переводит студента в другую группу. В заполненную группу перевод не выполняется:
+// возвращается ошибка domain.ErrGroupFull, и студента можно поставить в лист ожидания группы <--->
+// [ENG] TransferToGroup transfers a student to another group. A full group is not entered:
+// domain.ErrGroupFull is returned and the student can be put on the group's waitlist
 func (m *StudentManager) TransferToGroup(ctx context.Context, studentID, newGroupID int) error {
 	studentPtr, err := m.GetByID(ctx, studentID)
 	if err != nil {
@@ -174,8 +176,8 @@ func (m *StudentManager) CheckPhoneNumberUnique(ctx context.Context, phone strin
 	return len(students) == 0, nil
 }
 
-// [RU] Create создает нового студента <--->
-// [ENG] Create creates a new student
+// [RU] Create создает нового студента; если его группа заполнена, возвращается ошибка domain.ErrGroupFull <--->
+// [ENG] Create creates a new student; if the student's group is full, domain.ErrGroupFull is returned
 func (m *StudentManager) Create(ctx context.Context, student *domain.Student) error {
 	if err := student.Validate(); err != nil {
 		m.Logger.Error("Validation failed",
@@ -239,45 +241,26 @@ func (m *StudentManager) Update(ctx context.Context, student *domain.Student) er
 // [RU] BulkCreate массово создает студентов в транзакции <--->
 // [ENG] BulkCreate creates multiple students in a transaction
 func (m *StudentManager) BulkCreate(ctx context.Context, students []*domain.Student) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, student := range students {
-		if err := student.Validate(); err != nil {
-			return fmt.Errorf("validation failed for student %v: %w", student, err)
-		}
-
-		if student.PhoneNumber != nil {
-			isUnique, err := m.CheckPhoneNumberUnique(ctx, *student.PhoneNumber, 0)
-			if err != nil {
-				return fmt.Errorf("phone uniqueness check failed: %w", err)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Student, int]) error {
+		for _, student := range students {
+			if err := student.Validate(); err != nil {
+				return fmt.Errorf("validation failed for student %v: %w", student, err)
 			}
-			if !isUnique {
-				return fmt.Errorf("phone number %s already exists", *student.PhoneNumber)
+
+			if student.PhoneNumber != nil {
+				isUnique, err := m.CheckPhoneNumberUnique(ctx, *student.PhoneNumber, 0)
+				if err != nil {
+					return fmt.Errorf("phone uniqueness check failed: %w", err)
+				}
+				if !isUnique {
+					return fmt.Errorf("phone number %s already exists", *student.PhoneNumber)
+				}
 			}
-		}
 
-		if err := txRepo.Create(ctx, student); err != nil {
-			return fmt.Errorf("create failed for student %v: %w", student, err)
+			if err := txRepo.Create(ctx, student); err != nil {
+				return fmt.Errorf("create failed for student %v: %w", student, err)
+			}
 		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -52,15 +52,10 @@ func (m *SubjectManager) GetByType(ctx context.Context, subjectType string) ([]*
 	return subjects, nil
 }
 
-// [RU] SearchByName ищет предметы по названию <--->
-// [ENG] SearchByName searches for subjects by name
+// [RU] SearchByName ищет предметы по названию, типу и описанию, сначала самые релевантные <--->
+// [ENG] SearchByName searches for subjects by name, type and description, most relevant first
 func (m *SubjectManager) SearchByName(ctx context.Context, name string) ([]*domain.Subject, error) {
-	subjects, err := m.List(ctx, db.Filter{
-		Conditions: []db.Condition{
-			{Field: "subject_name", Operator: "ILIKE", Value: "%" + name + "%"},
-		},
-		OrderBy: "subject_name",
-	})
+	subjects, err := m.List(ctx, db.Filter{Search: name})
 	if err != nil {
 		m.Logger.Error("SearchByName failed",
 			logger.Field{Key: "error", Value: err},
@@ -199,43 +194,24 @@ func (m *SubjectManager) Update(ctx context.Context, subject *domain.Subject) er
 // [RU] BulkCreate массово создает предметы в транзакции <--->
 // [ENG] BulkCreate creates multiple subjects in a transaction
 func (m *SubjectManager) BulkCreate(ctx context.Context, subjects []*domain.Subject) error {
-	tx, err := m.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	txRepo := m.Repo.WithTx(tx)
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	for _, subject := range subjects {
-		if err := subject.Validate(); err != nil {
-			return fmt.Errorf("validation failed for subject %v: %w", subject, err)
+	return m.ExecuteInTx(ctx, m.db, func(txRepo db.Repository[domain.Subject, int]) error {
+		for _, subject := range subjects {
+			if err := subject.Validate(); err != nil {
+				return fmt.Errorf("validation failed for subject %v: %w", subject, err)
+			}
+
+			isUnique, err := m.CheckNameUnique(ctx, subject.SubjectName, 0)
+			if err != nil {
+				return fmt.Errorf("uniqueness check failed: %w", err)
+			}
+			if !isUnique {
+				return fmt.Errorf("subject name %s already exists", subject.SubjectName)
+			}
+
+			if err := txRepo.Create(ctx, subject); err != nil {
+				return fmt.Errorf("create failed for subject %v: %w", subject, err)
+			}
 		}
-
-		isUnique, err := m.CheckNameUnique(ctx, subject.SubjectName, 0)
-		if err != nil {
-			return fmt.Errorf("uniqueness check failed: %w", err)
-		}
-		if !isUnique {
-			return fmt.Errorf("subject name %s already exists", subject.SubjectName)
-		}
-
-		if err := txRepo.Create(ctx, subject); err != nil {
-			return fmt.Errorf("create failed for subject %v: %w", subject, err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,374 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// SubstitutionManager замены преподавателей на отдельных занятиях; создание и назначение - через методы Managers
+type SubstitutionManager struct {
+	*engine.BaseManager[int, domain.Substitution, *domain.Substitution]
+	db *sql.DB
+}
+
+// NewSubstitutionManager создает новый экземпляр SubstitutionManager
+func NewSubstitutionManager(
+	repo db.Repository[domain.Substitution, int],
+	db *sql.DB,
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *SubstitutionManager {
+	return &SubstitutionManager{
+		BaseManager: engine.NewBaseManager[int, domain.Substitution, *domain.Substitution](repo, logger, txTimeout),
+		db:          db,
+	}
+}
+
+// CoverOptions занятия, которым нужна замена: одно занятие или все занятия преподавателя за период
+type CoverOptions struct {
+	LessonID   int // 0 - все занятия преподавателя EmployeeID
+	EmployeeID int
+	DateFrom   time.Time
+	DateTo     time.Time // DateFrom == DateTo - одно занятие
+	Reason     *string
+}
+
+// [RU] RequestCover отмечает занятия периода, которым нужна замена, и возвращает их замены. Занятие (Lesson)
+// не меняется; отмененные занятия пропускаются, уже отмеченные возвращаются как есть <--->
+// [ENG] RequestCover marks the occurrences of the period as needing cover and returns their substitutions.
+// The lesson is left intact; cancelled occurrences are skipped, those already marked are returned as they are
+func (m *Managers) RequestCover(ctx context.Context, opts CoverOptions) ([]*domain.Substitution, error) {
+	if opts.LessonID == 0 && opts.EmployeeID == 0 {
+		return nil, fmt.Errorf("%w: lesson_id or employee_id is required", domain.ErrInvalidSubstitution)
+	}
+	if opts.DateTo.Before(opts.DateFrom) {
+		return nil, fmt.Errorf("%w: date_to is before date_from", domain.ErrInvalidSubstitution)
+	}
+
+	var lessons []*domain.Lesson
+	if opts.LessonID != 0 {
+		lesson, err := m.Lesson.GetByID(ctx, opts.LessonID)
+		if err != nil {
+			return nil, fmt.Errorf("lesson %d: %w", opts.LessonID, err)
+		}
+		if opts.EmployeeID != 0 && lesson.EmployeeID != opts.EmployeeID {
+			return nil, fmt.Errorf("%w: lesson %d is not taught by employee %d", domain.ErrInvalidSubstitution, lesson.LessonID, opts.EmployeeID)
+		}
+		lessons = append(lessons, lesson)
+	} else {
+		var err error
+		lessons, err = m.Lesson.List(ctx, db.Filter{Conditions: []db.Condition{
+			{Field: "employee_id", Operator: "=", Value: opts.EmployeeID},
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lessons of employee %d: %w", opts.EmployeeID, err)
+		}
+	}
+	byID := make(map[int]*domain.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		byID[lesson.LessonID] = lesson
+	}
+
+	occurrences, err := m.ScheduleOccurrences(ctx, opts.DateFrom, opts.DateTo, opts.LessonID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*domain.Substitution
+	err = engine.RunInTx(ctx, m.Substitution.db, func(tx *sql.Tx) error {
+		repo := m.Substitution.Repo.WithTx(tx)
+		for _, occurrence := range occurrences {
+			lesson, ok := byID[occurrence.LessonID]
+			if !ok {
+				continue
+			}
+			existing, err := repo.List(ctx, db.Filter{
+				Conditions: []db.Condition{
+					{Field: "schedule_id", Operator: "=", Value: occurrence.ScheduleID},
+					{Field: "occurrence_date", Operator: "=", Value: occurrence.OriginalDate},
+				},
+				Limit: 1,
+			})
+			if err != nil {
+				return fmt.Errorf("schedule %d: failed to load substitution: %w", occurrence.ScheduleID, err)
+			}
+			if len(existing) > 0 {
+				result = append(result, existing[0])
+				continue
+			}
+
+			substitution := &domain.Substitution{
+				ScheduleID:     occurrence.ScheduleID,
+				LessonID:       lesson.LessonID,
+				OccurrenceDate: occurrence.OriginalDate,
+				Date:           occurrence.Date,
+				TimeBegin:      occurrence.TimeBegin,
+				TimeEnd:        occurrence.TimeEnd,
+				EmployeeID:     lesson.EmployeeID,
+				Status:         domain.SubstitutionOpen,
+				Reason:         opts.Reason,
+				CreatedAt:      time.Now(),
+			}
+			if err := substitution.Validate(); err != nil {
+				return fmt.Errorf("validation failed: %w", err)
+			}
+			if err := repo.Create(ctx, substitution); err != nil {
+				return fmt.Errorf("schedule %d: substitution for %s: %w", occurrence.ScheduleID, domain.ToDMY(occurrence.OriginalDate), err)
+			}
+			result = append(result, substitution)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// [RU] SubstituteCandidates подбирает заменяющих: преподавателей предмета занятия (SubjectDistribution),
+// которые доступны и свободны во время занятия <--->
+// [ENG] SubstituteCandidates suggests substitutes: teachers of the lesson's subject (SubjectDistribution)
+// who are available and free at the time of the occurrence
+func (m *Managers) SubstituteCandidates(ctx context.Context, substitutionID int) ([]*domain.Employee, error) {
+	substitution, err := m.Substitution.GetByID(ctx, substitutionID)
+	if err != nil {
+		return nil, err
+	}
+	lesson, err := m.Lesson.GetByID(ctx, substitution.LessonID)
+	if err != nil {
+		return nil, fmt.Errorf("lesson %d: %w", substitution.LessonID, err)
+	}
+
+	qualified, err := m.SubjectDistr.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "subject_id", Operator: "=", Value: lesson.SubjectID},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teachers of subject %d: %w", lesson.SubjectID, err)
+	}
+	busy, err := m.teachersBusy(ctx, nil, substitution)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, distribution := range qualified {
+		employeeID := distribution.EmployeeID
+		if employeeID == substitution.EmployeeID || slices.Contains(ids, employeeID) {
+			continue
+		}
+		if err := m.checkSubstitute(ctx, nil, substitution, employeeID, busy); err != nil {
+			if errors.Is(err, domain.ErrUnavailable) {
+				continue
+			}
+			return nil, err
+		}
+		ids = append(ids, employeeID)
+	}
+	if len(ids) == 0 {
+		return []*domain.Employee{}, nil
+	}
+
+	employees, err := m.Employee.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load employees: %w", err)
+	}
+	slices.SortFunc(employees, func(a, b *domain.Employee) int { return a.EmployeeID - b.EmployeeID })
+	return employees, nil
+}
+
+// [RU] AssignSubstitute назначает заменяющего преподавателя; он должен вести предмет занятия и быть свободен.
+// Назначенного заменяющего можно сменить; проверки и назначение идут в одной транзакции <--->
+// [ENG] AssignSubstitute assigns the substitute teacher; they must teach the lesson's subject and be free.
+// An assigned substitute can be replaced; the checks and the assignment run in a single transaction
+func (m *Managers) AssignSubstitute(ctx context.Context, substitutionID, employeeID int) (*domain.Substitution, error) {
+	var substitution *domain.Substitution
+	err := engine.RunInTx(ctx, m.Substitution.db, func(tx *sql.Tx) error {
+		repo := m.Substitution.Repo.WithTx(tx)
+		var err error
+		substitution, err = repo.GetByID(ctx, substitutionID)
+		if err != nil {
+			return err
+		}
+		if employeeID == substitution.EmployeeID {
+			return fmt.Errorf("%w: employee %d cannot substitute themselves", domain.ErrInvalidSubstitution, employeeID)
+		}
+		lesson, err := m.Lesson.Repo.WithTx(tx).GetByID(ctx, substitution.LessonID)
+		if err != nil {
+			return fmt.Errorf("lesson %d: %w", substitution.LessonID, err)
+		}
+
+		teaches, err := m.SubjectDistr.Repo.WithTx(tx).Count(ctx, db.Filter{Conditions: []db.Condition{
+			{Field: "employee_id", Operator: "=", Value: employeeID},
+			{Field: "subject_id", Operator: "=", Value: lesson.SubjectID},
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to check qualification of employee %d: %w", employeeID, err)
+		}
+		if teaches == 0 {
+			return fmt.Errorf("%w: employee %d does not teach subject %d", domain.ErrInvalidSubstitution, employeeID, lesson.SubjectID)
+		}
+
+		busy, err := m.teachersBusy(ctx, tx, substitution)
+		if err != nil {
+			return err
+		}
+		if err := m.checkSubstitute(ctx, tx, substitution, employeeID, busy); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		substitution.SubstituteID, substitution.Status, substitution.AssignedAt = &employeeID, domain.SubstitutionAssigned, &now
+		if err := substitution.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		return repo.Update(ctx, substitution)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return substitution, nil
+}
+
+// [RU] SubstitutionReport возвращает замены преподавателя с датой проведения в периоде [from, to] для расчета оплаты <--->
+// [ENG] SubstitutionReport returns the employee's substitutions held within [from, to] for payroll
+func (m *Managers) SubstitutionReport(ctx context.Context, employeeID int, from, to time.Time) (*domain.SubstitutionReport, error) {
+	from, to = domain.DateOnly(from), domain.DateOnly(to)
+	var substitutions []*domain.Substitution
+	for _, field := range []string{"employee_id", "substitute_id"} {
+		found, err := m.Substitution.List(ctx, db.Filter{
+			Conditions: []db.Condition{
+				{Field: field, Operator: "=", Value: employeeID},
+				{Field: "date", Operator: ">=", Value: from},
+				{Field: "date", Operator: "<=", Value: to},
+			},
+			OrderBy: "date, time_begin, substitution_id",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list substitutions of employee %d: %w", employeeID, err)
+		}
+		substitutions = append(substitutions, found...)
+	}
+	return domain.NewSubstitutionReport(employeeID, from, to, substitutions), nil
+}
+
+// [RU] teachersBusy возвращает преподавателей, занятых во время занятия замены, и названия их занятий.
+// Занятие с заменой ведет заменяющий, преподаватель занятия на нем свободен. Чтение идет в tx; nil - вне транзакции <--->
+// [ENG] teachersBusy returns the teachers busy at the time of the substitution's occurrence and their lesson names.
+// An occurrence with a substitution is taught by the substitute, its own teacher is free.
+// Reads run in tx; nil means outside a transaction
+func (m *Managers) teachersBusy(ctx context.Context, tx *sql.Tx, substitution *domain.Substitution) (map[int]string, error) {
+	lessonRepo, substitutionRepo := m.Lesson.Repo, m.Substitution.Repo
+	if tx != nil {
+		lessonRepo, substitutionRepo = lessonRepo.WithTx(tx), substitutionRepo.WithTx(tx)
+	}
+	occurrences, err := m.expandSchedules(ctx, tx, substitution.Date, substitution.Date, 0, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	var lessonIDs []int
+	for _, occurrence := range occurrences {
+		if !slices.Contains(lessonIDs, occurrence.LessonID) {
+			lessonIDs = append(lessonIDs, occurrence.LessonID)
+		}
+	}
+	busy := map[int]string{}
+	if len(lessonIDs) == 0 {
+		return busy, nil
+	}
+	lessons, err := lessonRepo.GetByIDs(ctx, lessonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lessons: %w", err)
+	}
+	byID := make(map[int]*domain.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		byID[lesson.LessonID] = lesson
+	}
+
+	covered, err := substitutionRepo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "date", Operator: "=", Value: domain.DateOnly(substitution.Date)},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list substitutions: %w", err)
+	}
+	byOccurrence := make(map[occurrenceKey]*domain.Substitution, len(covered))
+	for _, other := range covered {
+		byOccurrence[occurrenceKey{other.ScheduleID, domain.DateOnly(other.OccurrenceDate)}] = other
+	}
+
+	for _, occurrence := range occurrences {
+		if occurrence.ScheduleID == substitution.ScheduleID && occurrence.OriginalDate.Equal(domain.DateOnly(substitution.OccurrenceDate)) {
+			continue
+		}
+		lesson, ok := byID[occurrence.LessonID]
+		if !ok || !domain.TimesOverlap(occurrence.TimeBegin, occurrence.TimeEnd, substitution.TimeBegin, substitution.TimeEnd) {
+			continue
+		}
+		teacher := lesson.EmployeeID
+		if other, ok := byOccurrence[occurrenceKey{occurrence.ScheduleID, occurrence.OriginalDate}]; ok {
+			if other.SubstituteID == nil {
+				continue
+			}
+			teacher = *other.SubstituteID
+		}
+		busy[teacher] = lesson.LessonName
+	}
+	return busy, nil
+}
+
+// substitutes возвращает назначенных заменяющих преподавателей занятий occurrences по адресу занятия
+func (m *Managers) substitutes(ctx context.Context, tx *sql.Tx, occurrences []domain.Occurrence) (map[occurrenceKey]int, error) {
+	if len(occurrences) == 0 {
+		return nil, nil
+	}
+	from, to := occurrences[0].OriginalDate, occurrences[0].OriginalDate
+	for _, occurrence := range occurrences {
+		from, to = minDate(from, occurrence.OriginalDate), maxDate(to, occurrence.OriginalDate)
+	}
+	repo := m.Substitution.Repo
+	if tx != nil {
+		repo = repo.WithTx(tx)
+	}
+	assigned, err := repo.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "occurrence_date", Operator: ">=", Value: from},
+		{Field: "occurrence_date", Operator: "<=", Value: to},
+		{Field: "status", Operator: "=", Value: domain.SubstitutionAssigned},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list substitutions: %w", err)
+	}
+	substitutes := make(map[occurrenceKey]int, len(assigned))
+	for _, substitution := range assigned {
+		if substitution.SubstituteID != nil {
+			substitutes[occurrenceKey{substitution.ScheduleID, domain.DateOnly(substitution.OccurrenceDate)}] = *substitution.SubstituteID
+		}
+	}
+	return substitutes, nil
+}
+
+// checkSubstitute проверяет, что преподаватель доступен и свободен во время занятия замены; tx == nil - вне транзакции
+func (m *Managers) checkSubstitute(ctx context.Context, tx *sql.Tx, substitution *domain.Substitution, employeeID int, busy map[int]string) error {
+	if lessonName, ok := busy[employeeID]; ok {
+		return &domain.AvailabilityError{
+			EmployeeID: employeeID,
+			Date:       substitution.Date,
+			TimeBegin:  substitution.TimeBegin,
+			TimeEnd:    substitution.TimeEnd,
+			Reason:     fmt.Sprintf("teaches %q", lessonName),
+		}
+	}
+	availability, err := m.Availability.load(ctx, tx, employeeID)
+	if err != nil {
+		return err
+	}
+	return availability.Check(employeeID, substitution.Date, substitution.TimeBegin, substitution.TimeEnd)
+}
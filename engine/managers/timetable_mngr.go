@@ -0,0 +1,475 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// TimetableManager черновики расписания решателя; решение и применение - через методы Managers
+type TimetableManager struct {
+	*engine.BaseManager[int, domain.TimetableDraft, *domain.TimetableDraft]
+	db *sql.DB
+}
+
+// NewTimetableManager создает новый экземпляр TimetableManager
+func NewTimetableManager(
+	repo db.Repository[domain.TimetableDraft, int],
+	db *sql.DB,
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *TimetableManager {
+	return &TimetableManager{
+		BaseManager: engine.NewBaseManager[int, domain.TimetableDraft, *domain.TimetableDraft](repo, logger, txTimeout),
+		db:          db,
+	}
+}
+
+// [RU] TimetableOptions параметры решателя; нулевые значения сетки заменяются значениями по умолчанию <--->
+// [ENG] TimetableOptions are the solver settings; zero grid values are replaced with defaults
+type TimetableOptions struct {
+	DateStart    time.Time      // начало периода, на который составляется расписание
+	DateEnd      time.Time      // конец периода
+	Days         []time.Weekday // учебные дни (по умолчанию понедельник - суббота)
+	DayBegin     time.Time      // начало учебного дня (по умолчанию 08:00)
+	DayEnd       time.Time      // конец учебного дня (по умолчанию 20:00)
+	SlotMinutes  int            // академический час (по умолчанию 45 минут)
+	BreakMinutes int            // перемена между часами в минутах
+	LessonIDs    []int          // занятия для размещения; пусто - все занятия без расписания в периоде
+}
+
+func (o TimetableOptions) withDefaults() TimetableOptions {
+	if len(o.Days) == 0 {
+		o.Days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	}
+	if o.DayBegin.IsZero() {
+		o.DayBegin = domain.ParseTimeHM("08:00")
+	}
+	if o.DayEnd.IsZero() {
+		o.DayEnd = domain.ParseTimeHM("20:00")
+	}
+	if o.SlotMinutes <= 0 {
+		o.SlotMinutes = 45
+	}
+	o.BreakMinutes = max(o.BreakMinutes, 0)
+	return o
+}
+
+// [RU] SolveTimetable составляет недельное расписание на период и сохраняет его черновиком. Часы занятия в неделю -
+// учебная нагрузка программы (Programm.StudyLoad), поровну разделенная между предметами программы
+// (ProgrammDistribution). Занятие не размещается, если преподаватель не ведет предмет (SubjectDistribution)
+// или предмета нет в программе; занятия, уже стоящие в расписании периода, занимают свои ресурсы.
+// Час ставится только в доступное преподавателю время во все даты периода с этим днем недели <--->
+// [ENG] SolveTimetable builds a weekly timetable for the period and saves it as a draft. A lesson's weekly hours
+// are the programm's study load (Programm.StudyLoad) split evenly between the programm's subjects
+// (ProgrammDistribution). A lesson is not placed if its teacher does not teach the subject (SubjectDistribution)
+// or the subject is not in the programm; lessons already scheduled in the period hold their resources.
+// An hour is placed only at a time the teacher is available on every date of the period with that weekday
+func (m *Managers) SolveTimetable(ctx context.Context, opts TimetableOptions) (*domain.TimetableDraft, error) {
+	opts = opts.withDefaults()
+	if opts.DateEnd.Before(opts.DateStart) {
+		return nil, fmt.Errorf("%w: date_end is before date_start", domain.ErrInvalidTimetable)
+	}
+	if !opts.DayBegin.Before(opts.DayEnd) {
+		return nil, fmt.Errorf("%w: day_end must be after day_begin", domain.ErrInvalidTimetable)
+	}
+
+	var lessons []*domain.Lesson
+	var err error
+	if len(opts.LessonIDs) > 0 {
+		lessons, err = m.Lesson.GetByIDs(ctx, opts.LessonIDs)
+	} else {
+		lessons, err = m.Lesson.List(ctx, db.Filter{OrderBy: "lesson_id"})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lessons: %w", err)
+	}
+
+	problem := domain.TimetableProblem{
+		Days:         opts.Days,
+		DayBegin:     opts.DayBegin,
+		DayEnd:       opts.DayEnd,
+		SlotMinutes:  opts.SlotMinutes,
+		BreakMinutes: opts.BreakMinutes,
+	}
+	var rejected []domain.UnsatisfiedConstraint
+
+	scheduled, err := m.timetableBusy(ctx, opts.DateStart, opts.DateEnd, &problem)
+	if err != nil {
+		return nil, err
+	}
+	load, err := m.timetableLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lesson := range lessons {
+		if scheduled[lesson.LessonID] {
+			if len(opts.LessonIDs) > 0 {
+				rejected = append(rejected, domain.UnsatisfiedConstraint{
+					LessonID: lesson.LessonID, LessonName: lesson.LessonName,
+					Constraint: domain.ConstraintScheduled, Reason: "lesson already has schedule in the period",
+				})
+			}
+			continue
+		}
+		demand, constraint, reason := load.demand(lesson)
+		if constraint != "" {
+			rejected = append(rejected, domain.UnsatisfiedConstraint{
+				LessonID: lesson.LessonID, LessonName: lesson.LessonName,
+				Constraint: constraint, Reason: reason, Hours: demand.Hours,
+			})
+			continue
+		}
+		problem.Demands = append(problem.Demands, demand)
+	}
+
+	if problem.Rooms, err = m.timetableRooms(ctx); err != nil {
+		return nil, err
+	}
+	if problem.Available, err = m.timetableAvailable(ctx, opts.DateStart, opts.DateEnd); err != nil {
+		return nil, err
+	}
+
+	proposal := domain.SolveTimetable(problem)
+	proposal.Unsatisfied = append(rejected, proposal.Unsatisfied...)
+	slices.SortStableFunc(proposal.Unsatisfied, func(a, b domain.UnsatisfiedConstraint) int { return a.LessonID - b.LessonID })
+
+	draft := &domain.TimetableDraft{
+		DateStart: domain.DateOnly(opts.DateStart),
+		DateEnd:   domain.DateOnly(opts.DateEnd),
+		Status:    domain.DraftProposed,
+		CreatedAt: time.Now(),
+	}
+	if err := draft.SetProposal(proposal); err != nil {
+		return nil, fmt.Errorf("failed to encode timetable: %w", err)
+	}
+	if err := m.Timetable.Create(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// [RU] ApplyTimetable создает записи расписания черновика в одной транзакции: по еженедельной серии на каждый час
+// с первой подходящей даты периода. Занятию без аудитории назначается выбранная решателем. Если после решения
+// ресурсы заняли, проверка конфликтов отклоняет все применение <--->
+// [ENG] ApplyTimetable creates the draft's schedule entries in a single transaction: a weekly series per hour
+// from the first matching date of the period. A lesson without a room gets the one the solver chose. If resources
+// were taken after solving, the conflict check rejects the whole application
+func (m *Managers) ApplyTimetable(ctx context.Context, draftID int) ([]*domain.Schedule, error) {
+	var created []*domain.Schedule
+	err := engine.RunInTx(ctx, m.Timetable.db, func(tx *sql.Tx) error {
+		drafts := m.Timetable.Repo.WithTx(tx)
+		draft, err := drafts.GetByID(ctx, draftID)
+		if err != nil {
+			return err
+		}
+		if draft.Status == domain.DraftApplied {
+			return fmt.Errorf("draft %d: %w", draftID, domain.ErrDraftApplied)
+		}
+		proposal, err := draft.Proposal()
+		if err != nil {
+			return err
+		}
+
+		lessons := m.Lesson.Repo.WithTx(tx)
+		assigned := map[int]bool{}
+		for _, slot := range proposal.Slots {
+			if assigned[slot.LessonID] {
+				continue
+			}
+			assigned[slot.LessonID] = true
+			lesson, err := lessons.GetByID(ctx, slot.LessonID)
+			if err != nil {
+				return fmt.Errorf("lesson %d: %w", slot.LessonID, err)
+			}
+			if lesson.AudienceID == nil {
+				audienceID := slot.AudienceID
+				lesson.AudienceID = &audienceID
+				if err := lessons.Update(ctx, lesson); err != nil {
+					return fmt.Errorf("lesson %d: failed to assign audience: %w", slot.LessonID, err)
+				}
+			}
+		}
+
+		schedules := m.Schedule.Repo.WithTx(tx)
+		for _, slot := range proposal.Slots {
+			day, ok := domain.ParseWeekday(slot.DayWeek)
+			if !ok {
+				return fmt.Errorf("%w: unknown day_week %q", domain.ErrInvalidTimetable, slot.DayWeek)
+			}
+			first := domain.DateOnly(draft.DateStart)
+			for first.Weekday() != day {
+				first = first.AddDate(0, 0, 1)
+			}
+			if first.After(draft.DateEnd) {
+				continue // период короче недели
+			}
+
+			schedule := &domain.Schedule{
+				LessonID:      slot.LessonID,
+				DayWeek:       slot.DayWeek,
+				TimeBegin:     slot.TimeBegin,
+				TimeEnd:       slot.TimeEnd,
+				SchdDateStart: first,
+				SchdDateEnd:   draft.DateEnd,
+			}
+			if err := m.Schedule.prepare(schedule); err != nil {
+				return fmt.Errorf("lesson %d on %s: %w", slot.LessonID, slot.DayWeek, err)
+			}
+			if err := schedules.Create(ctx, schedule); err != nil {
+				return fmt.Errorf("lesson %d on %s: %w", slot.LessonID, slot.DayWeek, err)
+			}
+			created = append(created, schedule)
+		}
+
+		now := time.Now()
+		draft.Status, draft.AppliedAt = domain.DraftApplied, &now
+		return drafts.Update(ctx, draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// timetableBusy заносит в задачу занятия, уже стоящие в расписании периода, и возвращает их ID
+func (m *Managers) timetableBusy(ctx context.Context, from, to time.Time, problem *domain.TimetableProblem) (map[int]bool, error) {
+	schedules, err := m.Schedule.List(ctx, db.Filter{Conditions: []db.Condition{
+		{Field: "schd_date_start", Operator: "<=", Value: domain.DateOnly(to)},
+		{Field: "schd_date_end", Operator: ">=", Value: domain.DateOnly(from)},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule: %w", err)
+	}
+	scheduled := map[int]bool{}
+	var lessonIDs []int
+	for _, schedule := range schedules {
+		if !scheduled[schedule.LessonID] {
+			scheduled[schedule.LessonID] = true
+			lessonIDs = append(lessonIDs, schedule.LessonID)
+		}
+	}
+	if len(lessonIDs) == 0 {
+		return scheduled, nil
+	}
+
+	lessons, err := m.Lesson.GetByIDs(ctx, lessonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lessons: %w", err)
+	}
+	groups, err := m.studentGroups(ctx, lessons)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*domain.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		byID[lesson.LessonID] = lesson
+	}
+	for _, schedule := range schedules {
+		lesson, ok := byID[schedule.LessonID]
+		if !ok {
+			continue // занятие в корзине
+		}
+		rule, err := schedule.Recurrence()
+		if err != nil {
+			return nil, fmt.Errorf("schedule %d: %w", schedule.ScheduleID, err)
+		}
+		// Серия через неделю занимает ресурсы каждую неделю: недельная сетка не различает четность
+		for _, day := range rule.ByDay {
+			busy := domain.TimetableBusy{Lesson: lesson, Day: day, TimeBegin: schedule.TimeBegin, TimeEnd: schedule.TimeEnd}
+			if lesson.StudentID != nil {
+				busy.StudentGroup = groups[*lesson.StudentID]
+			}
+			problem.Busy = append(problem.Busy, busy)
+		}
+	}
+	return scheduled, nil
+}
+
+// [RU] timetableAvailable возвращает проверку доступности преподавателей для решателя: время дня недели доступно,
+// если его допускают окна и отсутствия во все даты периода с этим днем <--->
+// [ENG] timetableAvailable returns the teacher availability check for the solver: a weekday time is available
+// if the windows and absences allow it on every date of the period with that weekday
+func (m *Managers) timetableAvailable(ctx context.Context, from, to time.Time) (func(int, time.Weekday, time.Time, time.Time) bool, error) {
+	entries, err := m.Availability.List(ctx, db.Filter{OrderBy: "employee_id, availability_id"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load availability: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	byEmployee := map[int]domain.Availability{}
+	for _, entry := range entries {
+		byEmployee[entry.EmployeeID] = append(byEmployee[entry.EmployeeID], entry)
+	}
+
+	from, to = domain.DateOnly(from), domain.DateOnly(to)
+	return func(employeeID int, day time.Weekday, begin, end time.Time) bool {
+		availability := byEmployee[employeeID]
+		if len(availability) == 0 {
+			return true
+		}
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if date.Weekday() == day && availability.Check(employeeID, date, begin, end) != nil {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// studentGroups возвращает группы студентов индивидуальных занятий
+func (m *Managers) studentGroups(ctx context.Context, lessons []*domain.Lesson) (map[int]int, error) {
+	var studentIDs []int
+	for _, lesson := range lessons {
+		if lesson.StudentID != nil && !slices.Contains(studentIDs, *lesson.StudentID) {
+			studentIDs = append(studentIDs, *lesson.StudentID)
+		}
+	}
+	groups := make(map[int]int, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return groups, nil
+	}
+	students, err := m.Student.GetByIDs(ctx, studentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load students: %w", err)
+	}
+	for _, student := range students {
+		groups[student.StudentID] = student.GroupID
+	}
+	return groups, nil
+}
+
+// timetableRooms загружает аудитории с названиями и типами их инструментов
+func (m *Managers) timetableRooms(ctx context.Context) ([]domain.TimetableRoom, error) {
+	audiences, err := m.Audience.List(ctx, db.Filter{OrderBy: "audience_id"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audiences: %w", err)
+	}
+	instruments, err := m.Instrument.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instruments: %w", err)
+	}
+	equipment := map[int][]string{}
+	for _, instrument := range instruments {
+		equipment[instrument.AudienceID] = append(equipment[instrument.AudienceID], instrument.Name, instrument.InstrType)
+	}
+
+	rooms := make([]domain.TimetableRoom, 0, len(audiences))
+	for _, audience := range audiences {
+		rooms = append(rooms, domain.TimetableRoom{
+			AudienceID:  audience.AudienceID,
+			Capacity:    audience.Capacity,
+			Instruments: equipment[audience.AudienceID],
+		})
+	}
+	return rooms, nil
+}
+
+// timetableLoad справочники учебной нагрузки: программы, их предметы, группы, студенты и квалификации преподавателей
+type timetableLoad struct {
+	programms map[int]*domain.Programm
+	subjects  map[int][]int // предметы программы
+	groups    map[int]*domain.StudyGroup
+	students  map[int]*domain.Student
+	teaches   map[[2]int]bool // {employee_id, subject_id}
+}
+
+// timetableLoad загружает справочники учебной нагрузки целиком: их размер - десятки и сотни строк
+func (m *Managers) timetableLoad(ctx context.Context) (*timetableLoad, error) {
+	load := &timetableLoad{
+		programms: map[int]*domain.Programm{},
+		subjects:  map[int][]int{},
+		groups:    map[int]*domain.StudyGroup{},
+		students:  map[int]*domain.Student{},
+		teaches:   map[[2]int]bool{},
+	}
+
+	programms, err := m.Programm.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load programms: %w", err)
+	}
+	for _, programm := range programms {
+		load.programms[programm.MusprogrammID] = programm
+	}
+	distributions, err := m.ProgrammDistr.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load programm distribution: %w", err)
+	}
+	for _, d := range distributions {
+		if !slices.Contains(load.subjects[d.MusprogrammID], d.SubjectID) {
+			load.subjects[d.MusprogrammID] = append(load.subjects[d.MusprogrammID], d.SubjectID)
+		}
+	}
+	qualifications, err := m.SubjectDistr.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subject distribution: %w", err)
+	}
+	for _, d := range qualifications {
+		load.teaches[[2]int{d.EmployeeID, d.SubjectID}] = true
+	}
+	groups, err := m.StudyGroup.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load groups: %w", err)
+	}
+	for _, group := range groups {
+		load.groups[group.GroupID] = group
+	}
+	students, err := m.Student.List(ctx, db.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load students: %w", err)
+	}
+	for _, student := range students {
+		load.students[student.StudentID] = student
+	}
+	return load, nil
+}
+
+// demand переводит занятие в задачу решателя; непустой constraint - занятие разместить нельзя
+func (l *timetableLoad) demand(lesson *domain.Lesson) (demand domain.TimetableDemand, constraint, reason string) {
+	demand = domain.TimetableDemand{Lesson: lesson, Size: 1}
+
+	var programmID int
+	if lesson.StudentID != nil {
+		student, ok := l.students[*lesson.StudentID]
+		if !ok {
+			return demand, domain.ConstraintProgramm, fmt.Sprintf("student %d not found", *lesson.StudentID)
+		}
+		demand.StudentGroup, programmID = student.GroupID, student.MusprogrammID
+	} else {
+		group, ok := l.groups[lesson.GroupID]
+		if !ok {
+			return demand, domain.ConstraintProgramm, fmt.Sprintf("group %d not found", lesson.GroupID)
+		}
+		demand.Size, programmID = max(group.NumberOfStudents, 1), group.MusProgrammID
+	}
+
+	programm, ok := l.programms[programmID]
+	if !ok {
+		return demand, domain.ConstraintProgramm, fmt.Sprintf("programm %d not found", programmID)
+	}
+	subjects := l.subjects[programmID]
+	if len(subjects) > 0 {
+		demand.Hours = (programm.StudyLoad + len(subjects) - 1) / len(subjects)
+	}
+	if lesson.StudentID != nil && programm.Instrument != nil {
+		demand.Instrument = *programm.Instrument
+	}
+
+	if !slices.Contains(subjects, lesson.SubjectID) {
+		return demand, domain.ConstraintProgramm, fmt.Sprintf("subject %d is not in programm %d", lesson.SubjectID, programmID)
+	}
+	if !l.teaches[[2]int{lesson.EmployeeID, lesson.SubjectID}] {
+		return demand, domain.ConstraintQualification, fmt.Sprintf("employee %d does not teach subject %d", lesson.EmployeeID, lesson.SubjectID)
+	}
+	return demand, "", ""
+}
@@ -14,7 +14,6 @@ import (
 
 	"github.com/SerMoskvin/access"
 	"github.com/SerMoskvin/logger"
-	"github.com/dgrijalva/jwt-go"
 )
 
 type UserManager struct {
@@ -123,17 +122,11 @@ func (m *UserManager) Login(ctx context.Context, login, password string) (string
 // [RU] GetCurrentUser  возвращает данные текущего аутентифицированного пользователя <--->
 // [ENG] GetCurrentUser  returns the data of the currently authenticated user
 func (m *UserManager) GetCurrentUser(ctx context.Context) (*domain.User, error) {
-	claims, ok := ctx.Value(access.UserClaimsKey).(jwt.MapClaims)
+	userID, ok := engine.ActorID(ctx)
 	if !ok {
 		return nil, errors.New("authentication required")
 	}
 
-	userIDFloat, ok := claims["user_id"].(float64)
-	if !ok {
-		return nil, errors.New("invalid user ID in token")
-	}
-	userID := int(userIDFloat)
-
 	user, err := m.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -197,10 +190,10 @@ func (m *UserManager) GetByRole(ctx context.Context, role string) ([]*domain.Use
 	return users, nil
 }
 
-// [RU] SearchByNames ищет пользователей по ФИО <--->
-// [ENG] SearchByNames searches for users by full name
+// [RU] SearchByNames ищет пользователей по ФИО, логину и email, сначала самые релевантные <--->
+// [ENG] SearchByNames searches for users by full name, login and email, most relevant first
 func (m *UserManager) SearchByNames(ctx context.Context, query string) ([]*domain.User, error) {
-	users, err := m.repo.SearchByName(ctx, query)
+	users, err := m.List(ctx, db.Filter{Search: query})
 	if err != nil {
 		m.Logger.Error("SearchByNames failed",
 			logger.Field{Key: "error", Value: err},
@@ -0,0 +1,328 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// queueOrder порядок очереди листа ожидания: кто раньше встал, тот раньше получает место
+const queueOrder = "enlisted_at,waitlist_id"
+
+// WaitlistManager лист ожидания групп; постановка, отзыв и зачисление - через методы Managers
+type WaitlistManager struct {
+	*engine.BaseManager[int, domain.WaitlistEntry, *domain.WaitlistEntry]
+	db *sql.DB
+}
+
+// NewWaitlistManager создает новый экземпляр WaitlistManager
+func NewWaitlistManager(
+	repo db.Repository[domain.WaitlistEntry, int],
+	db *sql.DB,
+	logger *logger.LevelLogger,
+	txTimeout time.Duration,
+) *WaitlistManager {
+	return &WaitlistManager{
+		BaseManager: engine.NewBaseManager[int, domain.WaitlistEntry, *domain.WaitlistEntry](repo, logger, txTimeout),
+		db:          db,
+	}
+}
+
+// [RU] GetByGroup возвращает все записи листа ожидания группы, включая историю, в порядке очереди;
+// у ожидающих записей заполнено место в очереди <--->
+// [ENG] GetByGroup returns every waitlist entry of the group, history included, in queue order;
+// waiting entries carry their queue position
+func (m *WaitlistManager) GetByGroup(ctx context.Context, groupID int) ([]*domain.WaitlistEntry, error) {
+	return m.listWithPositions(ctx, db.Filter{
+		Conditions: []db.Condition{{Field: "group_id", Operator: "=", Value: groupID}},
+		OrderBy:    queueOrder,
+	})
+}
+
+// [RU] GetByStudent возвращает все записи листа ожидания студента, включая историю;
+// у ожидающих записей заполнено место в очереди группы <--->
+// [ENG] GetByStudent returns every waitlist entry of the student, history included;
+// waiting entries carry their position in the group's queue
+func (m *WaitlistManager) GetByStudent(ctx context.Context, studentID int) ([]*domain.WaitlistEntry, error) {
+	return m.listWithPositions(ctx, db.Filter{
+		Conditions: []db.Condition{{Field: "student_id", Operator: "=", Value: studentID}},
+		OrderBy:    queueOrder,
+	})
+}
+
+func (m *WaitlistManager) listWithPositions(ctx context.Context, filter db.Filter) ([]*domain.WaitlistEntry, error) {
+	entries, err := m.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist: %w", err)
+	}
+	if err := m.FillPositions(ctx, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// [RU] FillPositions заполняет Position ожидающих записей: место в очереди их группы, начиная с 1 <--->
+// [ENG] FillPositions sets Position of waiting entries: their place in the group's queue, starting at 1
+func (m *WaitlistManager) FillPositions(ctx context.Context, entries []*domain.WaitlistEntry) error {
+	queues := map[int]map[int]int{} // группа -> запись -> место
+	for _, entry := range entries {
+		if entry.Status != domain.WaitlistWaiting {
+			continue
+		}
+		positions, ok := queues[entry.GroupID]
+		if !ok {
+			queue, err := m.List(ctx, db.Filter{
+				Conditions: []db.Condition{
+					{Field: "group_id", Operator: "=", Value: entry.GroupID},
+					{Field: "status", Operator: "=", Value: domain.WaitlistWaiting},
+				},
+				OrderBy: queueOrder,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to load waitlist queue of group %d: %w", entry.GroupID, err)
+			}
+			positions = make(map[int]int, len(queue))
+			for i, queued := range queue {
+				positions[queued.WaitlistID] = i + 1
+			}
+			queues[entry.GroupID] = positions
+		}
+		entry.Position = positions[entry.WaitlistID]
+	}
+	return nil
+}
+
+// [RU] EnrollWaitlist ставит студента в лист ожидания группы. Если в группе есть свободное место,
+// оно сразу предлагается первому в очереди <--->
+// [ENG] EnrollWaitlist puts the student on the group's waitlist. If the group has a free place,
+// it is offered to the head of the queue right away
+func (m *Managers) EnrollWaitlist(ctx context.Context, groupID, studentID int) (*domain.WaitlistEntry, error) {
+	entry := &domain.WaitlistEntry{
+		GroupID:    groupID,
+		StudentID:  studentID,
+		Status:     domain.WaitlistWaiting,
+		EnlistedAt: time.Now(),
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	err := engine.RunInTx(ctx, m.Waitlist.db, func(tx *sql.Tx) error {
+		if _, err := m.StudyGroup.Repo.WithTx(tx).GetByID(ctx, groupID); err != nil {
+			return fmt.Errorf("study group %d: %w", groupID, err)
+		}
+		student, err := m.Student.Repo.WithTx(tx).GetByID(ctx, studentID)
+		if err != nil {
+			return fmt.Errorf("student %d: %w", studentID, err)
+		}
+		if student.GroupID == groupID {
+			return fmt.Errorf("%w: student %d is already in group %d", domain.ErrWaitlistConflict, studentID, groupID)
+		}
+
+		active, err := m.activeWaitlist(ctx, tx, db.Filter{Conditions: []db.Condition{
+			{Field: "group_id", Operator: "=", Value: groupID},
+			{Field: "student_id", Operator: "=", Value: studentID},
+		}})
+		if err != nil {
+			return err
+		}
+		if len(active) > 0 {
+			return fmt.Errorf("%w: student %d is already on the waitlist of group %d", domain.ErrWaitlistConflict, studentID, groupID)
+		}
+
+		if err := m.Waitlist.Repo.WithTx(tx).Create(ctx, entry); err != nil {
+			return fmt.Errorf("waitlist create failed: %w", err)
+		}
+		return m.offerFreePlaces(ctx, tx, groupID)
+	})
+	if err != nil {
+		m.Waitlist.Logger.Error("EnrollWaitlist failed", logger.Error(err),
+			logger.Int("group_id", groupID), logger.Int("student_id", studentID))
+		return nil, err
+	}
+
+	enrolled, err := m.Waitlist.GetByID(ctx, entry.WaitlistID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Waitlist.FillPositions(ctx, []*domain.WaitlistEntry{enrolled}); err != nil {
+		return nil, err
+	}
+	return enrolled, nil
+}
+
+// [RU] WithdrawWaitlist снимает запись с листа ожидания; если записи было предложено место,
+// оно переходит следующему в очереди <--->
+// [ENG] WithdrawWaitlist takes the entry off the waitlist; if the entry held an offered place,
+// the place goes to the next in the queue
+func (m *Managers) WithdrawWaitlist(ctx context.Context, waitlistID int) error {
+	err := engine.RunInTx(ctx, m.Waitlist.db, func(tx *sql.Tx) error {
+		entry, err := m.Waitlist.Repo.WithTx(tx).GetByID(ctx, waitlistID)
+		if err != nil {
+			return fmt.Errorf("waitlist entry %d: %w", waitlistID, err)
+		}
+		if !entry.Active() {
+			return fmt.Errorf("%w: waitlist entry %d is already %s", domain.ErrWaitlistConflict, waitlistID, entry.Status)
+		}
+		return m.withdrawWaitlist(ctx, tx, db.Filter{Conditions: []db.Condition{
+			{Field: "waitlist_id", Operator: "=", Value: waitlistID},
+		}})
+	})
+	if err != nil {
+		m.Waitlist.Logger.Error("WithdrawWaitlist failed", logger.Error(err), logger.Int("waitlist_id", waitlistID))
+	}
+	return err
+}
+
+// [RU] AcceptWaitlistOffer принимает предложенное место: студент переводится в группу,
+// запись отмечается зачисленной <--->
+// [ENG] AcceptWaitlistOffer accepts the offered place: the student is transferred to the group
+// and the entry is marked enrolled
+func (m *Managers) AcceptWaitlistOffer(ctx context.Context, waitlistID int) error {
+	err := engine.RunInTx(ctx, m.Waitlist.db, func(tx *sql.Tx) error {
+		entry, err := m.Waitlist.Repo.WithTx(tx).GetByID(ctx, waitlistID)
+		if err != nil {
+			return fmt.Errorf("waitlist entry %d: %w", waitlistID, err)
+		}
+		if entry.Status != domain.WaitlistOffered {
+			return fmt.Errorf("%w: waitlist entry %d has no offered place (status %s)", domain.ErrWaitlistConflict, waitlistID, entry.Status)
+		}
+
+		students := m.Student.Repo.WithTx(tx)
+		student, err := students.GetByID(ctx, entry.StudentID)
+		if err != nil {
+			return fmt.Errorf("student %d: %w", entry.StudentID, err)
+		}
+		// Перевод проходит через обработчики численности: они займут место и закроют запись
+		student.GroupID = entry.GroupID
+		if err := students.Update(ctx, student); err != nil {
+			return fmt.Errorf("student %d: transfer failed: %w", entry.StudentID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		m.Waitlist.Logger.Error("AcceptWaitlistOffer failed", logger.Error(err), logger.Int("waitlist_id", waitlistID))
+	}
+	return err
+}
+
+// offerFreePlaces предлагает свободные места группы первым в очереди; в корзине группа не обслуживается
+func (m *Managers) offerFreePlaces(ctx context.Context, tx *sql.Tx, groupID int) error {
+	group, err := m.StudyGroup.Repo.WithTx(tx).GetByID(ctx, groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("study group %d: %w", groupID, err)
+	}
+
+	offered, err := m.offeredPlaces(ctx, tx, groupID, 0)
+	if err != nil {
+		return err
+	}
+	filter := db.Filter{
+		Conditions: []db.Condition{
+			{Field: "group_id", Operator: "=", Value: groupID},
+			{Field: "status", Operator: "=", Value: domain.WaitlistWaiting},
+		},
+		OrderBy: queueOrder,
+	}
+	if free, limited := group.FreePlaces(offered); limited {
+		if free == 0 {
+			return nil
+		}
+		filter.Limit = free
+	}
+
+	repo := m.Waitlist.Repo.WithTx(tx)
+	waiting, err := repo.List(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("study group %d: waitlist read failed: %w", groupID, err)
+	}
+	now := time.Now()
+	for _, entry := range waiting {
+		entry.Status = domain.WaitlistOffered
+		entry.OfferedAt = &now
+		if err := repo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("waitlist entry %d: offer failed: %w", entry.WaitlistID, err)
+		}
+	}
+	return nil
+}
+
+// offeredPlaces считает места группы, удерживаемые предложениями; предложение студента exceptStudentID не считается
+func (m *Managers) offeredPlaces(ctx context.Context, tx *sql.Tx, groupID, exceptStudentID int) (int, error) {
+	offered, err := m.Waitlist.Repo.WithTx(tx).Count(ctx, db.Filter{
+		Conditions: []db.Condition{
+			{Field: "group_id", Operator: "=", Value: groupID},
+			{Field: "status", Operator: "=", Value: domain.WaitlistOffered},
+			{Field: "student_id", Operator: "!=", Value: exceptStudentID},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("study group %d: offered places count failed: %w", groupID, err)
+	}
+	return offered, nil
+}
+
+// activeWaitlist возвращает записи под filter, которые еще стоят в очереди
+func (m *Managers) activeWaitlist(ctx context.Context, tx *sql.Tx, filter db.Filter) ([]*domain.WaitlistEntry, error) {
+	entries, err := m.Waitlist.Repo.WithTx(tx).List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist read failed: %w", err)
+	}
+	active := entries[:0]
+	for _, entry := range entries {
+		if entry.Active() {
+			active = append(active, entry)
+		}
+	}
+	return active, nil
+}
+
+// resolveWaitlist закрывает активные записи под filter со статусом status и возвращает их в прежнем статусе
+func (m *Managers) resolveWaitlist(ctx context.Context, tx *sql.Tx, filter db.Filter, status string) ([]*domain.WaitlistEntry, error) {
+	active, err := m.activeWaitlist(ctx, tx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := m.Waitlist.Repo.WithTx(tx)
+	now := time.Now()
+	resolved := make([]*domain.WaitlistEntry, 0, len(active))
+	for _, entry := range active {
+		closed := *entry
+		closed.Status = status
+		closed.ResolvedAt = &now
+		if err := repo.Update(ctx, &closed); err != nil {
+			return nil, fmt.Errorf("waitlist entry %d: %s failed: %w", entry.WaitlistID, status, err)
+		}
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// withdrawWaitlist отзывает активные записи под filter; освободившиеся предложенные места уходят следующим в очереди
+func (m *Managers) withdrawWaitlist(ctx context.Context, tx *sql.Tx, filter db.Filter) error {
+	withdrawn, err := m.resolveWaitlist(ctx, tx, filter, domain.WaitlistWithdrawn)
+	if err != nil {
+		return err
+	}
+	for _, entry := range withdrawn {
+		if entry.Status != domain.WaitlistOffered {
+			continue
+		}
+		if err := m.offerFreePlaces(ctx, tx, entry.GroupID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// AllTopics топик получателя, которому доставляются сообщения всех топиков
+const AllTopics = "*"
+
+// [RU] Outbox запись сообщений в таблицу outbox (миграция 0006_outbox) в транзакции изменения:
+// сообщение появляется только вместе с зафиксированным изменением <--->
+// [ENG] Outbox writes messages to the outbox table (migration 0006_outbox) within the change's transaction:
+// a message exists only together with the committed change
+type Outbox struct {
+	repo db.Repository[domain.OutboxMessage, int]
+}
+
+// [RU] NewOutbox создает outbox поверх репозитория таблицы outbox <--->
+// [ENG] NewOutbox creates the outbox on top of the outbox table repository
+func NewOutbox(repo db.Repository[domain.OutboxMessage, int]) *Outbox {
+	return &Outbox{repo: repo}
+}
+
+// [RU] Enqueue записывает сообщение в транзакции tx; payload сериализуется в JSON <--->
+// [ENG] Enqueue writes a message within tx; payload is serialized to JSON
+func (o *Outbox) Enqueue(ctx context.Context, tx *sql.Tx, topic string, payload interface{}) error {
+	if tx == nil {
+		return errors.New("outbox: transaction is required")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("outbox: marshal payload: %w", err)
+	}
+
+	now := time.Now()
+	msg := &domain.OutboxMessage{Topic: topic, Payload: string(data), NextAttemptAt: now, CreatedAt: now}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+	if err := o.repo.WithTx(tx).Create(ctx, msg); err != nil {
+		return fmt.Errorf("outbox: enqueue failed: %w", err)
+	}
+	return nil
+}
+
+// [RU] OutboxChange сообщение об изменении сущности, которое пишет EnableOutbox <--->
+// [ENG] OutboxChange is the entity change message written by EnableOutbox
+type OutboxChange[T any] struct {
+	Event  EventType `json:"event"`
+	Old    *T        `json:"old,omitempty"`
+	New    *T        `json:"new,omitempty"`
+	UserID *int      `json:"user_id,omitempty"` // автор изменения из JWT claims
+}
+
+// [RU] EnableOutbox пишет сообщение topic на каждое изменение сущности в транзакции изменения,
+// в том числе внутри ExecuteInTx. Использует шину событий, поэтому вызывается после EnableEvents <--->
+// [ENG] EnableOutbox writes a topic message for every entity change within the change's transaction,
+// including inside ExecuteInTx. It uses the event bus, so it is called after EnableEvents
+func (m *BaseManager[ID, T, PT]) EnableOutbox(outbox *Outbox, topic string) {
+	if m.events == nil {
+		panic("outbox requires EnableEvents")
+	}
+	for _, eventType := range []EventType{AfterCreate, AfterUpdate, AfterDelete, AfterRestore} {
+		m.events.Subscribe(eventType, func(ctx context.Context, e Event[T]) error {
+			change := OutboxChange[T]{Event: e.Type, Old: e.Old, New: e.New}
+			if userID, ok := ActorID(ctx); ok {
+				change.UserID = &userID
+			}
+			return outbox.Enqueue(ctx, e.Tx, topic, change)
+		})
+	}
+}
+
+// [RU] Sink получатель сообщений outbox (уведомления, очереди, вебхуки). Доставка - как минимум один раз:
+// при повторе сообщение получат и те получатели, которые уже приняли его, поэтому Deliver должен быть идемпотентным <--->
+// [ENG] Sink receives outbox messages (notifications, queues, webhooks). Delivery is at least once:
+// a retry reaches the sinks that already accepted the message, so Deliver must be idempotent
+type Sink interface {
+	Deliver(ctx context.Context, msg *domain.OutboxMessage) error
+}
+
+// SinkFunc позволяет использовать функцию как Sink
+type SinkFunc func(ctx context.Context, msg *domain.OutboxMessage) error
+
+func (f SinkFunc) Deliver(ctx context.Context, msg *domain.OutboxMessage) error {
+	return f(ctx, msg)
+}
+
+// [RU] DispatcherOptions параметры диспетчера outbox; нулевые значения заменяются значениями по умолчанию <--->
+// [ENG] DispatcherOptions are the outbox dispatcher settings; zero values are replaced with defaults
+type DispatcherOptions struct {
+	PollInterval time.Duration // пауза перед следующим опросом, если очередь пуста (по умолчанию 1s)
+	BatchSize    int           // сообщений в одной пачке (по умолчанию 100)
+	MaxAttempts  int           // после стольких неудач сообщение больше не выбирается (по умолчанию 10)
+	RetryBase    time.Duration // задержка после первой неудачи, далее удваивается (по умолчанию 1s)
+	RetryMax     time.Duration // предел задержки между попытками (по умолчанию 1h)
+	Lease        time.Duration // сколько пачка принадлежит забравшему ее диспетчеру (по умолчанию 5m)
+}
+
+func (o DispatcherOptions) withDefaults() DispatcherOptions {
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 10
+	}
+	if o.RetryBase <= 0 {
+		o.RetryBase = time.Second
+	}
+	if o.RetryMax <= 0 {
+		o.RetryMax = time.Hour
+	}
+	if o.Lease <= 0 {
+		o.Lease = 5 * time.Minute
+	}
+	return o
+}
+
+// [RU] OutboxDispatcher фоновая доставка сообщений outbox получателям. Строки забираются через
+// FOR UPDATE SKIP LOCKED и арендуются: next_attempt_at сдвигается на Lease вперед, поэтому несколько
+// экземпляров приложения не доставляют одно сообщение одновременно, а получатели вызываются вне транзакции <--->
+// [ENG] OutboxDispatcher delivers outbox messages to sinks in the background. Rows are claimed with
+// FOR UPDATE SKIP LOCKED and leased: next_attempt_at moves Lease ahead, so several application instances
+// never deliver the same message at once, and sinks are called outside a transaction
+type OutboxDispatcher struct {
+	repo   db.Repository[domain.OutboxMessage, int]
+	db     TxProvider
+	logger *logger.LevelLogger
+	opts   DispatcherOptions
+
+	mu    sync.RWMutex
+	sinks map[string][]Sink
+}
+
+// [RU] NewOutboxDispatcher создает диспетчер; получатели подключаются через Register <--->
+// [ENG] NewOutboxDispatcher creates a dispatcher; sinks are attached with Register
+func NewOutboxDispatcher(
+	repo db.Repository[domain.OutboxMessage, int],
+	txProvider TxProvider,
+	logger *logger.LevelLogger,
+	opts DispatcherOptions,
+) *OutboxDispatcher {
+	if logger == nil {
+		panic("logger is required")
+	}
+	return &OutboxDispatcher{
+		repo:   repo,
+		db:     txProvider,
+		logger: logger,
+		opts:   opts.withDefaults(),
+		sinks:  map[string][]Sink{},
+	}
+}
+
+// [RU] Register подключает получателя сообщений topic; AllTopics - сообщения всех топиков <--->
+// [ENG] Register attaches a sink for topic messages; AllTopics receives messages of every topic
+func (d *OutboxDispatcher) Register(topic string, sink Sink) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sinks[topic] = append(d.sinks[topic], sink)
+}
+
+// [RU] Run доставляет сообщения до отмены ctx <--->
+// [ENG] Run delivers messages until ctx is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Error("Outbox dispatch failed", logger.Error(err))
+		}
+		// Полная пачка - в очереди, вероятно, есть еще сообщения
+		if err == nil && n == d.opts.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.opts.PollInterval):
+		}
+	}
+}
+
+// [RU] DispatchOnce забирает одну пачку готовых сообщений в короткой транзакции, доставляет их вне транзакции
+// до конца аренды и сохраняет результат во второй транзакции. Сообщения, до которых не дошла очередь
+// за время аренды, снова станут готовыми после ее окончания. Возвращает число обработанных сообщений <--->
+// [ENG] DispatchOnce claims one batch of ready messages in a short transaction, delivers them outside a transaction
+// until the lease ends and stores the outcome in a second transaction. Messages not reached within the lease
+// become ready again once it ends. Returns the number of processed messages
+func (d *OutboxDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	// TIMESTAMP хранит микросекунды: конец аренды сравнивается с прочитанным из БД (см. save)
+	now := time.Now().Truncate(time.Microsecond)
+	lease := now.Add(d.opts.Lease)
+
+	var messages []*domain.OutboxMessage
+	err := RunInTx(ctx, d.db, func(tx *sql.Tx) error {
+		repo := d.repo.WithTx(tx)
+		var err error
+		messages, err = repo.List(ctx, db.Filter{
+			Conditions: []db.Condition{
+				{Field: "delivered_at", Operator: "IS NULL"},
+				{Field: "next_attempt_at", Operator: "<=", Value: now},
+				{Field: "attempts", Operator: "<", Value: d.opts.MaxAttempts},
+			},
+			OrderBy:    "next_attempt_at,outbox_id",
+			Limit:      d.opts.BatchSize,
+			SkipLocked: true,
+		})
+		if err != nil {
+			return fmt.Errorf("claim failed: %w", err)
+		}
+		for _, msg := range messages {
+			msg.NextAttemptAt = lease
+			if err := repo.Update(ctx, msg); err != nil {
+				return fmt.Errorf("outbox %d: lease failed: %w", msg.OutboxID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	deliverCtx, cancel := context.WithDeadline(ctx, lease)
+	defer cancel()
+	var processed []*domain.OutboxMessage
+	for _, msg := range messages {
+		if deliverCtx.Err() != nil {
+			break
+		}
+		d.deliver(deliverCtx, msg)
+		processed = append(processed, msg)
+	}
+
+	// Результат доставки сохраняется и при остановке приложения, иначе сообщения ушли бы повторно
+	return len(processed), d.save(context.WithoutCancel(ctx), processed, lease)
+}
+
+// leaseLayout время на часах конца аренды без часового пояса
+const leaseLayout = "2006-01-02 15:04:05.999999"
+
+// save записывает результаты доставки; строки, аренда которых истекла и перешла к другому диспетчеру, не трогает
+func (d *OutboxDispatcher) save(ctx context.Context, messages []*domain.OutboxMessage, lease time.Time) error {
+	return RunInTx(ctx, d.db, func(tx *sql.Tx) error {
+		repo := d.repo.WithTx(tx)
+		for _, msg := range messages {
+			current, err := repo.List(ctx, db.Filter{
+				Conditions: []db.Condition{{Field: "outbox_id", Operator: "=", Value: msg.OutboxID}},
+				SkipLocked: true,
+			})
+			if err != nil {
+				return fmt.Errorf("outbox %d: save failed: %w", msg.OutboxID, err)
+			}
+			// TIMESTAMP без часового пояса возвращается с тем же временем на часах, но в UTC
+			if len(current) == 0 || current[0].NextAttemptAt.Format(leaseLayout) != lease.Format(leaseLayout) {
+				d.logger.Warn("Outbox message lease lost, outcome dropped", logger.Int("outbox_id", msg.OutboxID))
+				continue
+			}
+			if err := repo.Update(ctx, msg); err != nil {
+				return fmt.Errorf("outbox %d: save failed: %w", msg.OutboxID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// deliver передает сообщение получателям топика и записывает в msg результат попытки
+func (d *OutboxDispatcher) deliver(ctx context.Context, msg *domain.OutboxMessage) {
+	d.mu.RLock()
+	sinks := append(append([]Sink(nil), d.sinks[msg.Topic]...), d.sinks[AllTopics]...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, sink := range sinks {
+		if err := d.safeDeliver(ctx, sink, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	now := time.Now()
+	if err := errors.Join(errs...); err != nil {
+		text := err.Error()
+		msg.Attempts++
+		msg.LastError = &text
+		msg.NextAttemptAt = now.Add(d.backoff(msg.Attempts))
+
+		fields := []logger.Field{logger.Int("outbox_id", msg.OutboxID), logger.String("topic", msg.Topic), logger.Int("attempts", msg.Attempts), logger.Error(err)}
+		if msg.Attempts >= d.opts.MaxAttempts {
+			d.logger.Error("Outbox message delivery abandoned", fields...)
+		} else {
+			d.logger.Warn("Outbox message delivery failed", fields...)
+		}
+		return
+	}
+	msg.LastError = nil
+	msg.DeliveredAt = &now
+}
+
+// safeDeliver превращает панику получателя в ошибку доставки
+func (d *OutboxDispatcher) safeDeliver(ctx context.Context, sink Sink, msg *domain.OutboxMessage) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("sink panicked: %v", p)
+		}
+	}()
+	return sink.Deliver(ctx, msg)
+}
+
+// backoff задержка перед попыткой attempts+1: RetryBase * 2^(attempts-1), не больше RetryMax
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.RetryBase
+	for i := 1; i < attempts && delay < d.opts.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.RetryMax)
+}
+
+// [RU] LogSink получатель, который только пишет сообщения в лог; используется, пока нет настоящих уведомлений <--->
+// [ENG] LogSink is a sink that only logs messages; used until real notifications exist
+func LogSink(l *logger.LevelLogger) Sink {
+	return SinkFunc(func(ctx context.Context, msg *domain.OutboxMessage) error {
+		l.Info("Outbox message",
+			logger.Int("outbox_id", msg.OutboxID),
+			logger.String("topic", msg.Topic),
+			logger.String("payload", msg.Payload),
+		)
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// [RU] Relation связь сущности T, которая загружается пачкой для всего списка (без N+1 запросов) <--->
+// [ENG] Relation is a relation of entity T that is loaded in one batch for the whole list (no N+1 queries)
+type Relation[T any] interface {
+	Preload(ctx context.Context, entities []*T) error
+}
+
+type belongsTo[T any, ID comparable, R any, PR interface {
+	*R
+	domain.Entity[ID]
+}] struct {
+	target *BaseManager[ID, R, PR]
+	key    func(*T) (ID, bool)
+	set    func(*T, *R)
+}
+
+// [RU] BelongsTo связь по внешнему ключу: key возвращает ID связанной сущности (false для NULL),
+// set встраивает найденную сущность; все ID списка загружаются одним GetByIDs менеджера target <--->
+// [ENG] BelongsTo is a foreign key relation: key returns the related ID (false for NULL),
+// set embeds the loaded entity; all IDs of the list are loaded with a single GetByIDs of the target manager
+func BelongsTo[T any, ID comparable, R any, PR interface {
+	*R
+	domain.Entity[ID]
+}](
+	target *BaseManager[ID, R, PR],
+	key func(*T) (ID, bool),
+	set func(*T, *R),
+) Relation[T] {
+	return &belongsTo[T, ID, R, PR]{target: target, key: key, set: set}
+}
+
+func (b *belongsTo[T, ID, R, PR]) Preload(ctx context.Context, entities []*T) error {
+	seen := make(map[ID]bool)
+	var ids []ID
+	for _, entity := range entities {
+		if id, ok := b.key(entity); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	related, err := b.target.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[ID]*R, len(related))
+	for _, r := range related {
+		byID[PR(r).GetID()] = r
+	}
+
+	for _, entity := range entities {
+		if id, ok := b.key(entity); ok {
+			if r, found := byID[id]; found {
+				b.set(entity, r)
+			}
+		}
+	}
+	return nil
+}
+
+// [RU] RegisterRelation объявляет связь, доступную через Filter.Preloads и ?include=; вызывается при инициализации <--->
+// [ENG] RegisterRelation declares a relation available via Filter.Preloads and ?include=; called during initialization
+func (m *BaseManager[ID, T, PT]) RegisterRelation(name string, relation Relation[T]) {
+	if m.relations == nil {
+		m.relations = make(map[string]Relation[T])
+	}
+	m.relations[name] = relation
+}
+
+// [RU] Relations возвращает имена объявленных связей <--->
+// [ENG] Relations returns the names of declared relations
+func (m *BaseManager[ID, T, PT]) Relations() []string {
+	names := make([]string, 0, len(m.relations))
+	for name := range m.relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkPreloads проверяет имена связей до обращения к БД
+func (m *BaseManager[ID, T, PT]) checkPreloads(names []string) error {
+	for _, name := range names {
+		if _, ok := m.relations[name]; !ok {
+			allowed := "none"
+			if len(m.relations) > 0 {
+				allowed = strings.Join(m.Relations(), ", ")
+			}
+			return fmt.Errorf("%w: unknown include %q (allowed: %s)", db.ErrInvalidFilter, name, allowed)
+		}
+	}
+	return nil
+}
+
+// [RU] Preload загружает связи names для сущностей: по одному запросу на связь <--->
+// [ENG] Preload loads the names relations for entities: one query per relation
+func (m *BaseManager[ID, T, PT]) Preload(ctx context.Context, entities []*T, names []string) error {
+	if err := m.checkPreloads(names); err != nil {
+		return err
+	}
+	if len(entities) == 0 {
+		return nil
+	}
+
+	for _, name := range names {
+		if err := m.relations[name].Preload(ctx, entities); err != nil {
+			m.Logger.Error("Preload failed", logger.Error(err), logger.String("relation", name))
+			return fmt.Errorf("preload %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
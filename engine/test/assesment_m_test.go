@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -17,23 +17,13 @@ import (
 )
 
 func TestStudentAssessmentManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -50,8 +40,8 @@ func TestStudentAssessmentManager_AllMethods(t *testing.T) {
 
 	// Тестовые данные
 	testAssessment := &domain.StudentAssessment{
-		StudentID:      2,
-		LessonID:       2,
+		StudentID:      fx.Students[1].StudentID,
+		LessonID:       fx.Lessons[1].LessonID,
 		Grade:          5,
 		TaskType:       "test",
 		AssessmentDate: time.Now(),
@@ -137,8 +127,8 @@ func TestStudentAssessmentManager_AllMethods(t *testing.T) {
 
 	t.Run("BulkUpsert", func(t *testing.T) {
 		assessments := []*domain.StudentAssessment{
-			{StudentID: 2, LessonID: 2, Grade: 5, TaskType: "test", AssessmentDate: time.Now()},
-			{StudentID: 2, LessonID: 3, Grade: 4, TaskType: "test", AssessmentDate: time.Now()},
+			{StudentID: fx.Students[1].StudentID, LessonID: fx.Lessons[1].LessonID, Grade: 5, TaskType: "test", AssessmentDate: time.Now()},
+			{StudentID: fx.Students[1].StudentID, LessonID: fx.Lessons[2].LessonID, Grade: 4, TaskType: "test", AssessmentDate: time.Now()},
 		}
 
 		err := mgr.BulkUpsert(ctx, assessments)
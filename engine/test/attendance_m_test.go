@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
 	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -17,23 +17,13 @@ import (
 )
 
 func TestStudentAttendanceManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -50,8 +40,8 @@ func TestStudentAttendanceManager_AllMethods(t *testing.T) {
 
 	// Тестовые данные
 	testAttendance := &domain.StudentAttendance{
-		StudentID:      2,
-		LessonID:       2,
+		StudentID:      fx.Students[1].StudentID,
+		LessonID:       fx.Lessons[1].LessonID,
 		PresenceMark:   true,
 		AttendanceDate: time.Now().Format("2006-01-02"),
 	}
@@ -187,8 +177,8 @@ func TestStudentAttendanceManager_AllMethods(t *testing.T) {
 
 	t.Run("BulkCreate", func(t *testing.T) {
 		records := []*domain.StudentAttendance{
-			{StudentID: 3, LessonID: 3, PresenceMark: true, AttendanceDate: time.Now().Format("2006-01-02")},
-			{StudentID: 4, LessonID: 4, PresenceMark: false, AttendanceDate: time.Now().Format("2006-01-02")},
+			{StudentID: fx.Students[2].StudentID, LessonID: fx.Lessons[2].LessonID, PresenceMark: true, AttendanceDate: time.Now().Format("2006-01-02")},
+			{StudentID: fx.Students[0].StudentID, LessonID: fx.Lessons[0].LessonID, PresenceMark: false, AttendanceDate: time.Now().Format("2006-01-02")},
 		}
 
 		err := mgr.BulkCreate(ctx, records)
@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -17,23 +16,13 @@ import (
 )
 
 func TestAudienceManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема
+	sqlDB := dbtest.New(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,107 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+
+	"github.com/SerMoskvin/access"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Audit(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+		ctx = context.WithValue(ctx, access.UserClaimsKey, jwt.MapClaims{"user_id": float64(5), "role": "admin"})
+
+		entries := func(t *testing.T, entityID int) []*domain.AuditEntry {
+			list, err := mgrs.Audit.List(ctx, db.Filter{
+				Conditions: []db.Condition{
+					{Field: "entity", Operator: "=", Value: "subject"},
+					{Field: "entity_id", Operator: "=", Value: strconv.Itoa(entityID)},
+				},
+				OrderBy: "audit_id",
+			})
+			if err != nil {
+				t.Fatalf("audit list failed: %v", err)
+			}
+			return list
+		}
+
+		changes := func(t *testing.T, entry *domain.AuditEntry) map[string]map[string]interface{} {
+			var diff map[string]map[string]interface{}
+			if err := json.Unmarshal([]byte(entry.Changes), &diff); err != nil {
+				t.Fatalf("audit changes are not JSON: %v", err)
+			}
+			return diff
+		}
+
+		subject := &domain.Subject{SubjectName: "Сольфеджио", SubjectType: "Теория", ShortDesc: "Теория музыки"}
+
+		t.Run("Create, Update, Delete", func(t *testing.T) {
+			if err := mgrs.Subject.Create(ctx, subject); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			subject.ShortDesc = "Основы теории музыки"
+			if err := mgrs.Subject.Update(ctx, subject); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+			if err := mgrs.Subject.Delete(ctx, subject.SubjectID); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+
+			log := entries(t, subject.SubjectID)
+			if !assert.Len(t, log, 3) {
+				return
+			}
+			for i, action := range []string{domain.AuditCreate, domain.AuditUpdate, domain.AuditDelete} {
+				assert.Equal(t, action, log[i].Action)
+				if assert.NotNil(t, log[i].UserID) {
+					assert.Equal(t, 5, *log[i].UserID)
+				}
+			}
+
+			created := changes(t, log[0])
+			assert.NotContains(t, created, "before")
+			assert.Equal(t, "Сольфеджио", created["after"]["subject_name"])
+
+			updated := changes(t, log[1])
+			assert.Equal(t, map[string]interface{}{"short_desc": "Теория музыки"}, updated["before"])
+			assert.Equal(t, map[string]interface{}{"short_desc": "Основы теории музыки"}, updated["after"])
+
+			deleted := changes(t, log[2])
+			assert.NotContains(t, deleted, "after")
+			assert.Equal(t, "Основы теории музыки", deleted["before"]["short_desc"])
+		})
+
+		t.Run("Rolled back transaction leaves no entry", func(t *testing.T) {
+			errAbort := errors.New("abort")
+			other := &domain.Subject{SubjectName: "Хор", SubjectType: "Практика", ShortDesc: "Хоровое пение"}
+			err := mgrs.Subject.ExecuteInTx(ctx, env.db, func(repo db.Repository[domain.Subject, int]) error {
+				if err := repo.Create(ctx, other); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			assert.Empty(t, entries(t, other.SubjectID))
+		})
+
+		t.Run("Anonymous change", func(t *testing.T) {
+			anonymous := &domain.Subject{SubjectName: "Ритмика", SubjectType: "Практика", ShortDesc: "Чувство ритма"}
+			if err := mgrs.Subject.Create(context.Background(), anonymous); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			log := entries(t, anonymous.SubjectID)
+			if assert.Len(t, log, 1) {
+				assert.Nil(t, log[0].UserID)
+			}
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_EmployeeAvailability(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+		at := func(value string) *time.Time {
+			clock := domain.ParseTimeHM(value)
+			return &clock
+		}
+		on := func(value string) *time.Time {
+			date := domain.ParseDMY(value)
+			return &date
+		}
+		text := func(value string) *string { return &value }
+
+		ivanova, petrova := env.employee("Иванова"), env.employee("Петрова")
+		piano := env.programm("Фортепиано")
+		choir := env.lesson(ivanova, env.group(piano, "1 класс", 1), env.subject("Хор"), "Хор")
+		solfeggio := env.lesson(petrova, env.group(piano, "2 класс", 2), env.subject("Сольфеджио"), "Сольфеджио")
+
+		// Иванова ведет по понедельникам с 9 до 13 и по средам с 15 до 16, Петрова - без окон
+		monday, wednesday := "Понедельник", "Среда"
+		env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+			EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityWeekly, DayWeek: &monday, TimeBegin: at("09:00"), TimeEnd: at("13:00"),
+		}))
+		env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+			EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityWeekly, DayWeek: &wednesday, TimeBegin: at("15:00"), TimeEnd: at("16:00"),
+		}))
+
+		schedule := func(lesson *domain.Lesson, day, begin, end, from, to string) *domain.Schedule {
+			return &domain.Schedule{
+				LessonID:      lesson.LessonID,
+				DayWeek:       day,
+				TimeBegin:     domain.ParseTimeHM(begin),
+				TimeEnd:       domain.ParseTimeHM(end),
+				SchdDateStart: domain.ParseDMY(from),
+				SchdDateEnd:   domain.ParseDMY(to),
+			}
+		}
+
+		t.Run("Entries are validated", func(t *testing.T) {
+			err := mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+				EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityWeekly, DayWeek: &monday, TimeBegin: at("13:00"), TimeEnd: at("09:00"),
+			})
+			assert.True(t, errors.Is(err, domain.ErrInvalidAvailability), "got %v", err)
+
+			err = mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityAbsence, DateStart: on("01.10.2024")})
+			assert.True(t, errors.Is(err, domain.ErrInvalidAvailability), "got %v", err)
+		})
+
+		t.Run("Schedule outside the weekly windows is rejected", func(t *testing.T) {
+			assert.NoError(t, mgrs.Schedule.Create(ctx, schedule(choir, "Понедельник", "10:00", "11:00", "02.09.2024", "30.09.2024")))
+
+			err := mgrs.Schedule.Create(ctx, schedule(choir, "Понедельник", "12:30", "13:30", "02.09.2024", "30.09.2024"))
+			assert.True(t, errors.Is(err, domain.ErrUnavailable), "got %v", err)
+			var unavailable *domain.AvailabilityError
+			if assert.True(t, errors.As(err, &unavailable)) {
+				assert.Equal(t, ivanova.EmployeeID, unavailable.EmployeeID)
+				assert.Equal(t, "02.09.2024", domain.ToDMY(unavailable.Date))
+				assert.Contains(t, unavailable.Reason, "outside availability windows")
+			}
+
+			err = mgrs.Schedule.GenerateSchedule(ctx, schedule(choir, "Вторник", "10:00", "11:00", "03.09.2024", "03.09.2024"), domain.ParseDMY("24.12.2024"))
+			assert.True(t, errors.Is(err, domain.ErrUnavailable), "got %v", err)
+		})
+
+		t.Run("Absence rejects lessons on its dates", func(t *testing.T) {
+			env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+				EmployeeID: petrova.EmployeeID, Kind: domain.AvailabilityAbsence, DateStart: on("14.10.2024"), DateEnd: on("25.10.2024"), Reason: text("Отпуск"),
+			}))
+
+			// Без окон преподаватель доступен в любое время вне отсутствий
+			assert.NoError(t, mgrs.Schedule.Create(ctx, schedule(solfeggio, "Вторник", "18:00", "19:00", "03.09.2024", "08.10.2024")))
+
+			err := mgrs.Schedule.Create(ctx, schedule(solfeggio, "Четверг", "18:00", "19:00", "05.09.2024", "26.12.2024"))
+			var unavailable *domain.AvailabilityError
+			if assert.True(t, errors.As(err, &unavailable), "got %v", err) {
+				assert.Equal(t, "17.10.2024", domain.ToDMY(unavailable.Date), "the first lesson during the absence")
+				assert.Equal(t, "absent (Отпуск)", unavailable.Reason)
+			}
+		})
+
+		t.Run("Changing the teacher checks the new teacher's availability", func(t *testing.T) {
+			lesson, err := mgrs.Lesson.GetByID(ctx, solfeggio.LessonID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			lesson.EmployeeID = ivanova.EmployeeID
+			err = mgrs.Lesson.Update(ctx, lesson)
+			assert.True(t, errors.Is(err, domain.ErrUnavailable), "got %v", err)
+		})
+
+		t.Run("Moved occurrence must stay available", func(t *testing.T) {
+			mondays := schedule(choir, "Понедельник", "09:00", "09:45", "02.09.2024", "30.09.2024")
+			env.must(mgrs.Schedule.Create(ctx, mondays))
+			move := func(from, to, begin, end string) error {
+				_, err := mgrs.EditOccurrence(ctx, mondays.ScheduleID, domain.ParseDMY(from), domain.ScheduleException{
+					NewDate: on(to), TimeBegin: at(begin), TimeEnd: at(end),
+				})
+				return err
+			}
+
+			err := move("09.09.2024", "10.09.2024", "09:00", "09:45")
+			assert.True(t, errors.Is(err, domain.ErrUnavailable), "got %v", err)
+			assert.NoError(t, move("09.09.2024", "11.09.2024", "15:00", "15:45"))
+
+			env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+				EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityAbsence, DateStart: on("23.09.2024"), DateEnd: on("23.09.2024"), Reason: text("Конференция"),
+			}))
+			err = move("16.09.2024", "23.09.2024", "11:00", "11:45")
+			var unavailable *domain.AvailabilityError
+			if assert.True(t, errors.As(err, &unavailable), "got %v", err) {
+				assert.Equal(t, "23.09.2024", domain.ToDMY(unavailable.Date))
+				assert.Equal(t, "absent (Конференция)", unavailable.Reason)
+			}
+		})
+
+		t.Run("Solver places hours inside the windows", func(t *testing.T) {
+			programm := &domain.Programm{
+				ProgrammName: "Хоровое пение", ProgrammType: "Общеразвивающая", Duration: 4, StudyLoad: 1, FinalCertificationForm: "Зачет",
+			}
+			env.must(mgrs.Programm.Create(ctx, programm))
+			subject := env.subject("Ансамбль")
+			env.must(mgrs.ProgrammDistr.Create(ctx, &domain.ProgrammDistribution{MusprogrammID: programm.MusprogrammID, SubjectID: subject.SubjectID}))
+			env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: ivanova.EmployeeID, SubjectID: subject.SubjectID}))
+			group := &domain.StudyGroup{MusProgrammID: programm.MusprogrammID, GroupName: "Хор", StudyYear: 1}
+			env.must(mgrs.StudyGroup.Create(ctx, group))
+			env.audience("301", 30)
+			ensemble := env.lesson(ivanova, group, subject, "Ансамбль")
+
+			draft, err := mgrs.SolveTimetable(ctx, managers.TimetableOptions{
+				DateStart:    domain.ParseDMY("06.01.2025"),
+				DateEnd:      domain.ParseDMY("28.03.2025"),
+				Days:         []time.Weekday{time.Monday, time.Tuesday, time.Wednesday},
+				DayBegin:     domain.ParseTimeHM("14:00"),
+				DayEnd:       domain.ParseTimeHM("17:00"),
+				BreakMinutes: 15,
+				LessonIDs:    []int{ensemble.LessonID},
+			})
+			if err != nil {
+				t.Fatalf("SolveTimetable failed: %v", err)
+			}
+			proposal, err := draft.Proposal()
+			if err != nil {
+				t.Fatalf("Proposal failed: %v", err)
+			}
+			if assert.Len(t, proposal.Slots, 1) {
+				assert.Equal(t, "Среда", proposal.Slots[0].DayWeek)
+				assert.Equal(t, "15:00", domain.ToTimeHM(proposal.Slots[0].TimeBegin))
+			}
+
+			// Больничный в одну из сред периода закрывает и это время
+			env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+				EmployeeID: ivanova.EmployeeID, Kind: domain.AvailabilityAbsence, DateStart: on("10.02.2025"), DateEnd: on("14.02.2025"), Reason: text("Больничный"),
+			}))
+			draft, err = mgrs.SolveTimetable(ctx, managers.TimetableOptions{
+				DateStart: domain.ParseDMY("06.01.2025"),
+				DateEnd:   domain.ParseDMY("28.03.2025"),
+				Days:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday},
+				DayBegin:  domain.ParseTimeHM("14:00"),
+				DayEnd:    domain.ParseTimeHM("17:00"),
+				LessonIDs: []int{ensemble.LessonID},
+			})
+			if err != nil {
+				t.Fatalf("SolveTimetable failed: %v", err)
+			}
+			proposal, err = draft.Proposal()
+			if err != nil {
+				t.Fatalf("Proposal failed: %v", err)
+			}
+			assert.Empty(t, proposal.Slots)
+			if assert.Len(t, proposal.Unsatisfied, 1) {
+				assert.Equal(t, domain.ConstraintNoSlot, proposal.Unsatisfied[0].Constraint)
+			}
+		})
+	})
+}
@@ -0,0 +1,198 @@
+package engine_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"GO_Music/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_AcademicCalendar(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		dates := func(occurrences []domain.Occurrence) []string {
+			result := make([]string, len(occurrences))
+			for i, o := range occurrences {
+				result[i] = domain.ToDMY(o.Date)
+			}
+			return result
+		}
+		date := func(value string) *time.Time {
+			d := domain.ParseDMY(value)
+			return &d
+		}
+
+		lesson := env.lesson(env.employee("Иванова"), env.group(env.programm("Фортепиано"), "1 класс", 1), env.subject("Сольфеджио"), "Сольфеджио")
+
+		// Еженедельно по понедельникам с 02.09.2024 по 28.10.2024
+		mondays := &domain.Schedule{
+			LessonID:      lesson.LessonID,
+			DayWeek:       "Понедельник",
+			TimeBegin:     domain.ParseTimeHM("10:00"),
+			TimeEnd:       domain.ParseTimeHM("10:45"),
+			SchdDateStart: domain.ParseDMY("02.09.2024"),
+		}
+		if err := mgrs.Schedule.GenerateSchedule(ctx, mondays, domain.ParseDMY("28.10.2024")); err != nil {
+			t.Fatalf("GenerateSchedule failed: %v", err)
+		}
+		september := func(t *testing.T, includeCancelled bool) []domain.Occurrence {
+			occurrences, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.09.2024"), domain.ParseDMY("30.09.2024"), mondays.LessonID, includeCancelled)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			return occurrences
+		}
+
+		t.Run("Holiday added after the fact lists the cancelled occurrences", func(t *testing.T) {
+			holiday := &domain.CalendarEntry{
+				Kind:      domain.CalendarHoliday,
+				Name:      "День школы",
+				DateStart: domain.ParseDMY("16.09.2024"),
+				DateEnd:   domain.ParseDMY("17.09.2024"),
+			}
+			affected, err := mgrs.AddCalendarEntry(ctx, holiday)
+			if err != nil {
+				t.Fatalf("AddCalendarEntry failed: %v", err)
+			}
+			assert.Equal(t, []string{"16.09.2024"}, dates(affected))
+			assert.True(t, affected[0].Closed)
+
+			assert.Equal(t, []string{"02.09.2024", "09.09.2024", "23.09.2024", "30.09.2024"}, dates(september(t, false)))
+
+			all := september(t, true)
+			assert.Equal(t, []string{"02.09.2024", "09.09.2024", "16.09.2024", "23.09.2024", "30.09.2024"}, dates(all))
+			assert.True(t, all[2].Cancelled)
+			assert.Equal(t, "День школы", *all[2].Reason)
+		})
+
+		t.Run("Swap moves the occurrences of the worked date", func(t *testing.T) {
+			swap := &domain.CalendarEntry{
+				Kind:      domain.CalendarSwap,
+				Name:      "Суббота по расписанию понедельника",
+				DateStart: domain.ParseDMY("28.09.2024"),
+				DateEnd:   domain.ParseDMY("28.09.2024"),
+				WorkedAs:  date("30.09.2024"),
+			}
+			affected, err := mgrs.AddCalendarEntry(ctx, swap)
+			if err != nil {
+				t.Fatalf("AddCalendarEntry failed: %v", err)
+			}
+			assert.Equal(t, []string{"28.09.2024"}, dates(affected))
+			assert.Equal(t, "30.09.2024", domain.ToDMY(affected[0].OriginalDate))
+
+			assert.Equal(t, []string{"02.09.2024", "09.09.2024", "23.09.2024", "28.09.2024"}, dates(september(t, false)))
+
+			// День переноса в периоде, дата занятий - за его пределами
+			weekend, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("28.09.2024"), domain.ParseDMY("29.09.2024"), 0, false)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"28.09.2024"}, dates(weekend))
+			assert.True(t, weekend[0].Swapped)
+		})
+
+		t.Run("Days outside the terms are closed", func(t *testing.T) {
+			term := &domain.CalendarEntry{
+				Kind:      domain.CalendarTerm,
+				Name:      "I четверть",
+				DateStart: domain.ParseDMY("02.09.2024"),
+				DateEnd:   domain.ParseDMY("25.10.2024"),
+			}
+			if _, err := mgrs.AddCalendarEntry(ctx, term); err != nil {
+				t.Fatalf("AddCalendarEntry failed: %v", err)
+			}
+
+			october, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.10.2024"), domain.ParseDMY("31.10.2024"), mondays.LessonID, true)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"07.10.2024", "14.10.2024", "21.10.2024", "28.10.2024"}, dates(october))
+			assert.False(t, october[2].Cancelled)
+			assert.True(t, october[3].Closed)
+			assert.Equal(t, "Каникулы", *october[3].Reason)
+		})
+
+		t.Run("Inconsistent entries are rejected", func(t *testing.T) {
+			for name, entry := range map[string]*domain.CalendarEntry{
+				"swap without worked_as": {Kind: domain.CalendarSwap, Name: "Перенос", DateStart: domain.ParseDMY("05.10.2024"), DateEnd: domain.ParseDMY("05.10.2024")},
+				"swap over several days": {Kind: domain.CalendarSwap, Name: "Перенос", DateStart: domain.ParseDMY("05.10.2024"), DateEnd: domain.ParseDMY("06.10.2024"), WorkedAs: date("07.10.2024")},
+				"end before start":       {Kind: domain.CalendarHoliday, Name: "Праздник", DateStart: domain.ParseDMY("05.10.2024"), DateEnd: domain.ParseDMY("04.10.2024")},
+			} {
+				_, err := mgrs.AddCalendarEntry(ctx, entry)
+				assert.True(t, errors.Is(err, domain.ErrInvalidCalendar), "%s: got %v", name, err)
+			}
+		})
+
+		t.Run("iCalendar import is idempotent by UID", func(t *testing.T) {
+			ics := strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"BEGIN:VEVENT",
+				"UID:teachers-day-2024",
+				"DTSTART;VALUE=DATE:20241007",
+				"DTEND;VALUE=DATE:20241008",
+				"SUMMARY:День учителя",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:swap-2024-10-19",
+				"DTSTART;VALUE=DATE:20241019",
+				"X-WORKED-AS;VALUE=DATE:20241021",
+				"SUMMARY:Перенос\\, суббота",
+				"CATEGORIES:SWAP",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			}, "\r\n")
+
+			entries, err := domain.ParseICalendar(strings.NewReader(ics))
+			if err != nil {
+				t.Fatalf("ParseICalendar failed: %v", err)
+			}
+			assert.Len(t, entries, 2)
+			assert.Equal(t, "07.10.2024", domain.ToDMY(entries[0].DateEnd))
+			assert.Equal(t, "Перенос, суббота", entries[1].Name)
+
+			result, err := mgrs.ImportCalendar(ctx, entries)
+			if err != nil {
+				t.Fatalf("ImportCalendar failed: %v", err)
+			}
+			assert.Len(t, result.Created, 2)
+			assert.Equal(t, []string{"07.10.2024", "19.10.2024"}, dates(result.Affected))
+
+			entries, err = domain.ParseICalendar(strings.NewReader(ics))
+			if err != nil {
+				t.Fatalf("ParseICalendar failed: %v", err)
+			}
+			result, err = mgrs.ImportCalendar(ctx, entries)
+			if err != nil {
+				t.Fatalf("ImportCalendar failed: %v", err)
+			}
+			assert.Empty(t, result.Created)
+			assert.Len(t, result.Updated, 2)
+		})
+
+		t.Run("CSV import", func(t *testing.T) {
+			csv := "kind;name;date_start;date_end;worked_as;uid\n" +
+				"holiday;Осенние каникулы;28.10.2024;03.11.2024;;autumn-2024\n"
+
+			entries, err := domain.ParseCalendarCSV(strings.NewReader(csv))
+			if err != nil {
+				t.Fatalf("ParseCalendarCSV failed: %v", err)
+			}
+			result, err := mgrs.ImportCalendar(ctx, entries)
+			if err != nil {
+				t.Fatalf("ImportCalendar failed: %v", err)
+			}
+			assert.Len(t, result.Created, 1)
+			assert.Equal(t, []string{"28.10.2024"}, dates(result.Affected))
+			assert.Equal(t, "Осенние каникулы", *result.Affected[0].Reason)
+
+			_, err = domain.ParseCalendarCSV(strings.NewReader("kind,name\nholiday,Праздник\n"))
+			assert.True(t, errors.Is(err, domain.ErrInvalidCalendar), "missing date_start: got %v", err)
+		})
+	})
+}
@@ -0,0 +1,204 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_ScheduleConflicts(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		rule := func(value string) *string { return &value }
+
+		programm := env.programm("Фортепиано")
+		first, second := env.group(programm, "1 класс", 1), env.group(programm, "2 класс", 2)
+		student := env.student(first, "Иванов", "Петр")
+		rooms := []*domain.Audience{env.audience("101", 20), env.audience("102", 20), env.audience("103", 2)}
+
+		// Первая группа у первого преподавателя в первой аудитории, вторая - у второго во второй,
+		// индивидуальное занятие студента первой группы у третьего преподавателя в третьей аудитории
+		lesson := func(teacher string, room *domain.Audience, group *domain.StudyGroup, studentID *int, name string) *domain.Lesson {
+			lesson := &domain.Lesson{
+				EmployeeID: env.employee(teacher).EmployeeID, AudienceID: &room.AudienceID, GroupID: group.GroupID,
+				StudentID: studentID, SubjectID: env.subject(name).SubjectID, LessonName: name,
+			}
+			env.must(mgrs.Lesson.Create(ctx, lesson))
+			return lesson
+		}
+		choir := lesson("Иванова", rooms[0], first, nil, "Хор")
+		solfeggio := lesson("Петрова", rooms[1], second, nil, "Сольфеджио")
+		piano := lesson("Сидорова", rooms[2], first, &student.StudentID, "Фортепиано")
+
+		schedule := func(lesson *domain.Lesson, day, begin, end, from, to string) *domain.Schedule {
+			return &domain.Schedule{
+				LessonID:      lesson.LessonID,
+				DayWeek:       day,
+				TimeBegin:     domain.ParseTimeHM(begin),
+				TimeEnd:       domain.ParseTimeHM(end),
+				SchdDateStart: domain.ParseDMY(from),
+				SchdDateEnd:   domain.ParseDMY(to),
+			}
+		}
+
+		choirMondays := schedule(choir, "Понедельник", "10:00", "11:00", "02.09.2024", "23.12.2024")
+		if err := mgrs.Schedule.Create(ctx, choirMondays); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		t.Run("Unrelated lessons at the same time do not conflict", func(t *testing.T) {
+			err := mgrs.Schedule.Create(ctx, schedule(solfeggio, "Понедельник", "10:00", "11:00", "02.09.2024", "23.12.2024"))
+			assert.NoError(t, err)
+		})
+
+		t.Run("Shared audience conflicts", func(t *testing.T) {
+			solfeggio.AudienceID = &rooms[0].AudienceID
+			err := mgrs.Lesson.Update(ctx, solfeggio)
+			assert.True(t, errors.Is(err, domain.ErrScheduleConflict), "got %v", err)
+
+			var conflict *domain.ConflictError
+			if assert.True(t, errors.As(err, &conflict)) && assert.Len(t, conflict.Conflicts, 1) {
+				c := conflict.Conflicts[0]
+				assert.Equal(t, domain.Resource{Kind: domain.ResourceAudience, ID: rooms[0].AudienceID}, c.Resource)
+				assert.Equal(t, choir.LessonID, c.LessonID)
+				assert.Equal(t, "Хор", c.LessonName)
+				assert.Equal(t, choirMondays.ScheduleID, c.ScheduleID)
+				assert.Equal(t, "02.09.2024 10:00-11:00", domain.ToDMY(c.Date)+" "+domain.ToTimeHM(c.TimeBegin)+"-"+domain.ToTimeHM(c.TimeEnd))
+			}
+			solfeggio.AudienceID = &rooms[1].AudienceID
+		})
+
+		t.Run("Date ranges must intersect", func(t *testing.T) {
+			conflicts, err := mgrs.ScheduleConflicts(ctx, schedule(choir, "Понедельник", "10:30", "11:30", "13.01.2025", "26.05.2025"))
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			assert.Empty(t, conflicts)
+
+			conflicts, err = mgrs.ScheduleConflicts(ctx, schedule(choir, "Понедельник", "10:30", "11:30", "16.12.2024", "26.05.2025"))
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			if assert.Len(t, conflicts, 3) {
+				assert.Equal(t, "16.12.2024", domain.ToDMY(conflicts[0].Date))
+			}
+		})
+
+		t.Run("Alternating biweekly series do not conflict", func(t *testing.T) {
+			// Хор идет каждую неделю; сравниваем две серии через неделю у другой группы
+			odd := schedule(solfeggio, "", "12:00", "13:00", "02.09.2024", "")
+			odd.RRule = rule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;UNTIL=20241223")
+			if err := mgrs.Schedule.Create(ctx, odd); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			even := schedule(solfeggio, "", "12:00", "13:00", "09.09.2024", "")
+			even.RRule = rule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;UNTIL=20241223")
+			assert.NoError(t, mgrs.Schedule.Create(ctx, even))
+
+			same := schedule(solfeggio, "", "12:30", "13:30", "16.09.2024", "")
+			same.RRule = rule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;COUNT=3")
+			err := mgrs.Schedule.Create(ctx, same)
+			assert.True(t, errors.Is(err, domain.ErrScheduleConflict), "got %v", err)
+		})
+
+		t.Run("Individual student is busy during group lessons", func(t *testing.T) {
+			err := mgrs.Schedule.Create(ctx, schedule(piano, "Понедельник", "10:45", "11:30", "02.09.2024", "23.12.2024"))
+			var conflict *domain.ConflictError
+			if assert.True(t, errors.As(err, &conflict), "got %v", err) {
+				assert.Equal(t, domain.Resource{Kind: domain.ResourceStudent, ID: student.StudentID}, conflict.Conflicts[0].Resource)
+				assert.Equal(t, choir.LessonID, conflict.Conflicts[0].LessonID)
+			}
+
+			// Занятия, которые только касаются друг друга, не пересекаются
+			assert.NoError(t, mgrs.Schedule.Create(ctx, schedule(piano, "Понедельник", "11:00", "11:45", "02.09.2024", "23.12.2024")))
+		})
+
+		t.Run("Cancelled occurrence frees its slot", func(t *testing.T) {
+			once := schedule(choir, "Понедельник", "10:00", "10:30", "07.10.2024", "07.10.2024")
+			conflicts, err := mgrs.ScheduleConflicts(ctx, once)
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			assert.Len(t, conflicts, 3)
+
+			if _, err := mgrs.CancelOccurrence(ctx, choirMondays.ScheduleID, domain.ParseDMY("07.10.2024"), nil); err != nil {
+				t.Fatalf("CancelOccurrence failed: %v", err)
+			}
+			conflicts, err = mgrs.ScheduleConflicts(ctx, once)
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			assert.Empty(t, conflicts)
+		})
+
+		t.Run("Moved occurrence is checked at its new date and time", func(t *testing.T) {
+			move := func(to string) error {
+				date := domain.ParseDMY(to)
+				_, err := mgrs.EditOccurrence(ctx, choirMondays.ScheduleID, domain.ParseDMY("14.10.2024"), domain.ScheduleException{NewDate: &date})
+				return err
+			}
+
+			// В новую дату идет другое занятие той же серии
+			err := move("21.10.2024")
+			var conflict *domain.ConflictError
+			if assert.True(t, errors.As(err, &conflict), "got %v", err) {
+				assert.Equal(t, choirMondays.ScheduleID, conflict.Conflicts[0].ScheduleID)
+				assert.Equal(t, "21.10.2024", domain.ToDMY(conflict.Conflicts[0].Date))
+			}
+
+			if err := move("22.10.2024"); err != nil {
+				t.Fatalf("EditOccurrence failed: %v", err)
+			}
+			conflicts, err := mgrs.ScheduleConflicts(ctx, schedule(choir, "Вторник", "10:30", "11:30", "22.10.2024", "22.10.2024"))
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			if assert.Len(t, conflicts, 3) {
+				assert.Equal(t, "22.10.2024", domain.ToDMY(conflicts[0].Date))
+			}
+			conflicts, err = mgrs.ScheduleConflicts(ctx, schedule(choir, "Понедельник", "10:00", "10:30", "14.10.2024", "14.10.2024"))
+			if err != nil {
+				t.Fatalf("ScheduleConflicts failed: %v", err)
+			}
+			assert.Empty(t, conflicts, "the slot the occurrence was moved from is free")
+		})
+
+		t.Run("Resource availability uses the same occurrences", func(t *testing.T) {
+			at := func(date, hm string) time.Time {
+				t, _ := time.Parse("02.01.2006 15:04", date+" "+hm)
+				return t
+			}
+			teacher := domain.Resource{Kind: domain.ResourceEmployee, ID: choir.EmployeeID}
+			room := domain.Resource{Kind: domain.ResourceAudience, ID: rooms[0].AudienceID}
+
+			for _, resource := range []domain.Resource{teacher, room} {
+				free, err := mgrs.ResourceAvailable(ctx, resource, at("21.10.2024", "10:30"), at("21.10.2024", "11:30"), 0)
+				if err != nil {
+					t.Fatalf("ResourceAvailable failed: %v", err)
+				}
+				assert.False(t, free, "%s is busy with the choir", resource.Kind)
+
+				free, err = mgrs.ResourceAvailable(ctx, resource, at("21.10.2024", "10:30"), at("21.10.2024", "11:30"), choir.LessonID)
+				if err != nil {
+					t.Fatalf("ResourceAvailable failed: %v", err)
+				}
+				assert.True(t, free, "the checked lesson itself is ignored")
+
+				// Занятие 07.10 отменено, 14.10 перенесено на вторник, 30.12 - после конца серии
+				for _, date := range []string{"07.10.2024", "14.10.2024", "30.12.2024"} {
+					free, err = mgrs.ResourceAvailable(ctx, resource, at(date, "10:30"), at(date, "11:30"), 0)
+					if err != nil {
+						t.Fatalf("ResourceAvailable failed: %v", err)
+					}
+					assert.True(t, free, "%s is free on %s", resource.Kind, date)
+				}
+			}
+		})
+	})
+}
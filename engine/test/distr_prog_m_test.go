@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestProgrammDistributionManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -49,8 +38,8 @@ func TestProgrammDistributionManager_AllMethods(t *testing.T) {
 
 	// Тестовые данные
 	testDistribution := &domain.ProgrammDistribution{
-		MusprogrammID: 1,
-		SubjectID:     2,
+		MusprogrammID: fx.Programms[0].MusprogrammID,
+		SubjectID:     fx.Subjects[1].SubjectID,
 	}
 
 	t.Run("Create", func(t *testing.T) {
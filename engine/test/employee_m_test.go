@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestEmployeeManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
 
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -48,7 +37,7 @@ func TestEmployeeManager_AllMethods(t *testing.T) {
 	mgr := managers.NewEmployeeManager(repo, sqlDB, levelLogger, 5*time.Second)
 
 	// Тестовые данные
-	UserID := 5
+	UserID := fx.Users[0].UserID
 	testEmployee := &domain.Employee{
 		PhoneNumber:    "79109532210",
 		UserID:         &UserID,
@@ -170,7 +159,7 @@ func TestEmployeeManager_AllMethods(t *testing.T) {
 	})
 
 	t.Run("BulkCreate", func(t *testing.T) {
-		UserID_1, UserID_2 := 6, 7
+		UserID_1, UserID_2 := fx.Users[1].UserID, fx.Users[2].UserID
 		FatherName := "Владимирович"
 		emps := []*domain.Employee{
 			{
@@ -184,7 +173,7 @@ func TestEmployeeManager_AllMethods(t *testing.T) {
 				FatherName:     &FatherName,
 			},
 			{
-				PhoneNumber:    "79109887645",
+				PhoneNumber:    "79109887646",
 				UserID:         &UserID_2,
 				WorkExperience: 4,
 				Birthday:       time.Date(1988, 8, 8, 0, 0, 0, 0, time.UTC),
@@ -0,0 +1,122 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Events(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs, repos := env.ctx, env.mgrs, env.repos
+		events := mgrs.Subject.Events()
+
+		var (
+			mu    sync.Mutex
+			async []string
+		)
+		events.SubscribeAsync(engine.AfterCreate, func(ctx context.Context, e engine.Event[domain.Subject]) {
+			mu.Lock()
+			defer mu.Unlock()
+			async = append(async, e.New.SubjectName)
+		})
+
+		errForbidden := errors.New("forbidden name")
+		events.Subscribe(engine.BeforeCreate, func(ctx context.Context, e engine.Event[domain.Subject]) error {
+			if e.Old != nil || e.Tx == nil {
+				t.Errorf("unexpected BeforeCreate event: %+v", e)
+			}
+			if e.New.SubjectName == "Запрещенный" {
+				return errForbidden
+			}
+			return nil
+		})
+
+		var updated []engine.Event[domain.Subject]
+		events.Subscribe(engine.AfterUpdate, func(ctx context.Context, e engine.Event[domain.Subject]) error {
+			updated = append(updated, e)
+			return nil
+		})
+
+		// Перед удалением предмета обработчик пишет в другую таблицу в той же транзакции и отменяет удаление
+		events.Subscribe(engine.BeforeDelete, func(ctx context.Context, e engine.Event[domain.Subject]) error {
+			audience := &domain.Audience{Name: "След удаления", AudinType: "Класс", AudinNumber: "999", Capacity: 1}
+			if err := repos.Audience.WithTx(e.Tx).Create(ctx, audience); err != nil {
+				return err
+			}
+			return errForbidden
+		})
+
+		subject := &domain.Subject{SubjectName: "Сольфеджио", SubjectType: "Теория", ShortDesc: "Теория музыки"}
+
+		t.Run("Async handler runs after commit", func(t *testing.T) {
+			if err := mgrs.Subject.Create(ctx, subject); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			subject.SubjectName = "Изменено после создания"
+			events.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, []string{"Сольфеджио"}, async)
+		})
+
+		t.Run("Sync handler vetoes create", func(t *testing.T) {
+			err := mgrs.Subject.Create(ctx, &domain.Subject{SubjectName: "Запрещенный", SubjectType: "Теория", ShortDesc: "-"})
+			assert.ErrorIs(t, err, engine.ErrVetoed)
+			assert.ErrorIs(t, err, errForbidden)
+
+			count, err := mgrs.Subject.Count(ctx, db.Filter{})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+		})
+
+		t.Run("Update carries old and new values", func(t *testing.T) {
+			subject.SubjectName = "Сольфеджио"
+			subject.ShortDesc = "Основы теории музыки"
+			if err := mgrs.Subject.Update(ctx, subject); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+			if assert.Len(t, updated, 1) {
+				assert.Equal(t, "Теория музыки", updated[0].Old.ShortDesc)
+				assert.Equal(t, "Основы теории музыки", updated[0].New.ShortDesc)
+			}
+		})
+
+		t.Run("Veto rolls back handler writes", func(t *testing.T) {
+			err := mgrs.Subject.Delete(ctx, subject.SubjectID)
+			assert.ErrorIs(t, err, engine.ErrVetoed)
+
+			exists, err := mgrs.Subject.Exists(ctx, subject.SubjectID)
+			assert.NoError(t, err)
+			assert.True(t, exists)
+
+			audiences, err := repos.Audience.Count(ctx, db.Filter{})
+			assert.NoError(t, err)
+			assert.Zero(t, audiences)
+		})
+
+		t.Run("Rolled back transaction skips async handlers", func(t *testing.T) {
+			errAbort := errors.New("abort")
+			err := mgrs.Subject.ExecuteInTx(ctx, env.db, func(repo db.Repository[domain.Subject, int]) error {
+				if err := repo.Create(ctx, &domain.Subject{SubjectName: "Хор", SubjectType: "Практика", ShortDesc: "Хоровое пение"}); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			events.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, []string{"Сольфеджио"}, async)
+		})
+	})
+}
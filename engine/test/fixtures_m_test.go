@@ -0,0 +1,147 @@
+package engine_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"GO_Music/db/dbtest"
+	"GO_Music/db/memory"
+	"GO_Music/db/repositories"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// managersEnv менеджеры поверх одного хранилища и фикстуры для тестов менеджеров
+type managersEnv struct {
+	t      *testing.T
+	ctx    context.Context
+	db     *sql.DB
+	repos  *repositories.Repositories
+	mgrs   *managers.Managers
+	logger *logger.LevelLogger
+	phones int // номера телефонов сотрудников уникальны
+}
+
+// managerStores хранилища, на которых идут тесты менеджеров; PostgreSQL пропускается без сервера (см. dbtest)
+var managerStores = []struct {
+	name string
+	open func(t *testing.T) (*sql.DB, *repositories.Repositories)
+}{
+	{"memory", func(t *testing.T) (*sql.DB, *repositories.Repositories) {
+		store := memory.NewStore()
+		return store.DB(), repositories.NewMemoryRepositories(store)
+	}},
+	{"postgres", func(t *testing.T) (*sql.DB, *repositories.Repositories) {
+		sqlDB := dbtest.New(t)
+		return sqlDB, repositories.NewRepositories(sqlDB)
+	}},
+}
+
+// runOnStores запускает test параллельно на каждом хранилище из managerStores с пустыми данными
+func runOnStores(t *testing.T, test func(t *testing.T, env *managersEnv)) {
+	t.Parallel()
+
+	for _, store := range managerStores {
+		t.Run(store.name, func(t *testing.T) {
+			t.Parallel()
+
+			levelLogger, err := logger.NewLevel("../../config/logger_config.yml")
+			if err != nil {
+				t.Fatalf("failed to create logger: %v", err)
+			}
+			t.Cleanup(func() { levelLogger.Sync() })
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			sqlDB, repos := store.open(t)
+			test(t, &managersEnv{
+				t:      t,
+				ctx:    ctx,
+				db:     sqlDB,
+				repos:  repos,
+				mgrs:   managers.NewManagers(sqlDB, repos, levelLogger, nil),
+				logger: levelLogger,
+			})
+		})
+	}
+}
+
+// must останавливает тест при ошибке подготовки данных
+func (env *managersEnv) must(err error) {
+	env.t.Helper()
+	if err != nil {
+		env.t.Fatalf("setup failed: %v", err)
+	}
+}
+
+// programm создает программу обучения на 4 часа в неделю
+func (env *managersEnv) programm(name string) *domain.Programm {
+	env.t.Helper()
+	programm := &domain.Programm{
+		ProgrammName: name, ProgrammType: "Предпрофессиональная", Duration: 8,
+		Instrument: &name, StudyLoad: 4, FinalCertificationForm: "Экзамен",
+	}
+	env.must(env.mgrs.Programm.Create(env.ctx, programm))
+	return programm
+}
+
+// group создает группу программы programm
+func (env *managersEnv) group(programm *domain.Programm, name string, studyYear int) *domain.StudyGroup {
+	env.t.Helper()
+	group := &domain.StudyGroup{MusProgrammID: programm.MusprogrammID, GroupName: name, StudyYear: studyYear}
+	env.must(env.mgrs.StudyGroup.Create(env.ctx, group))
+	return group
+}
+
+// student создает ученика группы group по ее программе
+func (env *managersEnv) student(group *domain.StudyGroup, surname, name string) *domain.Student {
+	env.t.Helper()
+	student := &domain.Student{
+		Surname: surname, Name: name, Birthday: domain.ParseDMY("01.01.2014"),
+		GroupID: group.GroupID, MusprogrammID: group.MusProgrammID,
+	}
+	env.must(env.mgrs.Student.Create(env.ctx, student))
+	return student
+}
+
+// employee создает преподавателя с новым номером телефона
+func (env *managersEnv) employee(surname string) *domain.Employee {
+	env.t.Helper()
+	env.phones++
+	employee := &domain.Employee{
+		Surname: surname, Name: "Мария", Birthday: domain.ParseDMY("01.01.1985"),
+		PhoneNumber: fmt.Sprintf("7910%07d", env.phones), Job: "Преподаватель", WorkExperience: 10,
+	}
+	env.must(env.mgrs.Employee.Create(env.ctx, employee))
+	return employee
+}
+
+// subject создает предмет
+func (env *managersEnv) subject(name string) *domain.Subject {
+	env.t.Helper()
+	subject := &domain.Subject{SubjectName: name, SubjectType: "Теория", ShortDesc: name}
+	env.must(env.mgrs.Subject.Create(env.ctx, subject))
+	return subject
+}
+
+// audience создает класс с номером number
+func (env *managersEnv) audience(number string, capacity int) *domain.Audience {
+	env.t.Helper()
+	audience := &domain.Audience{Name: "Класс " + number, AudinType: "Класс", AudinNumber: number, Capacity: capacity}
+	env.must(env.mgrs.Audience.Create(env.ctx, audience))
+	return audience
+}
+
+// lesson создает групповое занятие преподавателя teacher по предмету subject
+func (env *managersEnv) lesson(teacher *domain.Employee, group *domain.StudyGroup, subject *domain.Subject, name string) *domain.Lesson {
+	env.t.Helper()
+	lesson := &domain.Lesson{EmployeeID: teacher.EmployeeID, GroupID: group.GroupID, SubjectID: subject.SubjectID, LessonName: name}
+	env.must(env.mgrs.Lesson.Create(env.ctx, lesson))
+	return lesson
+}
@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestStudyGroupManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -50,7 +39,7 @@ func TestStudyGroupManager_AllMethods(t *testing.T) {
 	// Тестовые данные
 	testGroup := &domain.StudyGroup{
 		GroupName:        "TestGroup1",
-		MusProgrammID:    1,
+		MusProgrammID:    fx.Programms[0].MusprogrammID,
 		StudyYear:        2023,
 		NumberOfStudents: 10,
 	}
@@ -119,16 +108,11 @@ func TestStudyGroupManager_AllMethods(t *testing.T) {
 		assert.True(t, isUnique, "Nonexistent group name should be unique")
 	})
 
-	t.Run("UpdateStudentCount", func(t *testing.T) {
-		newCount := testGroup.NumberOfStudents + 5
-		err := mgr.UpdateStudentCount(ctx, testGroup.GroupID, newCount)
-		assert.NoError(t, err)
-
+	t.Run("NumberOfStudents is not set by hand", func(t *testing.T) {
 		gr, err := mgr.GetByID(ctx, testGroup.GroupID)
 		assert.NoError(t, err)
-		assert.NotNil(t, gr)
-		if gr != nil {
-			assert.Equal(t, newCount, gr.NumberOfStudents)
+		if assert.NotNil(t, gr) {
+			assert.Zero(t, gr.NumberOfStudents, "New group should have no students regardless of the request")
 		}
 	})
 
@@ -152,7 +136,7 @@ func TestStudyGroupManager_AllMethods(t *testing.T) {
 	t.Run("Create Duplicate Name", func(t *testing.T) {
 		dupGroup := &domain.StudyGroup{
 			GroupName:        testGroup.GroupName,
-			MusProgrammID:    2,
+			MusProgrammID:    fx.Programms[1].MusprogrammID,
 			StudyYear:        2024,
 			NumberOfStudents: 5,
 		}
@@ -164,13 +148,13 @@ func TestStudyGroupManager_AllMethods(t *testing.T) {
 		groups := []*domain.StudyGroup{
 			{
 				GroupName:        "BulkGroup1",
-				MusProgrammID:    1,
+				MusProgrammID:    fx.Programms[0].MusprogrammID,
 				StudyYear:        2022,
 				NumberOfStudents: 15,
 			},
 			{
 				GroupName:        "BulkGroup2",
-				MusProgrammID:    1,
+				MusProgrammID:    fx.Programms[0].MusprogrammID,
 				StudyYear:        2023,
 				NumberOfStudents: 20,
 			},
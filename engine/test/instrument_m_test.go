@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestInstrumentManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -51,7 +40,7 @@ func TestInstrumentManager_AllMethods(t *testing.T) {
 	testInstrument := &domain.Instrument{
 		Name:       "TestInstrument1",
 		InstrType:  "String",
-		AudienceID: 1,
+		AudienceID: fx.Audiences[0].AudienceID,
 		Condition:  "Good",
 		// Добавьте обязательные поля, если есть
 	}
@@ -151,7 +140,7 @@ func TestInstrumentManager_AllMethods(t *testing.T) {
 		dupInstrument := &domain.Instrument{
 			Name:       testInstrument.Name,
 			InstrType:  "Wind",
-			AudienceID: 2,
+			AudienceID: fx.Audiences[1].AudienceID,
 			Condition:  "Fair",
 		}
 		err := mgr.Create(ctx, dupInstrument)
@@ -163,13 +152,13 @@ func TestInstrumentManager_AllMethods(t *testing.T) {
 			{
 				Name:       "BulkInstrument1",
 				InstrType:  "Percussion",
-				AudienceID: 1,
+				AudienceID: fx.Audiences[0].AudienceID,
 				Condition:  "Good",
 			},
 			{
 				Name:       "BulkInstrument2",
 				InstrType:  "Keyboard",
-				AudienceID: 1,
+				AudienceID: fx.Audiences[0].AudienceID,
 				Condition:  "Good",
 			},
 		}
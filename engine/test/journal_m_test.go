@@ -0,0 +1,206 @@
+package engine_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_LessonJournal(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		programm := env.programm("Фортепиано")
+		first, second := env.group(programm, "1 класс", 1), env.group(programm, "2 класс", 2)
+		anna, boris := env.student(first, "Смирнов", "Анна"), env.student(first, "Смирнов", "Борис")
+		vera := env.student(second, "Смирнов", "Вера")
+
+		solfeggio := env.lesson(env.employee("Иванова"), first, env.subject("Сольфеджио"), "Сольфеджио")
+		individual := &domain.Lesson{
+			EmployeeID: env.employee("Петрова").EmployeeID, GroupID: second.GroupID, StudentID: &vera.StudentID,
+			SubjectID: env.subject("Фортепиано").SubjectID, LessonName: "Фортепиано",
+		}
+		env.must(mgrs.Lesson.Create(ctx, individual))
+		weekly := &domain.Schedule{
+			LessonID: solfeggio.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("10:00"), TimeEnd: domain.ParseTimeHM("11:00"),
+			SchdDateStart: domain.ParseDMY("02.09.2024"), SchdDateEnd: domain.ParseDMY("30.09.2024"),
+		}
+		for _, schedule := range []*domain.Schedule{
+			weekly,
+			{LessonID: individual.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("12:00"), TimeEnd: domain.ParseTimeHM("13:00"),
+				SchdDateStart: domain.ParseDMY("02.09.2024"), SchdDateEnd: domain.ParseDMY("30.09.2024")},
+		} {
+			env.must(mgrs.Schedule.Create(ctx, schedule))
+		}
+
+		date := domain.ParseDMY("09.09.2024")
+		// Отметка, сделанная до журнала без привязки к занятию
+		env.must(mgrs.Attendance.Create(ctx, &domain.StudentAttendance{StudentID: boris.StudentID, LessonID: solfeggio.LessonID, PresenceMark: true, AttendanceDate: "2024-09-09"}))
+
+		count := func(t *testing.T) (attendance, grades int) {
+			t.Helper()
+			attendance, err := mgrs.Attendance.Count(ctx, db.Filter{})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			grades, err = mgrs.Assessment.Count(ctx, db.Filter{})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			return attendance, grades
+		}
+
+		t.Run("Journal lists the group roster", func(t *testing.T) {
+			journal, err := mgrs.LessonJournal(ctx, solfeggio.LessonID, date)
+			if err != nil {
+				t.Fatalf("LessonJournal failed: %v", err)
+			}
+			if !assert.Len(t, journal.Entries, 2) {
+				return
+			}
+			assert.Nil(t, journal.Entry(anna.StudentID).Attendance)
+			if assert.NotNil(t, journal.Entry(boris.StudentID).Attendance) {
+				assert.True(t, journal.Entry(boris.StudentID).Attendance.PresenceMark)
+			}
+			assert.Nil(t, journal.Entry(vera.StudentID))
+
+			individualJournal, err := mgrs.LessonJournal(ctx, individual.LessonID, date)
+			if err != nil {
+				t.Fatalf("LessonJournal failed: %v", err)
+			}
+			if assert.Len(t, individualJournal.Entries, 1) {
+				assert.Equal(t, vera.StudentID, individualJournal.Entries[0].Student.StudentID)
+			}
+		})
+
+		var dictation int
+
+		t.Run("Saving twice writes the journal once", func(t *testing.T) {
+			topic := "Интервалы"
+			changes := managers.JournalChanges{Topic: &topic, Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: true, Grades: []managers.JournalGrade{{TaskType: "Диктант", Grade: 5}}},
+				{StudentID: boris.StudentID, Present: false},
+			}}
+			journal, err := mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, date, changes)
+			if err != nil {
+				t.Fatalf("SaveLessonJournal failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrenceHeld, journal.Occurrence.Status)
+			assert.Equal(t, topic, *journal.Occurrence.Topic)
+			assert.False(t, journal.Entry(boris.StudentID).Attendance.PresenceMark)
+			assert.NotNil(t, journal.Entry(boris.StudentID).Attendance.OccurrenceID, "the earlier mark is linked, not duplicated")
+			if !assert.Len(t, journal.Entry(anna.StudentID).Grades, 1) {
+				return
+			}
+			dictation = journal.Entry(anna.StudentID).Grades[0].AssessmentNoteID
+
+			changes.Marks[0].Grades[0].AssessmentNoteID = dictation
+			if _, err := mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, date, changes); err != nil {
+				t.Fatalf("SaveLessonJournal failed: %v", err)
+			}
+			attendance, grades := count(t)
+			assert.Equal(t, 2, attendance)
+			assert.Equal(t, 1, grades)
+		})
+
+		t.Run("Grades of a student are replaced by the saved list", func(t *testing.T) {
+			journal, err := mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, date, managers.JournalChanges{Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: true, Grades: []managers.JournalGrade{{TaskType: "Устный ответ", Grade: 4}}},
+			}})
+			if err != nil {
+				t.Fatalf("SaveLessonJournal failed: %v", err)
+			}
+			grades := journal.Entry(anna.StudentID).Grades
+			if assert.Len(t, grades, 1) {
+				assert.Equal(t, "Устный ответ", grades[0].TaskType)
+				assert.NotEqual(t, dictation, grades[0].AssessmentNoteID)
+			}
+		})
+
+		t.Run("Students outside the roster are rejected as a whole", func(t *testing.T) {
+			_, err := mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, date, managers.JournalChanges{Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: false},
+				{StudentID: vera.StudentID, Present: true},
+			}})
+			assert.True(t, errors.Is(err, domain.ErrInvalidJournal), "got %v", err)
+
+			_, err = mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, date, managers.JournalChanges{Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: false},
+				{StudentID: boris.StudentID, Present: true, Grades: []managers.JournalGrade{{AssessmentNoteID: dictation, TaskType: "Диктант", Grade: 3}}},
+			}})
+			assert.True(t, errors.Is(err, domain.ErrInvalidJournal), "got %v", err)
+
+			journal, err := mgrs.LessonJournal(ctx, solfeggio.LessonID, date)
+			if err != nil {
+				t.Fatalf("LessonJournal failed: %v", err)
+			}
+			assert.True(t, journal.Entry(anna.StudentID).Attendance.PresenceMark, "a rejected journal changes nothing")
+		})
+
+		t.Run("Concurrent saves mark a student once", func(t *testing.T) {
+			concurrent := domain.ParseDMY("23.09.2024")
+			changes := managers.JournalChanges{Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: true},
+				{StudentID: boris.StudentID, Present: true},
+			}}
+			errs := make([]error, 2)
+			var wg sync.WaitGroup
+			for i := range errs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, concurrent, changes)
+				}()
+			}
+			wg.Wait()
+			saved := 0
+			for _, err := range errs {
+				if err == nil {
+					saved++
+					continue
+				}
+				assert.True(t, errors.Is(err, db.ErrDuplicate) || errors.Is(err, db.ErrVersionConflict), "got %v", err)
+			}
+			assert.NotZero(t, saved)
+
+			journal, err := mgrs.LessonJournal(ctx, solfeggio.LessonID, concurrent)
+			if err != nil {
+				t.Fatalf("LessonJournal failed: %v", err)
+			}
+			count, err := mgrs.Attendance.Count(ctx, db.Filter{Conditions: []db.Condition{
+				{Field: "occurrence_id", Operator: "=", Value: journal.Occurrence.OccurrenceID},
+			}})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			assert.Equal(t, 2, count)
+
+			// Вторую отметку на то же занятие не пропускает уникальный ключ
+			err = mgrs.Attendance.Create(ctx, &domain.StudentAttendance{
+				StudentID: anna.StudentID, LessonID: solfeggio.LessonID, PresenceMark: false, AttendanceDate: "2024-09-23",
+				OccurrenceID: &journal.Occurrence.OccurrenceID,
+			})
+			assert.True(t, errors.Is(err, db.ErrDuplicate), "got %v", err)
+		})
+
+		t.Run("Cancelled occurrence has no journal to save", func(t *testing.T) {
+			cancelled := domain.ParseDMY("16.09.2024")
+			if _, err := mgrs.CancelOccurrence(ctx, weekly.ScheduleID, cancelled, nil); err != nil {
+				t.Fatalf("CancelOccurrence failed: %v", err)
+			}
+			_, err := mgrs.SaveLessonJournal(ctx, solfeggio.LessonID, cancelled, managers.JournalChanges{Marks: []managers.JournalMark{
+				{StudentID: anna.StudentID, Present: true},
+			}})
+			assert.True(t, errors.Is(err, domain.ErrInvalidJournal), "got %v", err)
+
+			_, err = mgrs.LessonJournal(ctx, solfeggio.LessonID, domain.ParseDMY("10.09.2024"))
+			assert.True(t, errors.Is(err, domain.ErrNoOccurrence), "got %v", err)
+		})
+	})
+}
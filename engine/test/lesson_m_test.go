@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestLessonManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -48,13 +37,13 @@ func TestLessonManager_AllMethods(t *testing.T) {
 	mgr := managers.NewLessonManager(repo, sqlDB, levelLogger, 5*time.Second)
 
 	// Тестовые данные
-	StudentID, AudienceID := 4, 3
+	StudentID, AudienceID := fx.Students[2].StudentID, fx.Audiences[2].AudienceID
 	testLesson := &domain.Lesson{
 		LessonName: "Testlesson",
-		EmployeeID: 7,
-		GroupID:    1,
+		EmployeeID: fx.Employees[2].EmployeeID,
+		GroupID:    fx.Groups[0].GroupID,
 		StudentID:  &StudentID,
-		SubjectID:  1,
+		SubjectID:  fx.Subjects[0].SubjectID,
 		AudienceID: &AudienceID,
 	}
 
@@ -139,46 +128,22 @@ func TestLessonManager_AllMethods(t *testing.T) {
 		assert.True(t, found, "Created lesson should be in GetByAudience result")
 	})
 
-	now := time.Now()
-	start := now
-	end := now.Add(1 * time.Hour)
-
-	t.Run("CheckEmployeeAvailability", func(t *testing.T) {
-		available, err := mgr.CheckEmployeeAvailability(ctx, testLesson.EmployeeID, start, end, 0)
-		if err != nil {
-			t.Errorf("CheckEmployeeAvailability returned error: %v", err)
-			levelLogger.Error("CheckEmployeeAvailability error", logger.Field{Key: "error", Value: err})
-			return
-		}
-		t.Logf("Employee availability: %v", available)
-	})
-
-	t.Run("CheckAudienceAvailability", func(t *testing.T) {
-		available, err := mgr.CheckAudienceAvailability(ctx, *testLesson.AudienceID, start, end, 0)
-		if err != nil {
-			t.Errorf("CheckAudienceAvailability returned error: %v", err)
-			levelLogger.Error("CheckAudienceAvailability error", logger.Field{Key: "error", Value: err})
-			return
-		}
-		t.Logf("Audience availability: %v", available)
-	})
-
 	t.Run("BulkCreate", func(t *testing.T) {
 		lessons := []*domain.Lesson{
 			{
 				LessonName: "BulkLesson1",
-				EmployeeID: 6,
-				GroupID:    3,
+				EmployeeID: fx.Employees[1].EmployeeID,
+				GroupID:    fx.Groups[2].GroupID,
 				StudentID:  &StudentID,
-				SubjectID:  3,
+				SubjectID:  fx.Subjects[2].SubjectID,
 				AudienceID: &AudienceID,
 			},
 			{
 				LessonName: "BulkLesson2",
-				EmployeeID: 7,
-				GroupID:    2,
+				EmployeeID: fx.Employees[2].EmployeeID,
+				GroupID:    fx.Groups[1].GroupID,
 				StudentID:  &StudentID,
-				SubjectID:  1,
+				SubjectID:  fx.Subjects[0].SubjectID,
 				AudienceID: &AudienceID,
 			},
 		}
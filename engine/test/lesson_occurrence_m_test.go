@@ -0,0 +1,202 @@
+package engine_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_LessonOccurrence(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		ivanova, petrova := env.employee("Иванова"), env.employee("Петрова")
+		subject := env.subject("Сольфеджио")
+		for _, teacher := range []*domain.Employee{ivanova, petrova} {
+			env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: teacher.EmployeeID, SubjectID: subject.SubjectID}))
+		}
+		group := env.group(env.programm("Фортепиано"), "1 класс", 1)
+		var students []*domain.Student
+		for _, name := range []string{"Анна", "Борис", "Вера"} {
+			students = append(students, env.student(group, "Смирнов", name))
+		}
+
+		room := env.audience("201", 20)
+		solfeggio := &domain.Lesson{EmployeeID: ivanova.EmployeeID, AudienceID: &room.AudienceID, GroupID: group.GroupID, SubjectID: subject.SubjectID, LessonName: "Сольфеджио"}
+		env.must(mgrs.Lesson.Create(ctx, solfeggio))
+		choir := env.lesson(petrova, group, subject, "Хор")
+		weekly := &domain.Schedule{
+			LessonID: solfeggio.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("10:00"), TimeEnd: domain.ParseTimeHM("11:00"),
+			SchdDateStart: domain.ParseDMY("02.09.2024"), SchdDateEnd: domain.ParseDMY("30.09.2024"),
+		}
+		env.must(mgrs.Schedule.Create(ctx, weekly))
+
+		t.Run("Occurrence is created once from the series", func(t *testing.T) {
+			occurrence, err := mgrs.OccurrenceAt(ctx, weekly.ScheduleID, domain.ParseDMY("02.09.2024"))
+			if err != nil {
+				t.Fatalf("OccurrenceAt failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrencePlanned, occurrence.Status)
+			assert.Equal(t, ivanova.EmployeeID, occurrence.EmployeeID)
+			if assert.NotNil(t, occurrence.AudienceID) {
+				assert.Equal(t, room.AudienceID, *occurrence.AudienceID)
+			}
+			assert.Equal(t, "10:00", domain.ToTimeHM(occurrence.TimeBegin))
+
+			again, err := mgrs.OccurrenceAt(ctx, weekly.ScheduleID, domain.ParseDMY("02.09.2024"))
+			if err != nil {
+				t.Fatalf("OccurrenceAt failed: %v", err)
+			}
+			assert.Equal(t, occurrence.OccurrenceID, again.OccurrenceID)
+
+			_, err = mgrs.OccurrenceAt(ctx, weekly.ScheduleID, domain.ParseDMY("03.09.2024"))
+			assert.True(t, errors.Is(err, domain.ErrNoOccurrence), "got %v", err)
+
+			// Отмена занятия после создания видна при следующем чтении
+			if _, err := mgrs.CancelOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("02.09.2024"), nil); err != nil {
+				t.Fatalf("CancelOccurrence failed: %v", err)
+			}
+			cancelled, err := mgrs.LessonOccurrence(ctx, occurrence.OccurrenceID)
+			if err != nil {
+				t.Fatalf("LessonOccurrence failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrenceCancelled, cancelled.Status)
+		})
+
+		t.Run("Moves and holidays are derived from the schedule", func(t *testing.T) {
+			newDate, begin, end := domain.ParseDMY("24.09.2024"), domain.ParseTimeHM("14:00"), domain.ParseTimeHM("15:00")
+			if _, err := mgrs.EditOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("23.09.2024"),
+				domain.ScheduleException{NewDate: &newDate, TimeBegin: &begin, TimeEnd: &end}); err != nil {
+				t.Fatalf("EditOccurrence failed: %v", err)
+			}
+			moved, err := mgrs.LessonOccurrenceOn(ctx, solfeggio.LessonID, newDate)
+			if err != nil {
+				t.Fatalf("LessonOccurrenceOn failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrenceMoved, moved.Status)
+			assert.Equal(t, "23.09.2024", domain.ToDMY(moved.OccurrenceDate))
+			assert.Equal(t, "24.09.2024", domain.ToDMY(moved.Date))
+			assert.Equal(t, "14:00", domain.ToTimeHM(moved.TimeBegin))
+
+			env.must(mgrs.Calendar.Create(ctx, &domain.CalendarEntry{
+				Kind: domain.CalendarHoliday, Name: "День школы",
+				DateStart: domain.ParseDMY("16.09.2024"), DateEnd: domain.ParseDMY("16.09.2024"),
+			}))
+			closed, err := mgrs.OccurrenceAt(ctx, weekly.ScheduleID, domain.ParseDMY("16.09.2024"))
+			if err != nil {
+				t.Fatalf("OccurrenceAt failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrenceCancelled, closed.Status)
+		})
+
+		var held *domain.LessonOccurrence
+
+		t.Run("Substitute teaches the occurrence until it is held", func(t *testing.T) {
+			substitutions, err := mgrs.RequestCover(ctx, managers.CoverOptions{
+				LessonID: solfeggio.LessonID, DateFrom: domain.ParseDMY("09.09.2024"), DateTo: domain.ParseDMY("09.09.2024"),
+			})
+			if err != nil || len(substitutions) != 1 {
+				t.Fatalf("RequestCover failed: %v", err)
+			}
+			if _, err := mgrs.AssignSubstitute(ctx, substitutions[0].SubstitutionID, petrova.EmployeeID); err != nil {
+				t.Fatalf("AssignSubstitute failed: %v", err)
+			}
+
+			held, err = mgrs.OccurrenceAt(ctx, weekly.ScheduleID, domain.ParseDMY("09.09.2024"))
+			if err != nil {
+				t.Fatalf("OccurrenceAt failed: %v", err)
+			}
+			assert.Equal(t, petrova.EmployeeID, held.EmployeeID)
+
+			topic := "Интервалы"
+			held.Status, held.Topic = domain.OccurrenceHeld, &topic
+			env.must(mgrs.Occurrence.Update(ctx, held))
+
+			// Перенос после проведения не меняет проведенное занятие
+			newDate := domain.ParseDMY("10.09.2024")
+			if _, err := mgrs.EditOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("09.09.2024"),
+				domain.ScheduleException{NewDate: &newDate}); err != nil {
+				t.Fatalf("EditOccurrence failed: %v", err)
+			}
+			frozen, err := mgrs.LessonOccurrence(ctx, held.OccurrenceID)
+			if err != nil {
+				t.Fatalf("LessonOccurrence failed: %v", err)
+			}
+			assert.Equal(t, domain.OccurrenceHeld, frozen.Status)
+			assert.Equal(t, "09.09.2024", domain.ToDMY(frozen.Date))
+		})
+
+		t.Run("Attendance and grades link to the occurrence", func(t *testing.T) {
+			linked := &domain.StudentAttendance{
+				StudentID: students[0].StudentID, LessonID: solfeggio.LessonID, PresenceMark: true, AttendanceDate: "2024-09-09", OccurrenceID: &held.OccurrenceID,
+			}
+			env.must(mgrs.Attendance.Create(ctx, linked))
+			// Запись без привязки находится по занятию и дате
+			env.must(mgrs.Attendance.Create(ctx, &domain.StudentAttendance{StudentID: students[1].StudentID, LessonID: solfeggio.LessonID, AttendanceDate: "2024-09-09"}))
+			env.must(mgrs.Assessment.Create(ctx, &domain.StudentAssessment{
+				LessonID: solfeggio.LessonID, StudentID: students[0].StudentID, TaskType: "Диктант", Grade: 5,
+				AssessmentDate: domain.ParseDMY("09.09.2024"), OccurrenceID: &held.OccurrenceID,
+			}))
+
+			err := mgrs.Attendance.Create(ctx, &domain.StudentAttendance{
+				StudentID: students[2].StudentID, LessonID: solfeggio.LessonID, AttendanceDate: "2024-09-10", OccurrenceID: &held.OccurrenceID,
+			})
+			assert.True(t, errors.Is(err, domain.ErrInvalidOccurrence), "got %v", err)
+			err = mgrs.Assessment.Create(ctx, &domain.StudentAssessment{
+				LessonID: choir.LessonID, StudentID: students[0].StudentID, TaskType: "Диктант", Grade: 4,
+				AssessmentDate: domain.ParseDMY("09.09.2024"), OccurrenceID: &held.OccurrenceID,
+			})
+			assert.True(t, errors.Is(err, domain.ErrInvalidOccurrence), "got %v", err)
+
+			session, err := mgrs.LessonSession(ctx, held.OccurrenceID)
+			if err != nil {
+				t.Fatalf("LessonSession failed: %v", err)
+			}
+			assert.Equal(t, held.OccurrenceID, session.Occurrence.OccurrenceID)
+			assert.Len(t, session.Attendance, 2)
+			assert.Len(t, session.Assessments, 1)
+
+			count, err := mgrs.Occurrence.Count(ctx, db.Filter{})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			assert.Equal(t, 4, count)
+		})
+
+		t.Run("Concurrent first reads create the occurrence once", func(t *testing.T) {
+			date := domain.ParseDMY("30.09.2024")
+			occurrences := make([]*domain.LessonOccurrence, 4)
+			errs := make([]error, len(occurrences))
+			var wg sync.WaitGroup
+			for i := range occurrences {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					occurrences[i], errs[i] = mgrs.OccurrenceAt(ctx, weekly.ScheduleID, date)
+				}()
+			}
+			wg.Wait()
+			for i, err := range errs {
+				if err != nil {
+					t.Fatalf("OccurrenceAt failed: %v", err)
+				}
+				assert.Equal(t, occurrences[0].OccurrenceID, occurrences[i].OccurrenceID)
+			}
+
+			count, err := mgrs.Occurrence.Count(ctx, db.Filter{Conditions: []db.Condition{
+				{Field: "schedule_id", Operator: "=", Value: weekly.ScheduleID},
+				{Field: "occurrence_date", Operator: "=", Value: date},
+			}})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			assert.Equal(t, 1, count)
+		})
+	})
+}
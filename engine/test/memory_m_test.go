@@ -0,0 +1,110 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/db/memory"
+	"GO_Music/db/repositories"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// Менеджеры поверх хранилища в памяти: тест не требует PostgreSQL и не пропускается
+func TestManagers_InMemory(t *testing.T) {
+	t.Parallel()
+
+	cfgPath_Log := "../../config/logger_config.yml"
+
+	levelLogger, err := logger.NewLevel(cfgPath_Log)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer levelLogger.Sync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	store := memory.NewStore()
+	repos := repositories.NewMemoryRepositories(store)
+	mgr := managers.NewInstrumentManager(repos.Instrument, store.DB(), levelLogger, 5*time.Second)
+
+	testInstrument := &domain.Instrument{
+		Name:       "Скрипка",
+		InstrType:  "String",
+		AudienceID: 1,
+		Condition:  "Good",
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		err := mgr.Create(ctx, testInstrument)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		assert.Equal(t, 1, testInstrument.InstrumentID)
+	})
+
+	t.Run("GetByName", func(t *testing.T) {
+		instr, err := mgr.GetByName(ctx, testInstrument.Name)
+		assert.NoError(t, err)
+		if assert.NotNil(t, instr) {
+			assert.Equal(t, testInstrument.InstrumentID, instr.InstrumentID)
+		}
+	})
+
+	t.Run("Create Duplicate Name", func(t *testing.T) {
+		err := mgr.Create(ctx, &domain.Instrument{Name: testInstrument.Name, InstrType: "Wind", AudienceID: 1, Condition: "Fair"})
+		assert.Error(t, err)
+	})
+
+	t.Run("BulkCreate", func(t *testing.T) {
+		instruments := []*domain.Instrument{
+			{Name: "Флейта", InstrType: "Wind", AudienceID: 2, Condition: "Good"},
+			{Name: "Арфа", InstrType: "String", AudienceID: 2, Condition: "Good"},
+		}
+		assert.NoError(t, mgr.BulkCreate(ctx, instruments))
+
+		byType, err := mgr.GetByType(ctx, "String")
+		assert.NoError(t, err)
+		assert.Len(t, byType, 2)
+	})
+
+	t.Run("ExecuteInTx rollback", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := mgr.ExecuteInTx(ctx, store.DB(), func(repo db.Repository[domain.Instrument, int]) error {
+			if err := repo.Create(ctx, &domain.Instrument{Name: "Барабан", InstrType: "Percussion", AudienceID: 3, Condition: "Good"}); err != nil {
+				return err
+			}
+			if err := repo.Delete(ctx, testInstrument.InstrumentID); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+
+		count, err := mgr.Count(ctx, db.Filter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		exists, err := mgr.Exists(ctx, testInstrument.InstrumentID)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("ExecuteInTx commit", func(t *testing.T) {
+		err := mgr.ExecuteInTx(ctx, store.DB(), func(repo db.Repository[domain.Instrument, int]) error {
+			return repo.Delete(ctx, testInstrument.InstrumentID)
+		})
+		assert.NoError(t, err)
+
+		exists, err := mgr.Exists(ctx, testInstrument.InstrumentID)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
@@ -0,0 +1,203 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Outbox(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs, repos := env.ctx, env.mgrs, env.repos
+
+		dispatcher := engine.NewOutboxDispatcher(repos.Outbox, env.db, env.logger, engine.DispatcherOptions{
+			MaxAttempts: 2,
+			RetryBase:   time.Hour,
+		})
+		var (
+			delivered []string
+			failing   = true
+		)
+		dispatcher.Register(managers.TopicAssessment, engine.SinkFunc(func(ctx context.Context, msg *domain.OutboxMessage) error {
+			if failing {
+				return errors.New("sink is down")
+			}
+			delivered = append(delivered, msg.Payload)
+			return nil
+		}))
+
+		pending := func(t *testing.T) []*domain.OutboxMessage {
+			list, err := repos.Outbox.List(ctx, db.Filter{
+				Conditions: []db.Condition{{Field: "delivered_at", Operator: "IS NULL"}},
+			})
+			if err != nil {
+				t.Fatalf("outbox list failed: %v", err)
+			}
+			return list
+		}
+
+		group := env.group(env.programm("Фортепиано"), "1 класс", 1)
+		student := env.student(group, "Смирнов", "Анна")
+		lesson := env.lesson(env.employee("Иванова"), group, env.subject("Фортепиано"), "Фортепиано")
+		newAssessment := func() *domain.StudentAssessment {
+			return &domain.StudentAssessment{LessonID: lesson.LessonID, StudentID: student.StudentID, TaskType: "Этюд", Grade: 5, AssessmentDate: time.Now()}
+		}
+
+		t.Run("Rolled back change writes nothing", func(t *testing.T) {
+			errAbort := errors.New("abort")
+			err := mgrs.Assessment.ExecuteInTx(ctx, env.db, func(repo db.Repository[domain.StudentAssessment, int]) error {
+				if err := repo.Create(ctx, newAssessment()); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			assert.Empty(t, pending(t))
+		})
+
+		t.Run("Committed change is enqueued", func(t *testing.T) {
+			if err := mgrs.Assessment.Create(ctx, newAssessment()); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			list := pending(t)
+			if !assert.Len(t, list, 1) {
+				return
+			}
+			assert.Equal(t, managers.TopicAssessment, list[0].Topic)
+
+			var change engine.OutboxChange[domain.StudentAssessment]
+			if assert.NoError(t, json.Unmarshal([]byte(list[0].Payload), &change)) {
+				assert.Equal(t, engine.AfterCreate, change.Event)
+				assert.Nil(t, change.Old)
+				if assert.NotNil(t, change.New) {
+					assert.Equal(t, 5, change.New.Grade)
+				}
+			}
+		})
+
+		t.Run("Failed delivery is retried with backoff", func(t *testing.T) {
+			n, err := dispatcher.DispatchOnce(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			list := pending(t)
+			if !assert.Len(t, list, 1) {
+				return
+			}
+			assert.Equal(t, 1, list[0].Attempts)
+			assert.NotNil(t, list[0].LastError)
+			assert.True(t, list[0].NextAttemptAt.After(time.Now().Add(30*time.Minute)))
+
+			// Следующая попытка еще не наступила
+			n, err = dispatcher.DispatchOnce(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, n)
+
+			failing = false
+			list[0].NextAttemptAt = time.Now().Add(-time.Second)
+			assert.NoError(t, repos.Outbox.Update(ctx, list[0]))
+
+			n, err = dispatcher.DispatchOnce(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Empty(t, pending(t))
+			assert.Len(t, delivered, 1)
+		})
+
+		t.Run("Message is abandoned after MaxAttempts", func(t *testing.T) {
+			failing = true
+			if err := mgrs.Assessment.Create(ctx, newAssessment()); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			for range 2 {
+				n, err := dispatcher.DispatchOnce(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, n)
+
+				for _, msg := range pending(t) {
+					msg.NextAttemptAt = time.Now().Add(-time.Second)
+					assert.NoError(t, repos.Outbox.Update(ctx, msg))
+				}
+			}
+
+			n, err := dispatcher.DispatchOnce(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, n)
+			if list := pending(t); assert.Len(t, list, 1) {
+				assert.Equal(t, 2, list[0].Attempts)
+			}
+		})
+
+		// Второй диспетчер - другой экземпляр приложения; получатели первого вызываются вне транзакции
+		other := engine.NewOutboxDispatcher(repos.Outbox, env.db, env.logger, engine.DispatcherOptions{MaxAttempts: 2})
+		other.Register(managers.TopicAssessment, engine.SinkFunc(func(ctx context.Context, msg *domain.OutboxMessage) error {
+			return nil
+		}))
+		leased := func(t *testing.T, deliver func(t *testing.T, msg *domain.OutboxMessage) error) *domain.OutboxMessage {
+			leasing := engine.NewOutboxDispatcher(repos.Outbox, env.db, env.logger, engine.DispatcherOptions{MaxAttempts: 2, Lease: time.Minute})
+			var id int
+			leasing.Register(managers.TopicAssessment, engine.SinkFunc(func(ctx context.Context, msg *domain.OutboxMessage) error {
+				id = msg.OutboxID
+				return deliver(t, msg)
+			}))
+
+			if err := mgrs.Assessment.Create(ctx, newAssessment()); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			n, err := leasing.DispatchOnce(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			stored, err := repos.Outbox.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			return stored
+		}
+
+		t.Run("Leased message is not claimed again", func(t *testing.T) {
+			stored := leased(t, func(t *testing.T, msg *domain.OutboxMessage) error {
+				n, err := other.DispatchOnce(ctx)
+				assert.NoError(t, err)
+				assert.Zero(t, n, "the message is leased")
+
+				current, err := repos.Outbox.GetByID(ctx, msg.OutboxID)
+				if assert.NoError(t, err) {
+					assert.True(t, current.NextAttemptAt.After(time.Now().Add(30*time.Second)))
+				}
+				return nil
+			})
+			assert.NotNil(t, stored.DeliveredAt)
+			assert.Zero(t, stored.Attempts)
+		})
+
+		t.Run("Outcome is dropped after the lease is lost", func(t *testing.T) {
+			stored := leased(t, func(t *testing.T, msg *domain.OutboxMessage) error {
+				// Аренда истекла, сообщение забрал и доставил другой диспетчер; неудача первого не записывается
+				current, err := repos.Outbox.GetByID(ctx, msg.OutboxID)
+				if err != nil {
+					t.Fatalf("GetByID failed: %v", err)
+				}
+				current.NextAttemptAt = time.Now().Add(-time.Second)
+				assert.NoError(t, repos.Outbox.Update(ctx, current))
+
+				n, err := other.DispatchOnce(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, n)
+				return errors.New("sink timed out")
+			})
+			assert.NotNil(t, stored.DeliveredAt)
+			assert.Zero(t, stored.Attempts)
+			assert.Nil(t, stored.LastError)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Preload(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs, repos := env.ctx, env.mgrs, env.repos
+
+		// Связанные сущности создаются напрямую через репозитории: проверки менеджеров здесь не нужны
+		employee := &domain.Employee{Surname: "Римский-Корсаков", Name: "Николай", Birthday: domain.ParseDMY("18.03.1844"), PhoneNumber: "79000000001", Job: "Преподаватель", WorkExperience: 30}
+		programm := &domain.Programm{ProgrammName: "Фортепиано", ProgrammType: "Предпрофессиональная", Duration: 8, StudyLoad: 4, FinalCertificationForm: "Экзамен"}
+		subject := &domain.Subject{SubjectName: "Сольфеджио", SubjectType: "Теория", ShortDesc: "Теория музыки"}
+		audience := &domain.Audience{Name: "Малый зал", AudinType: "Зал", AudinNumber: "101", Capacity: 30}
+		for _, err := range []error{
+			repos.Employee.Create(ctx, employee),
+			repos.Programm.Create(ctx, programm),
+			repos.Subject.Create(ctx, subject),
+			repos.Audience.Create(ctx, audience),
+		} {
+			if err != nil {
+				t.Fatalf("fixture create failed: %v", err)
+			}
+		}
+		group := &domain.StudyGroup{MusProgrammID: programm.MusprogrammID, GroupName: "1А", StudyYear: 1, NumberOfStudents: 10}
+		if err := repos.StudyGroup.Create(ctx, group); err != nil {
+			t.Fatalf("fixture create failed: %v", err)
+		}
+
+		lessons := []*domain.Lesson{
+			{EmployeeID: employee.EmployeeID, GroupID: group.GroupID, SubjectID: subject.SubjectID, LessonName: "Урок 1", AudienceID: &audience.AudienceID},
+			{EmployeeID: employee.EmployeeID, GroupID: group.GroupID, SubjectID: subject.SubjectID, LessonName: "Урок 2"},
+		}
+		for _, l := range lessons {
+			if err := repos.Lesson.Create(ctx, l); err != nil {
+				t.Fatalf("fixture create failed: %v", err)
+			}
+		}
+
+		t.Run("List without includes", func(t *testing.T) {
+			list, err := mgrs.Lesson.List(ctx, db.Filter{})
+			assert.NoError(t, err)
+			if assert.Len(t, list, 2) {
+				assert.Nil(t, list[0].Employee)
+				assert.Nil(t, list[0].Audience)
+			}
+		})
+
+		t.Run("List with includes", func(t *testing.T) {
+			list, err := mgrs.Lesson.List(ctx, db.Filter{Preloads: []string{"employee", "group", "subject", "audience"}})
+			assert.NoError(t, err)
+			if !assert.Len(t, list, 2) {
+				return
+			}
+			for _, l := range list {
+				if assert.NotNil(t, l.Employee) {
+					assert.Equal(t, "Римский-Корсаков", l.Employee.Surname)
+				}
+				if assert.NotNil(t, l.Group) {
+					assert.Equal(t, "1А", l.Group.GroupName)
+				}
+				if assert.NotNil(t, l.Subject) {
+					assert.Equal(t, "Сольфеджио", l.Subject.SubjectName)
+				}
+			}
+			if assert.NotNil(t, list[0].Audience) {
+				assert.Equal(t, "101", list[0].Audience.AudinNumber)
+			}
+			assert.Nil(t, list[1].Audience, "Lesson without audience should stay without it")
+		})
+
+		t.Run("Preload for a single entity", func(t *testing.T) {
+			student := &domain.Student{Surname: "Рахманинов", Name: "Сергей", Birthday: domain.ParseDMY("01.04.2012"), GroupID: group.GroupID, MusprogrammID: programm.MusprogrammID}
+			if err := repos.Student.Create(ctx, student); err != nil {
+				t.Fatalf("fixture create failed: %v", err)
+			}
+
+			loaded, err := mgrs.Student.GetByID(ctx, student.StudentID)
+			assert.NoError(t, err)
+			assert.NoError(t, mgrs.Student.Preload(ctx, []*domain.Student{loaded}, []string{"group", "programm"}))
+			if assert.NotNil(t, loaded.Programm) {
+				assert.Equal(t, "Фортепиано", loaded.Programm.ProgrammName)
+			}
+			assert.NotNil(t, loaded.Group)
+		})
+
+		t.Run("Relations are not stored", func(t *testing.T) {
+			list, err := mgrs.Lesson.List(ctx, db.Filter{Preloads: []string{"employee"}})
+			assert.NoError(t, err)
+			assert.NoError(t, mgrs.Lesson.Update(ctx, list[0]))
+
+			stored, err := mgrs.Lesson.GetByID(ctx, list[0].LessonID)
+			assert.NoError(t, err)
+			assert.Nil(t, stored.Employee)
+		})
+
+		t.Run("Unknown include", func(t *testing.T) {
+			_, err := mgrs.Lesson.List(ctx, db.Filter{Preloads: []string{"teacher"}})
+			assert.True(t, errors.Is(err, db.ErrInvalidFilter), "expected ErrInvalidFilter, got %v", err)
+
+			_, err = mgrs.Instrument.List(ctx, db.Filter{Preloads: []string{"audience"}})
+			assert.True(t, errors.Is(err, db.ErrInvalidFilter), "expected ErrInvalidFilter, got %v", err)
+		})
+
+		assert.Equal(t, []string{"audience", "employee", "group", "subject"}, mgrs.Lesson.Relations())
+	})
+}
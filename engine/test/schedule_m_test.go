@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestScheduleManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -49,7 +38,7 @@ func TestScheduleManager_AllMethods(t *testing.T) {
 
 	// Тестовые данные
 	testSchedule := &domain.Schedule{
-		LessonID:      3,
+		LessonID:      fx.Lessons[2].LessonID,
 		DayWeek:       "Понедельник",
 		TimeBegin:     domain.ParseTimeHM("10:00"),
 		TimeEnd:       domain.ParseTimeHM("11:00"),
@@ -111,15 +100,6 @@ func TestScheduleManager_AllMethods(t *testing.T) {
 		assert.NotEmpty(t, schedules)
 	})
 
-	t.Run("CheckTimeConflict", func(t *testing.T) {
-		conflict, err := mgr.CheckTimeConflict(ctx, testSchedule.DayWeek, testSchedule.TimeBegin.Format("15:04"), testSchedule.TimeEnd.Format("15:04"), testSchedule.ScheduleID)
-		if err != nil {
-			levelLogger.Error("CheckTimeConflict failed", logger.String("error", err.Error()))
-			t.Fatalf("CheckTimeConflict failed: %v", err)
-		}
-		assert.False(t, conflict, "There should be no time conflict for the created schedule")
-	})
-
 	t.Run("GetByDateRange", func(t *testing.T) {
 		startDate := domain.ParseDMY("01.08.2023")
 		endDate := domain.ParseDMY("01.09.2025")
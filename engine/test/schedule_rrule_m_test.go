@@ -0,0 +1,222 @@
+package engine_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"GO_Music/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_ScheduleRecurrence(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		dates := func(occurrences []domain.Occurrence) []string {
+			result := make([]string, len(occurrences))
+			for i, o := range occurrences {
+				result[i] = domain.ToDMY(o.Date)
+			}
+			return result
+		}
+		rule := func(value string) *string { return &value }
+
+		// Занятия одного преподавателя в разных группах
+		teacher, subject, programm := env.employee("Иванова"), env.subject("Сольфеджио"), env.programm("Фортепиано")
+		lessons := make([]*domain.Lesson, 4)
+		for i := range lessons {
+			group := env.group(programm, fmt.Sprintf("%d класс", i+1), i+1)
+			lessons[i] = env.lesson(teacher, group, subject, fmt.Sprintf("Сольфеджио %d", i+1))
+		}
+
+		// Раз в две недели по понедельникам и четвергам, 5 занятий, начиная с понедельника 02.09.2024
+		biweekly := &domain.Schedule{
+			LessonID:      lessons[0].LessonID,
+			TimeBegin:     domain.ParseTimeHM("10:00"),
+			TimeEnd:       domain.ParseTimeHM("10:45"),
+			SchdDateStart: domain.ParseDMY("02.09.2024"),
+			RRule:         rule("RRULE:freq=weekly;interval=2;byday=TH,MO;count=5"),
+		}
+
+		t.Run("Create derives day_week and end date from the rule", func(t *testing.T) {
+			if err := mgrs.Schedule.Create(ctx, biweekly); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=5", *biweekly.RRule)
+			assert.Equal(t, "Понедельник,Четверг", biweekly.DayWeek)
+			assert.Equal(t, "30.09.2024", domain.ToDMY(biweekly.SchdDateEnd))
+
+			byDay, err := mgrs.Schedule.GetByDay(ctx, "Четверг")
+			if err != nil {
+				t.Fatalf("GetByDay failed: %v", err)
+			}
+			assert.Len(t, byDay, 1)
+		})
+
+		t.Run("Occurrences are expanded on read", func(t *testing.T) {
+			occurrences, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.09.2024"), domain.ParseDMY("31.10.2024"), 0, false)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"02.09.2024", "05.09.2024", "16.09.2024", "19.09.2024", "30.09.2024"}, dates(occurrences))
+
+			// COUNT отсчитывается от начала серии, а не от начала периода
+			occurrences, err = mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("10.09.2024"), domain.ParseDMY("31.10.2024"), 0, false)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"16.09.2024", "19.09.2024", "30.09.2024"}, dates(occurrences))
+		})
+
+		t.Run("Overlapping rule of the same teacher is a conflict", func(t *testing.T) {
+			clash := &domain.Schedule{
+				LessonID:      lessons[1].LessonID,
+				TimeBegin:     domain.ParseTimeHM("10:30"),
+				TimeEnd:       domain.ParseTimeHM("11:15"),
+				SchdDateStart: domain.ParseDMY("03.09.2024"),
+				RRule:         rule("FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20241231"),
+			}
+			err := mgrs.Schedule.Create(ctx, clash)
+			assert.True(t, errors.Is(err, domain.ErrScheduleConflict), "got %v", err)
+			assert.ErrorContains(t, err, fmt.Sprintf("employee %d is busy with lesson %d on 05.09.2024", teacher.EmployeeID, lessons[0].LessonID))
+		})
+
+		t.Run("Invalid rules are rejected", func(t *testing.T) {
+			for _, value := range []string{
+				"FREQ=DAILY",
+				"FREQ=WEEKLY;BYDAY=XX;COUNT=2",
+				"FREQ=WEEKLY;UNTIL=20240101;COUNT=2",
+				"FREQ=WEEKLY;BYDAY=MO;UNTIL=20240101", // до начала серии занятий нет
+			} {
+				invalid := &domain.Schedule{
+					LessonID:      lessons[1].LessonID,
+					TimeBegin:     domain.ParseTimeHM("15:00"),
+					TimeEnd:       domain.ParseTimeHM("16:00"),
+					SchdDateStart: domain.ParseDMY("02.09.2024"),
+					RRule:         rule(value),
+				}
+				err := mgrs.Schedule.Create(ctx, invalid)
+				assert.True(t, errors.Is(err, domain.ErrInvalidRule), "%s: got %v", value, err)
+			}
+		})
+
+		// Еженедельно по средам до 25.09.2024 без 11.09.2024
+		weekly := &domain.Schedule{
+			LessonID:      lessons[2].LessonID,
+			DayWeek:       "Среда",
+			TimeBegin:     domain.ParseTimeHM("12:00"),
+			TimeEnd:       domain.ParseTimeHM("13:00"),
+			SchdDateStart: domain.ParseDMY("04.09.2024"),
+			SchdDateEnd:   domain.ParseDMY("30.09.2024"),
+			ExDate:        rule("20240911"),
+		}
+		september := func(t *testing.T, includeCancelled bool) []domain.Occurrence {
+			occurrences, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.09.2024"), domain.ParseDMY("30.09.2024"), weekly.LessonID, includeCancelled)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			return occurrences
+		}
+
+		t.Run("Weekly series without a rule honours EXDATE", func(t *testing.T) {
+			if err := mgrs.Schedule.Create(ctx, weekly); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			assert.Nil(t, weekly.RRule)
+			assert.Equal(t, "25.09.2024", domain.ToDMY(weekly.SchdDateEnd))
+			assert.Equal(t, []string{"04.09.2024", "18.09.2024", "25.09.2024"}, dates(september(t, false)))
+		})
+
+		t.Run("Cancel and move single occurrences", func(t *testing.T) {
+			reason := "Концерт"
+			cancelled, err := mgrs.CancelOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("18.09.2024"), &reason)
+			if err != nil {
+				t.Fatalf("CancelOccurrence failed: %v", err)
+			}
+			assert.True(t, cancelled.Cancelled)
+
+			newDate := domain.ParseDMY("26.09.2024")
+			begin := domain.ParseTimeHM("14:00")
+			end := domain.ParseTimeHM("15:00")
+			if _, err := mgrs.EditOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("04.09.2024"),
+				domain.ScheduleException{NewDate: &newDate, TimeBegin: &begin, TimeEnd: &end}); err != nil {
+				t.Fatalf("EditOccurrence failed: %v", err)
+			}
+
+			occurrences := september(t, false)
+			assert.Equal(t, []string{"25.09.2024", "26.09.2024"}, dates(occurrences))
+			moved := occurrences[1]
+			assert.Equal(t, "04.09.2024", domain.ToDMY(moved.OriginalDate))
+			assert.Equal(t, "14:00", domain.ToTimeHM(moved.TimeBegin))
+
+			all := september(t, true)
+			assert.Equal(t, []string{"18.09.2024", "25.09.2024", "26.09.2024"}, dates(all))
+			assert.True(t, all[0].Cancelled)
+			assert.Equal(t, reason, *all[0].Reason)
+		})
+
+		t.Run("Moved occurrence appears only in the target range", func(t *testing.T) {
+			newDate := domain.ParseDMY("02.10.2024")
+			if _, err := mgrs.EditOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("25.09.2024"),
+				domain.ScheduleException{NewDate: &newDate}); err != nil {
+				t.Fatalf("EditOccurrence failed: %v", err)
+			}
+			assert.Equal(t, []string{"26.09.2024"}, dates(september(t, false)))
+
+			october, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.10.2024"), domain.ParseDMY("31.10.2024"), weekly.LessonID, false)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"02.10.2024"}, dates(october))
+		})
+
+		t.Run("Reset restores the rule", func(t *testing.T) {
+			for _, date := range []string{"04.09.2024", "18.09.2024", "25.09.2024"} {
+				if err := mgrs.ResetOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY(date)); err != nil {
+					t.Fatalf("ResetOccurrence %s failed: %v", date, err)
+				}
+			}
+			assert.Equal(t, []string{"04.09.2024", "18.09.2024", "25.09.2024"}, dates(september(t, true)))
+		})
+
+		t.Run("Exceptions address existing occurrences only", func(t *testing.T) {
+			_, err := mgrs.CancelOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("11.09.2024"), nil)
+			assert.True(t, errors.Is(err, domain.ErrNoOccurrence), "EXDATE: got %v", err)
+
+			_, err = mgrs.CancelOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("05.09.2024"), nil)
+			assert.True(t, errors.Is(err, domain.ErrNoOccurrence), "not a Wednesday: got %v", err)
+
+			end := domain.ParseTimeHM("11:00")
+			_, err = mgrs.EditOccurrence(ctx, weekly.ScheduleID, domain.ParseDMY("18.09.2024"), domain.ScheduleException{TimeEnd: &end})
+			assert.True(t, errors.Is(err, domain.ErrInvalidRule), "end before begin: got %v", err)
+		})
+
+		t.Run("GenerateSchedule stores a single rule", func(t *testing.T) {
+			template := &domain.Schedule{
+				LessonID:      lessons[3].LessonID,
+				DayWeek:       "Пятница",
+				TimeBegin:     domain.ParseTimeHM("09:00"),
+				TimeEnd:       domain.ParseTimeHM("09:45"),
+				SchdDateStart: domain.ParseDMY("02.09.2024"),
+			}
+			if err := mgrs.Schedule.GenerateSchedule(ctx, template, domain.ParseDMY("30.09.2024")); err != nil {
+				t.Fatalf("GenerateSchedule failed: %v", err)
+			}
+			assert.Equal(t, "FREQ=WEEKLY;BYDAY=FR;UNTIL=20240930", *template.RRule)
+
+			rows, err := mgrs.Schedule.GetByLesson(ctx, template.LessonID)
+			if err != nil {
+				t.Fatalf("GetByLesson failed: %v", err)
+			}
+			assert.Len(t, rows, 1)
+
+			occurrences, err := mgrs.ScheduleOccurrences(ctx, domain.ParseDMY("01.09.2024"), domain.ParseDMY("30.09.2024"), template.LessonID, false)
+			if err != nil {
+				t.Fatalf("ScheduleOccurrences failed: %v", err)
+			}
+			assert.Equal(t, []string{"06.09.2024", "13.09.2024", "20.09.2024", "27.09.2024"}, dates(occurrences))
+		})
+	})
+}
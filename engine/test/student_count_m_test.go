@@ -0,0 +1,122 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_StudentCounts(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs, repos := env.ctx, env.mgrs, env.repos
+
+		program := env.programm("Фортепиано")
+		first, second := env.group(program, "1-А", 1), env.group(program, "1-Б", 1)
+
+		newStudent := func(name string, groupID int) *domain.Student {
+			return &domain.Student{
+				Surname:       "Иванов",
+				Name:          name,
+				Birthday:      time.Date(2012, 3, 1, 0, 0, 0, 0, time.UTC),
+				GroupID:       groupID,
+				MusprogrammID: program.MusprogrammID,
+			}
+		}
+		counts := func(t *testing.T) (int, int) {
+			a, err := mgrs.StudyGroup.GetByID(ctx, first.GroupID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			b, err := mgrs.StudyGroup.GetByID(ctx, second.GroupID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			return a.NumberOfStudents, b.NumberOfStudents
+		}
+
+		petr := newStudent("Петр", first.GroupID)
+
+		t.Run("Create increments group count", func(t *testing.T) {
+			for _, s := range []*domain.Student{petr, newStudent("Анна", first.GroupID)} {
+				if err := mgrs.Student.Create(ctx, s); err != nil {
+					t.Fatalf("student create failed: %v", err)
+				}
+			}
+			a, b := counts(t)
+			assert.Equal(t, 2, a)
+			assert.Zero(t, b)
+		})
+
+		t.Run("Transfer moves student between groups", func(t *testing.T) {
+			petr.GroupID = second.GroupID
+			if err := mgrs.Student.Update(ctx, petr); err != nil {
+				t.Fatalf("student update failed: %v", err)
+			}
+			a, b := counts(t)
+			assert.Equal(t, 1, a)
+			assert.Equal(t, 1, b)
+		})
+
+		t.Run("Delete and restore", func(t *testing.T) {
+			if err := mgrs.Student.Delete(ctx, petr.StudentID); err != nil {
+				t.Fatalf("student delete failed: %v", err)
+			}
+			_, b := counts(t)
+			assert.Zero(t, b)
+
+			if _, err := mgrs.Student.Restore(ctx, petr.StudentID); err != nil {
+				t.Fatalf("student restore failed: %v", err)
+			}
+			_, b = counts(t)
+			assert.Equal(t, 1, b)
+		})
+
+		t.Run("Rolled back transaction keeps count", func(t *testing.T) {
+			errAbort := errors.New("abort")
+			err := mgrs.Student.ExecuteInTx(ctx, env.db, func(repo db.Repository[domain.Student, int]) error {
+				if err := repo.Create(ctx, newStudent("Олег", first.GroupID)); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			a, _ := counts(t)
+			assert.Equal(t, 1, a)
+		})
+
+		t.Run("Reconcile reports and fixes drift", func(t *testing.T) {
+			group, err := repos.StudyGroup.GetByID(ctx, first.GroupID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			group.NumberOfStudents = 7
+			if err := repos.StudyGroup.Update(ctx, group); err != nil {
+				t.Fatalf("direct update failed: %v", err)
+			}
+
+			mismatches, err := mgrs.ReconcileStudentCounts(ctx, false)
+			assert.NoError(t, err)
+			assert.Equal(t, []managers.StudentCountMismatch{
+				{GroupID: first.GroupID, GroupName: "1-А", Stored: 7, Actual: 1},
+			}, mismatches)
+			a, _ := counts(t)
+			assert.Equal(t, 7, a, "dry run must not change counts")
+
+			mismatches, err = mgrs.ReconcileStudentCounts(ctx, true)
+			assert.NoError(t, err)
+			assert.Len(t, mismatches, 1)
+			a, _ = counts(t)
+			assert.Equal(t, 1, a)
+
+			mismatches, err = mgrs.ReconcileStudentCounts(ctx, true)
+			assert.NoError(t, err)
+			assert.Empty(t, mismatches)
+		})
+	})
+}
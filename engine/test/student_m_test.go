@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -16,23 +15,13 @@ import (
 )
 
 func TestStudentManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Загрузка конфигурации
-	cfgPath_DB := "../../config/DB_config.yml"
 	cfgPath_Log := "../../config/logger_config.yml"
-	cfg, err := config.LoadDBConfig(cfgPath_DB)
-	if err != nil {
-		t.Fatalf("failed to load config: %v", err)
-	}
 
-	sqlDB, err := db.InitPostgresDB(cfg)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Тестовая схема с фикстурами
+	sqlDB, fx := dbtest.NewSeeded(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -50,11 +39,11 @@ func TestStudentManager_AllMethods(t *testing.T) {
 	// Тестовые данные
 
 	testStudent := &domain.Student{
-		UserID:        ptrNumber(3),
+		UserID:        ptrNumber(fx.Users[0].UserID),
 		Name:          "Иван",
 		Surname:       "Иванов",
-		GroupID:       1,
-		MusprogrammID: 1,
+		GroupID:       fx.Groups[0].GroupID,
+		MusprogrammID: fx.Programms[0].MusprogrammID,
 		Birthday:      domain.ParseDMY("15.12.2006"),
 		PhoneNumber:   ptrString("79109546785"),
 	}
@@ -121,7 +110,7 @@ func TestStudentManager_AllMethods(t *testing.T) {
 	})
 
 	t.Run("TransferToGroup", func(t *testing.T) {
-		newGroupID := 2
+		newGroupID := fx.Groups[1].GroupID
 		err := mgr.TransferToGroup(ctx, testStudent.StudentID, newGroupID)
 		if err != nil {
 			levelLogger.Error("TransferToGroup failed", logger.String("error", err.Error()), logger.Int("studentID", testStudent.StudentID), logger.Int("newGroupID", newGroupID))
@@ -137,7 +126,7 @@ func TestStudentManager_AllMethods(t *testing.T) {
 	})
 
 	t.Run("ChangeProgram", func(t *testing.T) {
-		newProgramID := 3
+		newProgramID := fx.Programms[2].MusprogrammID
 		err := mgr.ChangeProgram(ctx, testStudent.StudentID, newProgramID)
 		if err != nil {
 			levelLogger.Error("ChangeProgram failed", logger.String("error", err.Error()), logger.Int("studentID", testStudent.StudentID), logger.Int("newProgramID", newProgramID))
@@ -189,20 +178,20 @@ func TestStudentManager_AllMethods(t *testing.T) {
 	t.Run("BulkCreate", func(t *testing.T) {
 		students := []*domain.Student{
 			{
-				UserID:        ptrNumber(6),
+				UserID:        ptrNumber(fx.Users[1].UserID),
 				Name:          "Алексей",
 				Surname:       "Сидоров",
-				GroupID:       1,
-				MusprogrammID: 1,
+				GroupID:       fx.Groups[0].GroupID,
+				MusprogrammID: fx.Programms[0].MusprogrammID,
 				Birthday:      domain.ParseDMY("10.10.2010"),
 				PhoneNumber:   ptrString("79881043122"),
 			},
 			{
-				UserID:        ptrNumber(7),
+				UserID:        ptrNumber(fx.Users[2].UserID),
 				Name:          "Мария",
 				Surname:       "Петрова",
-				GroupID:       2,
-				MusprogrammID: 2,
+				GroupID:       fx.Groups[1].GroupID,
+				MusprogrammID: fx.Programms[1].MusprogrammID,
 				Birthday:      domain.ParseDMY("12.12.2012"),
 				PhoneNumber:   ptrString("79109887676"),
 			},
@@ -0,0 +1,177 @@
+package engine_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Substitution(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		ivanova, petrova, sidorova := env.employee("Иванова"), env.employee("Петрова"), env.employee("Сидорова")
+		orlova, kozlova := env.employee("Орлова"), env.employee("Козлова")
+		theory, chorus := env.subject("Сольфеджио"), env.subject("Хор")
+		programm := env.programm("Фортепиано")
+		var groups []*domain.StudyGroup
+		for i := range 3 {
+			groups = append(groups, env.group(programm, fmt.Sprintf("%d класс", i+1), i+1))
+		}
+
+		// Сольфеджио ведут Иванова, Петрова, Сидорова и Орлова; Козлова его не ведет.
+		// Хор ведут Сидорова, Иванова и Петрова
+		for _, teacher := range []*domain.Employee{ivanova, petrova, sidorova, orlova} {
+			env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: teacher.EmployeeID, SubjectID: theory.SubjectID}))
+		}
+		for _, teacher := range []*domain.Employee{sidorova, ivanova, petrova} {
+			env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: teacher.EmployeeID, SubjectID: chorus.SubjectID}))
+		}
+		vacationStart, vacationEnd, vacation := domain.ParseDMY("09.09.2024"), domain.ParseDMY("13.09.2024"), "Отпуск"
+		env.must(mgrs.Availability.Create(ctx, &domain.EmployeeAvailability{
+			EmployeeID: orlova.EmployeeID, Kind: domain.AvailabilityAbsence,
+			DateStart: &vacationStart, DateEnd: &vacationEnd, Reason: &vacation,
+		}))
+
+		solfeggio := env.lesson(ivanova, groups[0], theory, "Сольфеджио")
+		choir := env.lesson(sidorova, groups[1], chorus, "Хор")
+		for _, schedule := range []*domain.Schedule{
+			{LessonID: solfeggio.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("10:00"), TimeEnd: domain.ParseTimeHM("11:00"),
+				SchdDateStart: domain.ParseDMY("02.09.2024"), SchdDateEnd: domain.ParseDMY("30.09.2024")},
+			{LessonID: choir.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("10:30"), TimeEnd: domain.ParseTimeHM("11:30"),
+				SchdDateStart: domain.ParseDMY("02.09.2024"), SchdDateEnd: domain.ParseDMY("30.09.2024")},
+		} {
+			env.must(mgrs.Schedule.Create(ctx, schedule))
+		}
+
+		candidateIDs := func(t *testing.T, substitutionID int) []int {
+			t.Helper()
+			candidates, err := mgrs.SubstituteCandidates(ctx, substitutionID)
+			if err != nil {
+				t.Fatalf("SubstituteCandidates failed: %v", err)
+			}
+			ids := []int{}
+			for _, candidate := range candidates {
+				ids = append(ids, candidate.EmployeeID)
+			}
+			return ids
+		}
+
+		var single *domain.Substitution
+
+		t.Run("Single occurrence needs cover", func(t *testing.T) {
+			reason := "Больничный"
+			substitutions, err := mgrs.RequestCover(ctx, managers.CoverOptions{
+				LessonID: solfeggio.LessonID, DateFrom: domain.ParseDMY("09.09.2024"), DateTo: domain.ParseDMY("09.09.2024"), Reason: &reason,
+			})
+			if err != nil {
+				t.Fatalf("RequestCover failed: %v", err)
+			}
+			if !assert.Len(t, substitutions, 1) {
+				return
+			}
+			single = substitutions[0]
+			assert.Equal(t, domain.SubstitutionOpen, single.Status)
+			assert.Equal(t, ivanova.EmployeeID, single.EmployeeID)
+			assert.Equal(t, "09.09.2024", domain.ToDMY(single.Date))
+
+			_, err = mgrs.RequestCover(ctx, managers.CoverOptions{DateFrom: domain.ParseDMY("09.09.2024"), DateTo: domain.ParseDMY("09.09.2024")})
+			assert.True(t, errors.Is(err, domain.ErrInvalidSubstitution), "got %v", err)
+		})
+
+		t.Run("Candidates teach the subject and are free", func(t *testing.T) {
+			// Сидорова ведет хор в это время, Орлова в отпуске, Козлова не ведет сольфеджио
+			assert.Equal(t, []int{petrova.EmployeeID}, candidateIDs(t, single.SubstitutionID))
+		})
+
+		t.Run("Assigning checks qualification and free time", func(t *testing.T) {
+			_, err := mgrs.AssignSubstitute(ctx, single.SubstitutionID, kozlova.EmployeeID)
+			assert.True(t, errors.Is(err, domain.ErrInvalidSubstitution), "got %v", err)
+
+			_, err = mgrs.AssignSubstitute(ctx, single.SubstitutionID, sidorova.EmployeeID)
+			var unavailable *domain.AvailabilityError
+			if assert.True(t, errors.As(err, &unavailable), "got %v", err) {
+				assert.Equal(t, `teaches "Хор"`, unavailable.Reason)
+			}
+
+			assigned, err := mgrs.AssignSubstitute(ctx, single.SubstitutionID, petrova.EmployeeID)
+			if err != nil {
+				t.Fatalf("AssignSubstitute failed: %v", err)
+			}
+			assert.Equal(t, domain.SubstitutionAssigned, assigned.Status)
+			assert.Equal(t, petrova.EmployeeID, *assigned.SubstituteID)
+
+			lesson, err := mgrs.Lesson.GetByID(ctx, solfeggio.LessonID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			assert.Equal(t, ivanova.EmployeeID, lesson.EmployeeID, "the lesson keeps its teacher")
+		})
+
+		t.Run("Date range covers every lesson of the teacher", func(t *testing.T) {
+			substitutions, err := mgrs.RequestCover(ctx, managers.CoverOptions{
+				EmployeeID: ivanova.EmployeeID, DateFrom: domain.ParseDMY("09.09.2024"), DateTo: domain.ParseDMY("23.09.2024"),
+			})
+			if err != nil {
+				t.Fatalf("RequestCover failed: %v", err)
+			}
+			if assert.Len(t, substitutions, 3) {
+				assert.Equal(t, single.SubstitutionID, substitutions[0].SubstitutionID, "an existing substitution is kept")
+				assert.Equal(t, domain.SubstitutionAssigned, substitutions[0].Status)
+			}
+		})
+
+		t.Run("Substitutes are busy and covered teachers are free", func(t *testing.T) {
+			substitutions, err := mgrs.RequestCover(ctx, managers.CoverOptions{
+				LessonID: choir.LessonID, DateFrom: domain.ParseDMY("09.09.2024"), DateTo: domain.ParseDMY("09.09.2024"),
+			})
+			if err != nil {
+				t.Fatalf("RequestCover failed: %v", err)
+			}
+			if assert.Len(t, substitutions, 1) {
+				assert.Equal(t, []int{ivanova.EmployeeID}, candidateIDs(t, substitutions[0].SubstitutionID))
+			}
+		})
+
+		t.Run("Report per employee", func(t *testing.T) {
+			report, err := mgrs.SubstitutionReport(ctx, petrova.EmployeeID, domain.ParseDMY("01.09.2024"), domain.ParseDMY("30.09.2024"))
+			if err != nil {
+				t.Fatalf("SubstitutionReport failed: %v", err)
+			}
+			assert.Len(t, report.Covered, 1)
+			assert.Equal(t, 60, report.CoveredMinutes)
+			assert.Empty(t, report.Absent)
+
+			report, err = mgrs.SubstitutionReport(ctx, ivanova.EmployeeID, domain.ParseDMY("01.09.2024"), domain.ParseDMY("30.09.2024"))
+			if err != nil {
+				t.Fatalf("SubstitutionReport failed: %v", err)
+			}
+			assert.Empty(t, report.Covered)
+			assert.Len(t, report.Absent, 3)
+			assert.Equal(t, 180, report.AbsentMinutes)
+		})
+
+		t.Run("Substitute cannot be double-booked by the schedule", func(t *testing.T) {
+			music := env.lesson(petrova, groups[2], theory, "Теория музыки")
+			once := func(date string) *domain.Schedule {
+				return &domain.Schedule{LessonID: music.LessonID, DayWeek: "Понедельник", TimeBegin: domain.ParseTimeHM("10:00"), TimeEnd: domain.ParseTimeHM("11:00"),
+					SchdDateStart: domain.ParseDMY(date), SchdDateEnd: domain.ParseDMY(date)}
+			}
+
+			// Петрова заменяет Иванову на сольфеджио 09.09
+			err := mgrs.Schedule.Create(ctx, once("09.09.2024"))
+			var conflict *domain.ConflictError
+			if assert.True(t, errors.As(err, &conflict), "got %v", err) {
+				assert.Equal(t, domain.Resource{Kind: domain.ResourceEmployee, ID: petrova.EmployeeID}, conflict.Conflicts[0].Resource)
+				assert.Equal(t, solfeggio.LessonID, conflict.Conflicts[0].LessonID)
+				assert.Equal(t, "09.09.2024", domain.ToDMY(conflict.Conflicts[0].Date))
+			}
+			assert.NoError(t, mgrs.Schedule.Create(ctx, once("16.09.2024")))
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GO_Music/db"
+	"GO_Music/domain"
+	"GO_Music/engine/managers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_TimetableSolver(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		// Программа на 4 часа в неделю из двух предметов: по 2 часа на предмет
+		programm := env.programm("Фортепиано")
+		theory, piano := env.subject("Сольфеджио"), env.subject("Фортепиано")
+		for _, subject := range []*domain.Subject{theory, piano} {
+			env.must(mgrs.ProgrammDistr.Create(ctx, &domain.ProgrammDistribution{MusprogrammID: programm.MusprogrammID, SubjectID: subject.SubjectID}))
+		}
+		ivanova, petrova, sidorova := env.employee("Иванова"), env.employee("Петрова"), env.employee("Сидорова")
+		env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: ivanova.EmployeeID, SubjectID: theory.SubjectID}))
+		env.must(mgrs.SubjectDistr.Create(ctx, &domain.SubjectDistribution{EmployeeID: petrova.EmployeeID, SubjectID: piano.SubjectID}))
+
+		group := env.group(programm, "1 класс", 1)
+		var students []*domain.Student
+		for _, name := range []string{"Анна", "Борис", "Вера"} {
+			students = append(students, env.student(group, "Смирнов", name))
+		}
+
+		// Маленький класс с роялем и большой класс без инструментов
+		small, hall := env.audience("101", 2), env.audience("201", 20)
+		env.must(mgrs.Instrument.Create(ctx, &domain.Instrument{AudienceID: small.AudienceID, Name: "Фортепиано Yamaha", InstrType: "Клавишные", Condition: "Хорошее"}))
+
+		solfeggio := env.lesson(ivanova, group, theory, "Сольфеджио")
+		individual := &domain.Lesson{EmployeeID: petrova.EmployeeID, GroupID: group.GroupID, StudentID: &students[0].StudentID, SubjectID: piano.SubjectID, LessonName: "Фортепиано"}
+		env.must(mgrs.Lesson.Create(ctx, individual))
+		unqualified := env.lesson(sidorova, group, theory, "Сольфеджио без квалификации")
+		cramped := &domain.Lesson{EmployeeID: ivanova.EmployeeID, AudienceID: &small.AudienceID, GroupID: group.GroupID, SubjectID: theory.SubjectID, LessonName: "Сольфеджио в малом классе"}
+		env.must(mgrs.Lesson.Create(ctx, cramped))
+
+		term := managers.TimetableOptions{
+			DateStart: domain.ParseDMY("02.09.2024"),
+			DateEnd:   domain.ParseDMY("27.12.2024"),
+			Days:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday},
+			DayBegin:  domain.ParseTimeHM("14:00"),
+			DayEnd:    domain.ParseTimeHM("16:00"),
+		}
+
+		var draft *domain.TimetableDraft
+
+		t.Run("Solver places lessons without clashes", func(t *testing.T) {
+			var err error
+			draft, err = mgrs.SolveTimetable(ctx, term)
+			if err != nil {
+				t.Fatalf("SolveTimetable failed: %v", err)
+			}
+			assert.Equal(t, domain.DraftProposed, draft.Status)
+
+			proposal, err := draft.Proposal()
+			if err != nil {
+				t.Fatalf("Proposal failed: %v", err)
+			}
+
+			days := map[int][]string{}
+			for _, slot := range proposal.Slots {
+				days[slot.LessonID] = append(days[slot.LessonID], slot.DayWeek)
+				switch slot.LessonID {
+				case solfeggio.LessonID:
+					assert.Equal(t, hall.AudienceID, slot.AudienceID, "the group does not fit the small room")
+				case individual.LessonID:
+					assert.Equal(t, small.AudienceID, slot.AudienceID, "the piano is in the small room")
+				}
+			}
+			assert.Len(t, days[solfeggio.LessonID], 2)
+			assert.Len(t, days[individual.LessonID], 2)
+			assert.NotEqual(t, days[solfeggio.LessonID][0], days[solfeggio.LessonID][1], "hours of a lesson go on different days")
+
+			// Студент индивидуального занятия учится в группе: их часы не пересекаются
+			for _, a := range proposal.Slots {
+				for _, b := range proposal.Slots {
+					if a.LessonID < b.LessonID && a.DayWeek == b.DayWeek {
+						assert.False(t, domain.TimesOverlap(a.TimeBegin, a.TimeEnd, b.TimeBegin, b.TimeEnd), "%v and %v", a, b)
+					}
+				}
+			}
+
+			constraints := map[int]string{}
+			for _, c := range proposal.Unsatisfied {
+				constraints[c.LessonID] = c.Constraint
+			}
+			assert.Equal(t, map[int]string{
+				unqualified.LessonID: domain.ConstraintQualification,
+				cramped.LessonID:     domain.ConstraintCapacity,
+			}, constraints)
+		})
+
+		t.Run("Applying creates weekly series for the term", func(t *testing.T) {
+			stale, err := mgrs.SolveTimetable(ctx, term)
+			if err != nil {
+				t.Fatalf("SolveTimetable failed: %v", err)
+			}
+
+			schedules, err := mgrs.ApplyTimetable(ctx, draft.DraftID)
+			if err != nil {
+				t.Fatalf("ApplyTimetable failed: %v", err)
+			}
+			assert.Len(t, schedules, 4)
+			for _, schedule := range schedules {
+				// Серия начинается в первый свой день недели периода
+				day, _ := domain.ParseWeekday(schedule.DayWeek)
+				assert.Equal(t, day, schedule.SchdDateStart.Weekday())
+				assert.True(t, schedule.SchdDateStart.Before(domain.ParseDMY("09.09.2024")), "starts %s", domain.ToDMY(schedule.SchdDateStart))
+			}
+
+			lesson, err := mgrs.Lesson.GetByID(ctx, solfeggio.LessonID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			if assert.NotNil(t, lesson.AudienceID) {
+				assert.Equal(t, hall.AudienceID, *lesson.AudienceID)
+			}
+
+			_, err = mgrs.ApplyTimetable(ctx, draft.DraftID)
+			assert.True(t, errors.Is(err, domain.ErrDraftApplied), "got %v", err)
+
+			// Второй черновик составлен до применения первого: его часы заняты
+			_, err = mgrs.ApplyTimetable(ctx, stale.DraftID)
+			assert.True(t, errors.Is(err, domain.ErrScheduleConflict), "got %v", err)
+			count, err := mgrs.Schedule.Count(ctx, db.Filter{})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			assert.Equal(t, 4, count, "a rejected draft creates nothing")
+		})
+
+		t.Run("Scheduled lessons are not placed again", func(t *testing.T) {
+			again, err := mgrs.SolveTimetable(ctx, term)
+			if err != nil {
+				t.Fatalf("SolveTimetable failed: %v", err)
+			}
+			proposal, err := again.Proposal()
+			if err != nil {
+				t.Fatalf("Proposal failed: %v", err)
+			}
+			assert.Empty(t, proposal.Slots)
+			assert.Len(t, proposal.Unsatisfied, 2)
+
+			_, err = mgrs.SolveTimetable(ctx, managers.TimetableOptions{DateStart: term.DateEnd, DateEnd: term.DateStart})
+			assert.True(t, errors.Is(err, domain.ErrInvalidTimetable), "got %v", err)
+		})
+	})
+}
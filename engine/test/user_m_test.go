@@ -5,8 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"GO_Music/config"
-	"GO_Music/db"
+	"GO_Music/db/dbtest"
 	"GO_Music/db/repositories"
 	"GO_Music/domain"
 	"GO_Music/engine/managers"
@@ -17,26 +16,14 @@ import (
 )
 
 func TestUserManager_AllMethods(t *testing.T) {
+	t.Parallel()
+
 	// Пути к конфигам
-	cfgPathDB := "../../config/DB_config.yml"
 	cfgPathLog := "../../config/logger_config.yml"
 	cfgPathAccess := "../../config/access_config.yml"
 
-	// Загружаем DB конфиг и инициализируем базу
-	cfgDB, err := config.LoadDBConfig(cfgPathDB)
-	if err != nil {
-		t.Fatalf("failed to load db config: %v", err)
-	}
-
-	sqlDB, err := db.InitPostgresDB(cfgDB)
-	if err != nil {
-		t.Fatalf("failed to init db: %v", err)
-	}
-	defer sqlDB.Close()
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("failed to ping db: %v", err)
-	}
+	// Изолированная тестовая схема
+	sqlDB := dbtest.New(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,131 @@
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	"GO_Music/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagers_Waitlist(t *testing.T) {
+	runOnStores(t, func(t *testing.T, env *managersEnv) {
+		ctx, mgrs := env.ctx, env.mgrs
+
+		program := env.programm("Скрипка")
+		capacity := 2
+		full := &domain.StudyGroup{MusProgrammID: program.MusprogrammID, GroupName: "2-А", StudyYear: 2, MaxStudents: &capacity}
+		env.must(mgrs.StudyGroup.Create(ctx, full))
+		other := env.group(program, "2-Б", 2)
+
+		count := func(t *testing.T) int {
+			group, err := mgrs.StudyGroup.GetByID(ctx, full.GroupID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			return group.NumberOfStudents
+		}
+
+		anna := env.student(full, "Петрова", "Анна")
+		env.student(full, "Петрова", "Мария")
+		olga, vera := env.student(other, "Петрова", "Ольга"), env.student(other, "Петрова", "Вера")
+
+		t.Run("Full group rejects create and transfer", func(t *testing.T) {
+			err := mgrs.Student.Create(ctx, &domain.Student{
+				Surname: "Лишняя", Name: "Ирина", Birthday: time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC),
+				GroupID: full.GroupID, MusprogrammID: program.MusprogrammID,
+			})
+			assert.ErrorIs(t, err, domain.ErrGroupFull)
+
+			err = mgrs.Student.TransferToGroup(ctx, olga.StudentID, full.GroupID)
+			assert.ErrorIs(t, err, domain.ErrGroupFull)
+			assert.Equal(t, 2, count(t))
+		})
+
+		var olgaEntry, veraEntry *domain.WaitlistEntry
+
+		t.Run("Enroll keeps queue order", func(t *testing.T) {
+			var err error
+			olgaEntry, err = mgrs.EnrollWaitlist(ctx, full.GroupID, olga.StudentID)
+			if err != nil {
+				t.Fatalf("EnrollWaitlist failed: %v", err)
+			}
+			veraEntry, err = mgrs.EnrollWaitlist(ctx, full.GroupID, vera.StudentID)
+			if err != nil {
+				t.Fatalf("EnrollWaitlist failed: %v", err)
+			}
+			assert.Equal(t, domain.WaitlistWaiting, olgaEntry.Status)
+			assert.Equal(t, 1, olgaEntry.Position)
+			assert.Equal(t, 2, veraEntry.Position)
+
+			_, err = mgrs.EnrollWaitlist(ctx, full.GroupID, olga.StudentID)
+			assert.ErrorIs(t, err, domain.ErrWaitlistConflict)
+			_, err = mgrs.EnrollWaitlist(ctx, full.GroupID, anna.StudentID)
+			assert.ErrorIs(t, err, domain.ErrWaitlistConflict)
+		})
+
+		t.Run("Freed place is offered to the head of the queue", func(t *testing.T) {
+			if err := mgrs.Student.Delete(ctx, anna.StudentID); err != nil {
+				t.Fatalf("student delete failed: %v", err)
+			}
+
+			entries, err := mgrs.Waitlist.GetByGroup(ctx, full.GroupID)
+			assert.NoError(t, err)
+			if assert.Len(t, entries, 2) {
+				assert.Equal(t, domain.WaitlistOffered, entries[0].Status)
+				assert.NotNil(t, entries[0].OfferedAt)
+				assert.Equal(t, domain.WaitlistWaiting, entries[1].Status)
+				assert.Equal(t, 1, entries[1].Position)
+			}
+
+			// Предложенное место удерживается за Ольгой
+			err = mgrs.Student.TransferToGroup(ctx, vera.StudentID, full.GroupID)
+			assert.ErrorIs(t, err, domain.ErrGroupFull)
+			assert.ErrorIs(t, mgrs.AcceptWaitlistOffer(ctx, veraEntry.WaitlistID), domain.ErrWaitlistConflict)
+		})
+
+		t.Run("Accepted offer transfers the student", func(t *testing.T) {
+			if err := mgrs.AcceptWaitlistOffer(ctx, olgaEntry.WaitlistID); err != nil {
+				t.Fatalf("AcceptWaitlistOffer failed: %v", err)
+			}
+			student, err := mgrs.Student.GetByID(ctx, olga.StudentID)
+			assert.NoError(t, err)
+			assert.Equal(t, full.GroupID, student.GroupID)
+			assert.Equal(t, 2, count(t))
+
+			entry, err := mgrs.Waitlist.GetByID(ctx, olgaEntry.WaitlistID)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.WaitlistEnrolled, entry.Status)
+			assert.NotNil(t, entry.ResolvedAt)
+		})
+
+		t.Run("Raised capacity offers places", func(t *testing.T) {
+			group, err := mgrs.StudyGroup.GetByID(ctx, full.GroupID)
+			if err != nil {
+				t.Fatalf("GetByID failed: %v", err)
+			}
+			raised := 3
+			group.MaxStudents = &raised
+			if err := mgrs.StudyGroup.Update(ctx, group); err != nil {
+				t.Fatalf("group update failed: %v", err)
+			}
+
+			entry, err := mgrs.Waitlist.GetByID(ctx, veraEntry.WaitlistID)
+			assert.NoError(t, err)
+			assert.Equal(t, domain.WaitlistOffered, entry.Status)
+		})
+
+		t.Run("Withdrawn entry stays in student history", func(t *testing.T) {
+			assert.NoError(t, mgrs.WithdrawWaitlist(ctx, veraEntry.WaitlistID))
+			assert.ErrorIs(t, mgrs.WithdrawWaitlist(ctx, veraEntry.WaitlistID), domain.ErrWaitlistConflict)
+
+			history, err := mgrs.Waitlist.GetByStudent(ctx, vera.StudentID)
+			assert.NoError(t, err)
+			if assert.Len(t, history, 1) {
+				assert.Equal(t, domain.WaitlistWithdrawn, history[0].Status)
+				assert.Zero(t, history[0].Position)
+			}
+		})
+	})
+}
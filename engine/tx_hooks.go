@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// afterCommit отложенные действия открытых транзакций (см. AfterCommit)
+var afterCommit = struct {
+	sync.Mutex
+	hooks map[*sql.Tx][]func()
+}{hooks: map[*sql.Tx][]func(){}}
+
+// [RU] AfterCommit откладывает fn до успешного CommitTx транзакции; при RollbackTx fn отбрасывается <--->
+// [ENG] AfterCommit defers fn until the transaction is committed with CommitTx; RollbackTx drops fn
+func AfterCommit(tx *sql.Tx, fn func()) {
+	afterCommit.Lock()
+	defer afterCommit.Unlock()
+	afterCommit.hooks[tx] = append(afterCommit.hooks[tx], fn)
+}
+
+// [RU] CommitTx фиксирует транзакцию и выполняет действия, отложенные через AfterCommit <--->
+// [ENG] CommitTx commits the transaction and runs the actions deferred with AfterCommit
+func CommitTx(tx *sql.Tx) error {
+	hooks := takeHooks(tx)
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, fn := range hooks {
+		fn()
+	}
+	return nil
+}
+
+// [RU] RollbackTx откатывает транзакцию и отбрасывает отложенные действия <--->
+// [ENG] RollbackTx rolls the transaction back and drops the deferred actions
+func RollbackTx(tx *sql.Tx) error {
+	takeHooks(tx)
+	return tx.Rollback()
+}
+
+// takeHooks забирает отложенные действия транзакции из реестра
+func takeHooks(tx *sql.Tx) []func() {
+	afterCommit.Lock()
+	defer afterCommit.Unlock()
+	hooks := afterCommit.hooks[tx]
+	delete(afterCommit.hooks, tx)
+	return hooks
+}
+
+// [RU] RunInTx выполняет fn в новой транзакции: CommitTx при успехе, RollbackTx при ошибке или панике <--->
+// [ENG] RunInTx runs fn in a new transaction: CommitTx on success, RollbackTx on error or panic
+func RunInTx(ctx context.Context, txProvider TxProvider, fn func(tx *sql.Tx) error) error {
+	tx, err := txProvider.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx failed: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = RollbackTx(tx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = RollbackTx(tx)
+		return err
+	}
+	if err := CommitTx(tx); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
@@ -15,3 +15,200 @@
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"GO_Music/api"
+	"GO_Music/api/handlers"
+	"GO_Music/config"
+	"GO_Music/db"
+	"GO_Music/db/memory"
+	"GO_Music/db/repositories"
+	"GO_Music/engine"
+	"GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/access"
+	"github.com/SerMoskvin/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// shutdownTimeout время на завершение обработки активных запросов
+const shutdownTimeout = 15 * time.Second
+
+func main() {
+	configPath := flag.String("config", "config/config.yml", "path to application config")
+	demo := flag.Bool("demo", false, "run without PostgreSQL, keeping data in memory")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(*configPath, flag.Args()[1:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+	if flag.Arg(0) == "reconcile" {
+		if err := runReconcile(*configPath, flag.Args()[1:]); err != nil {
+			log.Fatalf("reconcile: %v", err)
+		}
+		return
+	}
+
+	if err := run(*configPath, *demo); err != nil {
+		log.Fatalf("server stopped with error: %v", err)
+	}
+}
+
+// [RU] run собирает зависимости приложения, запускает HTTP-сервер и ждёт сигнала завершения.
+// В демо-режиме вместо PostgreSQL используется хранилище в памяти <--->
+// [ENG] run wires application dependencies, starts HTTP server and waits for shutdown signal.
+// Demo mode uses an in-memory store instead of PostgreSQL
+func run(configPath string, demo bool) error {
+	cfg, err := config.LoadAppConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	levelLogger, err := logger.NewLevel(cfg.Logger.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	defer levelLogger.Sync()
+
+	var (
+		sqlDB *sql.DB
+		repos *repositories.Repositories
+	)
+	if demo {
+		store := memory.NewStore()
+		sqlDB, repos = store.DB(), repositories.NewMemoryRepositories(store)
+		levelLogger.Warn("Demo mode: data is kept in memory and lost on shutdown")
+	} else {
+		sqlDB, err = db.InitPostgresDB(cfg.DB())
+		if err != nil {
+			levelLogger.Error("Failed to connect to database", logger.Error(err))
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		repos = repositories.NewRepositories(sqlDB)
+	}
+	defer func() {
+		if err := sqlDB.Close(); err != nil {
+			levelLogger.Error("Failed to close database", logger.Error(err))
+		}
+	}()
+
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		levelLogger.Error("Failed to create authenticator", logger.Error(err))
+		return fmt.Errorf("failed to create authenticator: %w", err)
+	}
+
+	engine.InitDefaultImage()
+
+	mngrs := managers.NewManagers(sqlDB, repos, levelLogger, auth)
+	hndlrs := handlers.NewHandlers(mngrs, levelLogger)
+	hndlrs.ApplyPageSizes(cfg.Handlers)
+	hndlrs.ApplyCursorSecret(cfg.CursorSecret())
+
+	// Настоящих уведомлений пока нет: сообщения outbox только пишутся в лог
+	dispatcher := engine.NewOutboxDispatcher(repos.Outbox, sqlDB, levelLogger, engine.DispatcherOptions{
+		PollInterval: cfg.Outbox.PollInterval.Std(),
+		BatchSize:    cfg.Outbox.BatchSize,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		RetryBase:    cfg.Outbox.RetryBase.Std(),
+		RetryMax:     cfg.Outbox.RetryMax.Std(),
+		Lease:        cfg.Outbox.Lease.Std(),
+	})
+	dispatcher.Register(engine.AllTopics, engine.LogSink(levelLogger))
+
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(middleware.RealIP)
+	router.Use(middleware.Recoverer)
+	api.SetupAll(router, auth, hndlrs.ToMap())
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(ctx)
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		levelLogger.Info("Server started", logger.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	var runErr error
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			levelLogger.Error("Server failed", logger.Error(err))
+			runErr = fmt.Errorf("server failed: %w", err)
+		}
+	case <-ctx.Done():
+		levelLogger.Info("Shutdown signal received, draining connections")
+	}
+
+	// Остановка одна и та же при сигнале и при падении сервера: диспетчер outbox и асинхронные
+	// обработчики событий дорабатывают до выхода
+	stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		levelLogger.Error("Graceful shutdown failed", logger.Error(err))
+		if runErr == nil {
+			runErr = fmt.Errorf("graceful shutdown failed: %w", err)
+		}
+	}
+	mngrs.Wait()
+	<-dispatcherDone
+
+	if runErr != nil {
+		return runErr
+	}
+	levelLogger.Info("Server stopped")
+	return nil
+}
+
+// [RU] newAuthenticator создает аутентификатор из итоговой конфигурации (с учетом переменных окружения) <--->
+// [ENG] newAuthenticator builds the authenticator from the effective config (including env overrides)
+func newAuthenticator(cfg *config.AppConfig) (*access.Authenticator, error) {
+	f, err := os.CreateTemp("", "go_music_access_*.yml")
+	if err != nil {
+		return nil, err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	if err := cfg.WriteAccessConfig(path); err != nil {
+		return nil, err
+	}
+	return access.NewAuthenticator(path)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2025 SerMoskvin - view full licesnse in main.go or GitHub
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"GO_Music/config"
+	"GO_Music/db"
+	"GO_Music/db/migrations"
+)
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n")
+	fmt.Fprintf(out, "  %s [-config path]                       start HTTP server\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] -demo                 start HTTP server with in-memory data\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] migrate up            apply all pending migrations\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] migrate down [n]      roll back n migrations (default 1)\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] migrate status        show applied and pending migrations\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] migrate to <version>  migrate up or down to version\n", os.Args[0])
+	fmt.Fprintf(out, "  %s [-config path] reconcile [-dry-run]  recount study group sizes and report mismatches\n", os.Args[0])
+	fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// [RU] runMigrate выполняет подкоманду migrate <--->
+// [ENG] runMigrate executes the migrate subcommand
+func runMigrate(configPath string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return errors.New("missing migrate command")
+	}
+
+	cfg, err := config.LoadAppConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sqlDB, err := db.InitPostgresDB(cfg.DB())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		printMigrations("reverted", reverted)
+		return err
+
+	case "to":
+		if len(args) < 2 {
+			return errors.New("migrate to requires a version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		changed, err := migrator.To(ctx, version)
+		printMigrations("changed", changed)
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, st := range statuses {
+			state, at := "pending", ""
+			if st.Applied {
+				state = "applied"
+				at = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, at)
+		}
+		return w.Flush()
+
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
+
+func printMigrations(action string, list []migrations.Migration) {
+	if len(list) == 0 {
+		fmt.Println("nothing to do")
+		return
+	}
+	for _, m := range list {
+		fmt.Printf("%s %04d_%s\n", action, m.Version, m.Name)
+	}
+}
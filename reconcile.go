@@ -0,0 +1,73 @@
+// Copyright (C) 2025 SerMoskvin - view full licesnse in main.go or GitHub
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"GO_Music/config"
+	"GO_Music/db"
+	"GO_Music/db/repositories"
+	"GO_Music/engine/managers"
+
+	"github.com/SerMoskvin/logger"
+)
+
+// [RU] runReconcile выполняет подкоманду reconcile: пересчитывает численность учебных групп
+// и печатает расхождения; с -dry-run только печатает <--->
+// [ENG] runReconcile executes the reconcile subcommand: recounts study group sizes
+// and prints the mismatches; with -dry-run it only prints them
+func runReconcile(configPath string, args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report mismatches without fixing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadAppConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	levelLogger, err := logger.NewLevel(cfg.Logger.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	defer levelLogger.Sync()
+
+	sqlDB, err := db.InitPostgresDB(cfg.DB())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer sqlDB.Close()
+
+	mngrs := managers.NewManagers(sqlDB, repositories.NewRepositories(sqlDB), levelLogger, nil)
+	mismatches, err := mngrs.ReconcileStudentCounts(context.Background(), !*dryRun)
+	if len(mismatches) == 0 && err == nil {
+		fmt.Println("student counts are consistent")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP ID\tNAME\tSTORED\tACTUAL")
+	for _, m := range mismatches {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", m.GroupID, m.GroupName, m.Stored, m.Actual)
+	}
+	if flushErr := w.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("%d mismatch(es) found, nothing changed (dry run)\n", len(mismatches))
+	} else {
+		fmt.Printf("fixed %d group(s)\n", len(mismatches))
+	}
+	return nil
+}